@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"log/slog"
@@ -14,9 +15,11 @@ import (
 	"time"
 
 	"github.com/absmach/magistrala-ui/internal/postgres"
+	"github.com/absmach/magistrala-ui/internal/ratelimit"
 	repo "github.com/absmach/magistrala-ui/postgres"
 	"github.com/absmach/magistrala-ui/ui"
 	"github.com/absmach/magistrala-ui/ui/api"
+	"github.com/absmach/magistrala-ui/ui/groups"
 	"github.com/absmach/magistrala-ui/ui/oauth2"
 	"github.com/absmach/magistrala-ui/ui/oauth2/google"
 	sdk "github.com/absmach/magistrala/pkg/sdk/go"
@@ -31,22 +34,37 @@ import (
 const envPrefixGoogle = "MG_GOOGLE_"
 
 type config struct {
-	LogLevel        string          `env:"MG_UI_LOG_LEVEL"        envDefault:"debug"`
-	Port            string          `env:"MG_UI_PORT"             envDefault:"9095"`
-	InstanceID      string          `env:"MG_UI_INSTANCE_ID"      envDefault:""`
-	HTTPAdapterURL  string          `env:"MG_HTTP_ADAPTER_URL"    envDefault:"http://localhost:8008"`
-	ReaderURL       string          `env:"MG_READER_URL"          envDefault:"http://localhost:9011"`
-	ThingsURL       string          `env:"MG_THINGS_URL"          envDefault:"http://localhost:9000"`
-	UsersURL        string          `env:"MG_USERS_URL"           envDefault:"http://localhost:9002"`
-	HostURL         string          `env:"MG_UI_HOST_URL"         envDefault:"http://localhost:9095"`
-	BootstrapURL    string          `env:"MG_BOOTSTRAP_URL"       envDefault:"http://localhost:9013"`
-	DomainsURL      string          `env:"MG_DOMAINS_URL"         envDefault:"http://localhost:8189"`
-	InvitationsURL  string          `env:"MG_INVITATIONS_URL"     envDefault:"http://localhost:9020"`
-	MsgContentType  sdk.ContentType `env:"MG_UI_CONTENT_TYPE"     envDefault:"application/senml+json"`
-	TLSVerification bool            `env:"MG_UI_VERIFICATION_TLS" envDefault:"false"`
-	HashKey         string          `env:"MG_UI_HASH_KEY"         envDefault:"5jx4x2Qg9OUmzpP5dbveWQ"`
-	BlockKey        string          `env:"MG_UI_BLOCK_KEY"        envDefault:"UtgZjr92jwRY6SPUndHXiyl9QY8qTUyZ"`
-	Prefix          string          `env:"MG_UI_PATH_PREFIX"      envDefault:""`
+	LogLevel              string          `env:"MG_UI_LOG_LEVEL"               envDefault:"debug"`
+	Port                  string          `env:"MG_UI_PORT"                    envDefault:"9095"`
+	InstanceID            string          `env:"MG_UI_INSTANCE_ID"             envDefault:""`
+	HTTPAdapterURL        string          `env:"MG_HTTP_ADAPTER_URL"           envDefault:"http://localhost:8008"`
+	ReaderURL             string          `env:"MG_READER_URL"                 envDefault:"http://localhost:9011"`
+	ThingsURL             string          `env:"MG_THINGS_URL"                 envDefault:"http://localhost:9000"`
+	UsersURL              string          `env:"MG_USERS_URL"                  envDefault:"http://localhost:9002"`
+	HostURL               string          `env:"MG_UI_HOST_URL"                envDefault:"http://localhost:9095"`
+	BootstrapURL          string          `env:"MG_BOOTSTRAP_URL"              envDefault:"http://localhost:9013"`
+	DomainsURL            string          `env:"MG_DOMAINS_URL"                envDefault:"http://localhost:8189"`
+	InvitationsURL        string          `env:"MG_INVITATIONS_URL"            envDefault:"http://localhost:9020"`
+	MsgContentType        sdk.ContentType `env:"MG_UI_CONTENT_TYPE"            envDefault:"application/senml+json"`
+	TLSVerification       bool            `env:"MG_UI_VERIFICATION_TLS"        envDefault:"false"`
+	HashKey               string          `env:"MG_UI_HASH_KEY"                envDefault:"5jx4x2Qg9OUmzpP5dbveWQ"`
+	BlockKey              string          `env:"MG_UI_BLOCK_KEY"               envDefault:"UtgZjr92jwRY6SPUndHXiyl9QY8qTUyZ"`
+	EncryptionKey         string          `env:"MG_UI_ENCRYPTION_KEY"          envDefault:"Vn4dXH0qJ2pB7rFkZs9LwT6yCmE3uGa1"`
+	Prefix                string          `env:"MG_UI_PATH_PREFIX"             envDefault:""`
+	SessionIdleTimeout    time.Duration   `env:"MG_UI_SESSION_IDLE_TIMEOUT"    envDefault:"30m"`
+	SessionMaxAge         time.Duration   `env:"MG_UI_SESSION_MAX_AGE"         envDefault:"12h"`
+	ContentSecurityPolicy string          `env:"MG_UI_CONTENT_SECURITY_POLICY" envDefault:""`
+	FrameOptions          string          `env:"MG_UI_FRAME_OPTIONS"           envDefault:"DENY"`
+	ReferrerPolicy        string          `env:"MG_UI_REFERRER_POLICY"         envDefault:"strict-origin-when-cross-origin"`
+	HSTSMaxAge            time.Duration   `env:"MG_UI_HSTS_MAX_AGE"            envDefault:"0s"`
+	HSTSIncludeSubdomains bool            `env:"MG_UI_HSTS_INCLUDE_SUBDOMAINS" envDefault:"false"`
+	CookieSecure          bool            `env:"MG_UI_COOKIE_SECURE"           envDefault:"false"`
+	CookieSameSite        string          `env:"MG_UI_COOKIE_SAME_SITE"        envDefault:"lax"`
+	CookieDomain          string          `env:"MG_UI_COOKIE_DOMAIN"           envDefault:""`
+	RotationCheckInterval time.Duration   `env:"MG_UI_ROTATION_CHECK_INTERVAL" envDefault:"1m"`
+	RotationTokenMaxAge   time.Duration   `env:"MG_UI_ROTATION_TOKEN_MAX_AGE" envDefault:"6h"`
+	TokenRefreshInterval  time.Duration   `env:"MG_UI_TOKEN_REFRESH_INTERVAL"  envDefault:"5m"`
+	TokenMaxAge           time.Duration   `env:"MG_UI_TOKEN_MAX_AGE"           envDefault:"6h"`
 }
 
 func main() {
@@ -79,7 +97,7 @@ func main() {
 		}
 	}
 
-	sdk := sdk.NewSDK(sdkConfig)
+	sdk := groups.NewSDK(sdkConfig)
 
 	oauthConfig := oauth2.Config{}
 	if err := env.ParseWithOptions(&oauthConfig, env.Options{Prefix: envPrefixGoogle}); err != nil {
@@ -97,10 +115,16 @@ func main() {
 	}
 
 	dbs := repo.NewRepository(db)
+	tokens := repo.NewTokenRepository(db)
+	twoFactor := repo.NewTwoFactorRepository(db)
+	audit := repo.NewTerminalAuditRepository(db)
+	policies := repo.NewTerminalPolicyRepository(db)
+	templates := repo.NewBootstrapTemplateRepository(db)
+	rotations := repo.NewSecretRotationRepository(db)
 
 	idp := uuid.New()
 
-	svc, err := ui.New(sdk, dbs, idp, cfg.Prefix, oauthProvider)
+	svc, err := ui.New(sdk, dbs, tokens, twoFactor, audit, policies, templates, rotations, []byte(cfg.EncryptionKey), idp, cfg.Prefix, oauthProvider)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -122,13 +146,60 @@ func main() {
 		}, []string{"method"}),
 	)
 
+	go ui.RunRotationWorker(context.Background(), svc, cfg.RotationCheckInterval, cfg.RotationTokenMaxAge)
+	go ui.RunTokenRefreshWorker(context.Background(), svc, cfg.TokenRefreshInterval, cfg.TokenMaxAge)
+
 	errs := make(chan error, 2)
 
 	mux := chi.NewRouter()
 
 	s := securecookie.New([]byte(cfg.HashKey), []byte(cfg.BlockKey))
 
-	handler, err := api.MakeHandler(svc, mux, cfg.InstanceID, cfg.Prefix, s, oauthProvider)
+	sessionCfg := api.SessionConfig{
+		IdleTimeout: cfg.SessionIdleTimeout,
+		MaxAge:      cfg.SessionMaxAge,
+	}
+
+	rlConfig := ratelimit.Config{}
+	if err := env.Parse(&rlConfig); err != nil {
+		log.Fatalf("failed to load rate limit configuration : %s", err.Error())
+	}
+	rateLimiter := api.NewRateLimiter(
+		ratelimit.New(ratelimit.NewMemoryStore(), rlConfig),
+		logger,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "ui",
+			Subsystem: "ratelimit",
+			Name:      "rejected_count",
+			Help:      "Number of requests rejected by the rate limiter.",
+		}, []string{"endpoint", "reason"}),
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "ui",
+			Subsystem: "ratelimit",
+			Name:      "failed_attempt_count",
+			Help:      "Number of failed attempts on rate limited endpoints.",
+		}, []string{"endpoint"}),
+	)
+
+	sameSite, err := api.ParseSameSite(cfg.CookieSameSite)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	securityCfg := api.SecurityConfig{
+		ContentSecurityPolicy: cfg.ContentSecurityPolicy,
+		FrameOptions:          cfg.FrameOptions,
+		ReferrerPolicy:        cfg.ReferrerPolicy,
+		HSTSMaxAge:            cfg.HSTSMaxAge,
+		HSTSIncludeSubdomains: cfg.HSTSIncludeSubdomains,
+		CookieSecure:          cfg.CookieSecure,
+		CookieSameSite:        sameSite,
+		CookieDomain:          cfg.CookieDomain,
+	}
+	if securityCfg.ContentSecurityPolicy == "" {
+		securityCfg.ContentSecurityPolicy = api.DefaultContentSecurityPolicy
+	}
+
+	handler, err := api.MakeHandler(svc, mux, cfg.InstanceID, cfg.Prefix, s, sessionCfg, securityCfg, rateLimiter, oauthProvider)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
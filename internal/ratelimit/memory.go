@@ -0,0 +1,158 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often expired entries are removed from the memory store.
+const sweepInterval = time.Minute
+
+var _ Store = (*memoryStore)(nil)
+
+type entry struct {
+	bucket      bool
+	tokens      float64
+	refilled    time.Time
+	failures    int
+	failedAt    time.Time
+	lockedUntil time.Time
+	expires     time.Time
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*entry
+	lastSweep time.Time
+}
+
+// NewMemoryStore returns a store that keeps its state in memory.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		entries:   make(map[string]*entry),
+		lastSweep: time.Now(),
+	}
+}
+
+func (ms *memoryStore) Take(_ context.Context, key string, rate float64, burst int) (time.Duration, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	now := time.Now()
+	ms.sweep(now)
+
+	e := ms.entry(key, now)
+	if !e.bucket {
+		e.bucket = true
+		e.tokens = float64(burst)
+	}
+
+	e.tokens = min(float64(burst), e.tokens+now.Sub(e.refilled).Seconds()*rate)
+	e.refilled = now
+
+	full := now
+	if rate > 0 {
+		full = now.Add(time.Duration((float64(burst) - e.tokens + 1) / rate * float64(time.Second)))
+	}
+	e.expires = later(e.expires, full)
+
+	if e.tokens < 1 {
+		if rate <= 0 {
+			return sweepInterval, nil
+		}
+		return time.Duration((1 - e.tokens) / rate * float64(time.Second)), nil
+	}
+	e.tokens--
+
+	return 0, nil
+}
+
+func (ms *memoryStore) Fail(_ context.Context, key string, window time.Duration) (int, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	now := time.Now()
+	e := ms.entry(key, now)
+	if window > 0 && now.Sub(e.failedAt) > window {
+		e.failures = 0
+	}
+	e.failures++
+	e.failedAt = now
+	e.expires = later(e.expires, now.Add(window))
+
+	return e.failures, nil
+}
+
+func (ms *memoryStore) Lock(_ context.Context, key string, until time.Time) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	e := ms.entry(key, time.Now())
+	e.lockedUntil = until
+	e.expires = later(e.expires, until)
+
+	return nil
+}
+
+func (ms *memoryStore) LockedUntil(_ context.Context, key string) (time.Time, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	e, ok := ms.entries[key]
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	return e.lockedUntil, nil
+}
+
+func (ms *memoryStore) Reset(_ context.Context, key string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if e, ok := ms.entries[key]; ok {
+		e.failures = 0
+		e.failedAt = time.Time{}
+		e.lockedUntil = time.Time{}
+	}
+
+	return nil
+}
+
+// entry returns the entry for key, creating it if needed. The caller must
+// hold the lock.
+func (ms *memoryStore) entry(key string, now time.Time) *entry {
+	e, ok := ms.entries[key]
+	if !ok {
+		e = &entry{refilled: now}
+		ms.entries[key] = e
+	}
+
+	return e
+}
+
+// sweep removes entries that no longer hold any state. The caller must hold
+// the lock.
+func (ms *memoryStore) sweep(now time.Time) {
+	if now.Sub(ms.lastSweep) < sweepInterval {
+		return
+	}
+	for key, e := range ms.entries {
+		if now.After(e.expires) {
+			delete(ms.entries, key)
+		}
+	}
+	ms.lastSweep = now
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
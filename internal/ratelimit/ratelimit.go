@@ -0,0 +1,155 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ratelimit implements token bucket rate limiting with progressive
+// lockout after repeated failed attempts.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/errors"
+)
+
+var (
+	// ErrRateLimited indicates that the request rate for a key was exceeded.
+	ErrRateLimited = errors.New("too many requests, please try again later")
+
+	// ErrLocked indicates that a key is locked out after repeated failures.
+	ErrLocked = errors.New("too many failed attempts, please try again later")
+)
+
+// Config defines the limits applied to each key.
+type Config struct {
+	RequestsPerMinute float64       `env:"MG_UI_RATE_LIMIT_REQUESTS_PER_MINUTE" envDefault:"10"`
+	Burst             int           `env:"MG_UI_RATE_LIMIT_BURST"               envDefault:"5"`
+	MaxFailures       int           `env:"MG_UI_RATE_LIMIT_MAX_FAILURES"        envDefault:"5"`
+	FailureWindow     time.Duration `env:"MG_UI_RATE_LIMIT_FAILURE_WINDOW"      envDefault:"1h"`
+	Lockout           time.Duration `env:"MG_UI_RATE_LIMIT_LOCKOUT"             envDefault:"1m"`
+	MaxLockout        time.Duration `env:"MG_UI_RATE_LIMIT_MAX_LOCKOUT"         envDefault:"1h"`
+	TrustProxy        bool          `env:"MG_UI_RATE_LIMIT_TRUST_PROXY"         envDefault:"false"`
+}
+
+// Store keeps the state of token buckets and failed attempts. The in-memory
+// store is suitable for a single instance, while running several instances
+// behind a load balancer requires a store shared between them.
+type Store interface {
+	// Take removes a token from the bucket identified by key. The bucket
+	// holds at most burst tokens and is refilled at rate tokens per second.
+	// It returns zero if a token was available, or how long to wait until
+	// one will be.
+	Take(ctx context.Context, key string, rate float64, burst int) (time.Duration, error)
+
+	// Fail records a failed attempt for key and returns the number of
+	// failures recorded within the window.
+	Fail(ctx context.Context, key string, window time.Duration) (int, error)
+
+	// Lock locks key out until the given time.
+	Lock(ctx context.Context, key string, until time.Time) error
+
+	// LockedUntil returns the time until which key is locked out. The zero
+	// time is returned if the key is not locked.
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+
+	// Reset clears the failed attempts and lockout of key.
+	Reset(ctx context.Context, key string) error
+}
+
+// Limiter applies the configured limits using a store.
+type Limiter struct {
+	store Store
+	cfg   Config
+}
+
+// New returns a limiter backed by the given store.
+func New(store Store, cfg Config) *Limiter {
+	return &Limiter{
+		store: store,
+		cfg:   cfg,
+	}
+}
+
+// Allow reports whether a request identified by all of the given keys may
+// proceed. When it may not, the returned duration tells the caller how long
+// to wait before retrying. Tokens are taken from the keys in order and a
+// rate limited key leaves the following keys untouched, so the most specific
+// key, such as an identity, comes first and requests refused for it do not
+// use up the tokens of a shared key, such as an address.
+func (l *Limiter) Allow(ctx context.Context, keys ...string) (time.Duration, error) {
+	now := time.Now()
+	for _, key := range keys {
+		until, err := l.store.LockedUntil(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+		if until.After(now) {
+			return until.Sub(now), ErrLocked
+		}
+	}
+
+	rate := l.cfg.RequestsPerMinute / 60
+	for _, key := range keys {
+		wait, err := l.store.Take(ctx, key, rate, l.cfg.Burst)
+		if err != nil {
+			return 0, err
+		}
+		if wait > 0 {
+			return wait, ErrRateLimited
+		}
+	}
+
+	return 0, nil
+}
+
+// Failure records a failed attempt for each key. Keys that reach the
+// configured number of failures are locked out, and the lockout doubles with
+// every further failure up to the configured maximum. It returns the longest
+// lockout applied.
+func (l *Limiter) Failure(ctx context.Context, keys ...string) (time.Duration, error) {
+	var lockout time.Duration
+	for _, key := range keys {
+		failures, err := l.store.Fail(ctx, key, l.cfg.FailureWindow)
+		if err != nil {
+			return 0, err
+		}
+		if l.cfg.MaxFailures <= 0 || failures < l.cfg.MaxFailures {
+			continue
+		}
+
+		d := l.lockout(failures)
+		if err := l.store.Lock(ctx, key, time.Now().Add(d)); err != nil {
+			return 0, err
+		}
+		lockout = max(lockout, d)
+	}
+
+	return lockout, nil
+}
+
+// Success clears the failed attempts recorded for each key.
+func (l *Limiter) Success(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		if err := l.store.Reset(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TrustProxy reports whether client addresses may be taken from proxy headers.
+func (l *Limiter) TrustProxy() bool {
+	return l.cfg.TrustProxy
+}
+
+func (l *Limiter) lockout(failures int) time.Duration {
+	exp := float64(failures - l.cfg.MaxFailures)
+	d := time.Duration(float64(l.cfg.Lockout) * math.Pow(2, exp))
+	if d <= 0 || (l.cfg.MaxLockout > 0 && d > l.cfg.MaxLockout) {
+		return l.cfg.MaxLockout
+	}
+
+	return d
+}
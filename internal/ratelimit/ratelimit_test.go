@@ -0,0 +1,159 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala-ui/internal/ratelimit"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllow(t *testing.T) {
+	cfg := ratelimit.Config{
+		RequestsPerMinute: 60,
+		Burst:             3,
+	}
+
+	cases := []struct {
+		desc     string
+		requests int
+		err      error
+	}{
+		{
+			desc:     "requests within burst",
+			requests: 3,
+			err:      nil,
+		},
+		{
+			desc:     "requests exceeding burst",
+			requests: 4,
+			err:      ratelimit.ErrRateLimited,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			limiter := ratelimit.New(ratelimit.NewMemoryStore(), cfg)
+			var err error
+			var wait time.Duration
+			for i := 0; i < tc.requests; i++ {
+				wait, err = limiter.Allow(context.Background(), "ip:127.0.0.1", "identity:user@example.com")
+			}
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err != nil {
+				assert.Greater(t, wait, time.Duration(0), "expected a positive retry duration")
+			}
+		})
+	}
+}
+
+func TestAllowSeparateKeys(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.NewMemoryStore(), ratelimit.Config{RequestsPerMinute: 60, Burst: 1})
+
+	_, err := limiter.Allow(context.Background(), "ip:127.0.0.1")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = limiter.Allow(context.Background(), "ip:127.0.0.2")
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = limiter.Allow(context.Background(), "ip:127.0.0.1")
+	assert.True(t, errors.Contains(err, ratelimit.ErrRateLimited), fmt.Sprintf("expected error: %s, got: %s", ratelimit.ErrRateLimited, err))
+}
+
+func TestAllowKeyOrder(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.NewMemoryStore(), ratelimit.Config{RequestsPerMinute: 60, Burst: 1})
+
+	_, err := limiter.Allow(context.Background(), "identity:user@example.com", "ip:127.0.0.1")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = limiter.Allow(context.Background(), "identity:user@example.com", "ip:127.0.0.2")
+	assert.True(t, errors.Contains(err, ratelimit.ErrRateLimited), fmt.Sprintf("expected error: %s, got: %s", ratelimit.ErrRateLimited, err))
+	// The address keeps its token when the identity is rate limited.
+	_, err = limiter.Allow(context.Background(), "identity:other@example.com", "ip:127.0.0.2")
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+}
+
+func TestFailure(t *testing.T) {
+	cfg := ratelimit.Config{
+		RequestsPerMinute: 600,
+		Burst:             100,
+		MaxFailures:       3,
+		FailureWindow:     time.Hour,
+		Lockout:           time.Minute,
+		MaxLockout:        5 * time.Minute,
+	}
+
+	cases := []struct {
+		desc     string
+		failures int
+		lockout  time.Duration
+		err      error
+	}{
+		{
+			desc:     "failures below threshold",
+			failures: 2,
+			lockout:  0,
+			err:      nil,
+		},
+		{
+			desc:     "failures reaching threshold",
+			failures: 3,
+			lockout:  time.Minute,
+			err:      ratelimit.ErrLocked,
+		},
+		{
+			desc:     "failures beyond threshold double the lockout",
+			failures: 5,
+			lockout:  4 * time.Minute,
+			err:      ratelimit.ErrLocked,
+		},
+		{
+			desc:     "lockout is capped",
+			failures: 10,
+			lockout:  5 * time.Minute,
+			err:      ratelimit.ErrLocked,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			limiter := ratelimit.New(ratelimit.NewMemoryStore(), cfg)
+			key := "identity:user@example.com"
+			var lockout time.Duration
+			for i := 0; i < tc.failures; i++ {
+				var err error
+				lockout, err = limiter.Failure(context.Background(), key)
+				require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			}
+			assert.Equal(t, tc.lockout, lockout)
+			_, err := limiter.Allow(context.Background(), key)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+		})
+	}
+}
+
+func TestSuccess(t *testing.T) {
+	cfg := ratelimit.Config{
+		RequestsPerMinute: 600,
+		Burst:             100,
+		MaxFailures:       2,
+		FailureWindow:     time.Hour,
+		Lockout:           time.Minute,
+		MaxLockout:        time.Hour,
+	}
+	limiter := ratelimit.New(ratelimit.NewMemoryStore(), cfg)
+	key := "identity:user@example.com"
+
+	_, err := limiter.Failure(context.Background(), key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = limiter.Success(context.Background(), key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	lockout, err := limiter.Failure(context.Background(), key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, time.Duration(0), lockout, "expected failures to be cleared after success")
+}
@@ -0,0 +1,195 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/jmoiron/sqlx"
+)
+
+type auditRepo struct {
+	db *sqlx.DB
+}
+
+func NewTerminalAuditRepository(db *sqlx.DB) ui.TerminalAuditRepository {
+	return &auditRepo{db: db}
+}
+
+// Save a terminal command before it is sent to the agent.
+func (r *auditRepo) Save(ctx context.Context, cmd ui.TerminalCommand) error {
+	q := `INSERT INTO terminal_commands (id, user_id, user_identity, domain_id, thing_id, command, response, error, duration, created_at)
+	VALUES (:id, :user_id, :user_identity, :domain_id, :thing_id, :command, :response, :error, :duration, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, q, toDBTerminalCommand(cmd)); err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+
+	return nil
+}
+
+// Update the outcome of a terminal command.
+func (r *auditRepo) Update(ctx context.Context, cmd ui.TerminalCommand) error {
+	q := `UPDATE terminal_commands SET response = :response, error = :error, duration = :duration WHERE id = :id`
+
+	res, err := r.db.NamedExecContext(ctx, q, toDBTerminalCommand(cmd))
+	if err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Retrieve the terminal commands of a domain matching the page filters.
+func (r *auditRepo) RetrieveAll(ctx context.Context, pm ui.TerminalCommandPageMeta) (ui.TerminalCommandPage, error) {
+	dbPm := toDBTerminalCommandPageMeta(pm)
+	where := terminalCommandsQuery(pm)
+
+	q := fmt.Sprintf(`SELECT id, user_id, user_identity, domain_id, thing_id, command, response, error, duration, created_at
+	FROM terminal_commands %s ORDER BY created_at DESC LIMIT :limit OFFSET :offset`, where)
+
+	rows, err := r.db.NamedQueryContext(ctx, q, dbPm)
+	if err != nil {
+		return ui.TerminalCommandPage{}, HandleError(err, ErrViewEntity)
+	}
+	defer rows.Close()
+
+	var commands []ui.TerminalCommand
+	for rows.Next() {
+		dbCmd := dbTerminalCommand{}
+		if err = rows.StructScan(&dbCmd); err != nil {
+			return ui.TerminalCommandPage{}, HandleError(err, ErrViewEntity)
+		}
+		commands = append(commands, toTerminalCommand(dbCmd))
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM terminal_commands %s`, where)
+	total, err := total(ctx, r.db, cq, dbPm)
+	if err != nil {
+		return ui.TerminalCommandPage{}, HandleError(err, ErrViewEntity)
+	}
+
+	return ui.TerminalCommandPage{
+		Total:    total,
+		Offset:   pm.Offset,
+		Limit:    pm.Limit,
+		Commands: commands,
+	}, nil
+}
+
+func terminalCommandsQuery(pm ui.TerminalCommandPageMeta) string {
+	conditions := []string{"domain_id = :domain_id"}
+	if pm.UserID != "" {
+		conditions = append(conditions, "user_id = :user_id")
+	}
+	if pm.ThingID != "" {
+		conditions = append(conditions, "thing_id = :thing_id")
+	}
+	if pm.Command != "" {
+		conditions = append(conditions, "command ILIKE :command")
+	}
+	if !pm.From.IsZero() {
+		conditions = append(conditions, "created_at >= :from")
+	}
+	if !pm.To.IsZero() {
+		conditions = append(conditions, "created_at <= :to")
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND ")
+}
+
+func total(ctx context.Context, db *sqlx.DB, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var total uint64
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+
+	return total, nil
+}
+
+type dbTerminalCommand struct {
+	ID           string    `db:"id"`
+	UserID       string    `db:"user_id"`
+	UserIdentity string    `db:"user_identity"`
+	DomainID     string    `db:"domain_id"`
+	ThingID      string    `db:"thing_id"`
+	Command      string    `db:"command"`
+	Response     string    `db:"response"`
+	Error        string    `db:"error"`
+	Duration     int64     `db:"duration"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+type dbTerminalCommandPageMeta struct {
+	Offset   uint64    `db:"offset"`
+	Limit    uint64    `db:"limit"`
+	DomainID string    `db:"domain_id"`
+	UserID   string    `db:"user_id"`
+	ThingID  string    `db:"thing_id"`
+	Command  string    `db:"command"`
+	From     time.Time `db:"from"`
+	To       time.Time `db:"to"`
+}
+
+func toDBTerminalCommand(cmd ui.TerminalCommand) dbTerminalCommand {
+	return dbTerminalCommand{
+		ID:           cmd.ID,
+		UserID:       cmd.UserID,
+		UserIdentity: cmd.UserIdentity,
+		DomainID:     cmd.DomainID,
+		ThingID:      cmd.ThingID,
+		Command:      cmd.Command,
+		Response:     cmd.Response,
+		Error:        cmd.Error,
+		Duration:     int64(cmd.Duration),
+		CreatedAt:    cmd.CreatedAt,
+	}
+}
+
+func toTerminalCommand(dbCmd dbTerminalCommand) ui.TerminalCommand {
+	return ui.TerminalCommand{
+		ID:           dbCmd.ID,
+		UserID:       dbCmd.UserID,
+		UserIdentity: dbCmd.UserIdentity,
+		DomainID:     dbCmd.DomainID,
+		ThingID:      dbCmd.ThingID,
+		Command:      dbCmd.Command,
+		Response:     dbCmd.Response,
+		Error:        dbCmd.Error,
+		Duration:     time.Duration(dbCmd.Duration),
+		CreatedAt:    dbCmd.CreatedAt,
+	}
+}
+
+func toDBTerminalCommandPageMeta(pm ui.TerminalCommandPageMeta) dbTerminalCommandPageMeta {
+	dbPm := dbTerminalCommandPageMeta{
+		Offset:   pm.Offset,
+		Limit:    pm.Limit,
+		DomainID: pm.DomainID,
+		UserID:   pm.UserID,
+		ThingID:  pm.ThingID,
+		From:     pm.From,
+		To:       pm.To,
+	}
+	if pm.Command != "" {
+		dbPm.Command = "%" + pm.Command + "%"
+	}
+
+	return dbPm
+}
@@ -0,0 +1,217 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala-ui/postgres"
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveTerminalCommand(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM terminal_commands")
+		require.Nil(t, err, fmt.Sprintf("clean terminal commands unexpected error: %s", err))
+	})
+
+	domainID := generateUUID(t)
+	cmd := generateTerminalCommand(t, domainID, generateUUID(t), generateUUID(t))
+	emptyThing := generateTerminalCommand(t, domainID, generateUUID(t), generateUUID(t))
+	emptyThing.ThingID = ""
+	malformed := generateTerminalCommand(t, domainID, generateUUID(t), generateUUID(t))
+	malformed.ID = strings.Repeat("a", 37)
+
+	cases := []struct {
+		desc string
+		cmd  ui.TerminalCommand
+		err  error
+	}{
+		{
+			desc: "save new terminal command",
+			cmd:  cmd,
+			err:  nil,
+		},
+		{
+			desc: "save existing terminal command",
+			cmd:  cmd,
+			err:  postgres.ErrConflict,
+		},
+		{
+			desc: "save terminal command with empty thing id",
+			cmd:  emptyThing,
+			err:  postgres.ErrCreateEntity,
+		},
+		{
+			desc: "save terminal command with malformed id",
+			cmd:  malformed,
+			err:  postgres.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := auditRepo.Save(context.Background(), tc.cmd)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s to contain: %s", err, tc.err))
+		})
+	}
+}
+
+func TestUpdateTerminalCommand(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM terminal_commands")
+		require.Nil(t, err, fmt.Sprintf("clean terminal commands unexpected error: %s", err))
+	})
+
+	domainID := generateUUID(t)
+	cmd := generateTerminalCommand(t, domainID, generateUUID(t), generateUUID(t))
+	err := auditRepo.Save(context.Background(), cmd)
+	require.Nil(t, err, fmt.Sprintf("save terminal command unexpected error: %s", err))
+
+	completed := cmd
+	completed.Response = "ok"
+	completed.Duration = 2 * time.Second
+	failed := cmd
+	failed.ID = generateUUID(t)
+
+	cases := []struct {
+		desc string
+		cmd  ui.TerminalCommand
+		err  error
+	}{
+		{
+			desc: "update existing terminal command",
+			cmd:  completed,
+			err:  nil,
+		},
+		{
+			desc: "update non-existing terminal command",
+			cmd:  failed,
+			err:  postgres.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := auditRepo.Update(context.Background(), tc.cmd)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s to contain: %s", err, tc.err))
+			if err == nil {
+				page, err := auditRepo.RetrieveAll(context.Background(), ui.TerminalCommandPageMeta{DomainID: domainID, Limit: 10})
+				require.Nil(t, err, fmt.Sprintf("retrieve terminal commands unexpected error: %s", err))
+				require.Len(t, page.Commands, 1)
+				assert.Equal(t, tc.cmd, page.Commands[0])
+			}
+		})
+	}
+}
+
+func TestRetrieveAllTerminalCommands(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM terminal_commands")
+		require.Nil(t, err, fmt.Sprintf("clean terminal commands unexpected error: %s", err))
+	})
+
+	domainID := generateUUID(t)
+	userID := generateUUID(t)
+	thingID := generateUUID(t)
+	num := 10
+
+	var commands []ui.TerminalCommand
+	for i := 0; i < num; i++ {
+		cmd := generateTerminalCommand(t, domainID, userID, thingID)
+		cmd.CreatedAt = cmd.CreatedAt.Add(time.Duration(i) * time.Minute)
+		if i%2 == 0 {
+			cmd.UserID = generateUUID(t)
+			cmd.ThingID = generateUUID(t)
+			cmd.Command = "reboot"
+		}
+		err := auditRepo.Save(context.Background(), cmd)
+		require.Nil(t, err, fmt.Sprintf("save terminal command unexpected error: %s", err))
+		commands = append([]ui.TerminalCommand{cmd}, commands...)
+	}
+	// Commands of another domain must never be returned.
+	err := auditRepo.Save(context.Background(), generateTerminalCommand(t, generateUUID(t), userID, thingID))
+	require.Nil(t, err, fmt.Sprintf("save terminal command unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		pm    ui.TerminalCommandPageMeta
+		total uint64
+		size  int
+	}{
+		{
+			desc:  "retrieve all terminal commands of a domain",
+			pm:    ui.TerminalCommandPageMeta{DomainID: domainID, Limit: 100},
+			total: uint64(num),
+			size:  num,
+		},
+		{
+			desc:  "retrieve terminal commands with limit and offset",
+			pm:    ui.TerminalCommandPageMeta{DomainID: domainID, Offset: 2, Limit: 3},
+			total: uint64(num),
+			size:  3,
+		},
+		{
+			desc:  "retrieve terminal commands of a user",
+			pm:    ui.TerminalCommandPageMeta{DomainID: domainID, UserID: userID, Limit: 100},
+			total: uint64(num / 2),
+			size:  num / 2,
+		},
+		{
+			desc:  "retrieve terminal commands of a thing",
+			pm:    ui.TerminalCommandPageMeta{DomainID: domainID, ThingID: thingID, Limit: 100},
+			total: uint64(num / 2),
+			size:  num / 2,
+		},
+		{
+			desc:  "retrieve terminal commands matching a command",
+			pm:    ui.TerminalCommandPageMeta{DomainID: domainID, Command: "REBOOT", Limit: 100},
+			total: uint64(num / 2),
+			size:  num / 2,
+		},
+		{
+			desc:  "retrieve terminal commands in a time range",
+			pm:    ui.TerminalCommandPageMeta{DomainID: domainID, From: commands[5].CreatedAt, To: commands[2].CreatedAt, Limit: 100},
+			total: 4,
+			size:  4,
+		},
+		{
+			desc:  "retrieve terminal commands of unknown domain",
+			pm:    ui.TerminalCommandPageMeta{DomainID: generateUUID(t), Limit: 100},
+			total: 0,
+			size:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			page, err := auditRepo.RetrieveAll(context.Background(), tc.pm)
+			require.Nil(t, err, fmt.Sprintf("retrieve terminal commands unexpected error: %s", err))
+			assert.Equal(t, tc.total, page.Total)
+			assert.Len(t, page.Commands, tc.size)
+			if tc.pm.UserID == "" && tc.pm.ThingID == "" && tc.pm.Command == "" && tc.pm.From.IsZero() && tc.size > 0 {
+				assert.Equal(t, commands[tc.pm.Offset:tc.pm.Offset+uint64(tc.size)], page.Commands)
+			}
+		})
+	}
+}
+
+func generateTerminalCommand(t *testing.T, domainID, userID, thingID string) ui.TerminalCommand {
+	return ui.TerminalCommand{
+		ID:           generateUUID(t),
+		UserID:       userID,
+		UserIdentity: "user@example.com",
+		DomainID:     domainID,
+		ThingID:      thingID,
+		Command:      "uptime",
+		CreatedAt:    time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/jmoiron/sqlx"
+)
+
+type templateRepo struct {
+	db *sqlx.DB
+}
+
+func NewBootstrapTemplateRepository(db *sqlx.DB) ui.BootstrapTemplateRepository {
+	return &templateRepo{db: db}
+}
+
+// Save a non-existing bootstrap template.
+func (r *templateRepo) Save(ctx context.Context, tpl ui.BootstrapTemplate) error {
+	q := `INSERT INTO bootstrap_templates (id, domain_id, name, description, content, channels, created_by, created_at, updated_at)
+	VALUES (:id, :domain_id, :name, :description, :content, :channels, :created_by, :created_at, :updated_at)`
+
+	dbTpl, err := toDBBootstrapTemplate(tpl)
+	if err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+	if _, err := r.db.NamedExecContext(ctx, q, dbTpl); err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+
+	return nil
+}
+
+// Retrieve a bootstrap template using a domain id and a template id.
+func (r *templateRepo) Retrieve(ctx context.Context, domainID, id string) (ui.BootstrapTemplate, error) {
+	q := `SELECT id, domain_id, name, description, content, channels, created_by, created_at, updated_at
+	FROM bootstrap_templates WHERE id = :id AND domain_id = :domain_id`
+
+	rows, err := r.db.NamedQueryContext(ctx, q, dbBootstrapTemplate{ID: id, DomainID: domainID})
+	if err != nil {
+		return ui.BootstrapTemplate{}, HandleError(err, ErrViewEntity)
+	}
+	defer rows.Close()
+
+	dbTpl := dbBootstrapTemplate{}
+	if rows.Next() {
+		if err = rows.StructScan(&dbTpl); err != nil {
+			return ui.BootstrapTemplate{}, HandleError(err, ErrViewEntity)
+		}
+		return toBootstrapTemplate(dbTpl)
+	}
+
+	return ui.BootstrapTemplate{}, ErrNotFound
+}
+
+// Retrieve the bootstrap templates of a domain.
+func (r *templateRepo) RetrieveAll(ctx context.Context, pm ui.BootstrapTemplatePageMeta) (ui.BootstrapTemplatePage, error) {
+	q := `SELECT id, domain_id, name, description, content, channels, created_by, created_at, updated_at
+	FROM bootstrap_templates WHERE domain_id = :domain_id ORDER BY name LIMIT :limit OFFSET :offset`
+
+	rows, err := r.db.NamedQueryContext(ctx, q, pm)
+	if err != nil {
+		return ui.BootstrapTemplatePage{}, HandleError(err, ErrViewEntity)
+	}
+	defer rows.Close()
+
+	var templates []ui.BootstrapTemplate
+	for rows.Next() {
+		dbTpl := dbBootstrapTemplate{}
+		if err = rows.StructScan(&dbTpl); err != nil {
+			return ui.BootstrapTemplatePage{}, HandleError(err, ErrViewEntity)
+		}
+		tpl, err := toBootstrapTemplate(dbTpl)
+		if err != nil {
+			return ui.BootstrapTemplatePage{}, HandleError(err, ErrViewEntity)
+		}
+		templates = append(templates, tpl)
+	}
+
+	cq := `SELECT COUNT(*) FROM bootstrap_templates WHERE domain_id = :domain_id`
+	total, err := total(ctx, r.db, cq, pm)
+	if err != nil {
+		return ui.BootstrapTemplatePage{}, HandleError(err, ErrViewEntity)
+	}
+
+	return ui.BootstrapTemplatePage{
+		Total:     total,
+		Offset:    pm.Offset,
+		Limit:     pm.Limit,
+		Templates: templates,
+	}, nil
+}
+
+// Update an existing bootstrap template.
+func (r *templateRepo) Update(ctx context.Context, tpl ui.BootstrapTemplate) error {
+	q := `UPDATE bootstrap_templates SET name = :name, description = :description, content = :content,
+	channels = :channels, updated_at = :updated_at WHERE id = :id AND domain_id = :domain_id`
+
+	dbTpl, err := toDBBootstrapTemplate(tpl)
+	if err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+	res, err := r.db.NamedExecContext(ctx, q, dbTpl)
+	if err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete an existing bootstrap template.
+func (r *templateRepo) Delete(ctx context.Context, domainID, id string) error {
+	q := `DELETE FROM bootstrap_templates WHERE id = :id AND domain_id = :domain_id`
+
+	res, err := r.db.NamedExecContext(ctx, q, dbBootstrapTemplate{ID: id, DomainID: domainID})
+	if err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+type dbBootstrapTemplate struct {
+	ID          string    `db:"id"`
+	DomainID    string    `db:"domain_id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Content     string    `db:"content"`
+	Channels    []byte    `db:"channels"`
+	CreatedBy   string    `db:"created_by"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func toDBBootstrapTemplate(tpl ui.BootstrapTemplate) (dbBootstrapTemplate, error) {
+	channels, err := json.Marshal(tpl.Channels)
+	if err != nil {
+		return dbBootstrapTemplate{}, errors.Wrap(ErrJSONMarshal, err)
+	}
+
+	return dbBootstrapTemplate{
+		ID:          tpl.ID,
+		DomainID:    tpl.DomainID,
+		Name:        tpl.Name,
+		Description: tpl.Description,
+		Content:     tpl.Content,
+		Channels:    channels,
+		CreatedBy:   tpl.CreatedBy,
+		CreatedAt:   tpl.CreatedAt,
+		UpdatedAt:   tpl.UpdatedAt,
+	}, nil
+}
+
+func toBootstrapTemplate(dbTpl dbBootstrapTemplate) (ui.BootstrapTemplate, error) {
+	var channels []string
+	if dbTpl.Channels != nil {
+		if err := json.Unmarshal(dbTpl.Channels, &channels); err != nil {
+			return ui.BootstrapTemplate{}, errors.Wrap(ErrJSONUnmarshal, err)
+		}
+	}
+
+	return ui.BootstrapTemplate{
+		ID:          dbTpl.ID,
+		DomainID:    dbTpl.DomainID,
+		Name:        dbTpl.Name,
+		Description: dbTpl.Description,
+		Content:     dbTpl.Content,
+		Channels:    channels,
+		CreatedBy:   dbTpl.CreatedBy,
+		CreatedAt:   dbTpl.CreatedAt,
+		UpdatedAt:   dbTpl.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,280 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala-ui/postgres"
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveBootstrapTemplate(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM bootstrap_templates")
+		require.Nil(t, err, fmt.Sprintf("clean bootstrap templates unexpected error: %s", err))
+	})
+
+	domainID := generateUUID(t)
+	tpl := generateBootstrapTemplate(t, domainID, "gateway")
+	sameName := generateBootstrapTemplate(t, domainID, "gateway")
+	otherDomain := generateBootstrapTemplate(t, generateUUID(t), "gateway")
+	emptyName := generateBootstrapTemplate(t, domainID, "")
+
+	cases := []struct {
+		desc string
+		tpl  ui.BootstrapTemplate
+		err  error
+	}{
+		{
+			desc: "save new bootstrap template",
+			tpl:  tpl,
+			err:  nil,
+		},
+		{
+			desc: "save existing bootstrap template",
+			tpl:  tpl,
+			err:  postgres.ErrConflict,
+		},
+		{
+			desc: "save bootstrap template with existing name",
+			tpl:  sameName,
+			err:  postgres.ErrConflict,
+		},
+		{
+			desc: "save bootstrap template with existing name in another domain",
+			tpl:  otherDomain,
+			err:  nil,
+		},
+		{
+			desc: "save bootstrap template with empty name",
+			tpl:  emptyName,
+			err:  postgres.ErrCreateEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := templateRepo.Save(context.Background(), tc.tpl)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s to contain: %s", err, tc.err))
+		})
+	}
+}
+
+func TestRetrieveBootstrapTemplate(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM bootstrap_templates")
+		require.Nil(t, err, fmt.Sprintf("clean bootstrap templates unexpected error: %s", err))
+	})
+
+	domainID := generateUUID(t)
+	tpl := generateBootstrapTemplate(t, domainID, "gateway")
+	err := templateRepo.Save(context.Background(), tpl)
+	require.Nil(t, err, fmt.Sprintf("save bootstrap template unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		domainID string
+		id       string
+		tpl      ui.BootstrapTemplate
+		err      error
+	}{
+		{
+			desc:     "retrieve existing bootstrap template",
+			domainID: domainID,
+			id:       tpl.ID,
+			tpl:      tpl,
+			err:      nil,
+		},
+		{
+			desc:     "retrieve bootstrap template of another domain",
+			domainID: generateUUID(t),
+			id:       tpl.ID,
+			err:      postgres.ErrNotFound,
+		},
+		{
+			desc:     "retrieve non-existing bootstrap template",
+			domainID: domainID,
+			id:       generateUUID(t),
+			err:      postgres.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			tpl, err := templateRepo.Retrieve(context.Background(), tc.domainID, tc.id)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s to contain: %s", err, tc.err))
+			if err == nil {
+				assert.Equal(t, tc.tpl, tpl)
+			}
+		})
+	}
+}
+
+func TestRetrieveAllBootstrapTemplates(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM bootstrap_templates")
+		require.Nil(t, err, fmt.Sprintf("clean bootstrap templates unexpected error: %s", err))
+	})
+
+	domainID := generateUUID(t)
+	num := 10
+	var templates []ui.BootstrapTemplate
+	for i := 0; i < num; i++ {
+		tpl := generateBootstrapTemplate(t, domainID, fmt.Sprintf("template-%02d", i))
+		err := templateRepo.Save(context.Background(), tpl)
+		require.Nil(t, err, fmt.Sprintf("save bootstrap template unexpected error: %s", err))
+		templates = append(templates, tpl)
+	}
+	err := templateRepo.Save(context.Background(), generateBootstrapTemplate(t, generateUUID(t), "template"))
+	require.Nil(t, err, fmt.Sprintf("save bootstrap template unexpected error: %s", err))
+
+	cases := []struct {
+		desc      string
+		pm        ui.BootstrapTemplatePageMeta
+		templates []ui.BootstrapTemplate
+	}{
+		{
+			desc:      "retrieve all bootstrap templates of a domain",
+			pm:        ui.BootstrapTemplatePageMeta{DomainID: domainID, Limit: 100},
+			templates: templates,
+		},
+		{
+			desc:      "retrieve bootstrap templates with limit and offset",
+			pm:        ui.BootstrapTemplatePageMeta{DomainID: domainID, Offset: 2, Limit: 3},
+			templates: templates[2:5],
+		},
+		{
+			desc: "retrieve bootstrap templates of unknown domain",
+			pm:   ui.BootstrapTemplatePageMeta{DomainID: generateUUID(t), Limit: 100},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			page, err := templateRepo.RetrieveAll(context.Background(), tc.pm)
+			require.Nil(t, err, fmt.Sprintf("retrieve bootstrap templates unexpected error: %s", err))
+			assert.Equal(t, tc.templates, page.Templates)
+			if len(tc.templates) > 0 {
+				assert.Equal(t, uint64(num), page.Total)
+			}
+		})
+	}
+}
+
+func TestUpdateBootstrapTemplate(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM bootstrap_templates")
+		require.Nil(t, err, fmt.Sprintf("clean bootstrap templates unexpected error: %s", err))
+	})
+
+	domainID := generateUUID(t)
+	tpl := generateBootstrapTemplate(t, domainID, "gateway")
+	err := templateRepo.Save(context.Background(), tpl)
+	require.Nil(t, err, fmt.Sprintf("save bootstrap template unexpected error: %s", err))
+
+	updated := tpl
+	updated.Name = "sensor"
+	updated.Content = `{"agent":{}}`
+	updated.Channels = []string{"{{ .data }}"}
+	updated.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	otherDomain := updated
+	otherDomain.DomainID = generateUUID(t)
+
+	cases := []struct {
+		desc string
+		tpl  ui.BootstrapTemplate
+		err  error
+	}{
+		{
+			desc: "update existing bootstrap template",
+			tpl:  updated,
+			err:  nil,
+		},
+		{
+			desc: "update bootstrap template of another domain",
+			tpl:  otherDomain,
+			err:  postgres.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := templateRepo.Update(context.Background(), tc.tpl)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s to contain: %s", err, tc.err))
+			if err == nil {
+				tpl, err := templateRepo.Retrieve(context.Background(), tc.tpl.DomainID, tc.tpl.ID)
+				require.Nil(t, err, fmt.Sprintf("retrieve bootstrap template unexpected error: %s", err))
+				assert.Equal(t, tc.tpl, tpl)
+			}
+		})
+	}
+}
+
+func TestDeleteBootstrapTemplate(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM bootstrap_templates")
+		require.Nil(t, err, fmt.Sprintf("clean bootstrap templates unexpected error: %s", err))
+	})
+
+	domainID := generateUUID(t)
+	tpl := generateBootstrapTemplate(t, domainID, "gateway")
+	err := templateRepo.Save(context.Background(), tpl)
+	require.Nil(t, err, fmt.Sprintf("save bootstrap template unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		domainID string
+		id       string
+		err      error
+	}{
+		{
+			desc:     "delete bootstrap template of another domain",
+			domainID: generateUUID(t),
+			id:       tpl.ID,
+			err:      postgres.ErrNotFound,
+		},
+		{
+			desc:     "delete existing bootstrap template",
+			domainID: domainID,
+			id:       tpl.ID,
+			err:      nil,
+		},
+		{
+			desc:     "delete deleted bootstrap template",
+			domainID: domainID,
+			id:       tpl.ID,
+			err:      postgres.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := templateRepo.Delete(context.Background(), tc.domainID, tc.id)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s to contain: %s", err, tc.err))
+		})
+	}
+}
+
+func generateBootstrapTemplate(t *testing.T, domainID, name string) ui.BootstrapTemplate {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	return ui.BootstrapTemplate{
+		ID:          generateUUID(t),
+		DomainID:    domainID,
+		Name:        name,
+		Description: "gateway template",
+		Content:     `{"agent":{"mqtt":{"url":"{{ .mqtt_url }}"}}}`,
+		Channels:    []string{"{{ .control }}"},
+		CreatedBy:   generateUUID(t),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
@@ -7,7 +7,7 @@ import (
 	migrate "github.com/rubenv/sql-migrate"
 )
 
-// Migration of dashboards table.
+// Migration of the UI tables.
 func Migration() *migrate.MemoryMigrationSource {
 	return &migrate.MemoryMigrationSource{
 		Migrations: []*migrate.Migration{
@@ -31,6 +31,158 @@ func Migration() *migrate.MemoryMigrationSource {
 					`DROP TABLE IF EXISTS dashboards`,
 				},
 			},
+			{
+				Id: "personal_tokens_01",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS personal_tokens (
+						id VARCHAR(36) NOT NULL CHECK (id <> ''),
+						user_id VARCHAR(36) NOT NULL CHECK (user_id <> ''),
+						domain_id VARCHAR(36),
+						name VARCHAR(1024) NOT NULL CHECK (name <> ''),
+						token_hash VARCHAR(64) NOT NULL,
+						scopes JSONB,
+						refresh_token TEXT,
+						expires_at TIMESTAMP,
+						last_used_at TIMESTAMP,
+						refreshed_at TIMESTAMP,
+						revoked BOOLEAN NOT NULL DEFAULT FALSE,
+						created_at TIMESTAMP,
+						UNIQUE (token_hash),
+						PRIMARY KEY (id)
+					);`,
+					`CREATE INDEX IF NOT EXISTS personal_tokens_user_id_idx ON personal_tokens (user_id);`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS personal_tokens`,
+				},
+			},
+			{
+				Id: "two_factor_01",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS two_factor (
+						user_id VARCHAR(36) NOT NULL CHECK (user_id <> ''),
+						secret TEXT NOT NULL,
+						enabled BOOLEAN NOT NULL DEFAULT FALSE,
+						recovery_codes JSONB,
+						last_step BIGINT NOT NULL DEFAULT 0,
+						created_at TIMESTAMP,
+						updated_at TIMESTAMP,
+						PRIMARY KEY (user_id)
+					);`,
+					`CREATE TABLE IF NOT EXISTS domain_two_factor (
+						domain_id VARCHAR(36) NOT NULL CHECK (domain_id <> ''),
+						required BOOLEAN NOT NULL DEFAULT FALSE,
+						updated_at TIMESTAMP,
+						PRIMARY KEY (domain_id)
+					);`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS two_factor`,
+					`DROP TABLE IF EXISTS domain_two_factor`,
+				},
+			},
+			{
+				Id: "terminal_commands_01",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS terminal_commands (
+						id VARCHAR(36) NOT NULL CHECK (id <> ''),
+						user_id VARCHAR(36) NOT NULL CHECK (user_id <> ''),
+						user_identity VARCHAR(254),
+						domain_id VARCHAR(36) NOT NULL CHECK (domain_id <> ''),
+						thing_id VARCHAR(36) NOT NULL CHECK (thing_id <> ''),
+						command TEXT NOT NULL,
+						response TEXT,
+						error TEXT,
+						duration BIGINT NOT NULL DEFAULT 0,
+						created_at TIMESTAMP,
+						PRIMARY KEY (id)
+					);`,
+					`CREATE INDEX IF NOT EXISTS terminal_commands_domain_id_idx ON terminal_commands (domain_id, created_at);`,
+					`CREATE INDEX IF NOT EXISTS terminal_commands_thing_id_idx ON terminal_commands (thing_id);`,
+					`CREATE INDEX IF NOT EXISTS terminal_commands_user_id_idx ON terminal_commands (user_id);`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS terminal_commands`,
+				},
+			},
+			{
+				Id: "terminal_policies_01",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS terminal_policies (
+						domain_id VARCHAR(36) NOT NULL CHECK (domain_id <> ''),
+						admin_only BOOLEAN NOT NULL DEFAULT FALSE,
+						read_only BOOLEAN NOT NULL DEFAULT FALSE,
+						allow JSONB,
+						deny JSONB,
+						updated_at TIMESTAMP,
+						PRIMARY KEY (domain_id)
+					);`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS terminal_policies`,
+				},
+			},
+			{
+				Id: "bootstrap_templates_01",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS bootstrap_templates (
+						id VARCHAR(36) PRIMARY KEY,
+						domain_id VARCHAR(36) NOT NULL CHECK (domain_id <> ''),
+						name VARCHAR(254) NOT NULL CHECK (name <> ''),
+						description VARCHAR(1024),
+						content TEXT NOT NULL,
+						channels JSONB,
+						created_by VARCHAR(36),
+						created_at TIMESTAMP,
+						updated_at TIMESTAMP,
+						UNIQUE (domain_id, name)
+					);`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS bootstrap_templates`,
+				},
+			},
+			{
+				Id: "secret_rotations_01",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS rotation_schedules (
+						id VARCHAR(36) PRIMARY KEY,
+						domain_id VARCHAR(36) NOT NULL CHECK (domain_id <> ''),
+						user_id VARCHAR(36) NOT NULL CHECK (user_id <> ''),
+						tag VARCHAR(254) NOT NULL CHECK (tag <> ''),
+						rotation_interval BIGINT NOT NULL CHECK (rotation_interval > 0),
+						refresh_token TEXT NOT NULL,
+						last_run_at TIMESTAMP,
+						next_run_at TIMESTAMP NOT NULL,
+						last_error TEXT,
+						claimed_until TIMESTAMP,
+						token_refreshed_at TIMESTAMP,
+						expired BOOLEAN NOT NULL DEFAULT FALSE,
+						created_at TIMESTAMP
+					);`,
+					`CREATE INDEX IF NOT EXISTS rotation_schedules_next_run_at_idx ON rotation_schedules (next_run_at);`,
+					`CREATE TABLE IF NOT EXISTS secret_rotations (
+						id VARCHAR(36) PRIMARY KEY,
+						run_id VARCHAR(36) NOT NULL CHECK (run_id <> ''),
+						domain_id VARCHAR(36) NOT NULL CHECK (domain_id <> ''),
+						thing_id VARCHAR(36) NOT NULL CHECK (thing_id <> ''),
+						thing_name VARCHAR(1024),
+						user_id VARCHAR(36),
+						schedule_id VARCHAR(36),
+						trigger VARCHAR(16) NOT NULL,
+						status VARCHAR(16) NOT NULL,
+						error TEXT,
+						secret TEXT,
+						created_at TIMESTAMP
+					);`,
+					`CREATE INDEX IF NOT EXISTS secret_rotations_domain_id_idx ON secret_rotations (domain_id, created_at);`,
+					`CREATE INDEX IF NOT EXISTS secret_rotations_run_id_idx ON secret_rotations (run_id);`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS secret_rotations`,
+					`DROP TABLE IF EXISTS rotation_schedules`,
+				},
+			},
 		},
 	}
 }
@@ -0,0 +1,114 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/jmoiron/sqlx"
+)
+
+type policyRepo struct {
+	db *sqlx.DB
+}
+
+func NewTerminalPolicyRepository(db *sqlx.DB) ui.TerminalPolicyRepository {
+	return &policyRepo{db: db}
+}
+
+// Save the terminal policy of a domain.
+func (r *policyRepo) Save(ctx context.Context, policy ui.TerminalPolicy) error {
+	q := `INSERT INTO terminal_policies (domain_id, admin_only, read_only, allow, deny, updated_at)
+	VALUES (:domain_id, :admin_only, :read_only, :allow, :deny, :updated_at)
+	ON CONFLICT (domain_id) DO UPDATE SET admin_only = :admin_only, read_only = :read_only,
+	allow = :allow, deny = :deny, updated_at = :updated_at`
+
+	dbPolicy, err := toDBTerminalPolicy(policy)
+	if err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+	if _, err := r.db.NamedExecContext(ctx, q, dbPolicy); err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+
+	return nil
+}
+
+// Retrieve the terminal policy of a domain using a domain id. A policy that
+// permits every command is returned if the domain has none.
+func (r *policyRepo) Retrieve(ctx context.Context, domainID string) (ui.TerminalPolicy, error) {
+	q := `SELECT domain_id, admin_only, read_only, allow, deny, updated_at FROM terminal_policies WHERE domain_id = :domain_id`
+
+	rows, err := r.db.NamedQueryContext(ctx, q, dbTerminalPolicy{DomainID: domainID})
+	if err != nil {
+		return ui.TerminalPolicy{}, HandleError(err, ErrViewEntity)
+	}
+	defer rows.Close()
+
+	dbPolicy := dbTerminalPolicy{}
+	if rows.Next() {
+		if err = rows.StructScan(&dbPolicy); err != nil {
+			return ui.TerminalPolicy{}, HandleError(err, ErrViewEntity)
+		}
+		return toTerminalPolicy(dbPolicy)
+	}
+
+	return ui.TerminalPolicy{DomainID: domainID}, nil
+}
+
+type dbTerminalPolicy struct {
+	DomainID  string    `db:"domain_id"`
+	AdminOnly bool      `db:"admin_only"`
+	ReadOnly  bool      `db:"read_only"`
+	Allow     []byte    `db:"allow"`
+	Deny      []byte    `db:"deny"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func toDBTerminalPolicy(policy ui.TerminalPolicy) (dbTerminalPolicy, error) {
+	allow, err := json.Marshal(policy.Allow)
+	if err != nil {
+		return dbTerminalPolicy{}, errors.Wrap(ErrJSONMarshal, err)
+	}
+	deny, err := json.Marshal(policy.Deny)
+	if err != nil {
+		return dbTerminalPolicy{}, errors.Wrap(ErrJSONMarshal, err)
+	}
+
+	return dbTerminalPolicy{
+		DomainID:  policy.DomainID,
+		AdminOnly: policy.AdminOnly,
+		ReadOnly:  policy.ReadOnly,
+		Allow:     allow,
+		Deny:      deny,
+		UpdatedAt: policy.UpdatedAt,
+	}, nil
+}
+
+func toTerminalPolicy(dbPolicy dbTerminalPolicy) (ui.TerminalPolicy, error) {
+	var allow, deny []string
+	if dbPolicy.Allow != nil {
+		if err := json.Unmarshal(dbPolicy.Allow, &allow); err != nil {
+			return ui.TerminalPolicy{}, errors.Wrap(ErrJSONUnmarshal, err)
+		}
+	}
+	if dbPolicy.Deny != nil {
+		if err := json.Unmarshal(dbPolicy.Deny, &deny); err != nil {
+			return ui.TerminalPolicy{}, errors.Wrap(ErrJSONUnmarshal, err)
+		}
+	}
+
+	return ui.TerminalPolicy{
+		DomainID:  dbPolicy.DomainID,
+		AdminOnly: dbPolicy.AdminOnly,
+		ReadOnly:  dbPolicy.ReadOnly,
+		Allow:     allow,
+		Deny:      deny,
+		UpdatedAt: dbPolicy.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala-ui/postgres"
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerminalPolicy(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM terminal_policies")
+		require.Nil(t, err, fmt.Sprintf("clean terminal policies unexpected error: %s", err))
+	})
+
+	domainID := generateUUID(t)
+	unknown := generateUUID(t)
+
+	cases := []struct {
+		desc   string
+		policy ui.TerminalPolicy
+		save   bool
+		err    error
+	}{
+		{
+			desc:   "retrieve policy of domain without one",
+			policy: ui.TerminalPolicy{DomainID: unknown},
+			err:    nil,
+		},
+		{
+			desc: "save terminal policy",
+			policy: ui.TerminalPolicy{
+				DomainID:  domainID,
+				AdminOnly: true,
+				Allow:     []string{"uptime", "systemctl restart *"},
+				Deny:      []string{"rm *"},
+				UpdatedAt: time.Now().UTC().Truncate(time.Microsecond),
+			},
+			save: true,
+			err:  nil,
+		},
+		{
+			desc: "replace terminal policy",
+			policy: ui.TerminalPolicy{
+				DomainID:  domainID,
+				ReadOnly:  true,
+				Allow:     []string{},
+				Deny:      []string{},
+				UpdatedAt: time.Now().UTC().Truncate(time.Microsecond),
+			},
+			save: true,
+			err:  nil,
+		},
+		{
+			desc:   "save terminal policy with empty domain id",
+			policy: ui.TerminalPolicy{ReadOnly: true},
+			save:   true,
+			err:    postgres.ErrCreateEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if tc.save {
+				err := policyRepo.Save(context.Background(), tc.policy)
+				assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s to contain: %s", err, tc.err))
+				if err != nil {
+					return
+				}
+			}
+			policy, err := policyRepo.Retrieve(context.Background(), tc.policy.DomainID)
+			require.Nil(t, err, fmt.Sprintf("retrieve terminal policy unexpected error: %s", err))
+			assert.Equal(t, tc.policy, policy)
+		})
+	}
+}
@@ -0,0 +1,323 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/jmoiron/sqlx"
+)
+
+type rotationRepo struct {
+	db *sqlx.DB
+}
+
+func NewSecretRotationRepository(db *sqlx.DB) ui.SecretRotationRepository {
+	return &rotationRepo{db: db}
+}
+
+// Save the rotations of a run.
+func (r *rotationRepo) Save(ctx context.Context, rotations ...ui.SecretRotation) error {
+	if len(rotations) == 0 {
+		return nil
+	}
+	q := `INSERT INTO secret_rotations (id, run_id, domain_id, thing_id, thing_name, user_id, schedule_id, trigger, status, error, secret, created_at)
+	VALUES (:id, :run_id, :domain_id, :thing_id, :thing_name, :user_id, :schedule_id, :trigger, :status, :error, :secret, :created_at)`
+
+	dbRotations := make([]dbSecretRotation, len(rotations))
+	for i, rotation := range rotations {
+		dbRotations[i] = toDBSecretRotation(rotation)
+	}
+	if _, err := r.db.NamedExecContext(ctx, q, dbRotations); err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+
+	return nil
+}
+
+// UpdateRotations updates the outcome of the rotations of a run, in a single
+// transaction.
+func (r *rotationRepo) UpdateRotations(ctx context.Context, rotations ...ui.SecretRotation) error {
+	q := `UPDATE secret_rotations SET status = :status, error = :error, secret = :secret
+	WHERE id = :id AND domain_id = :domain_id`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+	for _, rotation := range rotations {
+		if _, err := tx.NamedExecContext(ctx, q, toDBSecretRotation(rotation)); err != nil {
+			_ = tx.Rollback()
+			return HandleError(err, ErrCreateEntity)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+
+	return nil
+}
+
+// Retrieve the rotations of a domain, most recent first.
+func (r *rotationRepo) RetrieveAll(ctx context.Context, pm ui.SecretRotationPageMeta) (ui.SecretRotationPage, error) {
+	q := `SELECT id, run_id, domain_id, thing_id, thing_name, user_id, schedule_id, trigger, status, error, (status = 'rotated' AND secret IS NOT NULL) AS pending, created_at
+	FROM secret_rotations WHERE domain_id = :domain_id ORDER BY created_at DESC, thing_name LIMIT :limit OFFSET :offset`
+
+	rows, err := r.db.NamedQueryContext(ctx, q, pm)
+	if err != nil {
+		return ui.SecretRotationPage{}, HandleError(err, ErrViewEntity)
+	}
+	defer rows.Close()
+
+	var rotations []ui.SecretRotation
+	for rows.Next() {
+		dbRotation := dbSecretRotation{}
+		if err = rows.StructScan(&dbRotation); err != nil {
+			return ui.SecretRotationPage{}, HandleError(err, ErrViewEntity)
+		}
+		rotations = append(rotations, toSecretRotation(dbRotation))
+	}
+
+	cq := `SELECT COUNT(*) FROM secret_rotations WHERE domain_id = :domain_id`
+	total, err := total(ctx, r.db, cq, pm)
+	if err != nil {
+		return ui.SecretRotationPage{}, HandleError(err, ErrViewEntity)
+	}
+
+	return ui.SecretRotationPage{
+		Total:     total,
+		Offset:    pm.Offset,
+		Limit:     pm.Limit,
+		Rotations: rotations,
+	}, nil
+}
+
+// TakeSecrets retrieves the secrets of the applied rotations of a run and
+// removes them in the same statement, so that concurrent downloads cannot both
+// get them. The secrets of rotations whose outcome was not recorded are never
+// handed out.
+func (r *rotationRepo) TakeSecrets(ctx context.Context, domainID, runID string) ([]ui.SecretRotation, error) {
+	q := `UPDATE secret_rotations r SET secret = NULL
+	FROM (SELECT id, secret FROM secret_rotations WHERE domain_id = :domain_id AND run_id = :run_id AND status = 'rotated' AND secret IS NOT NULL FOR UPDATE) taken
+	WHERE r.id = taken.id
+	RETURNING r.id, r.run_id, r.domain_id, r.thing_id, r.thing_name, r.user_id, r.schedule_id, r.trigger, r.status, r.error, taken.secret, r.created_at`
+
+	rows, err := r.db.NamedQueryContext(ctx, q, dbSecretRotation{DomainID: domainID, RunID: runID})
+	if err != nil {
+		return nil, HandleError(err, ErrViewEntity)
+	}
+	defer rows.Close()
+
+	var rotations []ui.SecretRotation
+	for rows.Next() {
+		dbRotation := dbSecretRotation{}
+		if err = rows.StructScan(&dbRotation); err != nil {
+			return nil, HandleError(err, ErrViewEntity)
+		}
+		rotations = append(rotations, toSecretRotation(dbRotation))
+	}
+
+	return rotations, nil
+}
+
+// SaveSchedule saves a non-existing rotation schedule.
+func (r *rotationRepo) SaveSchedule(ctx context.Context, schedule ui.RotationSchedule) error {
+	q := `INSERT INTO rotation_schedules (id, domain_id, user_id, tag, rotation_interval, refresh_token, token_refreshed_at, expired, last_run_at, next_run_at, last_error, created_at)
+	VALUES (:id, :domain_id, :user_id, :tag, :rotation_interval, :refresh_token, :token_refreshed_at, :expired, :last_run_at, :next_run_at, :last_error, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, q, toDBRotationSchedule(schedule)); err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+
+	return nil
+}
+
+// RetrieveSchedules retrieves the rotation schedules of a domain.
+func (r *rotationRepo) RetrieveSchedules(ctx context.Context, domainID string) ([]ui.RotationSchedule, error) {
+	q := `SELECT id, domain_id, user_id, tag, rotation_interval, refresh_token, token_refreshed_at, expired, last_run_at, next_run_at, last_error, created_at
+	FROM rotation_schedules WHERE domain_id = :domain_id ORDER BY tag, created_at`
+
+	return r.retrieveSchedules(ctx, q, dbRotationSchedule{DomainID: domainID})
+}
+
+// ClaimSchedules claims the rotation schedules of all domains that have not
+// expired, whose next run is not after the given time or whose token was
+// refreshed before refreshBefore, and that are not claimed already. The
+// schedules are locked and claimed in the same statement, skipping the ones
+// locked by another instance, so that no schedule is run twice.
+func (r *rotationRepo) ClaimSchedules(ctx context.Context, now, refreshBefore, until time.Time) ([]ui.RotationSchedule, error) {
+	q := `WITH due AS (
+		SELECT id FROM rotation_schedules
+		WHERE NOT expired AND (next_run_at <= :next_run_at OR token_refreshed_at <= :token_refreshed_at)
+		AND (claimed_until IS NULL OR claimed_until <= :next_run_at)
+		FOR UPDATE SKIP LOCKED
+	)
+	UPDATE rotation_schedules s SET claimed_until = :claimed_until FROM due WHERE s.id = due.id
+	RETURNING s.id, s.domain_id, s.user_id, s.tag, s.rotation_interval, s.refresh_token, s.token_refreshed_at, s.expired,
+	s.last_run_at, s.next_run_at, s.last_error, s.created_at`
+
+	params := dbRotationSchedule{
+		NextRunAt:        now,
+		TokenRefreshedAt: sql.NullTime{Time: refreshBefore, Valid: true},
+		ClaimedUntil:     sql.NullTime{Time: until, Valid: true},
+	}
+
+	return r.retrieveSchedules(ctx, q, params)
+}
+
+// UpdateSchedule updates the refresh token and the runs of a rotation
+// schedule of its domain, and releases its claim.
+func (r *rotationRepo) UpdateSchedule(ctx context.Context, schedule ui.RotationSchedule) error {
+	q := `UPDATE rotation_schedules SET refresh_token = :refresh_token, token_refreshed_at = :token_refreshed_at,
+	expired = :expired, last_run_at = :last_run_at, next_run_at = :next_run_at, last_error = :last_error, claimed_until = NULL
+	WHERE id = :id AND domain_id = :domain_id`
+
+	res, err := r.db.NamedExecContext(ctx, q, toDBRotationSchedule(schedule))
+	if err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// RemoveSchedule removes a rotation schedule of a domain.
+func (r *rotationRepo) RemoveSchedule(ctx context.Context, domainID, id string) error {
+	q := `DELETE FROM rotation_schedules WHERE id = :id AND domain_id = :domain_id`
+
+	res, err := r.db.NamedExecContext(ctx, q, dbRotationSchedule{ID: id, DomainID: domainID})
+	if err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *rotationRepo) retrieveSchedules(ctx context.Context, q string, params dbRotationSchedule) ([]ui.RotationSchedule, error) {
+	rows, err := r.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, HandleError(err, ErrViewEntity)
+	}
+	defer rows.Close()
+
+	var schedules []ui.RotationSchedule
+	for rows.Next() {
+		dbSchedule := dbRotationSchedule{}
+		if err = rows.StructScan(&dbSchedule); err != nil {
+			return nil, HandleError(err, ErrViewEntity)
+		}
+		schedules = append(schedules, toRotationSchedule(dbSchedule))
+	}
+
+	return schedules, nil
+}
+
+type dbSecretRotation struct {
+	ID         string         `db:"id"`
+	RunID      string         `db:"run_id"`
+	DomainID   string         `db:"domain_id"`
+	ThingID    string         `db:"thing_id"`
+	ThingName  string         `db:"thing_name"`
+	UserID     string         `db:"user_id"`
+	ScheduleID string         `db:"schedule_id"`
+	Trigger    string         `db:"trigger"`
+	Status     string         `db:"status"`
+	Error      string         `db:"error"`
+	Secret     sql.NullString `db:"secret"`
+	Pending    bool           `db:"pending"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+func toDBSecretRotation(rotation ui.SecretRotation) dbSecretRotation {
+	return dbSecretRotation{
+		ID:         rotation.ID,
+		RunID:      rotation.RunID,
+		DomainID:   rotation.DomainID,
+		ThingID:    rotation.ThingID,
+		ThingName:  rotation.ThingName,
+		UserID:     rotation.UserID,
+		ScheduleID: rotation.ScheduleID,
+		Trigger:    rotation.Trigger,
+		Status:     rotation.Status,
+		Error:      rotation.Error,
+		Secret:     sql.NullString{String: rotation.Secret, Valid: rotation.Secret != ""},
+		CreatedAt:  rotation.CreatedAt,
+	}
+}
+
+func toSecretRotation(dbRotation dbSecretRotation) ui.SecretRotation {
+	return ui.SecretRotation{
+		ID:         dbRotation.ID,
+		RunID:      dbRotation.RunID,
+		DomainID:   dbRotation.DomainID,
+		ThingID:    dbRotation.ThingID,
+		ThingName:  dbRotation.ThingName,
+		UserID:     dbRotation.UserID,
+		ScheduleID: dbRotation.ScheduleID,
+		Trigger:    dbRotation.Trigger,
+		Status:     dbRotation.Status,
+		Error:      dbRotation.Error,
+		Secret:     dbRotation.Secret.String,
+		Pending:    dbRotation.Pending,
+		CreatedAt:  dbRotation.CreatedAt,
+	}
+}
+
+type dbRotationSchedule struct {
+	ID               string       `db:"id"`
+	DomainID         string       `db:"domain_id"`
+	UserID           string       `db:"user_id"`
+	Tag              string       `db:"tag"`
+	Interval         int64        `db:"rotation_interval"`
+	RefreshToken     string       `db:"refresh_token"`
+	TokenRefreshedAt sql.NullTime `db:"token_refreshed_at"`
+	Expired          bool         `db:"expired"`
+	LastRunAt        sql.NullTime `db:"last_run_at"`
+	NextRunAt        time.Time    `db:"next_run_at"`
+	LastError        string       `db:"last_error"`
+	CreatedAt        time.Time    `db:"created_at"`
+	ClaimedUntil     sql.NullTime `db:"claimed_until"`
+}
+
+func toDBRotationSchedule(schedule ui.RotationSchedule) dbRotationSchedule {
+	return dbRotationSchedule{
+		ID:               schedule.ID,
+		DomainID:         schedule.DomainID,
+		UserID:           schedule.UserID,
+		Tag:              schedule.Tag,
+		Interval:         int64(schedule.Interval),
+		RefreshToken:     schedule.RefreshToken,
+		TokenRefreshedAt: sql.NullTime{Time: schedule.TokenRefreshedAt, Valid: !schedule.TokenRefreshedAt.IsZero()},
+		Expired:          schedule.Expired,
+		LastRunAt:        sql.NullTime{Time: schedule.LastRunAt, Valid: !schedule.LastRunAt.IsZero()},
+		NextRunAt:        schedule.NextRunAt,
+		LastError:        schedule.LastError,
+		CreatedAt:        schedule.CreatedAt,
+	}
+}
+
+func toRotationSchedule(dbSchedule dbRotationSchedule) ui.RotationSchedule {
+	return ui.RotationSchedule{
+		ID:               dbSchedule.ID,
+		DomainID:         dbSchedule.DomainID,
+		UserID:           dbSchedule.UserID,
+		Tag:              dbSchedule.Tag,
+		Interval:         time.Duration(dbSchedule.Interval),
+		RefreshToken:     dbSchedule.RefreshToken,
+		TokenRefreshedAt: dbSchedule.TokenRefreshedAt.Time,
+		Expired:          dbSchedule.Expired,
+		LastRunAt:        dbSchedule.LastRunAt.Time,
+		NextRunAt:        dbSchedule.NextRunAt,
+		LastError:        dbSchedule.LastError,
+		CreatedAt:        dbSchedule.CreatedAt,
+	}
+}
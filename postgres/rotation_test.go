@@ -0,0 +1,183 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala-ui/postgres"
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretRotations(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM secret_rotations")
+		require.Nil(t, err, fmt.Sprintf("clean secret rotations unexpected error: %s", err))
+	})
+
+	domainID, runID := generateUUID(t), generateUUID(t)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	var rotations []ui.SecretRotation
+	for i := 0; i < 4; i++ {
+		rotation := ui.SecretRotation{
+			ID:        generateUUID(t),
+			RunID:     runID,
+			DomainID:  domainID,
+			ThingID:   generateUUID(t),
+			ThingName: fmt.Sprintf("thing-%d", i),
+			UserID:    generateUUID(t),
+			Trigger:   ui.RotationScheduled,
+			Status:    ui.RotationStarted,
+			Secret:    fmt.Sprintf("secret-%d", i),
+			CreatedAt: now,
+		}
+		rotations = append(rotations, rotation)
+	}
+
+	err := rotationRepo.Save(context.Background(), rotations...)
+	require.Nil(t, err, fmt.Sprintf("save secret rotations unexpected error: %s", err))
+
+	// The outcome of the last rotation is never recorded, so its secret must
+	// not be handed out.
+	for i := range rotations[:3] {
+		rotations[i].Status = ui.RotationRotated
+		if i == 2 {
+			rotations[i].Status, rotations[i].Error, rotations[i].Secret = ui.RotationFailed, "failed", ""
+		}
+	}
+	err = rotationRepo.UpdateRotations(context.Background(), rotations[:3]...)
+	require.Nil(t, err, fmt.Sprintf("update secret rotations unexpected error: %s", err))
+
+	err = rotationRepo.Save(context.Background(), ui.SecretRotation{ID: generateUUID(t), RunID: runID, ThingID: generateUUID(t)})
+	assert.True(t, errors.Contains(err, postgres.ErrCreateEntity), fmt.Sprintf("expected error: %s to contain: %s", err, postgres.ErrCreateEntity))
+
+	page, err := rotationRepo.RetrieveAll(context.Background(), ui.SecretRotationPageMeta{DomainID: domainID, Limit: 10})
+	require.Nil(t, err, fmt.Sprintf("retrieve secret rotations unexpected error: %s", err))
+	assert.Equal(t, uint64(4), page.Total)
+	for i, rotation := range page.Rotations {
+		assert.Equal(t, rotations[i].ThingID, rotation.ThingID)
+		assert.Equal(t, rotations[i].Status, rotation.Status)
+		assert.Empty(t, rotation.Secret)
+		assert.Equal(t, rotations[i].Status == ui.RotationRotated, rotation.Pending)
+	}
+
+	cases := []struct {
+		desc     string
+		domainID string
+		runID    string
+		secrets  []string
+	}{
+		{
+			desc:     "take secrets of another domain",
+			domainID: generateUUID(t),
+			runID:    runID,
+		},
+		{
+			desc:     "take secrets of a run",
+			domainID: domainID,
+			runID:    runID,
+			secrets:  []string{"secret-0", "secret-1"},
+		},
+		{
+			desc:     "take secrets of a run again",
+			domainID: domainID,
+			runID:    runID,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			taken, err := rotationRepo.TakeSecrets(context.Background(), tc.domainID, tc.runID)
+			require.Nil(t, err, fmt.Sprintf("take secrets unexpected error: %s", err))
+			var secrets []string
+			for _, rotation := range taken {
+				secrets = append(secrets, rotation.Secret)
+			}
+			assert.ElementsMatch(t, tc.secrets, secrets)
+		})
+	}
+}
+
+func TestRotationSchedules(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM rotation_schedules")
+		require.Nil(t, err, fmt.Sprintf("clean rotation schedules unexpected error: %s", err))
+	})
+
+	domainID := generateUUID(t)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	due := ui.RotationSchedule{
+		ID:               generateUUID(t),
+		DomainID:         domainID,
+		UserID:           generateUUID(t),
+		Tag:              "gateways",
+		Interval:         24 * time.Hour,
+		RefreshToken:     "refresh-token",
+		TokenRefreshedAt: now,
+		NextRunAt:        now.Add(-time.Minute),
+		CreatedAt:        now,
+	}
+	later := due
+	later.ID, later.Tag, later.NextRunAt = generateUUID(t), "sensors", now.Add(time.Hour)
+	stale := due
+	stale.ID, stale.Tag, stale.NextRunAt, stale.TokenRefreshedAt = generateUUID(t), "meters", now.Add(48*time.Hour), now.Add(-7*time.Hour)
+	expired := due
+	expired.ID, expired.Tag, expired.Expired = generateUUID(t), "valves", true
+
+	for _, schedule := range []ui.RotationSchedule{due, later, stale, expired} {
+		err := rotationRepo.SaveSchedule(context.Background(), schedule)
+		require.Nil(t, err, fmt.Sprintf("save rotation schedule unexpected error: %s", err))
+	}
+	err := rotationRepo.SaveSchedule(context.Background(), ui.RotationSchedule{ID: generateUUID(t), UserID: due.UserID, Tag: "tag", Interval: time.Hour})
+	assert.True(t, errors.Contains(err, postgres.ErrCreateEntity), fmt.Sprintf("expected error: %s to contain: %s", err, postgres.ErrCreateEntity))
+
+	schedules, err := rotationRepo.RetrieveSchedules(context.Background(), domainID)
+	require.Nil(t, err, fmt.Sprintf("retrieve rotation schedules unexpected error: %s", err))
+	assert.Equal(t, []ui.RotationSchedule{due, stale, later, expired}, schedules)
+
+	refreshBefore := now.Add(-6 * time.Hour)
+	schedules, err = rotationRepo.ClaimSchedules(context.Background(), now, refreshBefore, now.Add(time.Hour))
+	require.Nil(t, err, fmt.Sprintf("claim rotation schedules unexpected error: %s", err))
+	assert.ElementsMatch(t, []ui.RotationSchedule{due, stale}, schedules, "expected due and stale schedules to be claimed")
+	schedules, err = rotationRepo.ClaimSchedules(context.Background(), now, refreshBefore, now.Add(time.Hour))
+	require.Nil(t, err, fmt.Sprintf("claim rotation schedules unexpected error: %s", err))
+	assert.Empty(t, schedules, "expected claimed schedules not to be claimed again")
+	schedules, err = rotationRepo.ClaimSchedules(context.Background(), now.Add(time.Hour), refreshBefore, now.Add(2*time.Hour))
+	require.Nil(t, err, fmt.Sprintf("claim rotation schedules unexpected error: %s", err))
+	assert.ElementsMatch(t, []ui.RotationSchedule{due, later, stale}, schedules, "expected expired claims to be claimed again")
+
+	err = rotationRepo.UpdateSchedule(context.Background(), ui.RotationSchedule{ID: due.ID, DomainID: generateUUID(t), NextRunAt: now})
+	assert.True(t, errors.Contains(err, postgres.ErrNotFound), fmt.Sprintf("expected error: %s to contain: %s", err, postgres.ErrNotFound))
+
+	due.RefreshToken, due.LastRunAt, due.NextRunAt, due.LastError = "rotated-token", now, now.Add(due.Interval), "failure"
+	err = rotationRepo.UpdateSchedule(context.Background(), due)
+	require.Nil(t, err, fmt.Sprintf("update rotation schedule unexpected error: %s", err))
+	schedules, err = rotationRepo.ClaimSchedules(context.Background(), now.Add(2*time.Hour), refreshBefore, now.Add(3*time.Hour))
+	require.Nil(t, err, fmt.Sprintf("claim rotation schedules unexpected error: %s", err))
+	assert.ElementsMatch(t, []ui.RotationSchedule{later, stale}, schedules, "expected updated schedule not to be due")
+
+	stale.TokenRefreshedAt, stale.Expired = now, true
+	err = rotationRepo.UpdateSchedule(context.Background(), stale)
+	require.Nil(t, err, fmt.Sprintf("update rotation schedule unexpected error: %s", err))
+	schedules, err = rotationRepo.ClaimSchedules(context.Background(), now.Add(3*time.Hour), refreshBefore, now.Add(4*time.Hour))
+	require.Nil(t, err, fmt.Sprintf("claim rotation schedules unexpected error: %s", err))
+	assert.Equal(t, []ui.RotationSchedule{later}, schedules, "expected expired schedule not to be claimed")
+
+	err = rotationRepo.UpdateSchedule(context.Background(), ui.RotationSchedule{ID: generateUUID(t), NextRunAt: now})
+	assert.True(t, errors.Contains(err, postgres.ErrNotFound), fmt.Sprintf("expected error: %s to contain: %s", err, postgres.ErrNotFound))
+
+	err = rotationRepo.RemoveSchedule(context.Background(), generateUUID(t), due.ID)
+	assert.True(t, errors.Contains(err, postgres.ErrNotFound), fmt.Sprintf("expected error: %s to contain: %s", err, postgres.ErrNotFound))
+	err = rotationRepo.RemoveSchedule(context.Background(), domainID, due.ID)
+	require.Nil(t, err, fmt.Sprintf("remove rotation schedule unexpected error: %s", err))
+	schedules, err = rotationRepo.RetrieveSchedules(context.Background(), domainID)
+	require.Nil(t, err, fmt.Sprintf("retrieve rotation schedules unexpected error: %s", err))
+	assert.Equal(t, []ui.RotationSchedule{stale, later, expired}, schedules)
+}
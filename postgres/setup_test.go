@@ -20,8 +20,14 @@ import (
 )
 
 var (
-	db   *sqlx.DB
-	repo ui.DashboardRepository
+	db            *sqlx.DB
+	repo          ui.DashboardRepository
+	tokenRepo     ui.PersonalTokenRepository
+	twoFactorRepo ui.TwoFactorRepository
+	auditRepo     ui.TerminalAuditRepository
+	policyRepo    ui.TerminalPolicyRepository
+	templateRepo  ui.BootstrapTemplateRepository
+	rotationRepo  ui.SecretRotationRepository
 )
 
 func TestMain(m *testing.M) {
@@ -78,6 +84,12 @@ func TestMain(m *testing.M) {
 	}
 
 	repo = dpostgres.NewRepository(db)
+	tokenRepo = dpostgres.NewTokenRepository(db)
+	twoFactorRepo = dpostgres.NewTwoFactorRepository(db)
+	auditRepo = dpostgres.NewTerminalAuditRepository(db)
+	policyRepo = dpostgres.NewTerminalPolicyRepository(db)
+	templateRepo = dpostgres.NewBootstrapTemplateRepository(db)
+	rotationRepo = dpostgres.NewSecretRotationRepository(db)
 
 	code := m.Run()
 
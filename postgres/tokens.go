@@ -0,0 +1,255 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/jmoiron/sqlx"
+)
+
+type tokenRepo struct {
+	db *sqlx.DB
+}
+
+func NewTokenRepository(db *sqlx.DB) ui.PersonalTokenRepository {
+	return &tokenRepo{db: db}
+}
+
+// Create a personal access token for a user.
+func (r *tokenRepo) Create(ctx context.Context, pt ui.PersonalToken) (ui.PersonalToken, error) {
+	q := `INSERT INTO personal_tokens (id, user_id, domain_id, name, token_hash, scopes, refresh_token, expires_at, refreshed_at, revoked, created_at)
+	VALUES (:id, :user_id, :domain_id, :name, :token_hash, :scopes, :refresh_token, :expires_at, :refreshed_at, :revoked, :created_at)
+	RETURNING id, user_id, domain_id, name, token_hash, scopes, refresh_token, expires_at, last_used_at, refreshed_at, revoked, created_at`
+
+	dbPt, err := toDBPersonalToken(pt)
+	if err != nil {
+		return ui.PersonalToken{}, HandleError(err, ErrCreateEntity)
+	}
+	row, err := r.db.NamedQueryContext(ctx, q, dbPt)
+	if err != nil {
+		return ui.PersonalToken{}, HandleError(err, ErrCreateEntity)
+	}
+	defer row.Close()
+	row.Next()
+	dbPt = dbPersonalToken{}
+	if err = row.StructScan(&dbPt); err != nil {
+		return ui.PersonalToken{}, HandleError(err, ErrCreateEntity)
+	}
+
+	return toPersonalToken(dbPt)
+}
+
+// Retrieve a personal access token using the hash of its value.
+func (r *tokenRepo) RetrieveByHash(ctx context.Context, hash string) (ui.PersonalToken, error) {
+	q := `SELECT id, user_id, domain_id, name, token_hash, scopes, refresh_token, expires_at, last_used_at, refreshed_at, revoked, created_at
+	FROM personal_tokens WHERE token_hash = :token_hash`
+
+	rows, err := r.db.NamedQueryContext(ctx, q, dbPersonalToken{Hash: hash})
+	if err != nil {
+		return ui.PersonalToken{}, HandleError(err, ErrViewEntity)
+	}
+	defer rows.Close()
+
+	dbPt := dbPersonalToken{}
+	if rows.Next() {
+		if err = rows.StructScan(&dbPt); err != nil {
+			return ui.PersonalToken{}, HandleError(err, ErrViewEntity)
+		}
+		return toPersonalToken(dbPt)
+	}
+
+	return ui.PersonalToken{}, ErrNotFound
+}
+
+// Retrieve all personal access tokens for a user using a user id.
+func (r *tokenRepo) RetrieveAll(ctx context.Context, page ui.PersonalTokenPageMeta) (ui.PersonalTokenPage, error) {
+	q := `SELECT id, user_id, domain_id, name, scopes, expires_at, last_used_at, revoked, created_at FROM personal_tokens
+	WHERE user_id = :user_id ORDER BY created_at DESC LIMIT :limit OFFSET :offset`
+
+	rows, err := r.db.NamedQueryContext(ctx, q, page)
+	if err != nil {
+		return ui.PersonalTokenPage{}, HandleError(err, ErrViewEntity)
+	}
+	defer rows.Close()
+
+	var tokens []ui.PersonalToken
+	for rows.Next() {
+		dbPt := dbPersonalToken{}
+		if err = rows.StructScan(&dbPt); err != nil {
+			return ui.PersonalTokenPage{}, HandleError(err, ErrViewEntity)
+		}
+		pt, err := toPersonalToken(dbPt)
+		if err != nil {
+			return ui.PersonalTokenPage{}, HandleError(err, ErrViewEntity)
+		}
+		tokens = append(tokens, pt)
+	}
+	cq := `SELECT COUNT(*) FROM personal_tokens WHERE user_id = $1`
+	var total uint64
+	if err := r.db.GetContext(ctx, &total, cq, page.UserID); err != nil {
+		return ui.PersonalTokenPage{}, HandleError(err, ErrViewEntity)
+	}
+
+	return ui.PersonalTokenPage{
+		Total:  total,
+		Offset: page.Offset,
+		Limit:  page.Limit,
+		Tokens: tokens,
+	}, nil
+}
+
+// Retrieve the active personal access tokens whose refresh token was renewed
+// before the given time.
+func (r *tokenRepo) RetrieveStale(ctx context.Context, refreshedBefore time.Time) ([]ui.PersonalToken, error) {
+	q := `SELECT id, user_id, domain_id, name, token_hash, scopes, refresh_token, expires_at, last_used_at, refreshed_at, revoked, created_at
+	FROM personal_tokens WHERE revoked = FALSE AND (expires_at IS NULL OR expires_at > $2)
+	AND COALESCE(refreshed_at, created_at) < $1 ORDER BY COALESCE(refreshed_at, created_at)`
+
+	rows, err := r.db.QueryxContext(ctx, q, refreshedBefore, time.Now().UTC())
+	if err != nil {
+		return nil, HandleError(err, ErrViewEntity)
+	}
+	defer rows.Close()
+
+	var tokens []ui.PersonalToken
+	for rows.Next() {
+		dbPt := dbPersonalToken{}
+		if err = rows.StructScan(&dbPt); err != nil {
+			return nil, HandleError(err, ErrViewEntity)
+		}
+		pt, err := toPersonalToken(dbPt)
+		if err != nil {
+			return nil, HandleError(err, ErrViewEntity)
+		}
+		tokens = append(tokens, pt)
+	}
+
+	return tokens, nil
+}
+
+// Update the refresh token backing a personal access token if it is still
+// the one that was used to refresh.
+func (r *tokenRepo) UpdateRefreshToken(ctx context.Context, id, current, refreshToken string, refreshedAt time.Time) (bool, error) {
+	q := `UPDATE personal_tokens SET refresh_token = :refresh_token, refreshed_at = :refreshed_at
+	WHERE id = :id AND refresh_token = :current AND revoked = FALSE`
+
+	params := struct {
+		dbPersonalToken
+		Current string `db:"current"`
+	}{
+		dbPersonalToken: dbPersonalToken{
+			ID:           id,
+			RefreshToken: refreshToken,
+			RefreshedAt:  sql.NullTime{Time: refreshedAt, Valid: !refreshedAt.IsZero()},
+		},
+		Current: current,
+	}
+	res, err := r.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		return false, HandleError(err, ErrCreateEntity)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, HandleError(err, ErrCreateEntity)
+	}
+
+	return rows > 0, nil
+}
+
+// Update the last time a personal access token was used.
+func (r *tokenRepo) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	q := `UPDATE personal_tokens SET last_used_at = :last_used_at WHERE id = :id`
+
+	dbPt := dbPersonalToken{ID: id, LastUsedAt: sql.NullTime{Time: lastUsedAt, Valid: !lastUsedAt.IsZero()}}
+	res, err := r.db.NamedExecContext(ctx, q, dbPt)
+	if err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Revoke a personal access token for a user.
+func (r *tokenRepo) Revoke(ctx context.Context, id, userID string) error {
+	q := `UPDATE personal_tokens SET revoked = TRUE, refresh_token = '' WHERE id = :id AND user_id = :user_id`
+
+	res, err := r.db.NamedExecContext(ctx, q, dbPersonalToken{ID: id, UserID: userID})
+	if err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type dbPersonalToken struct {
+	ID           string       `db:"id"`
+	UserID       string       `db:"user_id"`
+	DomainID     string       `db:"domain_id"`
+	Name         string       `db:"name"`
+	Hash         string       `db:"token_hash"`
+	Scopes       []byte       `db:"scopes"`
+	RefreshToken string       `db:"refresh_token"`
+	ExpiresAt    sql.NullTime `db:"expires_at"`
+	LastUsedAt   sql.NullTime `db:"last_used_at"`
+	RefreshedAt  sql.NullTime `db:"refreshed_at"`
+	Revoked      bool         `db:"revoked"`
+	CreatedAt    time.Time    `db:"created_at"`
+}
+
+func toDBPersonalToken(pt ui.PersonalToken) (dbPersonalToken, error) {
+	scopes, err := json.Marshal(pt.Scopes)
+	if err != nil {
+		return dbPersonalToken{}, errors.Wrap(ErrJSONMarshal, err)
+	}
+
+	return dbPersonalToken{
+		ID:           pt.ID,
+		UserID:       pt.UserID,
+		DomainID:     pt.DomainID,
+		Name:         pt.Name,
+		Hash:         pt.Hash,
+		Scopes:       scopes,
+		RefreshToken: pt.RefreshToken,
+		ExpiresAt:    sql.NullTime{Time: pt.ExpiresAt, Valid: !pt.ExpiresAt.IsZero()},
+		LastUsedAt:   sql.NullTime{Time: pt.LastUsedAt, Valid: !pt.LastUsedAt.IsZero()},
+		RefreshedAt:  sql.NullTime{Time: pt.RefreshedAt, Valid: !pt.RefreshedAt.IsZero()},
+		Revoked:      pt.Revoked,
+		CreatedAt:    pt.CreatedAt,
+	}, nil
+}
+
+func toPersonalToken(dbPt dbPersonalToken) (ui.PersonalToken, error) {
+	var scopes []ui.TokenScope
+	if dbPt.Scopes != nil {
+		if err := json.Unmarshal(dbPt.Scopes, &scopes); err != nil {
+			return ui.PersonalToken{}, errors.Wrap(ErrJSONUnmarshal, err)
+		}
+	}
+
+	return ui.PersonalToken{
+		ID:           dbPt.ID,
+		UserID:       dbPt.UserID,
+		DomainID:     dbPt.DomainID,
+		Name:         dbPt.Name,
+		Hash:         dbPt.Hash,
+		Scopes:       scopes,
+		RefreshToken: dbPt.RefreshToken,
+		ExpiresAt:    dbPt.ExpiresAt.Time,
+		LastUsedAt:   dbPt.LastUsedAt.Time,
+		RefreshedAt:  dbPt.RefreshedAt.Time,
+		Revoked:      dbPt.Revoked,
+		CreatedAt:    dbPt.CreatedAt,
+	}, nil
+}
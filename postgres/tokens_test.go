@@ -0,0 +1,347 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala-ui/postgres"
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePersonalToken(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM personal_tokens")
+		require.Nil(t, err, fmt.Sprintf("clean personal tokens unexpected error: %s", err))
+	})
+
+	id := generateUUID(t)
+	hash := strings.Repeat("a", 64)
+
+	cases := []struct {
+		desc  string
+		token ui.PersonalToken
+		err   error
+	}{
+		{
+			desc:  "create new personal token",
+			token: generatePersonalToken(t, id, hash),
+			err:   nil,
+		},
+		{
+			desc:  "create existing personal token",
+			token: generatePersonalToken(t, id, strings.Repeat("b", 64)),
+			err:   postgres.ErrConflict,
+		},
+		{
+			desc:  "create personal token with existing hash",
+			token: generatePersonalToken(t, generateUUID(t), hash),
+			err:   postgres.ErrConflict,
+		},
+		{
+			desc:  "create personal token with empty id",
+			token: generatePersonalToken(t, "", strings.Repeat("c", 64)),
+			err:   postgres.ErrCreateEntity,
+		},
+		{
+			desc:  "create personal token with malformed id",
+			token: generatePersonalToken(t, strings.Repeat("a", 37), strings.Repeat("d", 64)),
+			err:   postgres.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			pt, err := tokenRepo.Create(context.Background(), tc.token)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s to contain: %s", err, tc.err))
+			if err == nil {
+				assert.Equal(t, tc.token, pt)
+			}
+		})
+	}
+}
+
+func TestRetrievePersonalTokenByHash(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM personal_tokens")
+		require.Nil(t, err, fmt.Sprintf("clean personal tokens unexpected error: %s", err))
+	})
+
+	token := generatePersonalToken(t, generateUUID(t), strings.Repeat("a", 64))
+	_, err := tokenRepo.Create(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("create personal token unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		hash string
+		err  error
+	}{
+		{
+			desc: "retrieve existing personal token",
+			hash: token.Hash,
+			err:  nil,
+		},
+		{
+			desc: "retrieve non-existing personal token",
+			hash: strings.Repeat("b", 64),
+			err:  postgres.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			pt, err := tokenRepo.RetrieveByHash(context.Background(), tc.hash)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s to contain: %s", err, tc.err))
+			if err == nil {
+				assert.Equal(t, token, pt)
+			}
+		})
+	}
+}
+
+func TestRetrieveAllPersonalTokens(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM personal_tokens")
+		require.Nil(t, err, fmt.Sprintf("clean personal tokens unexpected error: %s", err))
+	})
+
+	userID := generateUUID(t)
+	num := 10
+	for i := 0; i < num; i++ {
+		token := generatePersonalToken(t, generateUUID(t), fmt.Sprintf("%064d", i))
+		token.UserID = userID
+		_, err := tokenRepo.Create(context.Background(), token)
+		require.Nil(t, err, fmt.Sprintf("create personal token unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc  string
+		page  ui.PersonalTokenPageMeta
+		total uint64
+		size  int
+	}{
+		{
+			desc:  "retrieve all personal tokens",
+			page:  ui.PersonalTokenPageMeta{Offset: 0, Limit: uint64(num), UserID: userID},
+			total: uint64(num),
+			size:  num,
+		},
+		{
+			desc:  "retrieve personal tokens with offset",
+			page:  ui.PersonalTokenPageMeta{Offset: 5, Limit: uint64(num), UserID: userID},
+			total: uint64(num),
+			size:  num - 5,
+		},
+		{
+			desc:  "retrieve personal tokens of another user",
+			page:  ui.PersonalTokenPageMeta{Offset: 0, Limit: uint64(num), UserID: generateUUID(t)},
+			total: 0,
+			size:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			page, err := tokenRepo.RetrieveAll(context.Background(), tc.page)
+			assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			assert.Equal(t, tc.total, page.Total)
+			assert.Len(t, page.Tokens, tc.size)
+			for _, pt := range page.Tokens {
+				assert.Empty(t, pt.RefreshToken, "expected refresh token not to be listed")
+			}
+		})
+	}
+}
+
+func TestUpdateRefreshToken(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM personal_tokens")
+		require.Nil(t, err, fmt.Sprintf("clean personal tokens unexpected error: %s", err))
+	})
+
+	token := generatePersonalToken(t, generateUUID(t), strings.Repeat("a", 64))
+	_, err := tokenRepo.Create(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("create personal token unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		id       string
+		current  string
+		replaced bool
+	}{
+		{
+			desc:     "update refresh token of existing personal token",
+			id:       token.ID,
+			current:  token.RefreshToken,
+			replaced: true,
+		},
+		{
+			desc:     "update refresh token already replaced by another request",
+			id:       token.ID,
+			current:  token.RefreshToken,
+			replaced: false,
+		},
+		{
+			desc:     "update refresh token of non-existing personal token",
+			id:       generateUUID(t),
+			current:  token.RefreshToken,
+			replaced: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			replaced, err := tokenRepo.UpdateRefreshToken(context.Background(), tc.id, tc.current, namegen.Generate(), time.Now().UTC())
+			assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			assert.Equal(t, tc.replaced, replaced)
+		})
+	}
+}
+
+func TestRetrieveStalePersonalTokens(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM personal_tokens")
+		require.Nil(t, err, fmt.Sprintf("clean personal tokens unexpected error: %s", err))
+	})
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	stale := generatePersonalToken(t, generateUUID(t), strings.Repeat("a", 64))
+	stale.RefreshedAt = now.Add(-12 * time.Hour)
+	fresh := generatePersonalToken(t, generateUUID(t), strings.Repeat("b", 64))
+	revoked := generatePersonalToken(t, generateUUID(t), strings.Repeat("c", 64))
+	revoked.RefreshedAt = now.Add(-12 * time.Hour)
+	expired := generatePersonalToken(t, generateUUID(t), strings.Repeat("d", 64))
+	expired.RefreshedAt, expired.ExpiresAt = now.Add(-12*time.Hour), now.Add(-time.Hour)
+	for _, pt := range []ui.PersonalToken{stale, fresh, revoked, expired} {
+		_, err := tokenRepo.Create(context.Background(), pt)
+		require.Nil(t, err, fmt.Sprintf("create personal token unexpected error: %s", err))
+	}
+	err := tokenRepo.Revoke(context.Background(), revoked.ID, revoked.UserID)
+	require.Nil(t, err, fmt.Sprintf("revoke personal token unexpected error: %s", err))
+
+	tokens, err := tokenRepo.RetrieveStale(context.Background(), now.Add(-6*time.Hour))
+	require.Nil(t, err, fmt.Sprintf("retrieve stale personal tokens unexpected error: %s", err))
+	require.Len(t, tokens, 1)
+	assert.Equal(t, stale.ID, tokens[0].ID)
+
+	replaced, err := tokenRepo.UpdateRefreshToken(context.Background(), stale.ID, stale.RefreshToken, namegen.Generate(), now)
+	require.Nil(t, err, fmt.Sprintf("update refresh token unexpected error: %s", err))
+	require.True(t, replaced, "expected refresh token to be replaced")
+
+	tokens, err = tokenRepo.RetrieveStale(context.Background(), now.Add(-6*time.Hour))
+	require.Nil(t, err, fmt.Sprintf("retrieve stale personal tokens unexpected error: %s", err))
+	assert.Empty(t, tokens, "expected refreshed personal token not to be stale")
+}
+
+func TestUpdateLastUsed(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM personal_tokens")
+		require.Nil(t, err, fmt.Sprintf("clean personal tokens unexpected error: %s", err))
+	})
+
+	token := generatePersonalToken(t, generateUUID(t), strings.Repeat("a", 64))
+	_, err := tokenRepo.Create(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("create personal token unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		id   string
+		err  error
+	}{
+		{
+			desc: "update last use of existing personal token",
+			id:   token.ID,
+			err:  nil,
+		},
+		{
+			desc: "update last use of non-existing personal token",
+			id:   generateUUID(t),
+			err:  postgres.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			lastUsedAt := time.Now().UTC().Truncate(time.Millisecond)
+			err := tokenRepo.UpdateLastUsed(context.Background(), tc.id, lastUsedAt)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s to contain: %s", err, tc.err))
+			if err == nil {
+				pt, err := tokenRepo.RetrieveByHash(context.Background(), token.Hash)
+				require.Nil(t, err, fmt.Sprintf("retrieve personal token unexpected error: %s", err))
+				assert.Equal(t, lastUsedAt, pt.LastUsedAt)
+			}
+		})
+	}
+}
+
+func TestRevokePersonalToken(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM personal_tokens")
+		require.Nil(t, err, fmt.Sprintf("clean personal tokens unexpected error: %s", err))
+	})
+
+	token := generatePersonalToken(t, generateUUID(t), strings.Repeat("a", 64))
+	_, err := tokenRepo.Create(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("create personal token unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		userID string
+		err    error
+	}{
+		{
+			desc:   "revoke personal token of another user",
+			id:     token.ID,
+			userID: generateUUID(t),
+			err:    postgres.ErrNotFound,
+		},
+		{
+			desc:   "revoke existing personal token",
+			id:     token.ID,
+			userID: token.UserID,
+			err:    nil,
+		},
+		{
+			desc:   "revoke non-existing personal token",
+			id:     generateUUID(t),
+			userID: token.UserID,
+			err:    postgres.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tokenRepo.Revoke(context.Background(), tc.id, tc.userID)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s to contain: %s", err, tc.err))
+			if err == nil {
+				pt, err := tokenRepo.RetrieveByHash(context.Background(), token.Hash)
+				require.Nil(t, err, fmt.Sprintf("retrieve personal token unexpected error: %s", err))
+				assert.True(t, pt.Revoked, "expected personal token to be revoked")
+			}
+		})
+	}
+}
+
+func generatePersonalToken(t *testing.T, id, hash string) ui.PersonalToken {
+	return ui.PersonalToken{
+		ID:           id,
+		UserID:       generateUUID(t),
+		DomainID:     generateUUID(t),
+		Name:         namegen.Generate(),
+		Hash:         hash,
+		Scopes:       []ui.TokenScope{ui.EntitiesReadScope, ui.DataReadScope},
+		RefreshToken: namegen.Generate(),
+		ExpiresAt:    time.Now().Add(time.Hour).UTC().Truncate(time.Millisecond),
+		RefreshedAt:  time.Now().UTC().Truncate(time.Millisecond),
+		CreatedAt:    time.Now().UTC().Truncate(time.Millisecond),
+	}
+}
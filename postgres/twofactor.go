@@ -0,0 +1,195 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/jmoiron/sqlx"
+)
+
+type twoFactorRepo struct {
+	db *sqlx.DB
+}
+
+func NewTwoFactorRepository(db *sqlx.DB) ui.TwoFactorRepository {
+	return &twoFactorRepo{db: db}
+}
+
+// Save the two-factor enrolment of a user.
+func (r *twoFactorRepo) Save(ctx context.Context, tf ui.TwoFactor) error {
+	q := `INSERT INTO two_factor (user_id, secret, enabled, recovery_codes, last_step, created_at, updated_at)
+	VALUES (:user_id, :secret, :enabled, :recovery_codes, :last_step, :created_at, :updated_at)
+	ON CONFLICT (user_id) DO UPDATE SET secret = :secret, enabled = :enabled,
+	recovery_codes = :recovery_codes, last_step = :last_step, created_at = :created_at, updated_at = :updated_at`
+
+	dbTf, err := toDBTwoFactor(tf)
+	if err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+	if _, err := r.db.NamedExecContext(ctx, q, dbTf); err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+
+	return nil
+}
+
+// Retrieve the two-factor enrolment of a user using a user id. An empty
+// enrolment is returned if the user has not enrolled.
+func (r *twoFactorRepo) Retrieve(ctx context.Context, userID string) (ui.TwoFactor, error) {
+	q := `SELECT user_id, secret, enabled, recovery_codes, last_step, created_at, updated_at FROM two_factor WHERE user_id = :user_id`
+
+	rows, err := r.db.NamedQueryContext(ctx, q, dbTwoFactor{UserID: userID})
+	if err != nil {
+		return ui.TwoFactor{}, HandleError(err, ErrViewEntity)
+	}
+	defer rows.Close()
+
+	dbTf := dbTwoFactor{}
+	if rows.Next() {
+		if err = rows.StructScan(&dbTf); err != nil {
+			return ui.TwoFactor{}, HandleError(err, ErrViewEntity)
+		}
+		return toTwoFactor(dbTf)
+	}
+
+	return ui.TwoFactor{}, nil
+}
+
+// Accept the time step of a code of a user, if it is later than the last
+// accepted one.
+func (r *twoFactorRepo) AcceptStep(ctx context.Context, userID string, step int64) (bool, error) {
+	q := `UPDATE two_factor SET last_step = $2 WHERE user_id = $1 AND enabled AND last_step < $2 RETURNING user_id`
+
+	var id string
+	if err := r.db.QueryRowxContext(ctx, q, userID, step).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, HandleError(err, ErrCreateEntity)
+	}
+
+	return true, nil
+}
+
+// Use a recovery code of a user, removing its hash from the remaining codes
+// in the same statement that checks it is there.
+func (r *twoFactorRepo) UseRecoveryCode(ctx context.Context, userID, hash string) (bool, error) {
+	q := `UPDATE two_factor SET recovery_codes = recovery_codes - $2::text, updated_at = $3
+	WHERE user_id = $1 AND enabled AND recovery_codes @> jsonb_build_array($2::text) RETURNING user_id`
+
+	var id string
+	if err := r.db.QueryRowxContext(ctx, q, userID, hash, time.Now()).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, HandleError(err, ErrCreateEntity)
+	}
+
+	return true, nil
+}
+
+// Remove the two-factor enrolment of a user.
+func (r *twoFactorRepo) Remove(ctx context.Context, userID string) error {
+	q := `DELETE FROM two_factor WHERE user_id = :user_id`
+
+	res, err := r.db.NamedExecContext(ctx, q, dbTwoFactor{UserID: userID})
+	if err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Save whether two-factor authentication is required in a domain.
+func (r *twoFactorRepo) SaveDomainRequirement(ctx context.Context, domainID string, required bool) error {
+	q := `INSERT INTO domain_two_factor (domain_id, required, updated_at) VALUES (:domain_id, :required, :updated_at)
+	ON CONFLICT (domain_id) DO UPDATE SET required = :required, updated_at = :updated_at`
+
+	dbReq := dbDomainTwoFactor{
+		DomainID:  domainID,
+		Required:  required,
+		UpdatedAt: time.Now(),
+	}
+	if _, err := r.db.NamedExecContext(ctx, q, dbReq); err != nil {
+		return HandleError(err, ErrCreateEntity)
+	}
+
+	return nil
+}
+
+// Retrieve whether two-factor authentication is required in a domain.
+func (r *twoFactorRepo) RetrieveDomainRequirement(ctx context.Context, domainID string) (bool, error) {
+	q := `SELECT required FROM domain_two_factor WHERE domain_id = $1`
+
+	var required bool
+	if err := r.db.QueryRowxContext(ctx, q, domainID).Scan(&required); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, HandleError(err, ErrViewEntity)
+	}
+
+	return required, nil
+}
+
+type dbTwoFactor struct {
+	UserID        string    `db:"user_id"`
+	Secret        string    `db:"secret"`
+	Enabled       bool      `db:"enabled"`
+	RecoveryCodes []byte    `db:"recovery_codes"`
+	LastStep      int64     `db:"last_step"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+type dbDomainTwoFactor struct {
+	DomainID  string    `db:"domain_id"`
+	Required  bool      `db:"required"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func toDBTwoFactor(tf ui.TwoFactor) (dbTwoFactor, error) {
+	codes, err := json.Marshal(tf.RecoveryCodes)
+	if err != nil {
+		return dbTwoFactor{}, errors.Wrap(ErrJSONMarshal, err)
+	}
+
+	return dbTwoFactor{
+		UserID:        tf.UserID,
+		Secret:        tf.Secret,
+		Enabled:       tf.Enabled,
+		RecoveryCodes: codes,
+		LastStep:      tf.LastStep,
+		CreatedAt:     tf.CreatedAt,
+		UpdatedAt:     tf.UpdatedAt,
+	}, nil
+}
+
+func toTwoFactor(dbTf dbTwoFactor) (ui.TwoFactor, error) {
+	var codes []string
+	if dbTf.RecoveryCodes != nil {
+		if err := json.Unmarshal(dbTf.RecoveryCodes, &codes); err != nil {
+			return ui.TwoFactor{}, errors.Wrap(ErrJSONUnmarshal, err)
+		}
+	}
+
+	return ui.TwoFactor{
+		UserID:        dbTf.UserID,
+		Secret:        dbTf.Secret,
+		Enabled:       dbTf.Enabled,
+		RecoveryCodes: codes,
+		LastStep:      dbTf.LastStep,
+		CreatedAt:     dbTf.CreatedAt,
+		UpdatedAt:     dbTf.UpdatedAt,
+	}, nil
+}
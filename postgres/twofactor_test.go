@@ -0,0 +1,345 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala-ui/postgres"
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveTwoFactor(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM two_factor")
+		require.Nil(t, err, fmt.Sprintf("clean two factor unexpected error: %s", err))
+	})
+
+	userID := generateUUID(t)
+	enabled := generateTwoFactor(t, userID)
+	enabled.Enabled = true
+
+	cases := []struct {
+		desc string
+		tf   ui.TwoFactor
+		err  error
+	}{
+		{
+			desc: "save new two factor enrolment",
+			tf:   generateTwoFactor(t, userID),
+			err:  nil,
+		},
+		{
+			desc: "save existing two factor enrolment",
+			tf:   enabled,
+			err:  nil,
+		},
+		{
+			desc: "save two factor enrolment with empty user id",
+			tf:   generateTwoFactor(t, ""),
+			err:  postgres.ErrCreateEntity,
+		},
+		{
+			desc: "save two factor enrolment with malformed user id",
+			tf:   generateTwoFactor(t, strings.Repeat("a", 37)),
+			err:  postgres.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := twoFactorRepo.Save(context.Background(), tc.tf)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s to contain: %s", err, tc.err))
+			if err == nil {
+				tf, err := twoFactorRepo.Retrieve(context.Background(), tc.tf.UserID)
+				require.Nil(t, err, fmt.Sprintf("retrieve two factor unexpected error: %s", err))
+				assert.Equal(t, tc.tf, tf)
+			}
+		})
+	}
+}
+
+func TestRetrieveTwoFactor(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM two_factor")
+		require.Nil(t, err, fmt.Sprintf("clean two factor unexpected error: %s", err))
+	})
+
+	tf := generateTwoFactor(t, generateUUID(t))
+	err := twoFactorRepo.Save(context.Background(), tf)
+	require.Nil(t, err, fmt.Sprintf("save two factor unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		userID string
+		tf     ui.TwoFactor
+		err    error
+	}{
+		{
+			desc:   "retrieve existing two factor enrolment",
+			userID: tf.UserID,
+			tf:     tf,
+			err:    nil,
+		},
+		{
+			desc:   "retrieve non-existing two factor enrolment",
+			userID: generateUUID(t),
+			tf:     ui.TwoFactor{},
+			err:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			tf, err := twoFactorRepo.Retrieve(context.Background(), tc.userID)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s to contain: %s", err, tc.err))
+			assert.Equal(t, tc.tf, tf)
+		})
+	}
+}
+
+func TestAcceptStep(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM two_factor")
+		require.Nil(t, err, fmt.Sprintf("clean two factor unexpected error: %s", err))
+	})
+
+	tf := generateTwoFactor(t, generateUUID(t))
+	tf.Enabled = true
+	tf.LastStep = 100
+	err := twoFactorRepo.Save(context.Background(), tf)
+	require.Nil(t, err, fmt.Sprintf("save two factor unexpected error: %s", err))
+
+	pending := generateTwoFactor(t, generateUUID(t))
+	err = twoFactorRepo.Save(context.Background(), pending)
+	require.Nil(t, err, fmt.Sprintf("save two factor unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		userID   string
+		step     int64
+		accepted bool
+	}{
+		{
+			desc:     "accept later step",
+			userID:   tf.UserID,
+			step:     101,
+			accepted: true,
+		},
+		{
+			desc:     "accept already accepted step",
+			userID:   tf.UserID,
+			step:     101,
+			accepted: false,
+		},
+		{
+			desc:     "accept earlier step",
+			userID:   tf.UserID,
+			step:     99,
+			accepted: false,
+		},
+		{
+			desc:     "accept step of pending enrolment",
+			userID:   pending.UserID,
+			step:     101,
+			accepted: false,
+		},
+		{
+			desc:     "accept step of non-existing enrolment",
+			userID:   generateUUID(t),
+			step:     101,
+			accepted: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			accepted, err := twoFactorRepo.AcceptStep(context.Background(), tc.userID, tc.step)
+			require.Nil(t, err, fmt.Sprintf("accept step unexpected error: %s", err))
+			assert.Equal(t, tc.accepted, accepted)
+		})
+	}
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM two_factor")
+		require.Nil(t, err, fmt.Sprintf("clean two factor unexpected error: %s", err))
+	})
+
+	tf := generateTwoFactor(t, generateUUID(t))
+	tf.Enabled = true
+	err := twoFactorRepo.Save(context.Background(), tf)
+	require.Nil(t, err, fmt.Sprintf("save two factor unexpected error: %s", err))
+
+	pending := generateTwoFactor(t, generateUUID(t))
+	err = twoFactorRepo.Save(context.Background(), pending)
+	require.Nil(t, err, fmt.Sprintf("save two factor unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		userID string
+		hash   string
+		used   bool
+		codes  []string
+	}{
+		{
+			desc:   "use remaining recovery code",
+			userID: tf.UserID,
+			hash:   tf.RecoveryCodes[0],
+			used:   true,
+			codes:  tf.RecoveryCodes[1:],
+		},
+		{
+			desc:   "use already used recovery code",
+			userID: tf.UserID,
+			hash:   tf.RecoveryCodes[0],
+			used:   false,
+			codes:  tf.RecoveryCodes[1:],
+		},
+		{
+			desc:   "use unknown recovery code",
+			userID: tf.UserID,
+			hash:   strings.Repeat("f", 64),
+			used:   false,
+			codes:  tf.RecoveryCodes[1:],
+		},
+		{
+			desc:   "use recovery code of pending enrolment",
+			userID: pending.UserID,
+			hash:   pending.RecoveryCodes[0],
+			used:   false,
+			codes:  pending.RecoveryCodes,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			used, err := twoFactorRepo.UseRecoveryCode(context.Background(), tc.userID, tc.hash)
+			require.Nil(t, err, fmt.Sprintf("use recovery code unexpected error: %s", err))
+			assert.Equal(t, tc.used, used)
+			tf, err := twoFactorRepo.Retrieve(context.Background(), tc.userID)
+			require.Nil(t, err, fmt.Sprintf("retrieve two factor unexpected error: %s", err))
+			assert.Equal(t, tc.codes, tf.RecoveryCodes)
+		})
+	}
+}
+
+func TestRemoveTwoFactor(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM two_factor")
+		require.Nil(t, err, fmt.Sprintf("clean two factor unexpected error: %s", err))
+	})
+
+	tf := generateTwoFactor(t, generateUUID(t))
+	err := twoFactorRepo.Save(context.Background(), tf)
+	require.Nil(t, err, fmt.Sprintf("save two factor unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		userID string
+		err    error
+	}{
+		{
+			desc:   "remove existing two factor enrolment",
+			userID: tf.UserID,
+			err:    nil,
+		},
+		{
+			desc:   "remove non-existing two factor enrolment",
+			userID: tf.UserID,
+			err:    postgres.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := twoFactorRepo.Remove(context.Background(), tc.userID)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s to contain: %s", err, tc.err))
+		})
+	}
+}
+
+func TestDomainRequirement(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := db.Exec("DELETE FROM domain_two_factor")
+		require.Nil(t, err, fmt.Sprintf("clean domain two factor unexpected error: %s", err))
+	})
+
+	domainID := generateUUID(t)
+
+	cases := []struct {
+		desc     string
+		domainID string
+		save     bool
+		required bool
+		err      error
+	}{
+		{
+			desc:     "retrieve requirement of domain without one",
+			domainID: generateUUID(t),
+			required: false,
+			err:      nil,
+		},
+		{
+			desc:     "require two factor in domain",
+			domainID: domainID,
+			save:     true,
+			required: true,
+			err:      nil,
+		},
+		{
+			desc:     "stop requiring two factor in domain",
+			domainID: domainID,
+			save:     true,
+			required: false,
+			err:      nil,
+		},
+		{
+			desc:     "save requirement with empty domain id",
+			domainID: "",
+			save:     true,
+			required: true,
+			err:      postgres.ErrCreateEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if tc.save {
+				err := twoFactorRepo.SaveDomainRequirement(context.Background(), tc.domainID, tc.required)
+				assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s to contain: %s", err, tc.err))
+				if err != nil {
+					return
+				}
+			}
+			required, err := twoFactorRepo.RetrieveDomainRequirement(context.Background(), tc.domainID)
+			require.Nil(t, err, fmt.Sprintf("retrieve domain requirement unexpected error: %s", err))
+			assert.Equal(t, tc.required, required)
+		})
+	}
+}
+
+func generateTwoFactor(t *testing.T, userID string) ui.TwoFactor {
+	codes := make([]string, ui.RecoveryCodesCount)
+	for i := range codes {
+		codes[i] = strings.Repeat(fmt.Sprintf("%x", i), 64)
+	}
+
+	return ui.TwoFactor{
+		UserID:        userID,
+		Secret:        generateUUID(t),
+		Enabled:       false,
+		RecoveryCodes: codes,
+		CreatedAt:     time.Now().UTC().Truncate(time.Microsecond),
+		UpdatedAt:     time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -15,7 +16,6 @@ import (
 	"github.com/go-kit/kit/endpoint"
 	"github.com/golang-jwt/jwt"
 	"github.com/gorilla/securecookie"
-	"golang.org/x/sync/errgroup"
 )
 
 func indexEndpoint(svc ui.Service) endpoint.Endpoint {
@@ -114,29 +114,9 @@ func logoutEndpoint(svc ui.Service, prefix string) endpoint.Endpoint {
 			return nil, err
 		}
 
-		cookies := []*http.Cookie{
-			{
-				Name:   sessionDetailsKey,
-				Value:  "",
-				Path:   "/",
-				MaxAge: -1,
-			},
-			{
-				Name:   refreshTokenKey,
-				Value:  "",
-				Path:   fmt.Sprintf("%s/%s/login", prefix, domainsAPIEndpoint),
-				MaxAge: -1,
-			},
-			{
-				Name:   refreshTokenKey,
-				Value:  "",
-				Path:   fmt.Sprintf("%s/%s", prefix, tokenRefreshAPIEndpoint),
-				MaxAge: -1,
-			},
-		}
 		return uiRes{
 			code:    http.StatusOK,
-			cookies: cookies,
+			cookies: clearSessionCookies(prefix),
 		}, nil
 	}
 }
@@ -191,6 +171,20 @@ func showPasswordResetEndpoint(svc ui.Service) endpoint.Endpoint {
 	}
 }
 
+func sessionExpiredEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(_ context.Context, _ interface{}) (interface{}, error) {
+		res, err := svc.SessionExpired()
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
 func showUpdatePasswordEndpoint(svc ui.Service) endpoint.Endpoint {
 	return func(_ context.Context, request interface{}) (interface{}, error) {
 		req := request.(showUpdatePasswordReq)
@@ -294,82 +288,193 @@ func tokenEndpoint(svc ui.Service) endpoint.Endpoint {
 }
 
 func secureTokenEndpoint(svc ui.Service, s *securecookie.SecureCookie, prefix string) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(secureTokenReq)
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
 
+		now := time.Now()
 		sessionReq := ui.Session{
-			Token:       req.AccessToken,
-			LoginStatus: ui.UserLoginStatus,
+			Token:        req.AccessToken,
+			LoginStatus:  ui.UserLoginStatus,
+			LoginAt:      now,
+			LastActivity: now,
 		}
 
 		sessionDetails, err := svc.Session(sessionReq)
 		if err != nil {
 			return nil, err
 		}
+
+		clearTokens := []*http.Cookie{
+			{
+				Name:   accessTokenKey,
+				Value:  "",
+				Path:   "/",
+				MaxAge: -1,
+			},
+			{
+				Name:   refreshTokenKey,
+				Value:  "",
+				Path:   "/",
+				MaxAge: -1,
+			},
+		}
+
+		enabled, err := svc.TwoFactorEnabled(ctx, sessionDetails.User.ID)
+		if err != nil {
+			return nil, err
+		}
+		if enabled {
+			// The session is issued only after the second step of the login.
+			pending, err := json.Marshal(twoFactorPending{
+				UserID:       sessionDetails.User.ID,
+				AccessToken:  req.AccessToken,
+				RefreshToken: req.RefreshToken,
+				ExpiresAt:    now.Add(twoFactorLoginTimeout),
+			})
+			if err != nil {
+				return nil, errors.Wrap(ui.ErrJSONMarshal, err)
+			}
+			securePending, err := s.Encode(twoFactorKey, string(pending))
+			if err != nil {
+				return nil, errors.Wrap(errCookieEncrypt, err)
+			}
+
+			return uiRes{
+				code: http.StatusSeeOther,
+				cookies: append([]*http.Cookie{
+					{
+						Name:     twoFactorKey,
+						Value:    securePending,
+						Path:     fmt.Sprintf("%s/%s", prefix, twoFactorLoginAPIEndpoint),
+						MaxAge:   int(twoFactorLoginTimeout.Seconds()),
+						HttpOnly: true,
+					},
+				}, clearTokens...),
+				headers: map[string]string{"Location": fmt.Sprintf("%s/%s", prefix, twoFactorLoginAPIEndpoint)},
+			}, nil
+		}
+
 		sessionDetails.Token = req.AccessToken
-		session, err := json.Marshal(sessionDetails)
+		cookies, err := loginCookies(s, prefix, sessionDetails, req.RefreshToken)
 		if err != nil {
-			return nil, errors.Wrap(ui.ErrJSONMarshal, err)
+			return nil, err
 		}
-		secureSessionDetails, err := s.Encode(sessionDetailsKey, string(session))
+
+		return uiRes{
+			code:    http.StatusSeeOther,
+			cookies: append(cookies, clearTokens...),
+			headers: map[string]string{"Location": fmt.Sprintf("%s/%s", prefix, domainsAPIEndpoint)},
+		}, nil
+	}
+}
+
+func twoFactorLoginEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(_ context.Context, _ interface{}) (interface{}, error) {
+		res, err := svc.TwoFactorLogin()
 		if err != nil {
-			return nil, errors.Wrap(errCookieEncrypt, err)
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func verifyTwoFactorLoginEndpoint(svc ui.Service, s *securecookie.SecureCookie, prefix string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(twoFactorLoginReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.VerifyTwoFactor(ctx, req.pending.UserID, req.code); err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		sessionReq := ui.Session{
+			Token:        req.pending.AccessToken,
+			LoginStatus:  ui.UserLoginStatus,
+			LoginAt:      now,
+			LastActivity: now,
 		}
 
-		secureRefreshToken, err := s.Encode(refreshTokenKey, req.RefreshToken)
+		sessionDetails, err := svc.Session(sessionReq)
 		if err != nil {
-			return nil, errors.Wrap(errCookieEncrypt, err)
+			return nil, err
 		}
 
-		refreshExp, err := extractTokenExpiry(req.RefreshToken)
+		sessionDetails.Token = req.pending.AccessToken
+		cookies, err := loginCookies(s, prefix, sessionDetails, req.pending.RefreshToken)
 		if err != nil {
 			return nil, err
 		}
+		cookies = append(cookies, &http.Cookie{
+			Name:   twoFactorKey,
+			Value:  "",
+			Path:   fmt.Sprintf("%s/%s", prefix, twoFactorLoginAPIEndpoint),
+			MaxAge: -1,
+		})
 
 		return uiRes{
-			code: http.StatusSeeOther,
-			cookies: []*http.Cookie{
-				{
-					Name:     sessionDetailsKey,
-					Value:    secureSessionDetails,
-					Path:     "/",
-					HttpOnly: true,
-				},
-				{
-					Name:     refreshTokenKey,
-					Value:    secureRefreshToken,
-					Path:     fmt.Sprintf("%s/%s", prefix, tokenRefreshAPIEndpoint),
-					Expires:  refreshExp,
-					HttpOnly: true,
-				},
-				{
-					Name:     refreshTokenKey,
-					Value:    secureRefreshToken,
-					Path:     fmt.Sprintf("%s/%s/login", prefix, domainsAPIEndpoint),
-					Expires:  refreshExp,
-					HttpOnly: true,
-				},
-				{
-					Name:   accessTokenKey,
-					Value:  "",
-					Path:   "/",
-					MaxAge: -1,
-				},
-				{
-					Name:   refreshTokenKey,
-					Value:  "",
-					Path:   "/",
-					MaxAge: -1,
-				},
-			},
+			code:    http.StatusSeeOther,
+			cookies: cookies,
 			headers: map[string]string{"Location": fmt.Sprintf("%s/%s", prefix, domainsAPIEndpoint)},
 		}, nil
 	}
 }
 
+// loginCookies returns the encrypted session and refresh token cookies
+// issued once a user has logged in.
+func loginCookies(s *securecookie.SecureCookie, prefix string, sessionDetails ui.Session, refreshToken string) ([]*http.Cookie, error) {
+	session, err := json.Marshal(sessionDetails)
+	if err != nil {
+		return nil, errors.Wrap(ui.ErrJSONMarshal, err)
+	}
+	secureSessionDetails, err := s.Encode(sessionDetailsKey, string(session))
+	if err != nil {
+		return nil, errors.Wrap(errCookieEncrypt, err)
+	}
+
+	secureRefreshToken, err := s.Encode(refreshTokenKey, refreshToken)
+	if err != nil {
+		return nil, errors.Wrap(errCookieEncrypt, err)
+	}
+
+	refreshExp, err := extractTokenExpiry(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Cookie{
+		{
+			Name:     sessionDetailsKey,
+			Value:    secureSessionDetails,
+			Path:     "/",
+			HttpOnly: true,
+		},
+		{
+			Name:     refreshTokenKey,
+			Value:    secureRefreshToken,
+			Path:     fmt.Sprintf("%s/%s", prefix, tokenRefreshAPIEndpoint),
+			Expires:  refreshExp,
+			HttpOnly: true,
+		},
+		{
+			Name:     refreshTokenKey,
+			Value:    secureRefreshToken,
+			Path:     fmt.Sprintf("%s/%s/login", prefix, domainsAPIEndpoint),
+			Expires:  refreshExp,
+			HttpOnly: true,
+		},
+	}, nil
+}
+
 func refreshTokenEndpoint(svc ui.Service, s *securecookie.SecureCookie, prefix string) endpoint.Endpoint {
 	return func(_ context.Context, request interface{}) (interface{}, error) {
 		req := request.(refreshTokenReq)
@@ -449,23 +554,6 @@ func createUserEndpoint(svc ui.Service) endpoint.Endpoint {
 	}
 }
 
-func createUsersEndpoint(svc ui.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
-		req := request.(createUsersReq)
-		if err := req.validate(); err != nil {
-			return nil, err
-		}
-
-		if err := svc.CreateUsers(req.token, req.users...); err != nil {
-			return nil, err
-		}
-
-		return uiRes{
-			code: http.StatusCreated,
-		}, nil
-	}
-}
-
 func listUsersEndpoint(svc ui.Service) endpoint.Endpoint {
 	return func(_ context.Context, request interface{}) (interface{}, error) {
 		req := request.(listEntityReq)
@@ -698,23 +786,6 @@ func createThingEndpoint(svc ui.Service) endpoint.Endpoint {
 	}
 }
 
-func createThingsEndpoint(svc ui.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
-		req := request.(createThingsReq)
-		if err := req.validate(); err != nil {
-			return nil, err
-		}
-
-		if err := svc.CreateThings(req.token, req.things...); err != nil {
-			return nil, err
-		}
-
-		return uiRes{
-			code: http.StatusCreated,
-		}, nil
-	}
-}
-
 func listThingsEndpoint(svc ui.Service) endpoint.Endpoint {
 	return func(_ context.Context, request interface{}) (interface{}, error) {
 		req := request.(listEntityReq)
@@ -952,23 +1023,6 @@ func createChannelEndpoint(svc ui.Service) endpoint.Endpoint {
 	}
 }
 
-func createChannelsEndpoint(svc ui.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
-		req := request.(createChannelsReq)
-		if err := req.validate(); err != nil {
-			return nil, err
-		}
-
-		if err := svc.CreateChannels(req.token, req.Channels...); err != nil {
-			return nil, err
-		}
-
-		return uiRes{
-			code: http.StatusCreated,
-		}, nil
-	}
-}
-
 func listChannelsEndpoint(svc ui.Service) endpoint.Endpoint {
 	return func(_ context.Context, request interface{}) (interface{}, error) {
 		req := request.(listEntityReq)
@@ -1191,23 +1245,6 @@ func createGroupEndpoint(svc ui.Service) endpoint.Endpoint {
 	}
 }
 
-func createGroupsEndpoint(svc ui.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
-		req := request.(createGroupsReq)
-		if err := req.validate(); err != nil {
-			return nil, err
-		}
-
-		if err := svc.CreateGroups(req.token, req.Groups...); err != nil {
-			return nil, err
-		}
-
-		return uiRes{
-			code: http.StatusCreated,
-		}, nil
-	}
-}
-
 func listGroupMembersEndpoint(svc ui.Service) endpoint.Endpoint {
 	return func(_ context.Context, request interface{}) (interface{}, error) {
 		req := request.(listEntityByIDReq)
@@ -1443,6 +1480,43 @@ func updateBootstrap(svc ui.Service) endpoint.Endpoint {
 	}
 }
 
+func updateBootstrapContentEndpoint(svc ui.Service, prefix string) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(updateBootstrapReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UpdateBootstrap(req.token, req.BootstrapConfig); err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code:    http.StatusSeeOther,
+			headers: map[string]string{"Location": fmt.Sprintf("%s/%s/%s", prefix, bootstrapAPIEndpoint, req.ThingID)},
+		}, nil
+	}
+}
+
+func bootstrapContentDiffEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(bootstrapContentDiffReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.BootstrapContentDiff(req.Session, req.thingID, req.content)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
 func deleteBootstrapEndpoint(svc ui.Service, prefix string) endpoint.Endpoint {
 	return func(_ context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(deleteBootstrapReq)
@@ -1550,37 +1624,6 @@ func getTerminalEndpoint(svc ui.Service) endpoint.Endpoint {
 	}
 }
 
-func handleTerminalInputEndpoint(svc ui.Service) endpoint.Endpoint {
-	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
-		req := request.(bootstrapCommandReq)
-		if err := req.validate(); err != nil {
-			return nil, err
-		}
-
-		// Create a channel to receive the command result
-		ch := make(chan string)
-
-		g, ctx := errgroup.WithContext(ctx)
-
-		// Start a goroutine to process the command asynchronously
-		g.Go(func() error {
-			return svc.ProcessTerminalCommand(ctx, req.id, req.token, req.command, ch)
-		})
-
-		if err := g.Wait(); err != nil {
-			return nil, err
-		}
-
-		// Receive the command result from the channel
-		result := <-ch
-
-		return terminalResponse{
-			Command: req.command,
-			Result:  result,
-		}, nil
-	}
-}
-
 func getEntitiesEndpoint(svc ui.Service) endpoint.Endpoint {
 	return func(_ context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(getEntitiesReq)
@@ -1619,11 +1662,14 @@ func errorPageEndpoint(svc ui.Service) endpoint.Endpoint {
 }
 
 func domainLoginEndpoint(svc ui.Service, s *securecookie.SecureCookie, prefix string) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(domainLoginReq)
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
+		if err := svc.CheckDomainTwoFactor(ctx, req.DomainID, req.User.ID); err != nil {
+			return nil, err
+		}
 		token, err := svc.DomainLogin(req.Login, req.Token)
 		if err != nil {
 			return nil, err
@@ -1756,13 +1802,13 @@ func updateDomainTagsEndpoint(svc ui.Service) endpoint.Endpoint {
 }
 
 func domainEndpoint(svc ui.Service) endpoint.Endpoint {
-	return func(_ context.Context, request interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(listEntityByIDReq)
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
 
-		res, err := svc.Domain(req.Session)
+		res, err := svc.Domain(ctx, req.Session)
 		if err != nil {
 			return nil, err
 		}
@@ -2154,3 +2200,823 @@ func extractTokenExpiry(token string) (time.Time, error) {
 	}
 	return expTime, nil
 }
+
+func personalTokensEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(personalTokensReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.PersonalTokens(ctx, req.Session, req.page, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func createPersonalTokenEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createPersonalTokenReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.CreatePersonalToken(ctx, req.Session, req.password, req.ptReq)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code:    http.StatusCreated,
+			html:    res,
+			headers: map[string]string{"Content-Type": jsonContentType},
+		}, nil
+	}
+}
+
+func revokePersonalTokenEndpoint(svc ui.Service, prefix string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(revokePersonalTokenReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RevokePersonalToken(ctx, req.Session, req.id); err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code:    http.StatusSeeOther,
+			headers: map[string]string{"Location": fmt.Sprintf("%s/%s", prefix, personalTokensAPIEndpoint)},
+		}, nil
+	}
+}
+
+func twoFactorEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(twoFactorReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.TwoFactor(ctx, req.Session)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func enrollTwoFactorEndpoint(svc ui.Service, prefix string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(twoFactorReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.EnrollTwoFactor(ctx, req.Session); err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code:    http.StatusSeeOther,
+			headers: map[string]string{"Location": fmt.Sprintf("%s/%s", prefix, twoFactorAPIEndpoint)},
+		}, nil
+	}
+}
+
+func confirmTwoFactorEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(twoFactorCodeReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.ConfirmTwoFactor(ctx, req.Session, req.code)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code:    http.StatusOK,
+			html:    res,
+			headers: map[string]string{"Content-Type": jsonContentType},
+		}, nil
+	}
+}
+
+func disableTwoFactorEndpoint(svc ui.Service, prefix string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(twoFactorCodeReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.DisableTwoFactor(ctx, req.Session, req.code); err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code:    http.StatusSeeOther,
+			headers: map[string]string{"Location": fmt.Sprintf("%s/%s", prefix, twoFactorAPIEndpoint)},
+		}, nil
+	}
+}
+
+func updateDomainTwoFactorEndpoint(svc ui.Service, prefix string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateDomainTwoFactorReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UpdateDomainTwoFactor(ctx, req.Session, req.id, req.required); err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code:    http.StatusSeeOther,
+			headers: map[string]string{"Location": fmt.Sprintf("%s/%s/%s", prefix, domainsAPIEndpoint, req.id)},
+		}, nil
+	}
+}
+
+func updateTerminalPolicyEndpoint(svc ui.Service, prefix string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateTerminalPolicyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UpdateTerminalPolicy(ctx, req.Session, req.policy); err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code:    http.StatusSeeOther,
+			headers: map[string]string{"Location": fmt.Sprintf("%s/%s/%s", prefix, domainsAPIEndpoint, req.policy.DomainID)},
+		}, nil
+	}
+}
+
+func terminalAuditEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(terminalAuditReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.TerminalAudit(ctx, req.Session, req.page, req.limit, req.pm)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func exportTerminalAuditEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(terminalAuditReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.ExportTerminalAudit(ctx, req.Session, req.pm)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+			headers: map[string]string{
+				"Content-Type":        csvContentType,
+				"Content-Disposition": fmt.Sprintf("attachment; filename=terminal-audit-%s.csv", time.Now().UTC().Format(expiryDateFormat)),
+			},
+		}, nil
+	}
+}
+
+func importEntitiesEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(importEntitiesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.ImportEntities(ctx, req.Session, req.imp)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func bulkConnectEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(bulkConnectReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.BulkConnect(ctx, req.Session, req.rows)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func topologyEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(topologyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.Topology(ctx, req.Session, req.filter)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func topologyGraphEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(topologyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.TopologyGraph(ctx, req.Session, req.filter)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code:    http.StatusOK,
+			html:    res,
+			headers: map[string]string{"Content-Type": jsonContentType},
+		}, nil
+	}
+}
+
+func groupTreeEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(groupTreeReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.GroupTree(ctx, req.Session, req.parentID, req.page, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func moveGroupEndpoint(svc ui.Service, prefix string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(moveGroupReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.MoveGroup(ctx, req.Session, req.groupID, req.parentID); err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code:    http.StatusSeeOther,
+			headers: map[string]string{"Location": fmt.Sprintf("%s/%s/tree", prefix, groupsAPIEndpoint)},
+		}, nil
+	}
+}
+
+func hygieneReportEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(hygieneReportReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.HygieneReport(ctx, req.Session)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func hygieneCleanupEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(hygieneCleanupReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.HygieneCleanup(ctx, req.Session, req.cleanup)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func provisionWizardEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(provisionWizardReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.ProvisionWizard(req.Session)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func provisionThingEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(provisionThingReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.ProvisionThing(ctx, req.Session, req.provision)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func secretRotationsEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listEntityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.SecretRotations(ctx, req.Session, req.page, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func rotateThingSecretsEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(rotateThingSecretsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.RotateThingSecrets(ctx, req.Session, req.thingIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func createRotationScheduleEndpoint(svc ui.Service, prefix string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createRotationScheduleReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.CreateRotationSchedule(ctx, req.Session, req.password, req.schedule); err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code:    http.StatusSeeOther,
+			headers: map[string]string{"Location": fmt.Sprintf("%s/%s/rotations", prefix, thingsAPIEndpoint)},
+		}, nil
+	}
+}
+
+func deleteRotationScheduleEndpoint(svc ui.Service, prefix string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(deleteRotationScheduleReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.DeleteRotationSchedule(ctx, req.Session, req.id); err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code:    http.StatusSeeOther,
+			headers: map[string]string{"Location": fmt.Sprintf("%s/%s/rotations", prefix, thingsAPIEndpoint)},
+		}, nil
+	}
+}
+
+func downloadRotatedSecretsEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(downloadRotatedSecretsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.DownloadRotatedSecrets(ctx, req.Session, req.runID)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+			headers: map[string]string{
+				"Content-Type":        csvContentType,
+				"Content-Disposition": fmt.Sprintf("attachment; filename=thing-secrets-%s.csv", req.runID),
+			},
+		}, nil
+	}
+}
+
+func searchEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(searchReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		url, err := svc.FindEntity(ctx, req.Session, req.query)
+		if err != nil {
+			return nil, err
+		}
+		if url != "" {
+			return uiRes{
+				code:    http.StatusSeeOther,
+				headers: map[string]string{"Location": url},
+			}, nil
+		}
+
+		res, err := svc.Search(ctx, req.Session, req.query)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func bulkActionEndpoint(svc ui.Service, entity string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(bulkActionReq)
+		req.action.Entity = entity
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.BulkAction(ctx, req.Session, req.action)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func exportEntitiesEndpoint(svc ui.Service, entity string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportEntitiesReq)
+		req.export.Entity = entity
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		return streamRes{
+			headers: map[string]string{
+				"Content-Type":        csvContentType,
+				"Content-Disposition": fmt.Sprintf("attachment; filename=%s-%s.csv", entity, time.Now().UTC().Format(expiryDateFormat)),
+			},
+			write: func(w io.Writer) error {
+				return svc.ExportEntities(ctx, req.Session, req.export, w)
+			},
+		}, nil
+	}
+}
+
+func batchTerminalEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(indexReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.BatchTerminal(req.Session)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func runBatchCommandEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(batchCommandReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.RunBatchCommand(ctx, req.Session, req.batch)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func bootstrapTemplatesEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listEntityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.BootstrapTemplates(ctx, req.Session, req.page, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func createBootstrapTemplateEndpoint(svc ui.Service, prefix string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createBootstrapTemplateReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.CreateBootstrapTemplate(ctx, req.Session, req.tpl); err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code:    http.StatusSeeOther,
+			headers: map[string]string{"Location": fmt.Sprintf("%s/%s/templates", prefix, bootstrapAPIEndpoint)},
+		}, nil
+	}
+}
+
+func updateBootstrapTemplateEndpoint(svc ui.Service, prefix string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateBootstrapTemplateReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UpdateBootstrapTemplate(ctx, req.Session, req.tpl); err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code:    http.StatusSeeOther,
+			headers: map[string]string{"Location": fmt.Sprintf("%s/%s/templates", prefix, bootstrapAPIEndpoint)},
+		}, nil
+	}
+}
+
+func deleteBootstrapTemplateEndpoint(svc ui.Service, prefix string) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(deleteBootstrapTemplateReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.DeleteBootstrapTemplate(ctx, req.Session, req.id); err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code:    http.StatusSeeOther,
+			headers: map[string]string{"Location": fmt.Sprintf("%s/%s/templates", prefix, bootstrapAPIEndpoint)},
+		}, nil
+	}
+}
+
+func provisionBootstrapsEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(provisionBootstrapsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.ProvisionBootstraps(ctx, req.Session, req.templateID, req.rows)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func expiringCertsEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(expiringCertsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.ExpiringCertificates(ctx, req.Session, time.Duration(req.days)*24*time.Hour)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func bootstrapBundleEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(indexReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.BootstrapBundle(req.Session)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func exportBundleEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportBundleReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.ExportBundle(ctx, req.Session, req.export)
+		if err != nil {
+			return nil, err
+		}
+
+		contentType := jsonContentType
+		if req.export.Format == ui.BundleYAML {
+			contentType = yamlContentType
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+			headers: map[string]string{
+				"Content-Type":        contentType,
+				"Content-Disposition": fmt.Sprintf("attachment; filename=bootstrap-bundle-%s.%s", time.Now().UTC().Format(expiryDateFormat), req.export.Format),
+			},
+		}, nil
+	}
+}
+
+func importBundleEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(importBundleReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.ImportBundle(ctx, req.Session, req.bundle, req.opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func bootstrapStatesEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(bootstrapStatesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.BootstrapStates(req.Session, req.filter)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
+
+func updateBootstrapStatesEndpoint(svc ui.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateBootstrapStatesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		res, err := svc.UpdateBootstrapStates(ctx, req.Session, req.change)
+		if err != nil {
+			return nil, err
+		}
+
+		return uiRes{
+			code: http.StatusOK,
+			html: res,
+		}, nil
+	}
+}
@@ -6,50 +6,95 @@ package api
 import "github.com/absmach/magistrala/pkg/errors"
 
 var (
-	errInvalidCredentials     = errors.New("missing or invalid credentials provided")
-	errAuthentication         = errors.New("failed to perform authentication over the entity")
-	errAuthorization          = errors.New("failed to perform authorization over the entity")
-	errMissingSecret          = errors.New("missing secret")
-	errMissingIdentity        = errors.New("missing entity identity")
-	errLimitSize              = errors.New("invalid limit size")
-	errMissingConfigID        = errors.New("missing config id")
-	errPageSize               = errors.New("invalid page size")
-	errMissingEmail           = errors.New("missing email")
-	errMissingName            = errors.New("missing name")
-	errMissingPassword        = errors.New("missing password")
-	errMissingMetadata        = errors.New("missing entity metadata")
-	errMissingConfirmPassword = errors.New("missing confirm password")
-	errInvalidResetPassword   = errors.New("invalid reset password")
-	errNameSize               = errors.New("invalid name size")
-	errBearerKey              = errors.New("missing or invalid bearer entity key")
-	errMissingThingID         = errors.New("missing thing id")
-	errMissingItem            = errors.New("missing item")
-	errMissingChannelID       = errors.New("missing channel id")
-	errMissingUserID          = errors.New("missing user id")
-	errMissingRelation        = errors.New("missing relation")
-	errMissingGroupID         = errors.New("missing group id")
-	errMissingParentID        = errors.New("missing parent id")
-	errMissingDescription     = errors.New("missing description")
-	errMissingThingKey        = errors.New("missing thing key")
-	errMissingExternalID      = errors.New("missing external id")
-	errMissingExternalKey     = errors.New("missing external key")
-	errMissingChannel         = errors.New("missing channel")
-	errMissingPayload         = errors.New("missing payload")
-	errMissingError           = errors.New("missing error")
-	errMissingRefreshToken    = errors.New("missing refresh token")
-	errMissingRef             = errors.New("missing ref")
-	errInvalidQueryParams     = errors.New("invalid query parameters")
-	errInvalidFormValue       = errors.New("invalid form values")
-	errFileFormat             = errors.New("invalid file format")
-	errInvalidFile            = errors.New("unsupported file type")
-	errMissingDomainID        = errors.New("missing domain id")
-	errMissingRole            = errors.New("missing role")
-	errMissingValue           = errors.New("missing value")
-	errCookieDecryption       = errors.New("failed to decrypt the cookie")
-	errCookieEncrypt          = errors.New("failed to encrypt the cookie")
-	errMissingFrom            = errors.New("missing from time value")
-	errMissingTo              = errors.New("missing to time value")
-	errInvalidAggregation     = errors.New("invalid aggregation value")
-	errInvalidInterval        = errors.New("invalid interval value")
-	errParseToken             = errors.New("failed to parse token")
+	errInvalidCredentials      = errors.New("missing or invalid credentials provided")
+	errAuthentication          = errors.New("failed to perform authentication over the entity")
+	errAuthorization           = errors.New("failed to perform authorization over the entity")
+	errMissingSecret           = errors.New("missing secret")
+	errMissingIdentity         = errors.New("missing entity identity")
+	errLimitSize               = errors.New("invalid limit size")
+	errMissingConfigID         = errors.New("missing config id")
+	errPageSize                = errors.New("invalid page size")
+	errMissingEmail            = errors.New("missing email")
+	errMissingName             = errors.New("missing name")
+	errMissingPassword         = errors.New("missing password")
+	errMissingMetadata         = errors.New("missing entity metadata")
+	errMissingConfirmPassword  = errors.New("missing confirm password")
+	errInvalidResetPassword    = errors.New("invalid reset password")
+	errNameSize                = errors.New("invalid name size")
+	errBearerKey               = errors.New("missing or invalid bearer entity key")
+	errMissingThingID          = errors.New("missing thing id")
+	errMissingItem             = errors.New("missing item")
+	errMissingChannelID        = errors.New("missing channel id")
+	errMissingUserID           = errors.New("missing user id")
+	errMissingRelation         = errors.New("missing relation")
+	errMissingGroupID          = errors.New("missing group id")
+	errMissingParentID         = errors.New("missing parent id")
+	errMissingDescription      = errors.New("missing description")
+	errMissingThingKey         = errors.New("missing thing key")
+	errMissingExternalID       = errors.New("missing external id")
+	errMissingExternalKey      = errors.New("missing external key")
+	errMissingChannel          = errors.New("missing channel")
+	errMissingPayload          = errors.New("missing payload")
+	errMissingError            = errors.New("missing error")
+	errMissingRefreshToken     = errors.New("missing refresh token")
+	errMissingRef              = errors.New("missing ref")
+	errInvalidQueryParams      = errors.New("invalid query parameters")
+	errInvalidFormValue        = errors.New("invalid form values")
+	errFileFormat              = errors.New("invalid file format")
+	errInvalidFile             = errors.New("unsupported file type")
+	errMissingDomainID         = errors.New("missing domain id")
+	errMissingRole             = errors.New("missing role")
+	errMissingValue            = errors.New("missing value")
+	errCookieDecryption        = errors.New("failed to decrypt the cookie")
+	errCookieEncrypt           = errors.New("failed to encrypt the cookie")
+	errMissingFrom             = errors.New("missing from time value")
+	errMissingTo               = errors.New("missing to time value")
+	errInvalidAggregation      = errors.New("invalid aggregation value")
+	errInvalidInterval         = errors.New("invalid interval value")
+	errParseToken              = errors.New("failed to parse token")
+	errMissingScope            = errors.New("missing token scope")
+	errInvalidScope            = errors.New("invalid token scope")
+	errInvalidExpiry           = errors.New("invalid token expiry")
+	errMissingTokenID          = errors.New("missing token id")
+	errInvalidSameSite         = errors.New("invalid cookie SameSite mode")
+	errInsecureSameSite        = errors.New("cookies with SameSite=None must be secure")
+	errMissingCode             = errors.New("missing two-factor authentication code")
+	errTwoFactorSession        = errors.New("two-factor login session expired, please log in again")
+	errMissingCommand          = errors.New("missing terminal command")
+	errMissingCommandID        = errors.New("missing terminal command id")
+	errCommandRunning          = errors.New("a terminal command with the same id is already running")
+	errTooManyCommands         = errors.New("too many terminal commands running, wait for one to finish")
+	errTerminalSessionExpired  = errors.New("session expired")
+	errInvalidTerminalAction   = errors.New("invalid terminal action")
+	errHijack                  = errors.New("response writer does not support hijacking")
+	errStreamAborted           = errors.New("streamed response aborted")
+	errInvalidTimeRange        = errors.New("the start of the time range must not be after its end")
+	errMissingTargets          = errors.New("missing batch command targets")
+	errTooManyTargets          = errors.New("too many batch command targets")
+	errInvalidState            = errors.New("invalid bootstrap state")
+	errInvalidTimeout          = errors.New("invalid batch command timeout")
+	errInvalidConcurrency      = errors.New("invalid batch command concurrency")
+	errTooManyPatterns         = errors.New("too many terminal policy patterns")
+	errPatternSize             = errors.New("invalid terminal policy pattern size")
+	errMissingTemplateID       = errors.New("missing bootstrap template id")
+	errMissingContent          = errors.New("missing bootstrap template content")
+	errTooManyRows             = errors.New("too many rows")
+	errInvalidExpiryWindow     = errors.New("invalid certificate expiry window")
+	errMissingConfigs          = errors.New("missing bootstrap configs")
+	errTooManyConfigs          = errors.New("too many bootstrap configs")
+	errInvalidBundleFormat     = errors.New("invalid bundle format")
+	errInvalidRemap            = errors.New("invalid id remapping")
+	errInvalidEntity           = errors.New("invalid entity")
+	errInvalidStatus           = errors.New("invalid status")
+	errInvalidBulkAction       = errors.New("invalid bulk action")
+	errMissingTag              = errors.New("missing tag")
+	errMissingEntities         = errors.New("missing entity ids or filter")
+	errTooManyEntities         = errors.New("too many entities")
+	errQuerySize               = errors.New("invalid search query size")
+	errInvalidCategory         = errors.New("invalid hygiene category")
+	errMissingScheduleID       = errors.New("missing rotation schedule id")
+	errMissingRunID            = errors.New("missing rotation run id")
+	errInvalidRotationInterval = errors.New("invalid secret rotation interval")
+	errTooManyChannels         = errors.New("too many channels")
+	errMissingValueName        = errors.New("missing dashboard value name")
 )
@@ -5,6 +5,7 @@ package api
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"time"
 
@@ -208,6 +209,20 @@ func (lm *loggingMiddleware) Session(s ui.Session) (session ui.Session, err erro
 	return lm.svc.Session(s)
 }
 
+// SessionExpired adds logging middleware to session expired method.
+func (lm *loggingMiddleware) SessionExpired() (b []byte, err error) {
+	defer func(begin time.Time) {
+		duration := slog.String("duration", time.Since(begin).String())
+		if err != nil {
+			lm.logger.Warn("View session expired page failed to complete successfully", slog.Any("error", err), duration)
+			return
+		}
+		lm.logger.Info("View session expired page completed successfully", duration)
+	}(time.Now())
+
+	return lm.svc.SessionExpired()
+}
+
 // CreateUsers adds logging middleware to create users method.
 func (lm *loggingMiddleware) CreateUsers(token string, users ...sdk.User) (err error) {
 	defer func(begin time.Time) {
@@ -1250,6 +1265,24 @@ func (lm *loggingMiddleware) UpdateBootstrap(token string, config sdk.BootstrapC
 	return lm.svc.UpdateBootstrap(token, config)
 }
 
+// BootstrapContentDiff adds logging middleware to bootstrap content diff method.
+func (lm *loggingMiddleware) BootstrapContentDiff(s ui.Session, thingID, content string) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("thing_id", thingID),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Bootstrap content diff failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Bootstrap content diff completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.BootstrapContentDiff(s, thingID, content)
+}
+
 // UpdateBootstrapConnections adds logging middleware to update bootstrap connections method.
 func (lm *loggingMiddleware) UpdateBootstrapConnections(token string, config sdk.BootstrapConfig) (err error) {
 	defer func(begin time.Time) {
@@ -1359,22 +1392,43 @@ func (lm *loggingMiddleware) GetRemoteTerminal(s ui.Session, thingID string) (re
 	return lm.svc.GetRemoteTerminal(s, thingID)
 }
 
-// ProcessTerminalCommand adds logging middleware to async function ProcessTerminalCommand.
-func (lm *loggingMiddleware) ProcessTerminalCommand(ctx context.Context, thingID, token, command string, res chan string) (err error) {
+// OpenTerminal adds logging middleware to open terminal method.
+func (lm *loggingMiddleware) OpenTerminal(ctx context.Context, s ui.Session, thingID, channelID string) (term *ui.Terminal, err error) {
 	defer func(begin time.Time) {
 		args := []any{
 			slog.String("duration", time.Since(begin).String()),
 			slog.String("thing_id", thingID),
+			slog.String("channel_id", channelID),
+			slog.String("user_id", s.User.ID),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Open terminal failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Open terminal completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.OpenTerminal(ctx, s, thingID, channelID)
+}
+
+// ProcessTerminalCommand adds logging middleware to process terminal command method.
+func (lm *loggingMiddleware) ProcessTerminalCommand(ctx context.Context, s ui.Session, term *ui.Terminal, command string) (res string, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("thing_id", term.ThingID),
 			slog.String("command", command),
 		}
 		if err != nil {
 			args = append(args, slog.Any("error", err))
 			lm.logger.Warn("Process terminal command failed to complete successfully", args...)
+			return
 		}
 		lm.logger.Info("Process terminal command completed successfully", args...)
 	}(time.Now())
 
-	return lm.svc.ProcessTerminalCommand(ctx, thingID, token, command, res)
+	return lm.svc.ProcessTerminalCommand(ctx, s, term, command)
 }
 
 // GetEntities adds logging middleware to get entities method.
@@ -1486,7 +1540,7 @@ func (lm *loggingMiddleware) UpdateDomain(token string, domain sdk.Domain) (err
 }
 
 // Domain adds logging middleware to domain method.
-func (lm *loggingMiddleware) Domain(s ui.Session) (b []byte, err error) {
+func (lm *loggingMiddleware) Domain(ctx context.Context, s ui.Session) (b []byte, err error) {
 	defer func(begin time.Time) {
 		args := []any{
 			slog.String("duration", time.Since(begin).String()),
@@ -1499,7 +1553,7 @@ func (lm *loggingMiddleware) Domain(s ui.Session) (b []byte, err error) {
 		lm.logger.Info("View domain completed successfully", args...)
 	}(time.Now())
 
-	return lm.svc.Domain(s)
+	return lm.svc.Domain(ctx, s)
 }
 
 // EnableDomain adds logging middleware to enable domain method.
@@ -1792,3 +1846,932 @@ func (lm *loggingMiddleware) DeleteDashboard(ctx context.Context, token, dashboa
 
 	return lm.svc.DeleteDashboard(ctx, token, dashboardID)
 }
+
+// PersonalTokens adds logging middleware to personal tokens method.
+func (lm *loggingMiddleware) PersonalTokens(ctx context.Context, s ui.Session, page, limit uint64) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.Uint64("page", page),
+			slog.Uint64("limit", limit),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("List personal tokens failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("List personal tokens completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.PersonalTokens(ctx, s, page, limit)
+}
+
+// CreatePersonalToken adds logging middleware to create personal token method.
+func (lm *loggingMiddleware) CreatePersonalToken(ctx context.Context, s ui.Session, password string, ptReq ui.PersonalTokenReq) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("name", ptReq.Name),
+			slog.Any("scopes", ptReq.Scopes),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Create personal token failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Create personal token completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.CreatePersonalToken(ctx, s, password, ptReq)
+}
+
+// RevokePersonalToken adds logging middleware to revoke personal token method.
+func (lm *loggingMiddleware) RevokePersonalToken(ctx context.Context, s ui.Session, id string) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("token_id", id),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Revoke personal token failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Revoke personal token completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.RevokePersonalToken(ctx, s, id)
+}
+
+// AuthenticatePersonalToken adds logging middleware to authenticate personal token method.
+func (lm *loggingMiddleware) AuthenticatePersonalToken(ctx context.Context, token string, scope ui.TokenScope) (s ui.Session, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("scope", string(scope)),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Authenticate personal token failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Authenticate personal token completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.AuthenticatePersonalToken(ctx, token, scope)
+}
+
+// RefreshPersonalTokens adds logging middleware to refresh personal tokens method.
+func (lm *loggingMiddleware) RefreshPersonalTokens(ctx context.Context, now time.Time, tokenMaxAge time.Duration) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Refresh personal tokens failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Debug("Refresh personal tokens completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.RefreshPersonalTokens(ctx, now, tokenMaxAge)
+}
+
+// TwoFactor adds logging middleware to two-factor authentication method.
+func (lm *loggingMiddleware) TwoFactor(ctx context.Context, s ui.Session) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("View two-factor authentication failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("View two-factor authentication completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.TwoFactor(ctx, s)
+}
+
+// EnrollTwoFactor adds logging middleware to enroll two-factor authentication method.
+func (lm *loggingMiddleware) EnrollTwoFactor(ctx context.Context, s ui.Session) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Enroll two-factor authentication failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Enroll two-factor authentication completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.EnrollTwoFactor(ctx, s)
+}
+
+// ConfirmTwoFactor adds logging middleware to confirm two-factor authentication method.
+func (lm *loggingMiddleware) ConfirmTwoFactor(ctx context.Context, s ui.Session, code string) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Confirm two-factor authentication failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Confirm two-factor authentication completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ConfirmTwoFactor(ctx, s, code)
+}
+
+// DisableTwoFactor adds logging middleware to disable two-factor authentication method.
+func (lm *loggingMiddleware) DisableTwoFactor(ctx context.Context, s ui.Session, code string) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Disable two-factor authentication failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Disable two-factor authentication completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.DisableTwoFactor(ctx, s, code)
+}
+
+// TwoFactorEnabled adds logging middleware to two-factor authentication enabled method.
+func (lm *loggingMiddleware) TwoFactorEnabled(ctx context.Context, userID string) (enabled bool, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("user_id", userID),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Check two-factor authentication failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Check two-factor authentication completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.TwoFactorEnabled(ctx, userID)
+}
+
+// TwoFactorLogin adds logging middleware to two-factor login method.
+func (lm *loggingMiddleware) TwoFactorLogin() (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("View two-factor login failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("View two-factor login completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.TwoFactorLogin()
+}
+
+// VerifyTwoFactor adds logging middleware to verify two-factor authentication method.
+func (lm *loggingMiddleware) VerifyTwoFactor(ctx context.Context, userID, code string) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("user_id", userID),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Verify two-factor authentication failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Verify two-factor authentication completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.VerifyTwoFactor(ctx, userID, code)
+}
+
+// UpdateDomainTwoFactor adds logging middleware to update domain two-factor method.
+func (lm *loggingMiddleware) UpdateDomainTwoFactor(ctx context.Context, s ui.Session, domainID string, required bool) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("domain_id", domainID),
+			slog.Bool("required", required),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Update domain two-factor requirement failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Update domain two-factor requirement completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.UpdateDomainTwoFactor(ctx, s, domainID, required)
+}
+
+// CheckDomainTwoFactor adds logging middleware to check domain two-factor method.
+func (lm *loggingMiddleware) CheckDomainTwoFactor(ctx context.Context, domainID, userID string) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("domain_id", domainID),
+			slog.String("user_id", userID),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Check domain two-factor requirement failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Check domain two-factor requirement completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.CheckDomainTwoFactor(ctx, domainID, userID)
+}
+
+// TerminalAudit adds logging middleware to terminal audit method.
+func (lm *loggingMiddleware) TerminalAudit(ctx context.Context, s ui.Session, page, limit uint64, pm ui.TerminalCommandPageMeta) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.Uint64("page", page),
+			slog.Uint64("limit", limit),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("List terminal commands failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("List terminal commands completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.TerminalAudit(ctx, s, page, limit, pm)
+}
+
+// ExportTerminalAudit adds logging middleware to export terminal audit method.
+func (lm *loggingMiddleware) ExportTerminalAudit(ctx context.Context, s ui.Session, pm ui.TerminalCommandPageMeta) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Export terminal commands failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Export terminal commands completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ExportTerminalAudit(ctx, s, pm)
+}
+
+// BatchTerminal adds logging middleware to batch terminal method.
+func (lm *loggingMiddleware) BatchTerminal(s ui.Session) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Batch terminal failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Batch terminal completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.BatchTerminal(s)
+}
+
+// RunBatchCommand adds logging middleware to run batch command method.
+func (lm *loggingMiddleware) RunBatchCommand(ctx context.Context, s ui.Session, batch ui.BatchCommand) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.Group("batch",
+				slog.Int("things", len(batch.ThingIDs)),
+				slog.String("tag", batch.Tag),
+				slog.String("state", batch.State),
+				slog.String("timeout", batch.Timeout.String()),
+				slog.Int("concurrency", batch.Concurrency),
+			),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Run batch command failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Run batch command completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.RunBatchCommand(ctx, s, batch)
+}
+
+// UpdateTerminalPolicy adds logging middleware to update terminal policy method.
+func (lm *loggingMiddleware) UpdateTerminalPolicy(ctx context.Context, s ui.Session, policy ui.TerminalPolicy) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.Group("policy",
+				slog.String("domain_id", policy.DomainID),
+				slog.Bool("admin_only", policy.AdminOnly),
+				slog.Bool("read_only", policy.ReadOnly),
+				slog.Any("allow", policy.Allow),
+				slog.Any("deny", policy.Deny),
+			),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Update terminal policy failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Update terminal policy completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.UpdateTerminalPolicy(ctx, s, policy)
+}
+
+// BootstrapTemplates adds logging middleware to bootstrap templates method.
+func (lm *loggingMiddleware) BootstrapTemplates(ctx context.Context, s ui.Session, page, limit uint64) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.Uint64("page", page),
+			slog.Uint64("limit", limit),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Bootstrap templates failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Bootstrap templates completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.BootstrapTemplates(ctx, s, page, limit)
+}
+
+// CreateBootstrapTemplate adds logging middleware to create bootstrap template method.
+func (lm *loggingMiddleware) CreateBootstrapTemplate(ctx context.Context, s ui.Session, tpl ui.BootstrapTemplate) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("name", tpl.Name),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Create bootstrap template failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Create bootstrap template completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.CreateBootstrapTemplate(ctx, s, tpl)
+}
+
+// UpdateBootstrapTemplate adds logging middleware to update bootstrap template method.
+func (lm *loggingMiddleware) UpdateBootstrapTemplate(ctx context.Context, s ui.Session, tpl ui.BootstrapTemplate) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("id", tpl.ID),
+			slog.String("name", tpl.Name),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Update bootstrap template failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Update bootstrap template completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.UpdateBootstrapTemplate(ctx, s, tpl)
+}
+
+// DeleteBootstrapTemplate adds logging middleware to delete bootstrap template method.
+func (lm *loggingMiddleware) DeleteBootstrapTemplate(ctx context.Context, s ui.Session, id string) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("id", id),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Delete bootstrap template failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Delete bootstrap template completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.DeleteBootstrapTemplate(ctx, s, id)
+}
+
+// ProvisionBootstraps adds logging middleware to provision bootstraps method.
+func (lm *loggingMiddleware) ProvisionBootstraps(ctx context.Context, s ui.Session, templateID string, rows []ui.BootstrapRow) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("template_id", templateID),
+			slog.Int("rows", len(rows)),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Provision bootstraps failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Provision bootstraps completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ProvisionBootstraps(ctx, s, templateID, rows)
+}
+
+// ExpiringCertificates adds logging middleware to expiring certificates method.
+func (lm *loggingMiddleware) ExpiringCertificates(ctx context.Context, s ui.Session, within time.Duration) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("within", within.String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Expiring certificates failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Expiring certificates completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ExpiringCertificates(ctx, s, within)
+}
+
+// BootstrapBundle adds logging middleware to bootstrap bundle method.
+func (lm *loggingMiddleware) BootstrapBundle(s ui.Session) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Bootstrap bundle failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Bootstrap bundle completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.BootstrapBundle(s)
+}
+
+// ExportBundle adds logging middleware to export bundle method.
+func (lm *loggingMiddleware) ExportBundle(ctx context.Context, s ui.Session, export ui.BundleExport) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.Int("configs", len(export.ThingIDs)),
+			slog.String("format", export.Format),
+			slog.Bool("redact", export.Redact),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Export bundle failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Export bundle completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ExportBundle(ctx, s, export)
+}
+
+// ImportBundle adds logging middleware to import bundle method.
+func (lm *loggingMiddleware) ImportBundle(ctx context.Context, s ui.Session, bundle ui.Bundle, opts ui.BundleImport) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.Int("channels", len(bundle.Channels)),
+			slog.Int("configs", len(bundle.Configs)),
+			slog.Bool("dry_run", opts.DryRun),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Import bundle failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Import bundle completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ImportBundle(ctx, s, bundle, opts)
+}
+
+// BootstrapStates adds logging middleware to bootstrap states method.
+func (lm *loggingMiddleware) BootstrapStates(s ui.Session, filter ui.BootstrapStateFilter) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("state", filter.State),
+			slog.String("external_id", filter.ExternalID),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Bootstrap states failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Bootstrap states completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.BootstrapStates(s, filter)
+}
+
+// UpdateBootstrapStates adds logging middleware to update bootstrap states method.
+func (lm *loggingMiddleware) UpdateBootstrapStates(ctx context.Context, s ui.Session, change ui.BootstrapStateChange) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("state", change.State),
+			slog.Int("things", len(change.ThingIDs)),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Update bootstrap states failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Update bootstrap states completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.UpdateBootstrapStates(ctx, s, change)
+}
+
+// ExportEntities adds logging middleware to export entities method.
+func (lm *loggingMiddleware) ExportEntities(ctx context.Context, s ui.Session, export ui.EntityExport, w io.Writer) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("entity", export.Entity),
+			slog.String("status", export.Status),
+			slog.Bool("secrets", export.Secrets),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Export entities failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Export entities completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ExportEntities(ctx, s, export, w)
+}
+
+// ImportEntities adds logging middleware to import entities method.
+func (lm *loggingMiddleware) ImportEntities(ctx context.Context, s ui.Session, imp ui.EntityImport) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("entity", imp.Entity),
+			slog.Int("rows", len(imp.Records)),
+			slog.Bool("dry_run", imp.DryRun),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Import entities failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Import entities completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ImportEntities(ctx, s, imp)
+}
+
+// BulkConnect adds logging middleware to bulk connect method.
+func (lm *loggingMiddleware) BulkConnect(ctx context.Context, s ui.Session, rows []ui.ConnectionRow) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.Int("rows", len(rows)),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Bulk connect failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Bulk connect completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.BulkConnect(ctx, s, rows)
+}
+
+// BulkAction adds logging middleware to bulk action method.
+func (lm *loggingMiddleware) BulkAction(ctx context.Context, s ui.Session, action ui.EntityBulkAction) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("entity", action.Entity),
+			slog.String("action", action.Action),
+			slog.Int("ids", len(action.IDs)),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Bulk action failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Bulk action completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.BulkAction(ctx, s, action)
+}
+
+// Search adds logging middleware to search method.
+func (lm *loggingMiddleware) Search(ctx context.Context, s ui.Session, query string) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Search failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Search completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.Search(ctx, s, query)
+}
+
+// FindEntity adds logging middleware to find entity method.
+func (lm *loggingMiddleware) FindEntity(ctx context.Context, s ui.Session, query string) (url string, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.Bool("found", url != ""),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Find entity failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Find entity completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.FindEntity(ctx, s, query)
+}
+
+// Topology adds logging middleware to topology method.
+func (lm *loggingMiddleware) Topology(ctx context.Context, s ui.Session, filter ui.TopologyFilter) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Topology failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Topology completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.Topology(ctx, s, filter)
+}
+
+// TopologyGraph adds logging middleware to topology graph method.
+func (lm *loggingMiddleware) TopologyGraph(ctx context.Context, s ui.Session, filter ui.TopologyFilter) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("group_id", filter.GroupID),
+			slog.String("tag", filter.Tag),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Topology graph failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Topology graph completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.TopologyGraph(ctx, s, filter)
+}
+
+// GroupTree adds logging middleware to group tree method.
+func (lm *loggingMiddleware) GroupTree(ctx context.Context, s ui.Session, parentID string, page, limit uint64) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("parent_id", parentID),
+			slog.Uint64("page", page),
+			slog.Uint64("limit", limit),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Group tree failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Group tree completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.GroupTree(ctx, s, parentID, page, limit)
+}
+
+// MoveGroup adds logging middleware to move group method.
+func (lm *loggingMiddleware) MoveGroup(ctx context.Context, s ui.Session, groupID, parentID string) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("group_id", groupID),
+			slog.String("parent_id", parentID),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Move group failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Move group completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.MoveGroup(ctx, s, groupID, parentID)
+}
+
+// HygieneReport adds logging middleware to hygiene report method.
+func (lm *loggingMiddleware) HygieneReport(ctx context.Context, s ui.Session) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Hygiene report failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Hygiene report completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.HygieneReport(ctx, s)
+}
+
+// HygieneCleanup adds logging middleware to hygiene cleanup method.
+func (lm *loggingMiddleware) HygieneCleanup(ctx context.Context, s ui.Session, cleanup ui.HygieneCleanup) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("category", cleanup.Category),
+			slog.String("action", cleanup.Action),
+			slog.Int("entities", len(cleanup.IDs)),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Hygiene cleanup failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Hygiene cleanup completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.HygieneCleanup(ctx, s, cleanup)
+}
+
+// SecretRotations adds logging middleware to secret rotations method.
+func (lm *loggingMiddleware) SecretRotations(ctx context.Context, s ui.Session, page, limit uint64) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.Uint64("page", page),
+			slog.Uint64("limit", limit),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Secret rotations failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Secret rotations completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.SecretRotations(ctx, s, page, limit)
+}
+
+// RotateThingSecrets adds logging middleware to rotate thing secrets method.
+func (lm *loggingMiddleware) RotateThingSecrets(ctx context.Context, s ui.Session, thingIDs []string) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.Int("things", len(thingIDs)),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Rotate thing secrets failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Rotate thing secrets completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.RotateThingSecrets(ctx, s, thingIDs)
+}
+
+// CreateRotationSchedule adds logging middleware to create rotation schedule method.
+func (lm *loggingMiddleware) CreateRotationSchedule(ctx context.Context, s ui.Session, password string, schedule ui.RotationSchedule) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("tag", schedule.Tag),
+			slog.String("interval", schedule.Interval.String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Create rotation schedule failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Create rotation schedule completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.CreateRotationSchedule(ctx, s, password, schedule)
+}
+
+// DeleteRotationSchedule adds logging middleware to delete rotation schedule method.
+func (lm *loggingMiddleware) DeleteRotationSchedule(ctx context.Context, s ui.Session, id string) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("schedule_id", id),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Delete rotation schedule failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Delete rotation schedule completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.DeleteRotationSchedule(ctx, s, id)
+}
+
+// DownloadRotatedSecrets adds logging middleware to download rotated secrets method.
+func (lm *loggingMiddleware) DownloadRotatedSecrets(ctx context.Context, s ui.Session, runID string) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("run_id", runID),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Download rotated secrets failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Download rotated secrets completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.DownloadRotatedSecrets(ctx, s, runID)
+}
+
+// RunRotationSchedules adds logging middleware to run rotation schedules method.
+func (lm *loggingMiddleware) RunRotationSchedules(ctx context.Context, now time.Time, tokenMaxAge time.Duration) (err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Run rotation schedules failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Debug("Run rotation schedules completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.RunRotationSchedules(ctx, now, tokenMaxAge)
+}
+
+// ProvisionWizard adds logging middleware to provision wizard method.
+func (lm *loggingMiddleware) ProvisionWizard(s ui.Session) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Provision wizard failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Provision wizard completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ProvisionWizard(s)
+}
+
+// ProvisionThing adds logging middleware to provision thing method.
+func (lm *loggingMiddleware) ProvisionThing(ctx context.Context, s ui.Session, p ui.ThingProvision) (b []byte, err error) {
+	defer func(begin time.Time) {
+		args := []any{
+			slog.String("duration", time.Since(begin).String()),
+			slog.String("name", p.Name),
+			slog.Int("channels", len(p.ChannelIDs)+len(p.ChannelNames)),
+			slog.Bool("bootstrap", p.Bootstrap),
+			slog.Bool("dashboard", p.Dashboard),
+		}
+		if err != nil {
+			args = append(args, slog.Any("error", err))
+			lm.logger.Warn("Provision thing failed to complete successfully", args...)
+			return
+		}
+		lm.logger.Info("Provision thing completed successfully", args...)
+	}(time.Now())
+
+	return lm.svc.ProvisionThing(ctx, s, p)
+}
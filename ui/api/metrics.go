@@ -5,6 +5,7 @@ package api
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/absmach/magistrala-ui/ui"
@@ -159,6 +160,16 @@ func (mm *metricsMiddleware) Session(s ui.Session) (ui.Session, error) {
 	return mm.svc.Session(s)
 }
 
+// SessionExpired adds metrics middleware to session expired method.
+func (mm *metricsMiddleware) SessionExpired() ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "session_expired").Add(1)
+		mm.latency.With("method", "session_expired").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.SessionExpired()
+}
+
 // CreateUsers adds metrics middleware to create users method.
 func (mm *metricsMiddleware) CreateUsers(token string, users ...sdk.User) error {
 	defer func(begin time.Time) {
@@ -719,6 +730,16 @@ func (mm *metricsMiddleware) UpdateBootstrap(token string, config sdk.BootstrapC
 	return mm.svc.UpdateBootstrap(token, config)
 }
 
+// BootstrapContentDiff adds metrics middleware to bootstrap content diff method.
+func (mm *metricsMiddleware) BootstrapContentDiff(s ui.Session, thingID, content string) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "bootstrap_content_diff").Add(1)
+		mm.latency.With("method", "bootstrap_content_diff").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.BootstrapContentDiff(s, thingID, content)
+}
+
 // UpdateBootstrapConnections adds metrics middleware to  update bootstrap connections method.
 func (mm *metricsMiddleware) UpdateBootstrapConnections(token string, config sdk.BootstrapConfig) error {
 	defer func(begin time.Time) {
@@ -779,14 +800,24 @@ func (mm *metricsMiddleware) GetRemoteTerminal(s ui.Session, thingID string) ([]
 	return mm.svc.GetRemoteTerminal(s, thingID)
 }
 
+// OpenTerminal adds metrics middleware to open terminal method.
+func (mm *metricsMiddleware) OpenTerminal(ctx context.Context, s ui.Session, thingID, channelID string) (*ui.Terminal, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "open_terminal").Add(1)
+		mm.latency.With("method", "open_terminal").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.OpenTerminal(ctx, s, thingID, channelID)
+}
+
 // ProcessTerminalCommand adds metrics middleware to process terminal command method.
-func (mm *metricsMiddleware) ProcessTerminalCommand(ctx context.Context, thingID, token, command string, res chan string) error {
+func (mm *metricsMiddleware) ProcessTerminalCommand(ctx context.Context, s ui.Session, term *ui.Terminal, command string) (string, error) {
 	defer func(begin time.Time) {
-		mm.counter.With("method", "remote_terminal").Add(1)
-		mm.latency.With("method", "remote_terminal").Observe(time.Since(begin).Seconds())
+		mm.counter.With("method", "process_terminal_command").Add(1)
+		mm.latency.With("method", "process_terminal_command").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return mm.svc.ProcessTerminalCommand(ctx, thingID, token, command, res)
+	return mm.svc.ProcessTerminalCommand(ctx, s, term, command)
 }
 
 // GetEntities adds metrics middleware to get entities method.
@@ -850,13 +881,13 @@ func (mm *metricsMiddleware) UpdateDomain(token string, domain sdk.Domain) error
 }
 
 // Domain adds metrics middleware to domain method.
-func (mm *metricsMiddleware) Domain(s ui.Session) ([]byte, error) {
+func (mm *metricsMiddleware) Domain(ctx context.Context, s ui.Session) ([]byte, error) {
 	defer func(begin time.Time) {
 		mm.counter.With("method", "domain").Add(1)
 		mm.latency.With("method", "domain").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return mm.svc.Domain(s)
+	return mm.svc.Domain(ctx, s)
 }
 
 // EnableDomain adds metrics middleware to enable domain method.
@@ -1018,3 +1049,503 @@ func (mm *metricsMiddleware) DeleteDashboard(ctx context.Context, token string,
 
 	return mm.svc.DeleteDashboard(ctx, token, dashboardID)
 }
+
+// PersonalTokens adds metrics middleware to personal tokens method.
+func (mm *metricsMiddleware) PersonalTokens(ctx context.Context, s ui.Session, page, limit uint64) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "personal_tokens").Add(1)
+		mm.latency.With("method", "personal_tokens").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.PersonalTokens(ctx, s, page, limit)
+}
+
+// CreatePersonalToken adds metrics middleware to create personal token method.
+func (mm *metricsMiddleware) CreatePersonalToken(ctx context.Context, s ui.Session, password string, ptReq ui.PersonalTokenReq) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "create_personal_token").Add(1)
+		mm.latency.With("method", "create_personal_token").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.CreatePersonalToken(ctx, s, password, ptReq)
+}
+
+// RevokePersonalToken adds metrics middleware to revoke personal token method.
+func (mm *metricsMiddleware) RevokePersonalToken(ctx context.Context, s ui.Session, id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "revoke_personal_token").Add(1)
+		mm.latency.With("method", "revoke_personal_token").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RevokePersonalToken(ctx, s, id)
+}
+
+// AuthenticatePersonalToken adds metrics middleware to authenticate personal token method.
+func (mm *metricsMiddleware) AuthenticatePersonalToken(ctx context.Context, token string, scope ui.TokenScope) (ui.Session, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "authenticate_personal_token").Add(1)
+		mm.latency.With("method", "authenticate_personal_token").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.AuthenticatePersonalToken(ctx, token, scope)
+}
+
+// RefreshPersonalTokens adds metrics middleware to refresh personal tokens method.
+func (mm *metricsMiddleware) RefreshPersonalTokens(ctx context.Context, now time.Time, tokenMaxAge time.Duration) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "refresh_personal_tokens").Add(1)
+		mm.latency.With("method", "refresh_personal_tokens").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RefreshPersonalTokens(ctx, now, tokenMaxAge)
+}
+
+// TwoFactor adds metrics middleware to two-factor authentication method.
+func (mm *metricsMiddleware) TwoFactor(ctx context.Context, s ui.Session) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "two_factor").Add(1)
+		mm.latency.With("method", "two_factor").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.TwoFactor(ctx, s)
+}
+
+// EnrollTwoFactor adds metrics middleware to enroll two-factor authentication method.
+func (mm *metricsMiddleware) EnrollTwoFactor(ctx context.Context, s ui.Session) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "enroll_two_factor").Add(1)
+		mm.latency.With("method", "enroll_two_factor").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.EnrollTwoFactor(ctx, s)
+}
+
+// ConfirmTwoFactor adds metrics middleware to confirm two-factor authentication method.
+func (mm *metricsMiddleware) ConfirmTwoFactor(ctx context.Context, s ui.Session, code string) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "confirm_two_factor").Add(1)
+		mm.latency.With("method", "confirm_two_factor").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ConfirmTwoFactor(ctx, s, code)
+}
+
+// DisableTwoFactor adds metrics middleware to disable two-factor authentication method.
+func (mm *metricsMiddleware) DisableTwoFactor(ctx context.Context, s ui.Session, code string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "disable_two_factor").Add(1)
+		mm.latency.With("method", "disable_two_factor").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.DisableTwoFactor(ctx, s, code)
+}
+
+// TwoFactorEnabled adds metrics middleware to two-factor authentication enabled method.
+func (mm *metricsMiddleware) TwoFactorEnabled(ctx context.Context, userID string) (bool, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "two_factor_enabled").Add(1)
+		mm.latency.With("method", "two_factor_enabled").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.TwoFactorEnabled(ctx, userID)
+}
+
+// TwoFactorLogin adds metrics middleware to two-factor login method.
+func (mm *metricsMiddleware) TwoFactorLogin() ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "two_factor_login").Add(1)
+		mm.latency.With("method", "two_factor_login").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.TwoFactorLogin()
+}
+
+// VerifyTwoFactor adds metrics middleware to verify two-factor authentication method.
+func (mm *metricsMiddleware) VerifyTwoFactor(ctx context.Context, userID, code string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "verify_two_factor").Add(1)
+		mm.latency.With("method", "verify_two_factor").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.VerifyTwoFactor(ctx, userID, code)
+}
+
+// UpdateDomainTwoFactor adds metrics middleware to update domain two-factor method.
+func (mm *metricsMiddleware) UpdateDomainTwoFactor(ctx context.Context, s ui.Session, domainID string, required bool) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "update_domain_two_factor").Add(1)
+		mm.latency.With("method", "update_domain_two_factor").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.UpdateDomainTwoFactor(ctx, s, domainID, required)
+}
+
+// CheckDomainTwoFactor adds metrics middleware to check domain two-factor method.
+func (mm *metricsMiddleware) CheckDomainTwoFactor(ctx context.Context, domainID, userID string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "check_domain_two_factor").Add(1)
+		mm.latency.With("method", "check_domain_two_factor").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.CheckDomainTwoFactor(ctx, domainID, userID)
+}
+
+// TerminalAudit adds metrics middleware to terminal audit method.
+func (mm *metricsMiddleware) TerminalAudit(ctx context.Context, s ui.Session, page, limit uint64, pm ui.TerminalCommandPageMeta) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "terminal_audit").Add(1)
+		mm.latency.With("method", "terminal_audit").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.TerminalAudit(ctx, s, page, limit, pm)
+}
+
+// ExportTerminalAudit adds metrics middleware to export terminal audit method.
+func (mm *metricsMiddleware) ExportTerminalAudit(ctx context.Context, s ui.Session, pm ui.TerminalCommandPageMeta) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "export_terminal_audit").Add(1)
+		mm.latency.With("method", "export_terminal_audit").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ExportTerminalAudit(ctx, s, pm)
+}
+
+// BatchTerminal adds metrics middleware to batch terminal method.
+func (mm *metricsMiddleware) BatchTerminal(s ui.Session) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "batch_terminal").Add(1)
+		mm.latency.With("method", "batch_terminal").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.BatchTerminal(s)
+}
+
+// RunBatchCommand adds metrics middleware to run batch command method.
+func (mm *metricsMiddleware) RunBatchCommand(ctx context.Context, s ui.Session, batch ui.BatchCommand) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "run_batch_command").Add(1)
+		mm.latency.With("method", "run_batch_command").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RunBatchCommand(ctx, s, batch)
+}
+
+// UpdateTerminalPolicy adds metrics middleware to update terminal policy method.
+func (mm *metricsMiddleware) UpdateTerminalPolicy(ctx context.Context, s ui.Session, policy ui.TerminalPolicy) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "update_terminal_policy").Add(1)
+		mm.latency.With("method", "update_terminal_policy").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.UpdateTerminalPolicy(ctx, s, policy)
+}
+
+// BootstrapTemplates adds metrics middleware to bootstrap templates method.
+func (mm *metricsMiddleware) BootstrapTemplates(ctx context.Context, s ui.Session, page, limit uint64) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "bootstrap_templates").Add(1)
+		mm.latency.With("method", "bootstrap_templates").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.BootstrapTemplates(ctx, s, page, limit)
+}
+
+// CreateBootstrapTemplate adds metrics middleware to create bootstrap template method.
+func (mm *metricsMiddleware) CreateBootstrapTemplate(ctx context.Context, s ui.Session, tpl ui.BootstrapTemplate) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "create_bootstrap_template").Add(1)
+		mm.latency.With("method", "create_bootstrap_template").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.CreateBootstrapTemplate(ctx, s, tpl)
+}
+
+// UpdateBootstrapTemplate adds metrics middleware to update bootstrap template method.
+func (mm *metricsMiddleware) UpdateBootstrapTemplate(ctx context.Context, s ui.Session, tpl ui.BootstrapTemplate) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "update_bootstrap_template").Add(1)
+		mm.latency.With("method", "update_bootstrap_template").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.UpdateBootstrapTemplate(ctx, s, tpl)
+}
+
+// DeleteBootstrapTemplate adds metrics middleware to delete bootstrap template method.
+func (mm *metricsMiddleware) DeleteBootstrapTemplate(ctx context.Context, s ui.Session, id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "delete_bootstrap_template").Add(1)
+		mm.latency.With("method", "delete_bootstrap_template").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.DeleteBootstrapTemplate(ctx, s, id)
+}
+
+// ProvisionBootstraps adds metrics middleware to provision bootstraps method.
+func (mm *metricsMiddleware) ProvisionBootstraps(ctx context.Context, s ui.Session, templateID string, rows []ui.BootstrapRow) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "provision_bootstraps").Add(1)
+		mm.latency.With("method", "provision_bootstraps").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ProvisionBootstraps(ctx, s, templateID, rows)
+}
+
+// ExpiringCertificates adds metrics middleware to expiring certificates method.
+func (mm *metricsMiddleware) ExpiringCertificates(ctx context.Context, s ui.Session, within time.Duration) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "expiring_certificates").Add(1)
+		mm.latency.With("method", "expiring_certificates").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ExpiringCertificates(ctx, s, within)
+}
+
+// BootstrapBundle adds metrics middleware to bootstrap bundle method.
+func (mm *metricsMiddleware) BootstrapBundle(s ui.Session) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "bootstrap_bundle").Add(1)
+		mm.latency.With("method", "bootstrap_bundle").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.BootstrapBundle(s)
+}
+
+// ExportBundle adds metrics middleware to export bundle method.
+func (mm *metricsMiddleware) ExportBundle(ctx context.Context, s ui.Session, export ui.BundleExport) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "export_bundle").Add(1)
+		mm.latency.With("method", "export_bundle").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ExportBundle(ctx, s, export)
+}
+
+// ImportBundle adds metrics middleware to import bundle method.
+func (mm *metricsMiddleware) ImportBundle(ctx context.Context, s ui.Session, bundle ui.Bundle, opts ui.BundleImport) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "import_bundle").Add(1)
+		mm.latency.With("method", "import_bundle").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ImportBundle(ctx, s, bundle, opts)
+}
+
+// BootstrapStates adds metrics middleware to bootstrap states method.
+func (mm *metricsMiddleware) BootstrapStates(s ui.Session, filter ui.BootstrapStateFilter) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "bootstrap_states").Add(1)
+		mm.latency.With("method", "bootstrap_states").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.BootstrapStates(s, filter)
+}
+
+// UpdateBootstrapStates adds metrics middleware to update bootstrap states method.
+func (mm *metricsMiddleware) UpdateBootstrapStates(ctx context.Context, s ui.Session, change ui.BootstrapStateChange) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "update_bootstrap_states").Add(1)
+		mm.latency.With("method", "update_bootstrap_states").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.UpdateBootstrapStates(ctx, s, change)
+}
+
+// ExportEntities adds metrics middleware to export entities method.
+func (mm *metricsMiddleware) ExportEntities(ctx context.Context, s ui.Session, export ui.EntityExport, w io.Writer) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "export_entities").Add(1)
+		mm.latency.With("method", "export_entities").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ExportEntities(ctx, s, export, w)
+}
+
+// ImportEntities adds metrics middleware to import entities method.
+func (mm *metricsMiddleware) ImportEntities(ctx context.Context, s ui.Session, imp ui.EntityImport) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "import_entities").Add(1)
+		mm.latency.With("method", "import_entities").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ImportEntities(ctx, s, imp)
+}
+
+// BulkConnect adds metrics middleware to bulk connect method.
+func (mm *metricsMiddleware) BulkConnect(ctx context.Context, s ui.Session, rows []ui.ConnectionRow) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "bulk_connect").Add(1)
+		mm.latency.With("method", "bulk_connect").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.BulkConnect(ctx, s, rows)
+}
+
+// BulkAction adds metrics middleware to bulk action method.
+func (mm *metricsMiddleware) BulkAction(ctx context.Context, s ui.Session, action ui.EntityBulkAction) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "bulk_action").Add(1)
+		mm.latency.With("method", "bulk_action").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.BulkAction(ctx, s, action)
+}
+
+// Search adds metrics middleware to search method.
+func (mm *metricsMiddleware) Search(ctx context.Context, s ui.Session, query string) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "search").Add(1)
+		mm.latency.With("method", "search").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Search(ctx, s, query)
+}
+
+// FindEntity adds metrics middleware to find entity method.
+func (mm *metricsMiddleware) FindEntity(ctx context.Context, s ui.Session, query string) (string, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "find_entity").Add(1)
+		mm.latency.With("method", "find_entity").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.FindEntity(ctx, s, query)
+}
+
+// Topology adds metrics middleware to topology method.
+func (mm *metricsMiddleware) Topology(ctx context.Context, s ui.Session, filter ui.TopologyFilter) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "topology").Add(1)
+		mm.latency.With("method", "topology").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Topology(ctx, s, filter)
+}
+
+// TopologyGraph adds metrics middleware to topology graph method.
+func (mm *metricsMiddleware) TopologyGraph(ctx context.Context, s ui.Session, filter ui.TopologyFilter) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "topology_graph").Add(1)
+		mm.latency.With("method", "topology_graph").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.TopologyGraph(ctx, s, filter)
+}
+
+// GroupTree adds metrics middleware to group tree method.
+func (mm *metricsMiddleware) GroupTree(ctx context.Context, s ui.Session, parentID string, page, limit uint64) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "group_tree").Add(1)
+		mm.latency.With("method", "group_tree").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.GroupTree(ctx, s, parentID, page, limit)
+}
+
+// MoveGroup adds metrics middleware to move group method.
+func (mm *metricsMiddleware) MoveGroup(ctx context.Context, s ui.Session, groupID, parentID string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "move_group").Add(1)
+		mm.latency.With("method", "move_group").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.MoveGroup(ctx, s, groupID, parentID)
+}
+
+// HygieneReport adds metrics middleware to hygiene report method.
+func (mm *metricsMiddleware) HygieneReport(ctx context.Context, s ui.Session) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "hygiene_report").Add(1)
+		mm.latency.With("method", "hygiene_report").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.HygieneReport(ctx, s)
+}
+
+// HygieneCleanup adds metrics middleware to hygiene cleanup method.
+func (mm *metricsMiddleware) HygieneCleanup(ctx context.Context, s ui.Session, cleanup ui.HygieneCleanup) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "hygiene_cleanup").Add(1)
+		mm.latency.With("method", "hygiene_cleanup").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.HygieneCleanup(ctx, s, cleanup)
+}
+
+// SecretRotations adds metrics middleware to secret rotations method.
+func (mm *metricsMiddleware) SecretRotations(ctx context.Context, s ui.Session, page, limit uint64) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "secret_rotations").Add(1)
+		mm.latency.With("method", "secret_rotations").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.SecretRotations(ctx, s, page, limit)
+}
+
+// RotateThingSecrets adds metrics middleware to rotate thing secrets method.
+func (mm *metricsMiddleware) RotateThingSecrets(ctx context.Context, s ui.Session, thingIDs []string) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "rotate_thing_secrets").Add(1)
+		mm.latency.With("method", "rotate_thing_secrets").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RotateThingSecrets(ctx, s, thingIDs)
+}
+
+// CreateRotationSchedule adds metrics middleware to create rotation schedule method.
+func (mm *metricsMiddleware) CreateRotationSchedule(ctx context.Context, s ui.Session, password string, schedule ui.RotationSchedule) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "create_rotation_schedule").Add(1)
+		mm.latency.With("method", "create_rotation_schedule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.CreateRotationSchedule(ctx, s, password, schedule)
+}
+
+// DeleteRotationSchedule adds metrics middleware to delete rotation schedule method.
+func (mm *metricsMiddleware) DeleteRotationSchedule(ctx context.Context, s ui.Session, id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "delete_rotation_schedule").Add(1)
+		mm.latency.With("method", "delete_rotation_schedule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.DeleteRotationSchedule(ctx, s, id)
+}
+
+// DownloadRotatedSecrets adds metrics middleware to download rotated secrets method.
+func (mm *metricsMiddleware) DownloadRotatedSecrets(ctx context.Context, s ui.Session, runID string) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "download_rotated_secrets").Add(1)
+		mm.latency.With("method", "download_rotated_secrets").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.DownloadRotatedSecrets(ctx, s, runID)
+}
+
+// RunRotationSchedules adds metrics middleware to run rotation schedules method.
+func (mm *metricsMiddleware) RunRotationSchedules(ctx context.Context, now time.Time, tokenMaxAge time.Duration) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "run_rotation_schedules").Add(1)
+		mm.latency.With("method", "run_rotation_schedules").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RunRotationSchedules(ctx, now, tokenMaxAge)
+}
+
+// ProvisionWizard adds metrics middleware to provision wizard method.
+func (mm *metricsMiddleware) ProvisionWizard(s ui.Session) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "provision_wizard").Add(1)
+		mm.latency.With("method", "provision_wizard").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ProvisionWizard(s)
+}
+
+// ProvisionThing adds metrics middleware to provision thing method.
+func (mm *metricsMiddleware) ProvisionThing(ctx context.Context, s ui.Session, p ui.ThingProvision) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "provision_thing").Add(1)
+		mm.latency.With("method", "provision_thing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ProvisionThing(ctx, s, p)
+}
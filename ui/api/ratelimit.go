@@ -0,0 +1,155 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/absmach/magistrala-ui/internal/ratelimit"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/go-kit/kit/metrics"
+)
+
+// RateLimiter throttles the unauthenticated endpoints that accept user
+// credentials or identities.
+type RateLimiter struct {
+	limiter  *ratelimit.Limiter
+	logger   *slog.Logger
+	rejected metrics.Counter
+	failures metrics.Counter
+}
+
+// NewRateLimiter returns a rate limiter that reports rejected requests and
+// failed attempts through the given counters.
+func NewRateLimiter(limiter *ratelimit.Limiter, logger *slog.Logger, rejected, failures metrics.Counter) *RateLimiter {
+	return &RateLimiter{
+		limiter:  limiter,
+		logger:   logger,
+		rejected: rejected,
+		failures: failures,
+	}
+}
+
+// Middleware limits requests per client IP and per identity read from the
+// request by identity. Responses with status 401 count as failed attempts and
+// lead to a progressive lockout, while successful responses clear the
+// failures of the identity. Rejected requests are answered with 429, or
+// redirected to the login page with an error if redirect is set.
+func (rl *RateLimiter) Middleware(endpoint string, identity func(*http.Request) string, prefix string, redirect bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			ipKey := fmt.Sprintf("%s:ip:%s", endpoint, rl.clientIP(r))
+			keys := []string{ipKey}
+
+			// The identity is checked first, so that requests refused for it
+			// do not use up the tokens of the address.
+			var identityKey string
+			if id := strings.ToLower(strings.TrimSpace(identity(r))); id != "" {
+				identityKey = fmt.Sprintf("%s:identity:%s", endpoint, id)
+				keys = []string{identityKey, ipKey}
+			}
+
+			wait, err := rl.limiter.Allow(ctx, keys...)
+			switch {
+			case errors.Contains(err, ratelimit.ErrRateLimited):
+				rl.rejected.With("endpoint", endpoint, "reason", "rate_limited").Add(1)
+				rl.reject(w, r, ratelimit.ErrRateLimited, wait, prefix, redirect)
+				return
+			case errors.Contains(err, ratelimit.ErrLocked):
+				rl.rejected.With("endpoint", endpoint, "reason", "locked").Add(1)
+				rl.reject(w, r, ratelimit.ErrLocked, wait, prefix, redirect)
+				return
+			case err != nil:
+				rl.logger.Error("Rate limit check failed", slog.String("endpoint", endpoint), slog.Any("error", err))
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			sr := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(sr, r)
+
+			switch {
+			case sr.status == http.StatusUnauthorized:
+				rl.failures.With("endpoint", endpoint).Add(1)
+				lockout, err := rl.limiter.Failure(ctx, keys...)
+				if err != nil {
+					rl.logger.Error("Failed to record failed attempt", slog.String("endpoint", endpoint), slog.Any("error", err))
+					return
+				}
+				if lockout > 0 {
+					rl.logger.Warn("Client locked out after repeated failed attempts",
+						slog.String("endpoint", endpoint),
+						slog.String("ip", rl.clientIP(r)),
+						slog.String("lockout", lockout.String()),
+					)
+				}
+			case sr.status < http.StatusBadRequest && identityKey != "":
+				if err := rl.limiter.Success(ctx, identityKey); err != nil {
+					rl.logger.Error("Failed to clear failed attempts", slog.String("endpoint", endpoint), slog.Any("error", err))
+				}
+			}
+		})
+	}
+}
+
+// formIdentity reads the identity of a request from the field form value.
+func formIdentity(field string) func(*http.Request) string {
+	return func(r *http.Request) string {
+		return r.PostFormValue(field)
+	}
+}
+
+func (rl *RateLimiter) reject(w http.ResponseWriter, r *http.Request, err error, wait time.Duration, prefix string, redirect bool) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	if redirect {
+		http.Redirect(w, r, fmt.Sprintf("%s/%s?error=%s", prefix, loginAPIEndpoint, url.QueryEscape(err.Error())), http.StatusSeeOther)
+		return
+	}
+	w.Header().Set("X-Error-Message", err.Error())
+	w.WriteHeader(http.StatusTooManyRequests)
+}
+
+// clientIP returns the address of the client, taking it from the proxy
+// headers only when the proxy is trusted.
+func (rl *RateLimiter) clientIP(r *http.Request) string {
+	if rl.limiter.TrustProxy() {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			ip, _, _ := strings.Cut(fwd, ",")
+			return strings.TrimSpace(ip)
+		}
+		if ip := r.Header.Get("X-Real-IP"); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(code int) {
+	sr.status = code
+	sr.ResponseWriter.WriteHeader(code)
+}
+
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
@@ -108,47 +108,334 @@ func (req createUserReq) validate() error {
 	return nil
 }
 
-type createUsersReq struct {
-	token string
-	users []sdk.User
+type listEntityReq struct {
+	ui.Session
+	status string
+	page   uint64
+	limit  uint64
 }
 
-func (req createUsersReq) validate() error {
-	if req.token == "" {
+func (req listEntityReq) validate() error {
+	if req.Token == "" {
 		return errInvalidCredentials
 	}
-	for _, user := range req.users {
-		if user.Credentials.Secret == "" {
-			return errMissingSecret
-		}
-		if user.Credentials.Identity == "" {
-			return errMissingIdentity
-		}
+	if req.page == 0 {
+		return errPageSize
+	}
+	if req.limit == 0 {
+		return errLimitSize
 	}
+	return nil
+}
+
+type importEntitiesReq struct {
+	ui.Session
+	imp ui.EntityImport
+}
 
+func (req importEntitiesReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if len(req.imp.Records) > ui.MaxImportRows {
+		return errTooManyRows
+	}
 	return nil
 }
 
-type listEntityReq struct {
+type bulkConnectReq struct {
 	ui.Session
-	status string
-	page   uint64
-	limit  uint64
+	rows []ui.ConnectionRow
 }
 
-func (req listEntityReq) validate() error {
+func (req bulkConnectReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if len(req.rows) > ui.MaxConnectionRows {
+		return errTooManyRows
+	}
+	return nil
+}
+
+type topologyReq struct {
+	ui.Session
+	filter ui.TopologyFilter
+}
+
+func (req topologyReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	return nil
+}
+
+type groupTreeReq struct {
+	ui.Session
+	parentID string
+	page     uint64
+	limit    uint64
+}
+
+func (req groupTreeReq) validate() error {
 	if req.Token == "" {
 		return errInvalidCredentials
 	}
 	if req.page == 0 {
 		return errPageSize
 	}
-	if req.limit == 0 {
+	if req.limit == 0 || req.limit > maxLimitSize {
 		return errLimitSize
 	}
 	return nil
 }
 
+type moveGroupReq struct {
+	ui.Session
+	groupID  string
+	parentID string
+}
+
+func (req moveGroupReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.groupID == "" {
+		return errMissingGroupID
+	}
+	return nil
+}
+
+type hygieneReportReq struct {
+	ui.Session
+}
+
+func (req hygieneReportReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	return nil
+}
+
+type hygieneCleanupReq struct {
+	ui.Session
+	cleanup ui.HygieneCleanup
+}
+
+func (req hygieneCleanupReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	actions := ui.HygieneActions(req.cleanup.Category)
+	if actions == nil {
+		return errInvalidCategory
+	}
+	if !slices.Contains(actions, req.cleanup.Action) {
+		return errInvalidBulkAction
+	}
+	if len(req.cleanup.IDs) == 0 {
+		return errMissingEntities
+	}
+	if len(req.cleanup.IDs) > ui.MaxBulkEntities {
+		return errTooManyEntities
+	}
+	return nil
+}
+
+type provisionWizardReq struct {
+	ui.Session
+}
+
+func (req provisionWizardReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	return nil
+}
+
+type provisionThingReq struct {
+	ui.Session
+	provision ui.ThingProvision
+}
+
+func (req provisionThingReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.provision.Name == "" {
+		return errMissingName
+	}
+	channels := len(req.provision.ChannelIDs) + len(req.provision.ChannelNames)
+	if channels > ui.MaxProvisionChannels {
+		return errTooManyChannels
+	}
+	if req.provision.Bootstrap {
+		if req.provision.ExternalID == "" {
+			return errMissingExternalID
+		}
+		if req.provision.ExternalKey == "" {
+			return errMissingExternalKey
+		}
+	}
+	if req.provision.Dashboard {
+		if channels == 0 {
+			return errMissingChannel
+		}
+		if req.provision.ValueName == "" {
+			return errMissingValueName
+		}
+	}
+	return nil
+}
+
+type rotateThingSecretsReq struct {
+	ui.Session
+	thingIDs []string
+}
+
+func (req rotateThingSecretsReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if len(req.thingIDs) == 0 {
+		return errMissingEntities
+	}
+	if len(req.thingIDs) > ui.MaxBulkEntities {
+		return errTooManyEntities
+	}
+	return nil
+}
+
+type createRotationScheduleReq struct {
+	ui.Session
+	password string
+	schedule ui.RotationSchedule
+}
+
+func (req createRotationScheduleReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.password == "" {
+		return errMissingPassword
+	}
+	if req.schedule.Tag == "" {
+		return errMissingTag
+	}
+	if req.schedule.Interval < ui.MinRotationInterval {
+		return errInvalidRotationInterval
+	}
+	return nil
+}
+
+type deleteRotationScheduleReq struct {
+	ui.Session
+	id string
+}
+
+func (req deleteRotationScheduleReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.id == "" {
+		return errMissingScheduleID
+	}
+	return nil
+}
+
+type downloadRotatedSecretsReq struct {
+	ui.Session
+	runID string
+}
+
+func (req downloadRotatedSecretsReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.runID == "" {
+		return errMissingRunID
+	}
+	return nil
+}
+
+type searchReq struct {
+	ui.Session
+	query string
+}
+
+func (req searchReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if len(req.query) > maxNameSize {
+		return errQuerySize
+	}
+	return nil
+}
+
+type bulkActionReq struct {
+	ui.Session
+	action ui.EntityBulkAction
+}
+
+func (req bulkActionReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	switch req.action.Entity {
+	case ui.UsersEntity, ui.ThingsEntity, ui.ChannelsEntity, ui.GroupsEntity:
+	default:
+		return errInvalidEntity
+	}
+	switch req.action.Action {
+	case ui.BulkEnable, ui.BulkDisable:
+	case ui.BulkAddTag, ui.BulkRemoveTag:
+		if req.action.Entity != ui.UsersEntity && req.action.Entity != ui.ThingsEntity {
+			return errInvalidBulkAction
+		}
+		if req.action.Tag == "" {
+			return errMissingTag
+		}
+	default:
+		return errInvalidBulkAction
+	}
+	filter := req.action.Filter
+	if len(req.action.IDs) == 0 && filter.Name == "" && filter.Tag == "" && filter.Status == "" {
+		return errMissingEntities
+	}
+	if len(req.action.IDs) > ui.MaxBulkEntities {
+		return errTooManyEntities
+	}
+	switch filter.Status {
+	case "", statusEnabled, statusDisabled, statusAll:
+	default:
+		return errInvalidStatus
+	}
+	return nil
+}
+
+type exportEntitiesReq struct {
+	ui.Session
+	export ui.EntityExport
+}
+
+func (req exportEntitiesReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	switch req.export.Entity {
+	case ui.UsersEntity, ui.ThingsEntity, ui.ChannelsEntity, ui.GroupsEntity:
+	default:
+		return errInvalidEntity
+	}
+	switch req.export.Status {
+	case "", statusEnabled, statusDisabled, statusAll:
+	default:
+		return errInvalidStatus
+	}
+	return nil
+}
+
 type listEntityByIDReq struct {
 	ui.Session
 	id       string
@@ -417,18 +704,6 @@ func (req updateThingStatusReq) validate() error {
 	return nil
 }
 
-type createThingsReq struct {
-	token  string
-	things []sdk.Thing
-}
-
-func (req createThingsReq) validate() error {
-	if req.token == "" {
-		return errInvalidCredentials
-	}
-	return nil
-}
-
 type createChannelReq struct {
 	token string
 	sdk.Channel
@@ -444,23 +719,6 @@ func (req createChannelReq) validate() error {
 	return nil
 }
 
-type createChannelsReq struct {
-	token    string
-	Channels []sdk.Channel
-}
-
-func (req createChannelsReq) validate() error {
-	if req.token == "" {
-		return errInvalidCredentials
-	}
-	for _, channel := range req.Channels {
-		if channel.Name == "" {
-			return errMissingName
-		}
-	}
-	return nil
-}
-
 type updateChannelReq struct {
 	token string
 	sdk.Channel
@@ -558,24 +816,6 @@ func (req createGroupReq) validate() error {
 	return nil
 }
 
-type createGroupsReq struct {
-	token  string
-	Groups []sdk.Group
-}
-
-func (req createGroupsReq) validate() error {
-	if req.token == "" {
-		return errInvalidCredentials
-	}
-	for _, group := range req.Groups {
-		if group.Name == "" {
-			return errMissingName
-		}
-	}
-
-	return nil
-}
-
 type updateGroupReq struct {
 	token string
 	sdk.Group
@@ -690,33 +930,33 @@ func (req readMessagesReq) validate() error {
 	return nil
 }
 
-type bootstrapCommandReq struct {
-	token   string
-	command string
-	id      string
+type updateBootstrapReq struct {
+	token string
+	sdk.BootstrapConfig
 }
 
-func (req bootstrapCommandReq) validate() error {
+func (req updateBootstrapReq) validate() error {
 	if req.token == "" {
 		return errInvalidCredentials
 	}
-	if req.id == "" {
+
+	if req.ThingID == "" {
 		return errMissingConfigID
 	}
 	return nil
 }
 
-type updateBootstrapReq struct {
-	token string
-	sdk.BootstrapConfig
+type bootstrapContentDiffReq struct {
+	ui.Session
+	thingID string
+	content string
 }
 
-func (req updateBootstrapReq) validate() error {
-	if req.token == "" {
+func (req bootstrapContentDiffReq) validate() error {
+	if req.Token == "" {
 		return errInvalidCredentials
 	}
-
-	if req.ThingID == "" {
+	if req.thingID == "" {
 		return errMissingConfigID
 	}
 	return nil
@@ -1176,3 +1416,381 @@ func (req deleteDashboardReq) validate() error {
 	}
 	return nil
 }
+
+type personalTokensReq struct {
+	ui.Session
+	page  uint64
+	limit uint64
+}
+
+func (req personalTokensReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.page == 0 {
+		return errPageSize
+	}
+	if req.limit == 0 {
+		return errLimitSize
+	}
+	return nil
+}
+
+type createPersonalTokenReq struct {
+	ui.Session
+	password string
+	ptReq    ui.PersonalTokenReq
+}
+
+func (req createPersonalTokenReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.ptReq.Name == "" {
+		return errMissingName
+	}
+	if len(req.ptReq.Name) > maxNameSize {
+		return errNameSize
+	}
+	if req.password == "" {
+		return errMissingPassword
+	}
+	if len(req.ptReq.Scopes) == 0 {
+		return errMissingScope
+	}
+	for _, scope := range req.ptReq.Scopes {
+		if !slices.Contains(ui.TokenScopes, scope) {
+			return errInvalidScope
+		}
+	}
+	if !req.ptReq.ExpiresAt.IsZero() && req.ptReq.ExpiresAt.Before(time.Now()) {
+		return errInvalidExpiry
+	}
+	return nil
+}
+
+type revokePersonalTokenReq struct {
+	ui.Session
+	id string
+}
+
+func (req revokePersonalTokenReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.id == "" {
+		return errMissingTokenID
+	}
+	return nil
+}
+
+type twoFactorReq struct {
+	ui.Session
+}
+
+func (req twoFactorReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	return nil
+}
+
+type twoFactorCodeReq struct {
+	ui.Session
+	code string
+}
+
+func (req twoFactorCodeReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.code == "" {
+		return errMissingCode
+	}
+	return nil
+}
+
+type twoFactorLoginReq struct {
+	pending twoFactorPending
+	code    string
+}
+
+func (req twoFactorLoginReq) validate() error {
+	if req.pending.UserID == "" || req.pending.AccessToken == "" || req.pending.RefreshToken == "" {
+		return errTwoFactorSession
+	}
+	if time.Now().After(req.pending.ExpiresAt) {
+		return errTwoFactorSession
+	}
+	if req.code == "" {
+		return errMissingCode
+	}
+	return nil
+}
+
+type updateDomainTwoFactorReq struct {
+	ui.Session
+	id       string
+	required bool
+}
+
+func (req updateDomainTwoFactorReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.id == "" {
+		return errMissingDomainID
+	}
+	return nil
+}
+
+type terminalAuditReq struct {
+	ui.Session
+	page  uint64
+	limit uint64
+	pm    ui.TerminalCommandPageMeta
+}
+
+func (req terminalAuditReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.page == 0 {
+		return errPageSize
+	}
+	if req.limit == 0 {
+		return errLimitSize
+	}
+	if !req.pm.From.IsZero() && !req.pm.To.IsZero() && req.pm.From.After(req.pm.To) {
+		return errInvalidTimeRange
+	}
+	return nil
+}
+
+type batchCommandReq struct {
+	ui.Session
+	batch ui.BatchCommand
+}
+
+func (req batchCommandReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.batch.Command == "" {
+		return errMissingCommand
+	}
+	if len(req.batch.ThingIDs) == 0 && req.batch.Tag == "" && req.batch.State == "" {
+		return errMissingTargets
+	}
+	if len(req.batch.ThingIDs) > ui.MaxBatchTargets {
+		return errTooManyTargets
+	}
+	switch req.batch.State {
+	case "", ui.BatchStateActive, ui.BatchStateInactive:
+	default:
+		return errInvalidState
+	}
+	if req.batch.Timeout < minBatchTimeout || req.batch.Timeout > maxBatchTimeout {
+		return errInvalidTimeout
+	}
+	if req.batch.Concurrency < 1 || req.batch.Concurrency > maxBatchConcurrency {
+		return errInvalidConcurrency
+	}
+	return nil
+}
+
+type updateTerminalPolicyReq struct {
+	ui.Session
+	policy ui.TerminalPolicy
+}
+
+func (req updateTerminalPolicyReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.policy.DomainID == "" {
+		return errMissingDomainID
+	}
+	if len(req.policy.Allow) > maxPolicyPatterns || len(req.policy.Deny) > maxPolicyPatterns {
+		return errTooManyPatterns
+	}
+	for _, pattern := range append(req.policy.Allow, req.policy.Deny...) {
+		if len(pattern) > maxPolicyPatternSize {
+			return errPatternSize
+		}
+	}
+	return nil
+}
+
+type createBootstrapTemplateReq struct {
+	ui.Session
+	tpl ui.BootstrapTemplate
+}
+
+func (req createBootstrapTemplateReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.tpl.Name == "" {
+		return errMissingName
+	}
+	if req.tpl.Content == "" {
+		return errMissingContent
+	}
+	return nil
+}
+
+type updateBootstrapTemplateReq struct {
+	ui.Session
+	tpl ui.BootstrapTemplate
+}
+
+func (req updateBootstrapTemplateReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.tpl.ID == "" {
+		return errMissingTemplateID
+	}
+	if req.tpl.Name == "" {
+		return errMissingName
+	}
+	if req.tpl.Content == "" {
+		return errMissingContent
+	}
+	return nil
+}
+
+type deleteBootstrapTemplateReq struct {
+	ui.Session
+	id string
+}
+
+func (req deleteBootstrapTemplateReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.id == "" {
+		return errMissingTemplateID
+	}
+	return nil
+}
+
+type provisionBootstrapsReq struct {
+	ui.Session
+	templateID string
+	rows       []ui.BootstrapRow
+}
+
+func (req provisionBootstrapsReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.templateID == "" {
+		return errMissingTemplateID
+	}
+	if len(req.rows) == 0 {
+		return errFileFormat
+	}
+	if len(req.rows) > ui.MaxProvisionRows {
+		return errTooManyRows
+	}
+	return nil
+}
+
+type expiringCertsReq struct {
+	ui.Session
+	days uint64
+}
+
+func (req expiringCertsReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if req.days > maxCertExpiryDays {
+		return errInvalidExpiryWindow
+	}
+	return nil
+}
+
+type exportBundleReq struct {
+	ui.Session
+	export ui.BundleExport
+}
+
+func (req exportBundleReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if len(req.export.ThingIDs) == 0 {
+		return errMissingConfigs
+	}
+	if len(req.export.ThingIDs) > ui.MaxBundleConfigs {
+		return errTooManyConfigs
+	}
+	switch req.export.Format {
+	case ui.BundleJSON, ui.BundleYAML:
+	default:
+		return errInvalidBundleFormat
+	}
+	return nil
+}
+
+type bootstrapStatesReq struct {
+	ui.Session
+	filter ui.BootstrapStateFilter
+}
+
+func (req bootstrapStatesReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	switch req.filter.State {
+	case "", ui.BatchStateActive, ui.BatchStateInactive:
+	default:
+		return errInvalidState
+	}
+	return nil
+}
+
+type updateBootstrapStatesReq struct {
+	ui.Session
+	change ui.BootstrapStateChange
+}
+
+func (req updateBootstrapStatesReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if len(req.change.ThingIDs) == 0 {
+		return errMissingConfigs
+	}
+	if len(req.change.ThingIDs) > ui.MaxStateChanges {
+		return errTooManyConfigs
+	}
+	switch req.change.State {
+	case ui.BatchStateActive, ui.BatchStateInactive:
+	default:
+		return errInvalidState
+	}
+	return nil
+}
+
+type importBundleReq struct {
+	ui.Session
+	bundle ui.Bundle
+	opts   ui.BundleImport
+}
+
+func (req importBundleReq) validate() error {
+	if req.Token == "" {
+		return errInvalidCredentials
+	}
+	if len(req.bundle.Configs) == 0 {
+		return errMissingConfigs
+	}
+	if len(req.bundle.Configs) > ui.MaxBundleConfigs {
+		return errTooManyConfigs
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ package api
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/absmach/magistrala"
@@ -13,7 +14,6 @@ import (
 var (
 	_ magistrala.Response = (*uiRes)(nil)
 	_ magistrala.Response = (*tokenRes)(nil)
-	_ magistrala.Response = (*terminalResponse)(nil)
 )
 
 type uiRes struct {
@@ -46,6 +46,13 @@ func (res tokenRes) Empty() bool {
 	return res.token == ""
 }
 
+// streamRes is a response whose body is written as it is produced instead
+// of being held in memory.
+type streamRes struct {
+	headers map[string]string
+	write   func(w io.Writer) error
+}
+
 func (res uiRes) Code() int {
 	if res.code == 0 {
 		return http.StatusCreated
@@ -69,20 +76,3 @@ func (res uiRes) Cookies() []*http.Cookie {
 func (res uiRes) Empty() bool {
 	return res.html == nil
 }
-
-type terminalResponse struct {
-	Command string `json:"command"`
-	Result  string `json:"result"`
-}
-
-func (*terminalResponse) Code() int {
-	return http.StatusOK
-}
-
-func (*terminalResponse) Empty() bool {
-	return false
-}
-
-func (*terminalResponse) Headers() map[string]string {
-	return map[string]string{}
-}
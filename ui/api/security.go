@@ -0,0 +1,214 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/errors"
+)
+
+// NoncePlaceholder is replaced in the Content-Security-Policy with a nonce
+// generated for every response, which is given to the inline scripts of the
+// pages.
+const NoncePlaceholder = "{nonce}"
+
+// DefaultContentSecurityPolicy allows the inline scripts of the pages through
+// their nonce, together with the CDNs the templates load their libraries,
+// stylesheets and fonts from. The pages declare their event handlers in
+// data-on attributes run by /js/actions.js, so inline handlers are refused.
+const DefaultContentSecurityPolicy = "default-src 'self'; " +
+	"script-src 'self' 'nonce-" + NoncePlaceholder + "' https://cdn.jsdelivr.net https://cdnjs.cloudflare.com https://www.gstatic.com; " +
+	"style-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net https://cdnjs.cloudflare.com https://fonts.googleapis.com https://www.gstatic.com; " +
+	"font-src 'self' data: https://cdnjs.cloudflare.com https://fonts.gstatic.com; " +
+	"img-src 'self' data: blob:; " +
+	"connect-src 'self'; " +
+	"object-src 'none'; " +
+	"base-uri 'self'; " +
+	"form-action 'self'; " +
+	"frame-ancestors 'none'"
+
+// SecurityConfig defines the security headers added to every response and
+// the attributes applied to every cookie set by the UI. Empty header values
+// and a zero HSTS max age disable the corresponding header.
+type SecurityConfig struct {
+	ContentSecurityPolicy string
+	FrameOptions          string
+	ReferrerPolicy        string
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+	CookieSecure          bool
+	CookieSameSite        http.SameSite
+	CookieDomain          string
+}
+
+// ParseSameSite converts one of lax, strict, none or an empty string to the
+// corresponding cookie SameSite mode.
+func ParseSameSite(mode string) (http.SameSite, error) {
+	switch strings.ToLower(mode) {
+	case "":
+		return http.SameSiteDefaultMode, nil
+	case "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	default:
+		return 0, errors.Wrap(errInvalidSameSite, fmt.Errorf("%s", mode))
+	}
+}
+
+func (cfg SecurityConfig) validate() error {
+	if cfg.CookieSameSite == http.SameSiteNoneMode && !cfg.CookieSecure {
+		return errInsecureSameSite
+	}
+
+	return nil
+}
+
+// SecurityMiddleware adds the configured security headers to responses and
+// applies the configured Secure, SameSite and Domain attributes to the
+// cookies set by the handlers, including the ones clearing cookies so that
+// they match the cookies being removed. When the Content-Security-Policy
+// holds NoncePlaceholder, every request gets a nonce for its inline scripts.
+func SecurityMiddleware(cfg SecurityConfig) func(http.Handler) http.Handler {
+	headers := map[string]string{
+		"X-Content-Type-Options": "nosniff",
+	}
+	useNonce := strings.Contains(cfg.ContentSecurityPolicy, NoncePlaceholder)
+	if cfg.ContentSecurityPolicy != "" && !useNonce {
+		headers["Content-Security-Policy"] = cfg.ContentSecurityPolicy
+	}
+	if cfg.FrameOptions != "" {
+		headers["X-Frame-Options"] = cfg.FrameOptions
+	}
+	if cfg.ReferrerPolicy != "" {
+		headers["Referrer-Policy"] = cfg.ReferrerPolicy
+	}
+	if cfg.HSTSMaxAge > 0 {
+		hsts := fmt.Sprintf("max-age=%d", int(cfg.HSTSMaxAge.Seconds()))
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		headers["Strict-Transport-Security"] = hsts
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for k, v := range headers {
+				w.Header().Set(k, v)
+			}
+			if useNonce {
+				nonce, err := scriptNonce()
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set("Content-Security-Policy", strings.ReplaceAll(cfg.ContentSecurityPolicy, NoncePlaceholder, nonce))
+				r = r.WithContext(context.WithValue(r.Context(), nonceKey{}, nonce))
+			}
+
+			cw := &cookieWriter{ResponseWriter: w, cfg: cfg}
+			next.ServeHTTP(cw, r)
+			// The server writes the headers itself when the handler
+			// returns without writing them.
+			if !cw.wroteHeader {
+				cw.secureCookies()
+			}
+		})
+	}
+}
+
+type nonceKey struct{}
+
+func scriptNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// withScriptNonce gives the nonce of the request to the scripts of a page.
+func withScriptNonce(ctx context.Context, html []byte) []byte {
+	nonce, ok := ctx.Value(nonceKey{}).(string)
+	if !ok {
+		return html
+	}
+
+	return bytes.ReplaceAll(html, []byte("<script"), []byte(`<script nonce="`+nonce+`"`))
+}
+
+// cookieWriter rewrites the Set-Cookie headers of a response with the
+// configured cookie attributes before the headers are written, or once the
+// handler returns if it did not write them.
+type cookieWriter struct {
+	http.ResponseWriter
+	cfg         SecurityConfig
+	wroteHeader bool
+}
+
+func (cw *cookieWriter) WriteHeader(code int) {
+	if !cw.wroteHeader {
+		cw.wroteHeader = true
+		cw.secureCookies()
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *cookieWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	return cw.ResponseWriter.Write(b)
+}
+
+func (cw *cookieWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Hijack lets the WebSocket handlers take over the connection.
+func (cw *cookieWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := cw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijack
+	}
+
+	return h.Hijack()
+}
+
+func (cw *cookieWriter) secureCookies() {
+	header := cw.Header()
+	if len(header.Values("Set-Cookie")) == 0 {
+		return
+	}
+
+	cookies := (&http.Response{Header: header}).Cookies()
+	header.Del("Set-Cookie")
+	for _, cookie := range cookies {
+		if cw.cfg.CookieSecure {
+			cookie.Secure = true
+		}
+		if cw.cfg.CookieSameSite != http.SameSiteDefaultMode {
+			cookie.SameSite = cw.cfg.CookieSameSite
+		}
+		if cw.cfg.CookieDomain != "" {
+			cookie.Domain = cw.cfg.CookieDomain
+		}
+		header.Add("Set-Cookie", cookie.String())
+	}
+}
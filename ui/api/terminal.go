@@ -0,0 +1,295 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	terminalWriteWait      = 10 * time.Second
+	terminalPongWait       = 60 * time.Second
+	terminalPingPeriod     = (terminalPongWait * 9) / 10
+	terminalMaxMessageSize = 4096
+	// terminalMaxCommands limits the commands running at once on a
+	// connection, since each of them holds a request to the agent.
+	terminalMaxCommands = 4
+
+	terminalExecAction   = "exec"
+	terminalCancelAction = "cancel"
+
+	terminalRunning  = "running"
+	terminalDone     = "done"
+	terminalCanceled = "canceled"
+	terminalFailed   = "failed"
+)
+
+// The default origin check of the upgrader only accepts connections opened
+// by pages served from the same host.
+var terminalUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// terminalMessage is sent by the browser to run or cancel a command. The id
+// is chosen by the browser and identifies the command in the events sent back.
+type terminalMessage struct {
+	Action  string `json:"action"`
+	ID      string `json:"id"`
+	Command string `json:"command,omitempty"`
+}
+
+// terminalEvent reports the progress of a command to the browser.
+type terminalEvent struct {
+	ID      string `json:"id,omitempty"`
+	Status  string `json:"status"`
+	Command string `json:"command,omitempty"`
+	Result  string `json:"result,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// terminalHandler opens a terminal on the agent of a bootstrapped thing and
+// serves it over a WebSocket. The terminal is closed with the socket, which
+// is closed when the session expires.
+func terminalHandler(svc ui.Service, cfg SessionConfig, prefix string) http.HandlerFunc {
+	encodeErr := encodeError(prefix)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		session, err := sessionFromHeader(r)
+		if err != nil {
+			encodeErr(r.Context(), err, w)
+			return
+		}
+		thingID := chi.URLParam(r, "id")
+		if thingID == "" {
+			encodeErr(r.Context(), errMissingConfigID, w)
+			return
+		}
+
+		// The agent control channel is detected from the bootstrap config
+		// unless the client chose one.
+		term, err := svc.OpenTerminal(r.Context(), session, thingID, r.URL.Query().Get(channelKey))
+		if err != nil {
+			encodeErr(r.Context(), err, w)
+			return
+		}
+
+		// Upgrade replies to the client itself when it fails.
+		conn, err := terminalUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			term.Close()
+			return
+		}
+
+		ts := &terminalSession{
+			svc:          svc,
+			session:      session,
+			cfg:          cfg,
+			term:         term,
+			conn:         conn,
+			commands:     make(map[string]context.CancelFunc),
+			lastActivity: time.Now(),
+		}
+		ts.serve(r.Context())
+	}
+}
+
+// terminalSession runs the commands received over a WebSocket on an open
+// terminal. Up to terminalMaxCommands commands run concurrently, further ones
+// are refused, and their results are written back as soon as the agent
+// responds. The commands sent over the socket are the activity of the session
+// while it is open.
+type terminalSession struct {
+	svc     ui.Service
+	session ui.Session
+	cfg     SessionConfig
+	term    *ui.Terminal
+	conn    *websocket.Conn
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	// commands holds the cancel functions of the running commands by id.
+	commands     map[string]context.CancelFunc
+	lastActivity time.Time
+	wg           sync.WaitGroup
+}
+
+func (ts *terminalSession) serve(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		ts.wg.Wait()
+		ts.term.Close()
+		ts.conn.Close()
+	}()
+
+	ts.conn.SetReadLimit(terminalMaxMessageSize)
+	ts.conn.SetReadDeadline(time.Now().Add(terminalPongWait))
+	ts.conn.SetPongHandler(func(string) error {
+		return ts.conn.SetReadDeadline(time.Now().Add(terminalPongWait))
+	})
+
+	ts.wg.Add(2)
+	go ts.ping(ctx)
+	go ts.expire(ctx)
+
+	for {
+		var msg terminalMessage
+		if err := ts.conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		switch msg.Action {
+		case terminalExecAction:
+			ts.exec(ctx, msg)
+		case terminalCancelAction:
+			ts.cancel(msg.ID)
+		default:
+			ts.send(terminalEvent{ID: msg.ID, Status: terminalFailed, Error: errInvalidTerminalAction.Error()})
+		}
+	}
+}
+
+func (ts *terminalSession) exec(ctx context.Context, msg terminalMessage) {
+	command := agentCommand(msg.Command)
+	switch {
+	case msg.ID == "":
+		ts.send(terminalEvent{Status: terminalFailed, Command: msg.Command, Error: errMissingCommandID.Error()})
+		return
+	case command == "":
+		ts.send(terminalEvent{ID: msg.ID, Status: terminalFailed, Error: errMissingCommand.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	ts.mu.Lock()
+	ts.lastActivity = time.Now()
+	if _, ok := ts.commands[msg.ID]; ok {
+		ts.mu.Unlock()
+		cancel()
+		ts.send(terminalEvent{ID: msg.ID, Status: terminalFailed, Command: msg.Command, Error: errCommandRunning.Error()})
+		return
+	}
+	if len(ts.commands) >= terminalMaxCommands {
+		ts.mu.Unlock()
+		cancel()
+		ts.send(terminalEvent{ID: msg.ID, Status: terminalFailed, Command: msg.Command, Error: errTooManyCommands.Error()})
+		return
+	}
+	ts.commands[msg.ID] = cancel
+	ts.mu.Unlock()
+
+	ts.send(terminalEvent{ID: msg.ID, Status: terminalRunning, Command: msg.Command})
+
+	ts.wg.Add(1)
+	go func() {
+		defer ts.wg.Done()
+		defer func() {
+			ts.mu.Lock()
+			delete(ts.commands, msg.ID)
+			ts.mu.Unlock()
+			cancel()
+		}()
+
+		res, err := ts.svc.ProcessTerminalCommand(ctx, ts.session, ts.term, command)
+		ev := terminalEvent{ID: msg.ID, Status: terminalDone, Command: msg.Command, Result: res}
+		switch {
+		case errors.Contains(err, ui.ErrTerminalCanceled):
+			ev.Status = terminalCanceled
+		case err != nil:
+			ev.Status = terminalFailed
+			ev.Error = err.Error()
+		}
+		ts.send(ev)
+	}()
+}
+
+func (ts *terminalSession) cancel(id string) {
+	ts.mu.Lock()
+	cancel, ok := ts.commands[id]
+	ts.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+func (ts *terminalSession) ping(ctx context.Context) {
+	defer ts.wg.Done()
+
+	ticker := time.NewTicker(terminalPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ts.writeMu.Lock()
+			err := ts.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(terminalWriteWait))
+			ts.writeMu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// expire closes the socket once the session has been idle for longer than the
+// idle timeout or has outlived its maximum age. Closing the socket fails the
+// pending read, which ends the session.
+func (ts *terminalSession) expire(ctx context.Context) {
+	defer ts.wg.Done()
+
+	for {
+		ts.mu.Lock()
+		deadline, ok := sessionDeadline(ts.session, ts.cfg, ts.lastActivity)
+		ts.mu.Unlock()
+		if !ok {
+			return
+		}
+		if wait := time.Until(deadline); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				// Commands sent meanwhile moved the deadline.
+				continue
+			}
+		}
+
+		ts.writeMu.Lock()
+		msg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, errTerminalSessionExpired.Error())
+		ts.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(terminalWriteWait))
+		ts.writeMu.Unlock()
+		ts.conn.Close()
+		return
+	}
+}
+
+// send writes an event to the socket. Write errors are ignored since a
+// broken connection also fails the next read, which ends the session.
+func (ts *terminalSession) send(ev terminalEvent) {
+	ts.writeMu.Lock()
+	defer ts.writeMu.Unlock()
+
+	ts.conn.SetWriteDeadline(time.Now().Add(terminalWriteWait))
+	ts.conn.WriteJSON(ev)
+}
+
+// agentCommand formats a command typed by the user the way the agent expects
+// it, since the agent splits the arguments of a command on commas.
+func agentCommand(command string) string {
+	return strings.ReplaceAll(strings.TrimSpace(command), " ", ",")
+}
@@ -8,8 +8,10 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -30,73 +32,141 @@ import (
 )
 
 const (
-	htmContentType          = "text/html"
-	jsonContentType         = "application/json"
-	protocol                = "http"
-	pageKey                 = "page"
-	limitKey                = "limit"
-	itemKey                 = "item"
-	nameKey                 = "name"
-	refererKey              = "referer_url"
-	relationKey             = "relation"
-	domainKey               = "domain"
-	permissionKey           = "permission"
-	identityKey             = "identity"
-	statusKey               = "status"
-	formatKey               = "format"
-	subtopicKey             = "subtopic"
-	publisherKey            = "publisher"
-	protocolKey             = "protocol"
-	valueKey                = "v"
-	stringValueKey          = "vs"
-	dataValueKey            = "vd"
-	boolValueKey            = "vb"
-	comparatorKey           = "comparator"
-	fromKey                 = "from"
-	toKey                   = "to"
-	aggregationKey          = "aggregation"
-	intervalKey             = "interval"
-	defInterval             = "1s"
-	defPage                 = 1
-	defLimit                = 10
-	defKey                  = ""
-	usersAPIEndpoint        = "users"
-	thingsAPIEndpoint       = "things"
-	channelsAPIEndpoint     = "channels"
-	groupsAPIEndpoint       = "groups"
-	bootstrapAPIEndpoint    = "bootstraps"
-	membersAPIEndpoint      = "domains/members"
-	loginAPIEndpoint        = "login"
-	tokenRefreshAPIEndpoint = "token/refresh"
-	domainsAPIEndpoint      = "domains"
-	errorAPIEndpoint        = "error"
-	thingsItem              = "things"
-	channelsItem            = "channels"
-	groupsItem              = "groups"
-	accessTokenKey          = "access_token"
-	refreshTokenKey         = "refresh_token"
-	sessionDetailsKey       = "session"
-	channelKey              = "channel"
-	thingKey                = "thing"
-	loggedInKey             = "logged_in"
+	htmContentType            = "text/html"
+	jsonContentType           = "application/json"
+	csvContentType            = "text/csv"
+	yamlContentType           = "application/yaml"
+	protocol                  = "http"
+	pageKey                   = "page"
+	limitKey                  = "limit"
+	itemKey                   = "item"
+	nameKey                   = "name"
+	refererKey                = "referer_url"
+	relationKey               = "relation"
+	domainKey                 = "domain"
+	permissionKey             = "permission"
+	identityKey               = "identity"
+	statusKey                 = "status"
+	formatKey                 = "format"
+	subtopicKey               = "subtopic"
+	publisherKey              = "publisher"
+	protocolKey               = "protocol"
+	valueKey                  = "v"
+	stringValueKey            = "vs"
+	dataValueKey              = "vd"
+	boolValueKey              = "vb"
+	comparatorKey             = "comparator"
+	fromKey                   = "from"
+	toKey                     = "to"
+	userKey                   = "user"
+	commandKey                = "command"
+	aggregationKey            = "aggregation"
+	intervalKey               = "interval"
+	daysKey                   = "days"
+	stateKey                  = "state"
+	externalIDKey             = "external_id"
+	secretsKey                = "secrets"
+	queryKey                  = "query"
+	groupKey                  = "group"
+	tagKey                    = "tag"
+	parentKey                 = "parent"
+	statusEnabled             = "enabled"
+	statusDisabled            = "disabled"
+	statusAll                 = "all"
+	defInterval               = "1s"
+	defPage                   = 1
+	defLimit                  = 10
+	defKey                    = ""
+	usersAPIEndpoint          = "users"
+	thingsAPIEndpoint         = "things"
+	channelsAPIEndpoint       = "channels"
+	groupsAPIEndpoint         = "groups"
+	bootstrapAPIEndpoint      = "bootstraps"
+	membersAPIEndpoint        = "domains/members"
+	loginAPIEndpoint          = "login"
+	tokenRefreshAPIEndpoint   = "token/refresh"
+	domainsAPIEndpoint        = "domains"
+	errorAPIEndpoint          = "error"
+	sessionExpiredAPIEndpoint = "session/expired"
+	personalTokensAPIEndpoint = "tokens/personal"
+	twoFactorAPIEndpoint      = "two-factor"
+	twoFactorLoginAPIEndpoint = "login/two-factor"
+	twoFactorLoginTimeout     = 5 * time.Minute
+	defBatchTimeout           = 10 * time.Second
+	minBatchTimeout           = time.Second
+	maxBatchTimeout           = 5 * time.Minute
+	defBatchConcurrency       = 10
+	maxBatchConcurrency       = 50
+	maxPolicyPatterns         = 100
+	maxPolicyPatternSize      = 256
+	defCertExpiryDays         = 30
+	maxCertExpiryDays         = 3650
+	bearerPrefix              = "Bearer "
+	expiryDateFormat          = "2006-01-02"
+	thingsItem                = "things"
+	channelsItem              = "channels"
+	groupsItem                = "groups"
+	accessTokenKey            = "access_token"
+	refreshTokenKey           = "refresh_token"
+	sessionDetailsKey         = "session"
+	twoFactorKey              = "two_factor"
+	channelKey                = "channel"
+	thingKey                  = "thing"
+	loggedInKey               = "logged_in"
 )
 
 var (
 	clientsHeaderLen = 5
 	groupsHeaderLen  = 3
-	minRows          = 2
+	// A connection is a thing and a channel, each given by id or name.
+	connectionsHeaderLen = 2
+	minRows              = 2
+
+	// The columns of a bootstrap provisioning file that precede the
+	// template variables.
+	provisionHeader = []string{ui.ExternalIDVar, ui.ExternalKeyVar, ui.NameVar}
 )
 
+// SessionConfig holds the limits applied to a user's session. A zero value
+// disables the corresponding check.
+type SessionConfig struct {
+	IdleTimeout time.Duration
+	MaxAge      time.Duration
+}
+
+// sessionActivityInterval is how often the latest activity of a session is
+// recorded in its cookie.
+const sessionActivityInterval = time.Minute
+
+// twoFactorPending holds the tokens of a user who passed the password step
+// of the login and still has to provide a two-factor authentication code.
+type twoFactorPending struct {
+	UserID       string    `json:"user_id"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
 type number interface {
 	int64 | float64 | uint16 | uint64
 }
 
 // MakeHandler returns a HTTP handler for API endpoints.
-func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCookie *securecookie.SecureCookie, providers ...oauth2.Provider) (http.Handler, error) {
+func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCookie *securecookie.SecureCookie, sessionCfg SessionConfig, securityCfg SecurityConfig, rateLimiter *RateLimiter, providers ...oauth2.Provider) (http.Handler, error) {
+	if err := securityCfg.validate(); err != nil {
+		return nil, err
+	}
+
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(encodeError(prefix)),
 	}
 
+	apiOpts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeAPIError),
+	}
+
+	r.Use(SecurityMiddleware(securityCfg))
+
 	var pathPrefix string
 	if prefix != "" {
 		pathPrefix = prefix
@@ -129,7 +199,7 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 			opts...,
 		).ServeHTTP)
 
-		r.Post("/login", kithttp.NewServer(
+		r.With(rateLimiter.Middleware("login", formIdentity("email"), prefix, false)).Post("/login", kithttp.NewServer(
 			tokenEndpoint(svc),
 			decodeTokenRequest,
 			encodeResponse,
@@ -150,6 +220,20 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 			opts...,
 		).ServeHTTP)
 
+		r.Get("/login/two-factor", kithttp.NewServer(
+			twoFactorLoginEndpoint(svc),
+			decodeTwoFactorLoginPageRequest,
+			encodeResponse,
+			opts...,
+		).ServeHTTP)
+
+		r.With(rateLimiter.Middleware("two_factor", twoFactorIdentity(secureCookie), prefix, false)).Post("/login/two-factor", kithttp.NewServer(
+			verifyTwoFactorLoginEndpoint(svc, secureCookie, prefix),
+			decodeTwoFactorLoginRequest(secureCookie),
+			encodeResponse,
+			opts...,
+		).ServeHTTP)
+
 		r.Get("/logout", kithttp.NewServer(
 			logoutEndpoint(svc, prefix),
 			decodeLogoutRequest,
@@ -163,7 +247,7 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 			}
 		}
 
-		r.Post("/reset-request", kithttp.NewServer(
+		r.With(rateLimiter.Middleware("reset_request", formIdentity("email"), prefix, true)).Post("/reset-request", kithttp.NewServer(
 			passwordResetRequestEndpoint(svc, prefix),
 			decodePasswordResetRequest,
 			encodeResponse,
@@ -191,8 +275,39 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 			opts...,
 		).ServeHTTP)
 
+		r.Get("/session/expired", kithttp.NewServer(
+			sessionExpiredEndpoint(svc),
+			decodeSessionExpired,
+			encodeResponse,
+			opts...,
+		).ServeHTTP)
+
+		r.Route("/api", func(r chi.Router) {
+			r.With(PersonalTokenMiddleware(svc, ui.EntitiesReadScope)).Get("/entities", kithttp.NewServer(
+				getEntitiesEndpoint(svc),
+				decodeGetEntitiesRequest,
+				encodeResponse,
+				apiOpts...,
+			).ServeHTTP)
+
+			r.With(PersonalTokenMiddleware(svc, ui.DataReadScope)).Get("/data", kithttp.NewServer(
+				FetchChartDataEndpoint(svc),
+				decodeReadMessagesRequest,
+				encodeResponse,
+				apiOpts...,
+			).ServeHTTP)
+
+			r.With(PersonalTokenMiddleware(svc, ui.DashboardsReadScope)).Get("/dashboards/list", kithttp.NewServer(
+				listDashboardsEndpoint(svc),
+				decodeListDashboardsRequest,
+				encodeResponse,
+				apiOpts...,
+			).ServeHTTP)
+		})
+
 		r.Route("/", func(r chi.Router) {
 			r.Use(DecryptCookieMiddleware(secureCookie, prefix))
+			r.Use(SessionTimeoutMiddleware(secureCookie, sessionCfg, prefix))
 			r.Use(TokenMiddleware(prefix))
 			r.Route("/", func(r chi.Router) {
 				r.Use(AuthnMiddleware(prefix))
@@ -248,6 +363,13 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 					opts...,
 				).ServeHTTP)
 
+				r.Get("/search", kithttp.NewServer(
+					searchEndpoint(svc),
+					decodeSearchRequest,
+					encodeResponse,
+					opts...,
+				).ServeHTTP)
+
 				r.Post("/password", kithttp.NewServer(
 					updatePasswordEndpoint(svc, prefix),
 					decodePasswordUpdate,
@@ -262,6 +384,29 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 					opts...,
 				).ServeHTTP)
 
+				r.Route("/tokens/personal", func(r chi.Router) {
+					r.Get("/", kithttp.NewServer(
+						personalTokensEndpoint(svc),
+						decodePersonalTokensRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Post("/", kithttp.NewServer(
+						createPersonalTokenEndpoint(svc),
+						decodeCreatePersonalTokenRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Post("/{id}/revoke", kithttp.NewServer(
+						revokePersonalTokenEndpoint(svc, prefix),
+						decodeRevokePersonalTokenRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+				})
+
 				r.Route("/users", func(r chi.Router) {
 					r.Use(AdminAuthMiddleware(prefix))
 					r.Post("/", kithttp.NewServer(
@@ -278,8 +423,22 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 						opts...,
 					).ServeHTTP)
 
+					r.Post("/actions", kithttp.NewServer(
+						bulkActionEndpoint(svc, ui.UsersEntity),
+						decodeBulkActionRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Get("/export", kithttp.NewServer(
+						exportEntitiesEndpoint(svc, ui.UsersEntity),
+						decodeExportEntitiesRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
 					r.Post("/bulk", kithttp.NewServer(
-						createUsersEndpoint(svc),
+						importEntitiesEndpoint(svc),
 						decodeUsersCreation,
 						encodeResponse,
 						opts...,
@@ -335,6 +494,38 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 					).ServeHTTP)
 				})
 
+				r.Route("/topology", func(r chi.Router) {
+					r.Get("/", kithttp.NewServer(
+						topologyEndpoint(svc),
+						decodeTopologyRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Get("/graph", kithttp.NewServer(
+						topologyGraphEndpoint(svc),
+						decodeTopologyRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+				})
+
+				r.Route("/hygiene", func(r chi.Router) {
+					r.Get("/", kithttp.NewServer(
+						hygieneReportEndpoint(svc),
+						decodeHygieneReportRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Post("/cleanup", kithttp.NewServer(
+						hygieneCleanupEndpoint(svc),
+						decodeHygieneCleanupRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+				})
+
 				r.Route("/things", func(r chi.Router) {
 					r.Post("/", kithttp.NewServer(
 						createThingEndpoint(svc),
@@ -343,8 +534,29 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 						opts...,
 					).ServeHTTP)
 
+					r.Post("/connections", kithttp.NewServer(
+						bulkConnectEndpoint(svc),
+						decodeBulkConnectRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Post("/actions", kithttp.NewServer(
+						bulkActionEndpoint(svc, ui.ThingsEntity),
+						decodeBulkActionRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Get("/export", kithttp.NewServer(
+						exportEntitiesEndpoint(svc, ui.ThingsEntity),
+						decodeExportEntitiesRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
 					r.Post("/bulk", kithttp.NewServer(
-						createThingsEndpoint(svc),
+						importEntitiesEndpoint(svc),
 						decodeThingsCreation,
 						encodeResponse,
 						opts...,
@@ -371,6 +583,55 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 						opts...,
 					).ServeHTTP)
 
+					r.Get("/provision", kithttp.NewServer(
+						provisionWizardEndpoint(svc),
+						decodeProvisionWizardRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Post("/provision", kithttp.NewServer(
+						provisionThingEndpoint(svc),
+						decodeProvisionThingRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Post("/secrets/rotate", kithttp.NewServer(
+						rotateThingSecretsEndpoint(svc),
+						decodeRotateThingSecretsRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Get("/rotations", kithttp.NewServer(
+						secretRotationsEndpoint(svc),
+						decodeListEntityRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Post("/rotations/schedules", kithttp.NewServer(
+						createRotationScheduleEndpoint(svc, prefix),
+						decodeCreateRotationScheduleRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Post("/rotations/schedules/{id}/delete", kithttp.NewServer(
+						deleteRotationScheduleEndpoint(svc, prefix),
+						decodeDeleteRotationScheduleRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Get("/rotations/{id}/secrets", kithttp.NewServer(
+						downloadRotatedSecretsEndpoint(svc),
+						decodeDownloadRotatedSecretsRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
 					r.Get("/{id}", kithttp.NewServer(
 						viewThingEndpoint(svc),
 						decodeView,
@@ -450,8 +711,22 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 						opts...,
 					).ServeHTTP)
 
+					r.Post("/actions", kithttp.NewServer(
+						bulkActionEndpoint(svc, ui.ChannelsEntity),
+						decodeBulkActionRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Get("/export", kithttp.NewServer(
+						exportEntitiesEndpoint(svc, ui.ChannelsEntity),
+						decodeExportEntitiesRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
 					r.Post("/bulk", kithttp.NewServer(
-						createChannelsEndpoint(svc),
+						importEntitiesEndpoint(svc),
 						decodeChannelsCreation,
 						encodeResponse,
 						opts...,
@@ -564,8 +839,22 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 						opts...,
 					).ServeHTTP)
 
+					r.Post("/actions", kithttp.NewServer(
+						bulkActionEndpoint(svc, ui.GroupsEntity),
+						decodeBulkActionRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Get("/export", kithttp.NewServer(
+						exportEntitiesEndpoint(svc, ui.GroupsEntity),
+						decodeExportEntitiesRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
 					r.Post("/bulk", kithttp.NewServer(
-						createGroupsEndpoint(svc),
+						importEntitiesEndpoint(svc),
 						decodeGroupsCreation,
 						encodeResponse,
 						opts...,
@@ -592,6 +881,20 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 						opts...,
 					).ServeHTTP)
 
+					r.Get("/tree", kithttp.NewServer(
+						groupTreeEndpoint(svc),
+						decodeGroupTreeRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Post("/{id}/parent", kithttp.NewServer(
+						moveGroupEndpoint(svc, prefix),
+						decodeMoveGroupRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
 					r.Get("/{id}", kithttp.NewServer(
 						viewGroupEndpoint(svc),
 						decodeView,
@@ -679,6 +982,113 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 						opts...,
 					).ServeHTTP)
 
+					r.Get("/terminal/batch", kithttp.NewServer(
+						batchTerminalEndpoint(svc),
+						decodeIndexRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Post("/terminal/batch", kithttp.NewServer(
+						runBatchCommandEndpoint(svc),
+						decodeBatchCommandRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Get("/terminal/audit", kithttp.NewServer(
+						terminalAuditEndpoint(svc),
+						decodeTerminalAuditRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Get("/terminal/audit/export", kithttp.NewServer(
+						exportTerminalAuditEndpoint(svc),
+						decodeTerminalAuditRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Route("/templates", func(r chi.Router) {
+						r.Get("/", kithttp.NewServer(
+							bootstrapTemplatesEndpoint(svc),
+							decodeListEntityRequest,
+							encodeResponse,
+							opts...,
+						).ServeHTTP)
+
+						r.Post("/", kithttp.NewServer(
+							createBootstrapTemplateEndpoint(svc, prefix),
+							decodeCreateBootstrapTemplateRequest,
+							encodeResponse,
+							opts...,
+						).ServeHTTP)
+
+						r.Post("/{id}", kithttp.NewServer(
+							updateBootstrapTemplateEndpoint(svc, prefix),
+							decodeUpdateBootstrapTemplateRequest,
+							encodeResponse,
+							opts...,
+						).ServeHTTP)
+
+						r.Post("/{id}/delete", kithttp.NewServer(
+							deleteBootstrapTemplateEndpoint(svc, prefix),
+							decodeDeleteBootstrapTemplateRequest,
+							encodeResponse,
+							opts...,
+						).ServeHTTP)
+
+						r.Post("/{id}/provision", kithttp.NewServer(
+							provisionBootstrapsEndpoint(svc),
+							decodeProvisionBootstrapsRequest,
+							encodeResponse,
+							opts...,
+						).ServeHTTP)
+					})
+
+					r.Get("/bundle", kithttp.NewServer(
+						bootstrapBundleEndpoint(svc),
+						decodeIndexRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Post("/bundle/export", kithttp.NewServer(
+						exportBundleEndpoint(svc),
+						decodeExportBundleRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Post("/bundle/import", kithttp.NewServer(
+						importBundleEndpoint(svc),
+						decodeImportBundleRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Get("/states", kithttp.NewServer(
+						bootstrapStatesEndpoint(svc),
+						decodeBootstrapStatesRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Post("/states", kithttp.NewServer(
+						updateBootstrapStatesEndpoint(svc),
+						decodeUpdateBootstrapStatesRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Get("/certs/expiring", kithttp.NewServer(
+						expiringCertsEndpoint(svc),
+						decodeExpiringCertsRequest,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
 					r.Post("/", kithttp.NewServer(
 						createBootstrap(svc, prefix),
 						decodeCreateBootstrapRequest,
@@ -721,6 +1131,20 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 						opts...,
 					).ServeHTTP)
 
+					r.Post("/{id}/content", kithttp.NewServer(
+						updateBootstrapContentEndpoint(svc, prefix),
+						decodeUpdateBootstrapContent,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
+					r.Post("/{id}/content/diff", kithttp.NewServer(
+						bootstrapContentDiffEndpoint(svc),
+						decodeBootstrapContentDiff,
+						encodeResponse,
+						opts...,
+					).ServeHTTP)
+
 					r.Post("/{id}/connections", kithttp.NewServer(
 						updateBootstrapConnections(svc, prefix),
 						decodeUpdateBootstrapConnections,
@@ -735,12 +1159,7 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 						opts...,
 					).ServeHTTP)
 
-					r.Post("/{id}/terminal/input", kithttp.NewServer(
-						handleTerminalInputEndpoint(svc),
-						decodeTerminalCommandRequest,
-						encodeJSONResponse,
-						opts...,
-					).ServeHTTP)
+					r.Get("/{id}/terminal/ws", terminalHandler(svc, sessionCfg, prefix))
 				})
 
 				r.Route("/invitations", func(r chi.Router) {
@@ -773,6 +1192,36 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 					).ServeHTTP)
 				})
 			})
+			r.Route("/two-factor", func(r chi.Router) {
+				r.Get("/", kithttp.NewServer(
+					twoFactorEndpoint(svc),
+					decodeTwoFactorRequest,
+					encodeResponse,
+					opts...,
+				).ServeHTTP)
+
+				r.Post("/enroll", kithttp.NewServer(
+					enrollTwoFactorEndpoint(svc, prefix),
+					decodeTwoFactorRequest,
+					encodeResponse,
+					opts...,
+				).ServeHTTP)
+
+				r.Post("/confirm", kithttp.NewServer(
+					confirmTwoFactorEndpoint(svc),
+					decodeTwoFactorCodeRequest,
+					encodeResponse,
+					opts...,
+				).ServeHTTP)
+
+				r.Post("/disable", kithttp.NewServer(
+					disableTwoFactorEndpoint(svc, prefix),
+					decodeTwoFactorCodeRequest,
+					encodeResponse,
+					opts...,
+				).ServeHTTP)
+			})
+
 			r.Route("/domains", func(r chi.Router) {
 				r.Post("/login", kithttp.NewServer(
 					domainLoginEndpoint(svc, secureCookie, prefix),
@@ -823,6 +1272,20 @@ func MakeHandler(svc ui.Service, r *chi.Mux, instanceID, prefix string, secureCo
 					opts...,
 				).ServeHTTP)
 
+				r.Post("/{id}/two-factor", kithttp.NewServer(
+					updateDomainTwoFactorEndpoint(svc, prefix),
+					decodeUpdateDomainTwoFactorRequest,
+					encodeResponse,
+					opts...,
+				).ServeHTTP)
+
+				r.Post("/{id}/terminal-policy", kithttp.NewServer(
+					updateTerminalPolicyEndpoint(svc, prefix),
+					decodeUpdateTerminalPolicyRequest,
+					encodeResponse,
+					opts...,
+				).ServeHTTP)
+
 				r.Post("/{id}/tags", kithttp.NewServer(
 					updateDomainTagsEndpoint(svc),
 					decodeUpdateDomainTagsRequest,
@@ -1040,6 +1503,10 @@ func decodeShowPasswordReset(_ context.Context, _ *http.Request) (interface{}, e
 	return nil, nil
 }
 
+func decodeSessionExpired(_ context.Context, _ *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
 func decodeRegisterUserRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	return registerUserReq{
 		User: sdk.User{
@@ -1079,6 +1546,53 @@ func decodeSecureTokenRequest(_ context.Context, r *http.Request) (interface{},
 	}, nil
 }
 
+func decodeTwoFactorLoginPageRequest(_ context.Context, _ *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
+func decodeTwoFactorLoginRequest(s *securecookie.SecureCookie) kithttp.DecodeRequestFunc {
+	return func(_ context.Context, r *http.Request) (interface{}, error) {
+		pending, err := twoFactorPendingFromCookie(s, r)
+		if err != nil {
+			return nil, err
+		}
+
+		return twoFactorLoginReq{
+			pending: pending,
+			code:    r.PostFormValue("code"),
+		}, nil
+	}
+}
+
+// twoFactorIdentity reads the user of the pending two-factor login, so that
+// the codes of a user are limited whatever address they are sent from.
+func twoFactorIdentity(s *securecookie.SecureCookie) func(*http.Request) string {
+	return func(r *http.Request) string {
+		pending, err := twoFactorPendingFromCookie(s, r)
+		if err != nil {
+			return ""
+		}
+		return pending.UserID
+	}
+}
+
+func twoFactorPendingFromCookie(s *securecookie.SecureCookie, r *http.Request) (twoFactorPending, error) {
+	pendingCookie, err := r.Cookie(twoFactorKey)
+	if err != nil {
+		return twoFactorPending{}, errTwoFactorSession
+	}
+	var pendingJSON string
+	if err := s.Decode(twoFactorKey, pendingCookie.Value, &pendingJSON); err != nil {
+		return twoFactorPending{}, errors.Wrap(errTwoFactorSession, err)
+	}
+	var pending twoFactorPending
+	if err := json.Unmarshal([]byte(pendingJSON), &pending); err != nil {
+		return twoFactorPending{}, errors.Wrap(errTwoFactorSession, err)
+	}
+
+	return pending, nil
+}
+
 func decodeRefreshTokenRequest(s *securecookie.SecureCookie) kithttp.DecodeRequestFunc {
 	return func(_ context.Context, r *http.Request) (interface{}, error) {
 		sessionCookie, err := tokenFromCookie(r, sessionDetailsKey)
@@ -1163,12 +1677,14 @@ func decodeUserCreation(_ context.Context, r *http.Request) (interface{}, error)
 	}, nil
 }
 
-func decodeUsersCreation(_ context.Context, r *http.Request) (interface{}, error) {
+// decodeImportRequest reads a bulk import file. Only the file itself is
+// checked here, the rows are checked one by one by the import.
+func decodeImportRequest(r *http.Request, entity, field string, headerLen int) (interface{}, error) {
 	session, err := sessionFromHeader(r)
 	if err != nil {
 		return nil, err
 	}
-	file, handler, err := r.FormFile("usersFile")
+	file, handler, err := r.FormFile(field)
 	if err != nil {
 		return nil, err
 	}
@@ -1178,6 +1694,8 @@ func decodeUsersCreation(_ context.Context, r *http.Request) (interface{}, error
 		return nil, errInvalidFile
 	}
 	reader := csv.NewReader(file)
+	// Rows with the wrong number of columns are reported with the others.
+	reader.FieldsPerRecord = -1
 
 	rows, err := reader.ReadAll()
 	if err != nil {
@@ -1188,46 +1706,57 @@ func decodeUsersCreation(_ context.Context, r *http.Request) (interface{}, error
 		return nil, errFileFormat
 	}
 
-	if len(rows[0]) != clientsHeaderLen {
+	if len(rows[0]) != headerLen {
 		return nil, errFileFormat
 	}
 
-	users := []sdk.User{}
+	return importEntitiesReq{
+		Session: session,
+		imp: ui.EntityImport{
+			Entity:  entity,
+			Records: rows[1:],
+			DryRun:  r.PostFormValue("dryRun") == "true",
+		},
+	}, nil
+}
 
-	for _, row := range rows[1:] {
-		var user sdk.User
+func decodeBulkConnectRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	file, handler, err := r.FormFile("connectionsFile")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
 
-		if row[1] == "" && row[2] == "" {
-			return nil, errFileFormat
-		}
-		user.Credentials.Identity = row[1]
-		user.Credentials.Secret = row[2]
-
-		if row[0] != "" {
-			user.Name = row[0]
-		}
-
-		if row[3] != "" {
-			if err := json.Unmarshal([]byte(row[3]), &user.Metadata); err != nil {
-				return nil, errFileFormat
-			}
-		}
-
-		if row[4] != "" {
-			if err := json.Unmarshal([]byte(row[4]), &user.Tags); err != nil {
-				return nil, errFileFormat
-			}
-		}
+	if !strings.HasSuffix(handler.Filename, ".csv") {
+		return nil, errInvalidFile
+	}
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, errFileFormat
+	}
+	if len(records) < minRows || len(records[0]) != connectionsHeaderLen {
+		return nil, errFileFormat
+	}
 
-		users = append(users, user)
+	rows := make([]ui.ConnectionRow, len(records)-1)
+	for i, record := range records[1:] {
+		rows[i] = ui.ConnectionRow{Thing: record[0], Channel: record[1]}
 	}
 
-	return createUsersReq{
-		token: session.Token,
-		users: users,
+	return bulkConnectReq{
+		Session: session,
+		rows:    rows,
 	}, nil
 }
 
+func decodeUsersCreation(_ context.Context, r *http.Request) (interface{}, error) {
+	return decodeImportRequest(r, ui.UsersEntity, "usersFile", clientsHeaderLen)
+}
+
 func decodeView(_ context.Context, r *http.Request) (interface{}, error) {
 	session, err := sessionFromHeader(r)
 	if err != nil {
@@ -1469,72 +1998,7 @@ func decodeThingStatusUpdate(_ context.Context, r *http.Request) (interface{}, e
 }
 
 func decodeThingsCreation(_ context.Context, r *http.Request) (interface{}, error) {
-	session, err := sessionFromHeader(r)
-	if err != nil {
-		return nil, err
-	}
-	file, handler, err := r.FormFile("thingsFile")
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	if !strings.HasSuffix(handler.Filename, ".csv") {
-		return nil, errInvalidFile
-	}
-	reader := csv.NewReader(file)
-
-	rows, err := reader.ReadAll()
-	if err != nil {
-		return nil, errFileFormat
-	}
-
-	if len(rows) < minRows {
-		return nil, errFileFormat
-	}
-
-	if len(rows[0]) != clientsHeaderLen {
-		return nil, errFileFormat
-	}
-
-	things := []sdk.Thing{}
-
-	for _, row := range rows[1:] {
-		if row[0] == "" && row[1] == "" && row[2] == "" && row[3] == "" && row[4] == "" {
-			continue
-		}
-		var thing sdk.Thing
-		if row[0] != "" {
-			thing.Name = row[0]
-		}
-
-		if row[1] != "" {
-			thing.Credentials.Identity = row[1]
-		}
-
-		if row[2] != "" {
-			thing.Credentials.Secret = row[2]
-		}
-
-		if row[3] != "" {
-			if err := json.Unmarshal([]byte(row[3]), &thing.Tags); err != nil {
-				return nil, errFileFormat
-			}
-		}
-
-		if row[4] != "" {
-			if err := json.Unmarshal([]byte(row[4]), &thing.Metadata); err != nil {
-				return nil, errFileFormat
-			}
-		}
-
-		things = append(things, thing)
-	}
-
-	return createThingsReq{
-		token:  session.Token,
-		things: things,
-	}, nil
+	return decodeImportRequest(r, ui.ThingsEntity, "thingsFile", clientsHeaderLen)
 }
 
 func decodeChannelCreation(_ context.Context, r *http.Request) (interface{}, error) {
@@ -1560,60 +2024,7 @@ func decodeChannelCreation(_ context.Context, r *http.Request) (interface{}, err
 }
 
 func decodeChannelsCreation(_ context.Context, r *http.Request) (interface{}, error) {
-	session, err := sessionFromHeader(r)
-	if err != nil {
-		return nil, err
-	}
-	file, handler, err := r.FormFile("channelsFile")
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	if !strings.HasSuffix(handler.Filename, ".csv") {
-		return nil, errInvalidFile
-	}
-	reader := csv.NewReader(file)
-
-	rows, err := reader.ReadAll()
-	if err != nil {
-		return nil, errInvalidFile
-	}
-
-	if len(rows) < minRows {
-		return nil, errFileFormat
-	}
-
-	if len(rows[0]) != groupsHeaderLen {
-		return nil, errFileFormat
-	}
-
-	channels := []sdk.Channel{}
-
-	for _, row := range rows[1:] {
-		var channel sdk.Channel
-		if row[0] == "" {
-			return nil, errFileFormat
-		}
-		channel.Name = row[0]
-
-		if row[1] != "" {
-			if err := json.Unmarshal([]byte(row[1]), &channel.Metadata); err != nil {
-				return nil, errFileFormat
-			}
-		}
-
-		if row[2] != "" {
-			channel.Description = row[2]
-		}
-
-		channels = append(channels, channel)
-	}
-
-	return createChannelsReq{
-		token:    session.Token,
-		Channels: channels,
-	}, nil
+	return decodeImportRequest(r, ui.ChannelsEntity, "channelsFile", groupsHeaderLen)
 }
 
 func decodeChannelUpdate(_ context.Context, r *http.Request) (interface{}, error) {
@@ -1733,60 +2144,7 @@ func decodeGroupCreation(_ context.Context, r *http.Request) (interface{}, error
 }
 
 func decodeGroupsCreation(_ context.Context, r *http.Request) (interface{}, error) {
-	session, err := sessionFromHeader(r)
-	if err != nil {
-		return nil, err
-	}
-	file, handler, err := r.FormFile("groupsFile")
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	if !strings.HasSuffix(handler.Filename, ".csv") {
-		return nil, errInvalidFile
-	}
-	reader := csv.NewReader(file)
-
-	rows, err := reader.ReadAll()
-	if err != nil {
-		return nil, errInvalidFile
-	}
-
-	if len(rows) < minRows {
-		return nil, errFileFormat
-	}
-
-	if len(rows[0]) != groupsHeaderLen {
-		return nil, errFileFormat
-	}
-
-	groups := []sdk.Group{}
-
-	for _, row := range rows[1:] {
-		var group sdk.Group
-		if row[0] == "" {
-			return nil, errFileFormat
-		}
-		group.Name = row[0]
-
-		if row[1] != "" {
-			if err := json.Unmarshal([]byte(row[1]), &group.Metadata); err != nil {
-				return nil, errFileFormat
-			}
-		}
-
-		if row[2] != "" {
-			group.Description = row[2]
-		}
-
-		groups = append(groups, group)
-	}
-
-	return createGroupsReq{
-		token:  session.Token,
-		Groups: groups,
-	}, nil
+	return decodeImportRequest(r, ui.GroupsEntity, "groupsFile", groupsHeaderLen)
 }
 
 func decodeGroupUpdate(_ context.Context, r *http.Request) (interface{}, error) {
@@ -1947,15 +2305,83 @@ func decodeReadMessagesRequest(_ context.Context, r *http.Request) (interface{},
 	}, nil
 }
 
-func decodeTerminalCommandRequest(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeBatchCommandRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	session, err := sessionFromHeader(r)
 	if err != nil {
 		return nil, err
 	}
-	return bootstrapCommandReq{
-		token:   session.Token,
-		id:      chi.URLParam(r, "id"),
-		command: strings.ReplaceAll(strings.Trim(r.PostFormValue("command"), " "), " ", ","),
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	timeout, concurrency := defBatchTimeout, defBatchConcurrency
+	if val := r.PostFormValue("timeout"); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, errors.Wrap(errInvalidFormValue, err)
+		}
+		timeout = time.Duration(secs) * time.Second
+	}
+	if val := r.PostFormValue("concurrency"); val != "" {
+		if concurrency, err = strconv.Atoi(val); err != nil {
+			return nil, errors.Wrap(errInvalidFormValue, err)
+		}
+	}
+
+	return batchCommandReq{
+		Session: session,
+		batch: ui.BatchCommand{
+			Command:     agentCommand(r.PostFormValue("command")),
+			ThingIDs:    r.PostForm["thingID"],
+			Tag:         r.PostFormValue(tagKey),
+			State:       r.PostFormValue("state"),
+			Timeout:     timeout,
+			Concurrency: concurrency,
+		},
+	}, nil
+}
+
+func decodeTerminalAuditRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := readNumQuery[uint64](r, pageKey, defPage)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := readNumQuery[uint64](r, limitKey, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	pm := ui.TerminalCommandPageMeta{}
+	if pm.UserID, err = readStringQuery(r, userKey, ""); err != nil {
+		return nil, err
+	}
+	if pm.ThingID, err = readStringQuery(r, thingKey, ""); err != nil {
+		return nil, err
+	}
+	if pm.Command, err = readStringQuery(r, commandKey, ""); err != nil {
+		return nil, err
+	}
+	if pm.From, err = readDateQuery(r, fromKey); err != nil {
+		return nil, err
+	}
+	if pm.To, err = readDateQuery(r, toKey); err != nil {
+		return nil, err
+	}
+	// The end date is inclusive.
+	if !pm.To.IsZero() {
+		pm.To = pm.To.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return terminalAuditReq{
+		Session: session,
+		page:    page,
+		limit:   limit,
+		pm:      pm,
 	}, nil
 }
 
@@ -2002,6 +2428,41 @@ func decodeUpdateBootstrap(_ context.Context, r *http.Request) (interface{}, err
 	}, nil
 }
 
+func decodeUpdateBootstrapContent(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return updateBootstrapReq{
+		token: session.Token,
+		BootstrapConfig: sdk.BootstrapConfig{
+			ThingID: chi.URLParam(r, "id"),
+			Name:    r.PostFormValue("name"),
+			Content: r.PostFormValue("content"),
+		},
+	}, nil
+}
+
+func decodeBootstrapContentDiff(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return bootstrapContentDiffReq{
+		Session: session,
+		thingID: chi.URLParam(r, "id"),
+		content: r.PostFormValue("content"),
+	}, nil
+}
+
 func decodeDeleteBootstrap(_ context.Context, r *http.Request) (interface{}, error) {
 	session, err := sessionFromHeader(r)
 	if err != nil {
@@ -2049,61 +2510,168 @@ func decodeUpdateBootstrapCerts(_ context.Context, r *http.Request) (interface{}
 	}, nil
 }
 
-func decodeUpdateBootstrapConnections(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeExpiringCertsRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	session, err := sessionFromHeader(r)
 	if err != nil {
 		return nil, err
 	}
-	if err := r.ParseForm(); err != nil {
+
+	days, err := readNumQuery[uint64](r, daysKey, defCertExpiryDays)
+	if err != nil {
 		return nil, err
 	}
 
-	return updateBootstrapConnReq{
-		token: session.Token,
-		BootstrapConfig: sdk.BootstrapConfig{
-			ThingID:  chi.URLParam(r, "id"),
-			Channels: r.PostForm["channelID"],
-		},
+	return expiringCertsReq{
+		Session: session,
+		days:    days,
 	}, nil
 }
 
-func decodeListEntityRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	page, err := readNumQuery[uint64](r, pageKey, defPage)
+func decodeBootstrapStatesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
 	if err != nil {
 		return nil, err
 	}
 
-	limit, err := readNumQuery[uint64](r, limitKey, defLimit)
+	state, err := readStringQuery(r, stateKey, defKey)
 	if err != nil {
 		return nil, err
 	}
-
-	status, err := readStringQuery(r, statusKey, defKey)
+	externalID, err := readStringQuery(r, externalIDKey, defKey)
 	if err != nil {
 		return nil, err
 	}
 
+	return bootstrapStatesReq{
+		Session: session,
+		filter: ui.BootstrapStateFilter{
+			State:      state,
+			ExternalID: externalID,
+		},
+	}, nil
+}
+
+func decodeUpdateBootstrapStatesRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	session, err := sessionFromHeader(r)
 	if err != nil {
 		return nil, err
 	}
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
 
-	return listEntityReq{
-		status:  status,
-		page:    page,
-		limit:   limit,
+	return updateBootstrapStatesReq{
 		Session: session,
+		change: ui.BootstrapStateChange{
+			ThingIDs: r.PostForm["thingID"],
+			State:    r.PostFormValue(stateKey),
+		},
 	}, nil
 }
 
-func decodeListEntityByIDRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	page, err := readNumQuery[uint64](r, pageKey, defPage)
+func decodeUpdateBootstrapConnections(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
 	if err != nil {
 		return nil, err
 	}
-
-	limit, err := readNumQuery[uint64](r, limitKey, defLimit)
-	if err != nil {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return updateBootstrapConnReq{
+		token: session.Token,
+		BootstrapConfig: sdk.BootstrapConfig{
+			ThingID:  chi.URLParam(r, "id"),
+			Channels: r.PostForm["channelID"],
+		},
+	}, nil
+}
+
+func decodeListEntityRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	page, err := readNumQuery[uint64](r, pageKey, defPage)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := readNumQuery[uint64](r, limitKey, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := readStringQuery(r, statusKey, defKey)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return listEntityReq{
+		status:  status,
+		page:    page,
+		limit:   limit,
+		Session: session,
+	}, nil
+}
+
+func decodeExportEntitiesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := readStringQuery(r, statusKey, defKey)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := readBoolQuery(r, secretsKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return exportEntitiesReq{
+		Session: session,
+		export: ui.EntityExport{
+			Status:  status,
+			Secrets: secrets,
+		},
+	}, nil
+}
+
+func decodeBulkActionRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return bulkActionReq{
+		Session: session,
+		action: ui.EntityBulkAction{
+			Action: r.PostFormValue("action"),
+			Tag:    strings.TrimSpace(r.PostFormValue(tagKey)),
+			IDs:    r.PostForm["entityID"],
+			Filter: ui.BulkFilter{
+				Name:   strings.TrimSpace(r.PostFormValue("filterName")),
+				Tag:    strings.TrimSpace(r.PostFormValue("filterTag")),
+				Status: r.PostFormValue("filterStatus"),
+			},
+		},
+	}, nil
+}
+
+func decodeListEntityByIDRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	page, err := readNumQuery[uint64](r, pageKey, defPage)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := readNumQuery[uint64](r, limitKey, defLimit)
+	if err != nil {
 		return nil, err
 	}
 
@@ -2126,6 +2694,207 @@ func decodeListEntityByIDRequest(_ context.Context, r *http.Request) (interface{
 	}, nil
 }
 
+func decodeTopologyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	groupID, err := readStringQuery(r, groupKey, defKey)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := readStringQuery(r, tagKey, defKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return topologyReq{
+		Session: session,
+		filter: ui.TopologyFilter{
+			GroupID: groupID,
+			Tag:     strings.TrimSpace(tag),
+		},
+	}, nil
+}
+
+func decodeGroupTreeRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := readStringQuery(r, parentKey, defKey)
+	if err != nil {
+		return nil, err
+	}
+	page, err := readNumQuery[uint64](r, pageKey, defPage)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := readNumQuery[uint64](r, limitKey, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupTreeReq{
+		Session:  session,
+		parentID: parentID,
+		page:     page,
+		limit:    limit,
+	}, nil
+}
+
+func decodeMoveGroupRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return moveGroupReq{
+		Session:  session,
+		groupID:  chi.URLParam(r, "id"),
+		parentID: r.PostFormValue("parentID"),
+	}, nil
+}
+
+func decodeHygieneReportRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return hygieneReportReq{
+		Session: session,
+	}, nil
+}
+
+func decodeHygieneCleanupRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return hygieneCleanupReq{
+		Session: session,
+		cleanup: ui.HygieneCleanup{
+			Category: r.PostFormValue("category"),
+			Action:   r.PostFormValue("action"),
+			IDs:      r.PostForm["entityID"],
+		},
+	}, nil
+}
+
+func decodeProvisionWizardRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return provisionWizardReq{
+		Session: session,
+	}, nil
+}
+
+func decodeProvisionThingRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return provisionThingReq{
+		Session: session,
+		provision: ui.ThingProvision{
+			Name:         strings.TrimSpace(r.PostFormValue("name")),
+			Tags:         readLines(r.PostFormValue("tags")),
+			ChannelIDs:   readLines(r.PostFormValue("channelIDs")),
+			ChannelNames: readLines(r.PostFormValue("channelNames")),
+			Bootstrap:    r.PostFormValue("bootstrap") == "true",
+			ExternalID:   strings.TrimSpace(r.PostFormValue("externalID")),
+			ExternalKey:  strings.TrimSpace(r.PostFormValue("externalKey")),
+			Content:      r.PostFormValue("content"),
+			Dashboard:    r.PostFormValue("dashboard") == "true",
+			ValueName:    strings.TrimSpace(r.PostFormValue("valueName")),
+		},
+	}, nil
+}
+
+func decodeRotateThingSecretsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return rotateThingSecretsReq{
+		Session:  session,
+		thingIDs: r.PostForm["entityID"],
+	}, nil
+}
+
+func decodeCreateRotationScheduleRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	hours, err := strconv.Atoi(r.PostFormValue("intervalHours"))
+	if err != nil {
+		return nil, errors.Wrap(errInvalidFormValue, err)
+	}
+
+	return createRotationScheduleReq{
+		Session:  session,
+		password: r.PostFormValue("password"),
+		schedule: ui.RotationSchedule{
+			Tag:      strings.TrimSpace(r.PostFormValue(tagKey)),
+			Interval: time.Duration(hours) * time.Hour,
+		},
+	}, nil
+}
+
+func decodeDeleteRotationScheduleRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return deleteRotationScheduleReq{
+		Session: session,
+		id:      chi.URLParam(r, "id"),
+	}, nil
+}
+
+func decodeDownloadRotatedSecretsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return downloadRotatedSecretsReq{
+		Session: session,
+		runID:   chi.URLParam(r, "id"),
+	}, nil
+}
+
+func decodeSearchRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	query, err := readStringQuery(r, queryKey, defKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return searchReq{
+		Session: session,
+		query:   query,
+	}, nil
+}
+
 func decodeGetEntitiesRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	session, err := sessionFromHeader(r)
 	if err != nil {
@@ -2198,6 +2967,49 @@ func decodeDomainLoginRequest(s *securecookie.SecureCookie) kithttp.DecodeReques
 	}
 }
 
+func decodeUpdateDomainTwoFactorRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateDomainTwoFactorReq{
+		Session:  session,
+		id:       chi.URLParam(r, "id"),
+		required: r.PostFormValue("required") == "true",
+	}, nil
+}
+
+func decodeUpdateTerminalPolicyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateTerminalPolicyReq{
+		Session: session,
+		policy: ui.TerminalPolicy{
+			DomainID:  chi.URLParam(r, "id"),
+			AdminOnly: r.PostFormValue("adminOnly") == "true",
+			ReadOnly:  r.PostFormValue("readOnly") == "true",
+			Allow:     readLines(r.PostFormValue("allow")),
+			Deny:      readLines(r.PostFormValue("deny")),
+		},
+	}, nil
+}
+
+// readLines reads the values of a form field entered one per line.
+func readLines(val string) []string {
+	values := []string{}
+	for _, line := range strings.Split(val, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			values = append(values, line)
+		}
+	}
+
+	return values
+}
+
 func decodeListDomainsRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	page, err := readNumQuery[uint64](r, pageKey, defPage)
 	if err != nil {
@@ -2379,57 +3191,147 @@ func decodeListInvitationsRequest(_ context.Context, r *http.Request) (interface
 	}, nil
 }
 
-func decodeAcceptInvitationRequest(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeAcceptInvitationRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return acceptInvitationReq{
+		token:    session.Token,
+		domainID: r.Form.Get("domainID"),
+	}, nil
+}
+
+func decodeDeleteInvitationRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	domain, err := readStringQuery(r, domainKey, defKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return deleteInvitationReq{
+		token:    session.Token,
+		domain:   domain,
+		domainID: r.Form.Get("domainID"),
+		userID:   r.Form.Get("userID"),
+	}, nil
+}
+
+func decodeError(_ context.Context, r *http.Request) (interface{}, error) {
+	errValue, err := readStringQuery(r, "error", "")
+	if err != nil {
+		return nil, err
+	}
+	return errorReq{
+		err: errValue,
+	}, nil
+}
+
+func decodePageNotFound(_ context.Context, r *http.Request) (interface{}, error) {
+	return errorReq{
+		pageURL: r.URL.String(),
+		err:     "Whoops! Page not found",
+	}, nil
+}
+
+func decodePersonalTokensRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	page, err := readNumQuery[uint64](r, pageKey, defPage)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := readNumQuery[uint64](r, limitKey, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return personalTokensReq{
+		Session: session,
+		page:    page,
+		limit:   limit,
+	}, nil
+}
+
+func decodeTwoFactorRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return twoFactorReq{
+		Session: session,
+	}, nil
+}
+
+func decodeTwoFactorCodeRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	session, err := sessionFromHeader(r)
 	if err != nil {
 		return nil, err
 	}
-	if err := r.ParseForm(); err != nil {
-		return nil, err
-	}
 
-	return acceptInvitationReq{
-		token:    session.Token,
-		domainID: r.Form.Get("domainID"),
+	return twoFactorCodeReq{
+		Session: session,
+		code:    r.PostFormValue("code"),
 	}, nil
 }
 
-func decodeDeleteInvitationRequest(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeCreatePersonalTokenRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	session, err := sessionFromHeader(r)
 	if err != nil {
 		return nil, err
 	}
-	domain, err := readStringQuery(r, domainKey, defKey)
-	if err != nil {
-		return nil, err
+
+	name := r.PostFormValue("name")
+
+	var scopes []ui.TokenScope
+	for _, scope := range r.PostForm["scopes"] {
+		scopes = append(scopes, ui.TokenScope(scope))
 	}
 
-	if err := r.ParseForm(); err != nil {
-		return nil, err
+	var expiresAt time.Time
+	if exp := r.PostFormValue("expiresAt"); exp != "" {
+		expiresAt, err = time.Parse(expiryDateFormat, exp)
+		if err != nil {
+			return nil, errors.Wrap(errInvalidFormValue, err)
+		}
 	}
 
-	return deleteInvitationReq{
-		token:    session.Token,
-		domain:   domain,
-		domainID: r.Form.Get("domainID"),
-		userID:   r.Form.Get("userID"),
+	return createPersonalTokenReq{
+		Session:  session,
+		password: r.PostFormValue("password"),
+		ptReq: ui.PersonalTokenReq{
+			Name:      name,
+			Scopes:    scopes,
+			ExpiresAt: expiresAt,
+		},
 	}, nil
 }
 
-func decodeError(_ context.Context, r *http.Request) (interface{}, error) {
-	errValue, err := readStringQuery(r, "error", "")
+func decodeRevokePersonalTokenRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
 	if err != nil {
 		return nil, err
 	}
-	return errorReq{
-		err: errValue,
-	}, nil
-}
 
-func decodePageNotFound(_ context.Context, r *http.Request) (interface{}, error) {
-	return errorReq{
-		pageURL: r.URL.String(),
-		err:     "Whoops! Page not found",
+	return revokePersonalTokenReq{
+		Session: session,
+		id:      chi.URLParam(r, "id"),
 	}, nil
 }
 
@@ -2446,6 +3348,22 @@ func readStringQuery(r *http.Request, key string, def string) (string, error) {
 	return vals[0], nil
 }
 
+// readDateQuery reads a date in the YYYY-MM-DD format, returning the zero
+// time when the query parameter is missing or empty.
+func readDateQuery(r *http.Request, key string) (time.Time, error) {
+	val, err := readStringQuery(r, key, "")
+	if err != nil || val == "" {
+		return time.Time{}, err
+	}
+
+	date, err := time.Parse(expiryDateFormat, val)
+	if err != nil {
+		return time.Time{}, errors.Wrap(errInvalidQueryParams, err)
+	}
+
+	return date, nil
+}
+
 func readNumQuery[N number](r *http.Request, key string, def N) (N, error) {
 	vals := bone.GetQuery(r, key)
 	if len(vals) > 1 {
@@ -2608,6 +3526,136 @@ func DecryptCookieMiddleware(s *securecookie.SecureCookie, prefix string) func(h
 	}
 }
 
+// SessionTimeoutMiddleware ends sessions that have been idle for longer than
+// the configured idle timeout or that have outlived the configured maximum
+// age, and records the latest activity for those that are still valid. The
+// activity is recorded at most once every sessionActivityInterval, so that
+// the session cookie is only set again when the idle deadline moves.
+func SessionTimeoutMiddleware(s *securecookie.SecureCookie, cfg SessionConfig, prefix string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session, err := sessionFromHeader(r)
+			if err != nil {
+				http.Redirect(w, r, fmt.Sprintf("%s/%s", prefix, loginAPIEndpoint), http.StatusSeeOther)
+				return
+			}
+
+			now := time.Now()
+			if sessionExpired(session, cfg, now) {
+				for _, cookie := range clearSessionCookies(prefix) {
+					http.SetCookie(w, cookie)
+				}
+				http.Redirect(w, r, fmt.Sprintf("%s/%s", prefix, sessionExpiredAPIEndpoint), http.StatusSeeOther)
+				return
+			}
+
+			if cfg.IdleTimeout <= 0 || now.Sub(session.LastActivity) < sessionActivityInterval {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			session.LastActivity = now
+			sessionDetails, err := json.Marshal(session)
+			if err != nil {
+				http.Redirect(w, r, fmt.Sprintf("%s/%s?error=%s", prefix, errorAPIEndpoint, url.QueryEscape(errors.Wrap(ui.ErrJSONMarshal, err).Error())), http.StatusSeeOther)
+				return
+			}
+			secureSessionDetails, err := s.Encode(sessionDetailsKey, string(sessionDetails))
+			if err != nil {
+				http.Redirect(w, r, fmt.Sprintf("%s/%s?error=%s", prefix, errorAPIEndpoint, url.QueryEscape(errors.Wrap(errCookieEncrypt, err).Error())), http.StatusSeeOther)
+				return
+			}
+
+			http.SetCookie(w, &http.Cookie{
+				Name:     sessionDetailsKey,
+				Value:    secureSessionDetails,
+				Path:     "/",
+				HttpOnly: true,
+			})
+			r.Header.Set(sessionDetailsKey, string(sessionDetails))
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func sessionExpired(session ui.Session, cfg SessionConfig, now time.Time) bool {
+	if session.LoginAt.IsZero() {
+		return true
+	}
+	deadline, ok := sessionDeadline(session, cfg, session.LastActivity)
+	return ok && now.After(deadline)
+}
+
+// sessionDeadline returns when the session expires if there is no activity
+// after lastActivity, and whether it expires at all.
+func sessionDeadline(session ui.Session, cfg SessionConfig, lastActivity time.Time) (time.Time, bool) {
+	var deadline time.Time
+	if cfg.MaxAge > 0 {
+		deadline = session.LoginAt.Add(cfg.MaxAge)
+	}
+	if cfg.IdleTimeout > 0 && !lastActivity.IsZero() {
+		if idle := lastActivity.Add(cfg.IdleTimeout); deadline.IsZero() || idle.Before(deadline) {
+			deadline = idle
+		}
+	}
+
+	return deadline, !deadline.IsZero()
+}
+
+func clearSessionCookies(prefix string) []*http.Cookie {
+	return []*http.Cookie{
+		{
+			Name:   sessionDetailsKey,
+			Value:  "",
+			Path:   "/",
+			MaxAge: -1,
+		},
+		{
+			Name:   refreshTokenKey,
+			Value:  "",
+			Path:   fmt.Sprintf("%s/%s/login", prefix, domainsAPIEndpoint),
+			MaxAge: -1,
+		},
+		{
+			Name:   refreshTokenKey,
+			Value:  "",
+			Path:   fmt.Sprintf("%s/%s", prefix, tokenRefreshAPIEndpoint),
+			MaxAge: -1,
+		},
+	}
+}
+
+// PersonalTokenMiddleware authenticates requests made with a personal access
+// token in the Authorization header and requires it to hold the given scope.
+// It replaces the cookie based middlewares for the API routes.
+func PersonalTokenMiddleware(svc ui.Service, scope ui.TokenScope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if !strings.HasPrefix(header, bearerPrefix) {
+				encodeAPIError(r.Context(), errBearerKey, w)
+				return
+			}
+
+			session, err := svc.AuthenticatePersonalToken(r.Context(), strings.TrimPrefix(header, bearerPrefix), scope)
+			if err != nil {
+				encodeAPIError(r.Context(), err, w)
+				return
+			}
+
+			sessionDetails, err := json.Marshal(session)
+			if err != nil {
+				encodeAPIError(r.Context(), errors.Wrap(ui.ErrJSONMarshal, err), w)
+				return
+			}
+			r.Header.Set(sessionDetailsKey, string(sessionDetails))
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 func handleStaticFiles(m *chi.Mux) error {
 	entries, err := ui.StaticFS.ReadDir(ui.StaticDir)
 	if err != nil {
@@ -2656,7 +3704,11 @@ func parseTags(r *http.Request) ([]string, error) {
 	return tags, nil
 }
 
-func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	if sr, ok := response.(streamRes); ok {
+		return encodeStreamResponse(w, sr)
+	}
+
 	w.Header().Set("Content-Type", htmContentType)
 	ar, _ := response.(uiRes)
 	for k, v := range ar.Headers() {
@@ -2674,31 +3726,78 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 		return nil
 	}
 
-	if _, err := w.Write(ar.html); err != nil {
+	if _, err := w.Write(withScriptNonce(ctx, ar.html)); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func encodeJSONResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
-	if ar, ok := response.(magistrala.Response); ok {
-		for k, v := range ar.Headers() {
-			w.Header().Set(k, v)
+// encodeStreamResponse only sends the status and headers of a streamed
+// response with the first bytes of its body, so that a failure before
+// anything is written is still reported as an error. A failure after that
+// is wrapped in errStreamAborted, for which encodeError aborts the response.
+func encodeStreamResponse(w http.ResponseWriter, res streamRes) error {
+	sw := &streamWriter{ResponseWriter: w, headers: res.headers}
+	if err := res.write(sw); err != nil {
+		if !sw.started {
+			return err
 		}
-		w.Header().Set("Content-Type", jsonContentType)
-		w.WriteHeader(ar.Code())
+		return errors.Wrap(errStreamAborted, err)
+	}
+	if !sw.started {
+		sw.start()
+	}
 
-		if ar.Empty() {
-			return nil
-		}
+	return nil
+}
+
+// streamWriter sends the status and headers of a streamed response on the
+// first write.
+type streamWriter struct {
+	http.ResponseWriter
+	headers map[string]string
+	started bool
+}
+
+func (sw *streamWriter) Write(p []byte) (int, error) {
+	if !sw.started {
+		sw.start()
+	}
+
+	return sw.ResponseWriter.Write(p)
+}
+
+func (sw *streamWriter) start() {
+	for k, v := range sw.headers {
+		sw.Header().Set(k, v)
 	}
+	sw.WriteHeader(http.StatusOK)
+	sw.started = true
+}
 
-	return json.NewEncoder(w).Encode(response)
+// abortResponse sends what is written of a response whose body is already
+// partly sent and closes its connection, so that the client does not take
+// the truncated body for a complete one. Connections that cannot be taken
+// over, such as HTTP/2 streams, are reset by the server when the handler
+// panics with http.ErrAbortHandler.
+func abortResponse(w http.ResponseWriter) {
+	rc := http.NewResponseController(w)
+	_ = rc.Flush()
+	conn, _, err := rc.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	conn.Close()
 }
 
 func encodeError(prefix string) kithttp.ErrorEncoder {
 	return func(_ context.Context, err error, w http.ResponseWriter) {
+		if errors.Contains(err, errStreamAborted) {
+			abortResponse(w)
+			return
+		}
+
 		_, displayError := errors.Unwrap(err)
 
 		switch {
@@ -2708,8 +3807,17 @@ func encodeError(prefix string) kithttp.ErrorEncoder {
 			errors.Contains(err, ui.ErrTokenRefresh):
 			w.Header().Set("Location", fmt.Sprintf("%s/login", prefix))
 			w.WriteHeader(http.StatusSeeOther)
-		case errors.Contains(err, ui.ErrToken):
+		case errors.Contains(err, errTwoFactorSession):
+			w.Header().Set("Location", fmt.Sprintf("%s/%s?error=%s", prefix, loginAPIEndpoint, url.QueryEscape(errTwoFactorSession.Error())))
+			w.WriteHeader(http.StatusSeeOther)
+		case errors.Contains(err, ui.ErrToken),
+			errors.Contains(err, ui.ErrTwoFactorCode),
+			errors.Contains(err, ui.ErrTwoFactorEnrolment):
+			w.Header().Set("X-Error-Message", err.Error())
 			w.WriteHeader(http.StatusUnauthorized)
+		case errors.Contains(err, ui.ErrTwoFactorRequired):
+			w.Header().Set("Location", fmt.Sprintf("%s/%s?error=%s", prefix, twoFactorAPIEndpoint, url.QueryEscape(ui.ErrTwoFactorRequired.Error())))
+			w.WriteHeader(http.StatusSeeOther)
 		case errors.Contains(err, ui.ErrConflict):
 			w.Header().Set("X-Error-Message", err.Error())
 			w.WriteHeader(http.StatusConflict)
@@ -2717,6 +3825,7 @@ func encodeError(prefix string) kithttp.ErrorEncoder {
 			w.Header().Set("X-Error-Message", err.Error())
 			w.WriteHeader(http.StatusUnsupportedMediaType)
 		case errors.Contains(err, errFileFormat),
+			errors.Contains(err, ui.ErrInvalidCert),
 			errors.Contains(err, errCookieEncrypt),
 			errors.Contains(err, errInvalidQueryParams),
 			errors.Contains(err, errInvalidFormValue):
@@ -2750,7 +3859,26 @@ func encodeError(prefix string) kithttp.ErrorEncoder {
 			errors.Contains(err, ui.ErrJSONUnmarshal),
 			errors.Contains(err, ui.ErrFailedSend),
 			errors.Contains(err, ui.ErrFailedAccept),
-			errors.Contains(err, ui.ErrFailedDashboardRetrieve):
+			errors.Contains(err, ui.ErrFailedDashboardRetrieve),
+			errors.Contains(err, ui.ErrFailedPersonalToken),
+			errors.Contains(err, ui.ErrFailedRevokeToken),
+			errors.Contains(err, ui.ErrFailedTwoFactor),
+			errors.Contains(err, ui.ErrFailedAudit),
+			errors.Contains(err, ui.ErrTooManyTargets),
+			errors.Contains(err, ui.ErrTerminalForbidden),
+			errors.Contains(err, ui.ErrTerminalNoChannel),
+			errors.Contains(err, ui.ErrTerminalChannel),
+			errors.Contains(err, ui.ErrTerminalBroker),
+			errors.Contains(err, ui.ErrBootstrapTemplate),
+			errors.Contains(err, ui.ErrTooManyRows),
+			errors.Contains(err, ui.ErrInvalidContent),
+			errors.Contains(err, ui.ErrInvalidBundle),
+			errors.Contains(err, ui.ErrTooManyEntities),
+			errors.Contains(err, ui.ErrInvalidMove),
+			errors.Contains(err, ui.ErrFailedRotation),
+			errors.Contains(err, ui.ErrSecretsTaken),
+			errors.Contains(err, ui.ErrFailedProvision),
+			errors.Contains(err, ui.ErrProvisionRollback):
 			w.Header().Set("Location", fmt.Sprintf("%s/%s?error=%s", prefix, errorAPIEndpoint, url.QueryEscape(displayError.Error())))
 			w.WriteHeader(http.StatusSeeOther)
 		default:
@@ -2794,7 +3922,41 @@ func encodeError(prefix string) kithttp.ErrorEncoder {
 				errMissingExternalID,
 				errMissingRole,
 				errMissingValue,
-				errMissingExternalKey:
+				errMissingExternalKey,
+				errMissingScope,
+				errInvalidScope,
+				errInvalidExpiry,
+				errMissingTokenID,
+				errMissingCode,
+				errInvalidTimeRange,
+				errMissingTargets,
+				errTooManyTargets,
+				errInvalidState,
+				errInvalidTimeout,
+				errInvalidConcurrency,
+				errTooManyPatterns,
+				errPatternSize,
+				errMissingTemplateID,
+				errMissingContent,
+				errTooManyRows,
+				errInvalidExpiryWindow,
+				errMissingConfigs,
+				errTooManyConfigs,
+				errInvalidBundleFormat,
+				errInvalidRemap,
+				errInvalidEntity,
+				errInvalidStatus,
+				errInvalidBulkAction,
+				errMissingTag,
+				errMissingEntities,
+				errTooManyEntities,
+				errQuerySize,
+				errInvalidCategory,
+				errMissingScheduleID,
+				errMissingRunID,
+				errInvalidRotationInterval,
+				errTooManyChannels,
+				errMissingValueName:
 				w.Header().Set("X-Error-Message", err.Error())
 				w.WriteHeader(http.StatusBadRequest)
 			default:
@@ -2803,3 +3965,229 @@ func encodeError(prefix string) kithttp.ErrorEncoder {
 		}
 	}
 }
+
+func encodeAPIError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", jsonContentType)
+
+	switch {
+	case errors.Contains(err, errBearerKey),
+		errors.Contains(err, errInvalidCredentials),
+		errors.Contains(err, ui.ErrPersonalToken),
+		errors.Contains(err, ui.ErrPersonalTokenExpired),
+		errors.Contains(err, ui.ErrTokenRefresh):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, ui.ErrPersonalTokenScope):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Contains(err, ui.ErrFailedRetreive),
+		errors.Contains(err, ui.ErrFailedUpdate),
+		errors.Contains(err, ui.ErrJSONMarshal),
+		errors.Contains(err, ui.ErrJSONUnmarshal):
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		if e, ok := status.FromError(err); ok && e.Code() == codes.PermissionDenied {
+			w.WriteHeader(http.StatusForbidden)
+			break
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	}
+
+	if err := json.NewEncoder(w).Encode(map[string]string{"error": err.Error()}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func decodeCreateBootstrapTemplateRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return createBootstrapTemplateReq{
+		Session: session,
+		tpl:     readBootstrapTemplate(r),
+	}, nil
+}
+
+func decodeUpdateBootstrapTemplateRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	tpl := readBootstrapTemplate(r)
+	tpl.ID = chi.URLParam(r, "id")
+
+	return updateBootstrapTemplateReq{
+		Session: session,
+		tpl:     tpl,
+	}, nil
+}
+
+func readBootstrapTemplate(r *http.Request) ui.BootstrapTemplate {
+	return ui.BootstrapTemplate{
+		Name:        r.PostFormValue("name"),
+		Description: r.PostFormValue("description"),
+		Content:     r.PostFormValue("content"),
+		Channels:    readLines(r.PostFormValue("channels")),
+	}
+}
+
+func decodeDeleteBootstrapTemplateRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return deleteBootstrapTemplateReq{
+		Session: session,
+		id:      chi.URLParam(r, "id"),
+	}, nil
+}
+
+func decodeProvisionBootstrapsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	file, handler, err := r.FormFile("bootstrapsFile")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if !strings.HasSuffix(handler.Filename, ".csv") {
+		return nil, errInvalidFile
+	}
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, errFileFormat
+	}
+	rows, err := readBootstrapRows(records)
+	if err != nil {
+		return nil, err
+	}
+
+	return provisionBootstrapsReq{
+		Session:    session,
+		templateID: chi.URLParam(r, "id"),
+		rows:       rows,
+	}, nil
+}
+
+func decodeExportBundleRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	format := r.PostFormValue("format")
+	if format == "" {
+		format = ui.BundleJSON
+	}
+
+	return exportBundleReq{
+		Session: session,
+		export: ui.BundleExport{
+			ThingIDs: r.PostForm["thingID"],
+			Format:   format,
+			Redact:   r.PostFormValue("redact") == "true",
+		},
+	}, nil
+}
+
+func decodeImportBundleRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	session, err := sessionFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	file, handler, err := r.FormFile("bundleFile")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(handler.Filename)) {
+	case ".json", ".yaml", ".yml":
+	default:
+		return nil, errInvalidFile
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, errFileFormat
+	}
+	bundle, err := ui.ParseBundle(data)
+	if err != nil {
+		return nil, err
+	}
+	remap, err := readRemap(r.PostFormValue("remap"))
+	if err != nil {
+		return nil, err
+	}
+
+	return importBundleReq{
+		Session: session,
+		bundle:  bundle,
+		opts: ui.BundleImport{
+			DryRun: r.PostFormValue("dryRun") == "true",
+			Remap:  remap,
+		},
+	}, nil
+}
+
+// readRemap reads the id remapping of a bundle import, written as one
+// old=new pair per line.
+func readRemap(val string) (map[string]string, error) {
+	remap := make(map[string]string)
+	for _, line := range readLines(val) {
+		old, id, ok := strings.Cut(line, "=")
+		old, id = strings.TrimSpace(old), strings.TrimSpace(id)
+		if !ok || old == "" || id == "" {
+			return nil, errInvalidRemap
+		}
+		remap[old] = id
+	}
+
+	return remap, nil
+}
+
+// readBootstrapRows reads the devices of a provisioning file. The header
+// starts with the external id, external key and name columns, and every
+// other column is a template variable named after its header.
+func readBootstrapRows(records [][]string) ([]ui.BootstrapRow, error) {
+	if len(records) < minRows || len(records[0]) < len(provisionHeader) {
+		return nil, errFileFormat
+	}
+	header := make([]string, len(records[0]))
+	for i, col := range records[0] {
+		header[i] = strings.TrimSpace(col)
+		if i < len(provisionHeader) && !strings.EqualFold(header[i], provisionHeader[i]) {
+			return nil, errFileFormat
+		}
+		if header[i] == "" {
+			return nil, errFileFormat
+		}
+	}
+
+	rows := []ui.BootstrapRow{}
+	for i, record := range records[1:] {
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		row := ui.BootstrapRow{
+			// The header is the first line of the file.
+			Line:        i + 2,
+			ExternalID:  strings.TrimSpace(record[0]),
+			ExternalKey: strings.TrimSpace(record[1]),
+			Name:        strings.TrimSpace(record[2]),
+			Vars:        make(map[string]string, len(record)-len(provisionHeader)),
+		}
+		for j := len(provisionHeader); j < len(record); j++ {
+			row.Vars[header[j]] = strings.TrimSpace(record[j])
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
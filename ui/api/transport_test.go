@@ -0,0 +1,101 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeStreamResponse(t *testing.T) {
+	rows := "id,name\n1,thing\n"
+
+	cases := []struct {
+		desc     string
+		write    func(w io.Writer) error
+		code     int
+		body     string
+		complete bool
+	}{
+		{
+			desc: "stream response",
+			write: func(w io.Writer) error {
+				_, err := io.WriteString(w, rows)
+				return err
+			},
+			code:     http.StatusOK,
+			body:     rows,
+			complete: true,
+		},
+		{
+			desc: "stream response with failure before writing",
+			write: func(w io.Writer) error {
+				return ui.ErrFailedRetreive
+			},
+			code:     http.StatusSeeOther,
+			complete: true,
+		},
+		{
+			desc: "stream response with failure while writing",
+			write: func(w io.Writer) error {
+				if _, err := io.WriteString(w, rows); err != nil {
+					return err
+				}
+				return ui.ErrFailedRetreive
+			},
+			code:     http.StatusOK,
+			body:     rows,
+			complete: false,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			handler := kithttp.NewServer(
+				func(_ context.Context, _ interface{}) (interface{}, error) {
+					return streamRes{
+						headers: map[string]string{"Content-Type": csvContentType},
+						write:   tc.write,
+					}, nil
+				},
+				func(_ context.Context, _ *http.Request) (interface{}, error) {
+					return nil, nil
+				},
+				encodeResponse,
+				kithttp.ServerErrorEncoder(encodeError("")),
+			)
+			ts := httptest.NewServer(handler)
+			defer ts.Close()
+
+			client := ts.Client()
+			client.CheckRedirect = func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			}
+			res, err := client.Get(ts.URL)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+			defer res.Body.Close()
+
+			body, err := io.ReadAll(res.Body)
+			assert.Equal(t, tc.code, res.StatusCode, fmt.Sprintf("%s: expected status %d got %d", tc.desc, tc.code, res.StatusCode))
+			assert.Equal(t, tc.body, string(body), fmt.Sprintf("%s: expected body %q got %q", tc.desc, tc.body, string(body)))
+			switch tc.complete {
+			case true:
+				assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+			default:
+				assert.True(t, errors.Contains(err, io.ErrUnexpectedEOF), fmt.Sprintf("%s: expected %s got %s", tc.desc, io.ErrUnexpectedEOF, err))
+			}
+		})
+	}
+}
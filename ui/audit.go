@@ -0,0 +1,61 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"context"
+	"time"
+)
+
+// TerminalCommand is an audit record of a command sent to an agent through
+// the remote terminal.
+type TerminalCommand struct {
+	ID           string        `json:"id" db:"id"`
+	UserID       string        `json:"user_id" db:"user_id"`
+	UserIdentity string        `json:"user_identity" db:"user_identity"`
+	DomainID     string        `json:"domain_id" db:"domain_id"`
+	ThingID      string        `json:"thing_id" db:"thing_id"`
+	Command      string        `json:"command" db:"command"`
+	Response     string        `json:"response,omitempty" db:"response"`
+	Error        string        `json:"error,omitempty" db:"error"`
+	Duration     time.Duration `json:"duration" db:"duration"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+}
+
+type TerminalCommandPage struct {
+	Total    uint64            `json:"total"`
+	Offset   uint64            `json:"offset"`
+	Limit    uint64            `json:"limit"`
+	Commands []TerminalCommand `json:"commands"`
+}
+
+// TerminalCommandPageMeta filters the terminal commands of a domain. Empty
+// filters and zero times match all commands.
+type TerminalCommandPageMeta struct {
+	Offset   uint64    `json:"offset" db:"offset"`
+	Limit    uint64    `json:"limit" db:"limit"`
+	DomainID string    `json:"domain_id" db:"domain_id"`
+	UserID   string    `json:"user_id" db:"user_id"`
+	ThingID  string    `json:"thing_id" db:"thing_id"`
+	Command  string    `json:"command" db:"command"`
+	From     time.Time `json:"from" db:"from"`
+	To       time.Time `json:"to" db:"to"`
+}
+
+// TerminalAuditRepository provides an interface for interacting with the terminal command audit log.
+//
+//go:generate mockery --name TerminalAuditRepository --output=./mocks --filename audit.go --quiet --note "Copyright (c) Abstract Machines"
+type TerminalAuditRepository interface {
+	// Persists a terminal command before it is sent to the agent. A non-nil
+	// error is returned to indicate a failure to persist.
+	Save(ctx context.Context, cmd TerminalCommand) error
+
+	// Updates the response, error and duration of a terminal command once it
+	// completes. A non-nil error is returned to indicate a failure to update.
+	Update(ctx context.Context, cmd TerminalCommand) error
+
+	// Retrieves the terminal commands matching the page metadata, newest
+	// first. A non-nil error is returned to indicate a failure to retrieve.
+	RetrieveAll(ctx context.Context, pm TerminalCommandPageMeta) (TerminalCommandPage, error)
+}
@@ -0,0 +1,198 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	BatchStateActive   = "active"
+	BatchStateInactive = "inactive"
+
+	BatchDone    = "done"
+	BatchFailed  = "failed"
+	BatchTimeout = "timeout"
+
+	// MaxBatchTargets limits the number of agents a single batch command
+	// is sent to.
+	MaxBatchTargets = 500
+
+	batchPageSize = 100
+)
+
+// BatchCommand is a terminal command sent to the agents of many bootstrapped
+// things at once. The targets are the selected things or, when no thing is
+// selected, the bootstrap configs matching both the tag and the state.
+type BatchCommand struct {
+	Command     string
+	ThingIDs    []string
+	Tag         string
+	State       string
+	Timeout     time.Duration
+	Concurrency int
+}
+
+// BatchCommandResult is the outcome of a batch command on a single agent.
+type BatchCommandResult struct {
+	ThingID  string
+	Name     string
+	Status   string
+	Response string
+	Error    string
+	Duration time.Duration
+}
+
+// bootstrapConfigs retrieves all the bootstrap configs of the domain.
+func (us *uiService) bootstrapConfigs(token string) ([]sdk.BootstrapConfig, error) {
+	var configs []sdk.BootstrapConfig
+	for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+		page, err := us.sdk.Bootstraps(sdk.PageMetadata{Offset: offset, Limit: batchPageSize}, token)
+		if err != nil {
+			return nil, errors.Wrap(ErrFailedRetreive, err)
+		}
+		if len(page.Configs) == 0 {
+			break
+		}
+		configs = append(configs, page.Configs...)
+		total = page.Total
+	}
+
+	return configs, nil
+}
+
+// batchTargets resolves the bootstrap configs a batch command is sent to.
+func (us *uiService) batchTargets(s Session, batch BatchCommand) ([]sdk.BootstrapConfig, error) {
+	configs, err := us.bootstrapConfigs(s.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(batch.ThingIDs) > 0 {
+		names := make(map[string]string, len(configs))
+		for _, cfg := range configs {
+			names[cfg.ThingID] = cfg.Name
+		}
+		// Selected things without a bootstrap config are kept so that they
+		// are reported as failed rather than silently left out.
+		var targets []sdk.BootstrapConfig
+		seen := make(map[string]bool, len(batch.ThingIDs))
+		for _, id := range batch.ThingIDs {
+			if !seen[id] {
+				seen[id] = true
+				targets = append(targets, sdk.BootstrapConfig{ThingID: id, Name: names[id]})
+			}
+		}
+		return targets, nil
+	}
+
+	var tagged map[string]bool
+	if batch.Tag != "" {
+		tagged = make(map[string]bool)
+		for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+			page, err := us.sdk.Things(sdk.PageMetadata{Offset: offset, Limit: batchPageSize, Tag: batch.Tag}, s.Token)
+			if err != nil {
+				return nil, errors.Wrap(ErrFailedRetreive, err)
+			}
+			if len(page.Things) == 0 {
+				break
+			}
+			for _, th := range page.Things {
+				tagged[th.ID] = true
+			}
+			total = page.Total
+		}
+	}
+
+	var targets []sdk.BootstrapConfig
+	for _, cfg := range configs {
+		if tagged != nil && !tagged[cfg.ThingID] {
+			continue
+		}
+		switch batch.State {
+		case BatchStateActive:
+			if cfg.State == 0 {
+				continue
+			}
+		case BatchStateInactive:
+			if cfg.State != 0 {
+				continue
+			}
+		}
+		targets = append(targets, cfg)
+	}
+
+	return targets, nil
+}
+
+// runBatch sends the command to every target, running at most
+// batch.Concurrency commands at a time. The results are in the order of the
+// targets.
+func (us *uiService) runBatch(ctx context.Context, s Session, targets []sdk.BootstrapConfig, batch BatchCommand) []BatchCommandResult {
+	results := make([]BatchCommandResult, len(targets))
+
+	var g errgroup.Group
+	g.SetLimit(batch.Concurrency)
+	for i, cfg := range targets {
+		i, cfg := i, cfg
+		g.Go(func() error {
+			results[i] = us.runBatchTarget(ctx, s, cfg, batch)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return results
+}
+
+func (us *uiService) runBatchTarget(ctx context.Context, s Session, cfg sdk.BootstrapConfig, batch BatchCommand) BatchCommandResult {
+	ctx, cancel := context.WithTimeout(ctx, batch.Timeout)
+	defer cancel()
+
+	res := BatchCommandResult{ThingID: cfg.ThingID, Name: cfg.Name, Status: BatchDone}
+	start := time.Now()
+
+	term, err := us.OpenTerminal(ctx, s, cfg.ThingID, "")
+	if err == nil {
+		res.Response, err = us.ProcessTerminalCommand(ctx, s, term, batch.Command)
+		term.Close()
+	}
+	res.Duration = time.Since(start)
+
+	switch {
+	case err != nil && ctx.Err() == context.DeadlineExceeded:
+		res.Status = BatchTimeout
+		res.Error = fmt.Sprintf("no response within %s", batch.Timeout)
+	case err != nil:
+		res.Status = BatchFailed
+		res.Error = err.Error()
+	}
+
+	return res
+}
+
+func batchResultsCSV(command string, results []BatchCommandResult) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"thing_id", "name", "command", "status", "response", "error", "duration"}); err != nil {
+		return nil, err
+	}
+	for _, res := range results {
+		record := []string{res.ThingID, res.Name, command, res.Status, res.Response, res.Error, res.Duration.String()}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
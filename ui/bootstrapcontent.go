@@ -0,0 +1,297 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/absmach/agent/pkg/bootstrap"
+)
+
+const (
+	DiffSame    = "same"
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+
+	// maxDiffCells bounds the size of the table used to diff bootstrap
+	// content. Larger contents are shown as entirely changed.
+	maxDiffCells = 4_000_000
+)
+
+var (
+	servicesConfigType = reflect.TypeOf(bootstrap.ServicesConfig{})
+
+	// requiredContentFields are the fields the agent needs to reach the
+	// MQTT broker.
+	requiredContentFields = []string{"agent.mqtt.url"}
+
+	// requiredContentSubfields are the fields the agent can not do without
+	// once their parent object is set.
+	requiredContentSubfields = map[string][]string{
+		"agent.heartbeat": {"interval"},
+		"agent.terminal":  {"session_timeout"},
+	}
+)
+
+// ContentReport lists the problems of bootstrap content checked against the
+// services config read by the agent. Fields are dot separated paths such as
+// agent.mqtt.url.
+type ContentReport struct {
+	Unknown []string
+	Missing []string
+	Invalid string
+}
+
+// Valid reports whether the content has no problems.
+func (r ContentReport) Valid() bool {
+	return len(r.Unknown) == 0 && len(r.Missing) == 0 && r.Invalid == ""
+}
+
+func (r ContentReport) String() string {
+	var problems []string
+	if r.Invalid != "" {
+		problems = append(problems, r.Invalid)
+	}
+	if len(r.Unknown) > 0 {
+		problems = append(problems, "unknown fields "+strings.Join(r.Unknown, ", "))
+	}
+	if len(r.Missing) > 0 {
+		problems = append(problems, "missing fields "+strings.Join(r.Missing, ", "))
+	}
+
+	return strings.Join(problems, "; ")
+}
+
+// DiffLine is a row of a side by side diff. Line numbers start at 1 and are
+// 0 on the side missing the line.
+type DiffLine struct {
+	Kind    string
+	Left    string
+	LeftNo  int
+	Right   string
+	RightNo int
+}
+
+// ValidateBootstrapContent checks bootstrap content against the services
+// config read by the agent. Empty content is valid.
+func ValidateBootstrapContent(content string) ContentReport {
+	var report ContentReport
+	if strings.TrimSpace(content) == "" {
+		return report
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &fields); err != nil {
+		report.Invalid = fmt.Sprintf("content is not a JSON object: %s", err)
+		return report
+	}
+
+	report.Unknown = unknownFields("", fields, servicesConfigType)
+	for _, field := range requiredContentFields {
+		if !hasField(fields, field) {
+			report.Missing = append(report.Missing, field)
+		}
+	}
+	for parent, subfields := range requiredContentSubfields {
+		if !hasField(fields, parent) {
+			continue
+		}
+		for _, field := range subfields {
+			if !hasField(fields, parent+"."+field) {
+				report.Missing = append(report.Missing, parent+"."+field)
+			}
+		}
+	}
+	sort.Strings(report.Missing)
+
+	// Unknown and missing fields aside, the values must have the types the
+	// agent expects.
+	if len(report.Unknown) == 0 && len(report.Missing) == 0 {
+		var cfg bootstrap.ServicesConfig
+		if err := json.Unmarshal([]byte(content), &cfg); err != nil {
+			report.Invalid = fmt.Sprintf("content does not match the agent config: %s", err)
+		}
+	}
+
+	return report
+}
+
+// unknownFields returns the fields of an object the type has no field for,
+// matching names the way encoding/json does.
+func unknownFields(path string, fields map[string]interface{}, t reflect.Type) []string {
+	known := jsonFields(t)
+
+	var unknown []string
+	for name, val := range fields {
+		field := joinPath(path, name)
+		ft, ok := known[strings.ToLower(name)]
+		if !ok {
+			unknown = append(unknown, field)
+			continue
+		}
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		switch {
+		case ft.Kind() == reflect.Struct:
+			if obj, ok := val.(map[string]interface{}); ok {
+				unknown = append(unknown, unknownFields(field, obj, ft)...)
+			}
+		case ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Struct:
+			if items, ok := val.([]interface{}); ok {
+				for i, item := range items {
+					if obj, ok := item.(map[string]interface{}); ok {
+						unknown = append(unknown, unknownFields(fmt.Sprintf("%s[%d]", field, i), obj, ft.Elem())...)
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(unknown)
+
+	return unknown
+}
+
+// jsonFields maps the lower cased JSON names of the fields of a struct to
+// their types.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = f.Type
+	}
+
+	return fields
+}
+
+// hasField reports whether the dot separated field is set in the object.
+func hasField(fields map[string]interface{}, path string) bool {
+	name, rest, nested := strings.Cut(path, ".")
+	for key, val := range fields {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		if !nested {
+			return val != nil
+		}
+		if obj, ok := val.(map[string]interface{}); ok && hasField(obj, rest) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// DiffBootstrapContent compares the current and proposed content line by
+// line. JSON content is indented first so that formatting changes do not
+// show up.
+func DiffBootstrapContent(current, proposed string) []DiffLine {
+	left := contentLines(current)
+	right := contentLines(proposed)
+
+	if len(left)*len(right) > maxDiffCells {
+		var lines []DiffLine
+		for i := 0; i < len(left) || i < len(right); i++ {
+			line := DiffLine{Kind: DiffChanged}
+			if i < len(left) {
+				line.Left, line.LeftNo = left[i], i+1
+			}
+			if i < len(right) {
+				line.Right, line.RightNo = right[i], i+1
+			}
+			lines = append(lines, line)
+		}
+		return lines
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of
+	// left[i:] and right[j:].
+	lcs := make([][]int, len(left)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(right)+1)
+	}
+	for i := len(left) - 1; i >= 0; i-- {
+		for j := len(right) - 1; j >= 0; j-- {
+			if left[i] == right[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines, removed, added []DiffLine
+	// Removed and added lines next to each other are shown side by side.
+	flush := func() {
+		for k := 0; k < len(removed) || k < len(added); k++ {
+			var line DiffLine
+			switch {
+			case k < len(removed) && k < len(added):
+				line = DiffLine{Kind: DiffChanged, Left: removed[k].Left, LeftNo: removed[k].LeftNo, Right: added[k].Right, RightNo: added[k].RightNo}
+			case k < len(removed):
+				line = removed[k]
+			default:
+				line = added[k]
+			}
+			lines = append(lines, line)
+		}
+		removed, added = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(left) || j < len(right) {
+		switch {
+		case i < len(left) && j < len(right) && left[i] == right[j]:
+			flush()
+			lines = append(lines, DiffLine{Kind: DiffSame, Left: left[i], LeftNo: i + 1, Right: right[j], RightNo: j + 1})
+			i++
+			j++
+		case j == len(right) || (i < len(left) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, DiffLine{Kind: DiffRemoved, Left: left[i], LeftNo: i + 1})
+			i++
+		default:
+			added = append(added, DiffLine{Kind: DiffAdded, Right: right[j], RightNo: j + 1})
+			j++
+		}
+	}
+	flush()
+
+	return lines
+}
+
+func contentLines(content string) []string {
+	if strings.TrimSpace(content) == "" {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(content), "", "  "); err == nil {
+		content = buf.String()
+	}
+
+	return strings.Split(strings.TrimRight(content, "\n"), "\n")
+}
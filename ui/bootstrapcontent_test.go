@@ -0,0 +1,123 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui_test
+
+import (
+	"testing"
+
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBootstrapContent(t *testing.T) {
+	cases := []struct {
+		desc    string
+		content string
+		unknown []string
+		missing []string
+		invalid bool
+	}{
+		{
+			desc:    "validate empty content",
+			content: "",
+		},
+		{
+			desc:    "validate agent content",
+			content: `{"agent":{"channels":{"control":"c1","data":"c2"},"heartbeat":{"interval":"30s"},"mqtt":{"url":"tcp://broker:1883","qos":1}},"export":{"routes":[{"mqtt_topic":"t","workers":2}]}}`,
+		},
+		{
+			desc:    "validate content with field names in another case",
+			content: `{"Agent":{"MQTT":{"URL":"tcp://broker:1883"},"Edgex":{"URL":"http://edgex"}}}`,
+		},
+		{
+			desc:    "validate content with unknown fields",
+			content: `{"agent":{"mqtt":{"url":"tcp://broker:1883","usrname":"u"}},"exprt":{},"export":{"routes":[{"topic":"t"}]}}`,
+			unknown: []string{"agent.mqtt.usrname", "export.routes[0].topic", "exprt"},
+		},
+		{
+			desc:    "validate content with ignored fields",
+			content: `{"agent":{"mqtt":{"url":"tcp://broker:1883","CA":"ca"}}}`,
+			unknown: []string{"agent.mqtt.CA"},
+		},
+		{
+			desc:    "validate content without broker url",
+			content: `{"agent":{"heartbeat":{},"terminal":{"session_timeout":"60s"}}}`,
+			missing: []string{"agent.heartbeat.interval", "agent.mqtt.url"},
+		},
+		{
+			desc:    "validate content with wrong types",
+			content: `{"agent":{"mqtt":{"url":"tcp://broker:1883","qos":"high"}}}`,
+			invalid: true,
+		},
+		{
+			desc:    "validate content that is not an object",
+			content: `["agent"]`,
+			invalid: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			report := ui.ValidateBootstrapContent(tc.content)
+			assert.Equal(t, tc.unknown, report.Unknown)
+			assert.Equal(t, tc.missing, report.Missing)
+			assert.Equal(t, tc.invalid, report.Invalid != "")
+			assert.Equal(t, tc.unknown == nil && tc.missing == nil && !tc.invalid, report.Valid())
+		})
+	}
+}
+
+func TestDiffBootstrapContent(t *testing.T) {
+	cases := []struct {
+		desc     string
+		current  string
+		proposed string
+		diff     []ui.DiffLine
+	}{
+		{
+			desc:     "diff identical content with another layout",
+			current:  `{"a":1}`,
+			proposed: "{\n    \"a\": 1\n}",
+			diff: []ui.DiffLine{
+				{Kind: ui.DiffSame, Left: "{", LeftNo: 1, Right: "{", RightNo: 1},
+				{Kind: ui.DiffSame, Left: `  "a": 1`, LeftNo: 2, Right: `  "a": 1`, RightNo: 2},
+				{Kind: ui.DiffSame, Left: "}", LeftNo: 3, Right: "}", RightNo: 3},
+			},
+		},
+		{
+			desc:     "diff changed and added lines",
+			current:  `{"a":1,"b":2}`,
+			proposed: `{"a":1,"b":3,"c":4}`,
+			diff: []ui.DiffLine{
+				{Kind: ui.DiffSame, Left: "{", LeftNo: 1, Right: "{", RightNo: 1},
+				{Kind: ui.DiffSame, Left: `  "a": 1,`, LeftNo: 2, Right: `  "a": 1,`, RightNo: 2},
+				{Kind: ui.DiffChanged, Left: `  "b": 2`, LeftNo: 3, Right: `  "b": 3,`, RightNo: 3},
+				{Kind: ui.DiffAdded, Right: `  "c": 4`, RightNo: 4},
+				{Kind: ui.DiffSame, Left: "}", LeftNo: 4, Right: "}", RightNo: 5},
+			},
+		},
+		{
+			desc:     "diff removed content",
+			current:  `{}`,
+			proposed: "",
+			diff: []ui.DiffLine{
+				{Kind: ui.DiffRemoved, Left: "{}", LeftNo: 1},
+			},
+		},
+		{
+			desc:     "diff invalid content",
+			current:  "",
+			proposed: "{\"a\":",
+			diff: []ui.DiffLine{
+				{Kind: ui.DiffAdded, Right: "{\"a\":", RightNo: 1},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.diff, ui.DiffBootstrapContent(tc.current, tc.proposed))
+		})
+	}
+}
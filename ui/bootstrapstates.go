@@ -0,0 +1,144 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	StateChanged   = "changed"
+	StateUnchanged = "unchanged"
+	StateFailed    = "failed"
+
+	// MaxStateChanges limits the number of bootstrap configs whose state is
+	// changed at once.
+	MaxStateChanges = 500
+
+	stateChangeConcurrency = 10
+)
+
+// BootstrapStateFilter selects the bootstrap configs shown in the state
+// overview. State is either BatchStateActive, BatchStateInactive or empty for
+// both, and ExternalID matches part of the external id, ignoring case.
+type BootstrapStateFilter struct {
+	State      string
+	ExternalID string
+}
+
+// BootstrapStateChange activates or deactivates the bootstrap configs of the
+// things.
+type BootstrapStateChange struct {
+	ThingIDs []string
+	State    string
+}
+
+// BootstrapStateCounts counts the bootstrap configs in each state.
+type BootstrapStateCounts struct {
+	Active   int
+	Inactive int
+	Total    int
+}
+
+// BootstrapStateResult is the outcome of changing the state of a single
+// bootstrap config.
+type BootstrapStateResult struct {
+	ThingID    string
+	Name       string
+	ExternalID string
+	From       int
+	To         int
+	Status     string
+	Error      string
+}
+
+func countBootstrapStates(configs []sdk.BootstrapConfig) BootstrapStateCounts {
+	counts := BootstrapStateCounts{Total: len(configs)}
+	for _, cfg := range configs {
+		if cfg.State == 0 {
+			counts.Inactive++
+			continue
+		}
+		counts.Active++
+	}
+
+	return counts
+}
+
+func filterBootstrapStates(configs []sdk.BootstrapConfig, filter BootstrapStateFilter) []sdk.BootstrapConfig {
+	externalID := strings.ToLower(filter.ExternalID)
+
+	var filtered []sdk.BootstrapConfig
+	for _, cfg := range configs {
+		switch {
+		case filter.State == BatchStateActive && cfg.State == 0,
+			filter.State == BatchStateInactive && cfg.State != 0,
+			!strings.Contains(strings.ToLower(cfg.ExternalID), externalID):
+			continue
+		}
+		filtered = append(filtered, cfg)
+	}
+
+	return filtered
+}
+
+// changeBootstrapStates moves the bootstrap configs of the things to the
+// state, changing at most stateChangeConcurrency configs at a time. Configs
+// already in the state are left as they are. The results are in the order
+// of the things.
+func (us *uiService) changeBootstrapStates(token string, configs []sdk.BootstrapConfig, change BootstrapStateChange) []BootstrapStateResult {
+	byThing := make(map[string]sdk.BootstrapConfig, len(configs))
+	for _, cfg := range configs {
+		byThing[cfg.ThingID] = cfg
+	}
+	to := 0
+	if change.State == BatchStateActive {
+		to = 1
+	}
+
+	var thingIDs []string
+	seen := make(map[string]bool, len(change.ThingIDs))
+	for _, id := range change.ThingIDs {
+		if !seen[id] {
+			seen[id] = true
+			thingIDs = append(thingIDs, id)
+		}
+	}
+
+	results := make([]BootstrapStateResult, len(thingIDs))
+
+	var g errgroup.Group
+	g.SetLimit(stateChangeConcurrency)
+	for i, id := range thingIDs {
+		cfg, ok := byThing[id]
+		res := BootstrapStateResult{ThingID: id, Name: cfg.Name, ExternalID: cfg.ExternalID, From: cfg.State, To: to}
+		switch {
+		case !ok:
+			res.Status, res.Error = StateFailed, "thing has no bootstrap config"
+			results[i] = res
+			continue
+		case cfg.State == to:
+			res.Status = StateUnchanged
+			results[i] = res
+			continue
+		}
+
+		i := i
+		g.Go(func() error {
+			res.Status = StateChanged
+			if err := us.sdk.Whitelist(sdk.BootstrapConfig{ThingID: res.ThingID, State: res.To}, token); err != nil {
+				res.Status, res.Error = StateFailed, fmt.Sprintf("state was not changed: %s", err)
+			}
+			results[i] = res
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return results
+}
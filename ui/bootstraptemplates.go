@@ -0,0 +1,227 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+)
+
+const (
+	ProvisionCreated = "created"
+	ProvisionFailed  = "failed"
+
+	// MaxProvisionRows limits the number of bootstrap configs created from a
+	// single CSV file.
+	MaxProvisionRows = 1000
+
+	ExternalIDVar  = "external_id"
+	ExternalKeyVar = "external_key"
+	NameVar        = "name"
+)
+
+// BootstrapTemplate renders bootstrap configs. The content and every channel
+// are Go text templates executed with the variables of a device, such as
+// {{ .external_id }} or {{ .mqtt_url }}. The json function quotes a value
+// as a JSON string, as in {{ json .name }}. A rendered channel may hold
+// several channel ids separated by commas, semicolons or spaces.
+type BootstrapTemplate struct {
+	ID          string    `json:"id" db:"id"`
+	DomainID    string    `json:"domain_id" db:"domain_id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description,omitempty" db:"description"`
+	Content     string    `json:"content" db:"content"`
+	Channels    []string  `json:"channels,omitempty" db:"channels"`
+	CreatedBy   string    `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+type BootstrapTemplatePage struct {
+	Total     uint64              `json:"total"`
+	Offset    uint64              `json:"offset"`
+	Limit     uint64              `json:"limit"`
+	Templates []BootstrapTemplate `json:"templates"`
+}
+
+type BootstrapTemplatePageMeta struct {
+	Offset   uint64 `json:"offset" db:"offset"`
+	Limit    uint64 `json:"limit" db:"limit"`
+	DomainID string `json:"domain_id" db:"domain_id"`
+}
+
+// BootstrapRow is a device to provision from a bootstrap template. Vars holds
+// the template variables besides the external id, external key and name.
+type BootstrapRow struct {
+	Line        int
+	ExternalID  string
+	ExternalKey string
+	Name        string
+	Vars        map[string]string
+}
+
+// ProvisionResult is the outcome of provisioning a single device.
+type ProvisionResult struct {
+	Line       int
+	ExternalID string
+	Name       string
+	ThingID    string
+	Status     string
+	Error      string
+}
+
+// BootstrapTemplateRepository provides an interface for interacting with the bootstrap templates storage.
+//
+//go:generate mockery --name BootstrapTemplateRepository --output=./mocks --filename bootstraptemplates.go --quiet --note "Copyright (c) Abstract Machines"
+type BootstrapTemplateRepository interface {
+	// Persists a new bootstrap template. A non-nil error is returned to
+	// indicate a failure to persist.
+	Save(ctx context.Context, tpl BootstrapTemplate) error
+
+	// Retrieves a bootstrap template of a domain. A non-nil error is
+	// returned to indicate a failure to retrieve.
+	Retrieve(ctx context.Context, domainID, id string) (BootstrapTemplate, error)
+
+	// Retrieves the bootstrap templates of a domain, ordered by name. A
+	// non-nil error is returned to indicate a failure to retrieve.
+	RetrieveAll(ctx context.Context, pm BootstrapTemplatePageMeta) (BootstrapTemplatePage, error)
+
+	// Updates the name, description, content and channels of a bootstrap
+	// template. A non-nil error is returned to indicate a failure to update.
+	Update(ctx context.Context, tpl BootstrapTemplate) error
+
+	// Deletes a bootstrap template of a domain. A non-nil error is returned
+	// to indicate a failure to delete.
+	Delete(ctx context.Context, domainID, id string) error
+}
+
+var bootstrapTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// Validate reports whether the content and channels of the template parse.
+func (t BootstrapTemplate) Validate() error {
+	if _, err := parseBootstrapTemplate("content", t.Content); err != nil {
+		return errors.Wrap(ErrBootstrapTemplate, err)
+	}
+	for i, ch := range t.Channels {
+		if _, err := parseBootstrapTemplate(fmt.Sprintf("channel %d", i+1), ch); err != nil {
+			return errors.Wrap(ErrBootstrapTemplate, err)
+		}
+	}
+
+	return nil
+}
+
+// Render returns the bootstrap config of a device. Referencing a variable the
+// device does not have is an error, and the rendered content must be JSON.
+func (t BootstrapTemplate) Render(row BootstrapRow) (sdk.BootstrapConfig, error) {
+	vars := make(map[string]string, len(row.Vars)+3)
+	for k, v := range row.Vars {
+		vars[k] = v
+	}
+	vars[ExternalIDVar] = row.ExternalID
+	vars[ExternalKeyVar] = row.ExternalKey
+	vars[NameVar] = row.Name
+
+	content, err := executeBootstrapTemplate("content", t.Content, vars)
+	if err != nil {
+		return sdk.BootstrapConfig{}, errors.Wrap(ErrBootstrapTemplate, err)
+	}
+	if content != "" && !json.Valid([]byte(content)) {
+		return sdk.BootstrapConfig{}, errors.Wrap(ErrBootstrapTemplate, errors.New("rendered content is not valid JSON"))
+	}
+
+	channels := []string{}
+	for i, ch := range t.Channels {
+		ids, err := executeBootstrapTemplate(fmt.Sprintf("channel %d", i+1), ch, vars)
+		if err != nil {
+			return sdk.BootstrapConfig{}, errors.Wrap(ErrBootstrapTemplate, err)
+		}
+		channels = append(channels, strings.FieldsFunc(ids, func(r rune) bool {
+			return r == ',' || r == ';' || unicode.IsSpace(r)
+		})...)
+	}
+
+	return sdk.BootstrapConfig{
+		ExternalID:  row.ExternalID,
+		ExternalKey: row.ExternalKey,
+		Name:        row.Name,
+		Content:     content,
+		Channels:    channels,
+	}, nil
+}
+
+func parseBootstrapTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(bootstrapTemplateFuncs).Option("missingkey=error").Parse(text)
+}
+
+func executeBootstrapTemplate(name, text string, vars map[string]string) (string, error) {
+	tpl, err := parseBootstrapTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, vars); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// provision creates the bootstrap config of every row. A failing row does not
+// stop the others from being provisioned.
+func (us *uiService) provision(s Session, tpl BootstrapTemplate, rows []BootstrapRow) []ProvisionResult {
+	results := make([]ProvisionResult, len(rows))
+	for i, row := range rows {
+		res := ProvisionResult{Line: row.Line, ExternalID: row.ExternalID, Name: row.Name, Status: ProvisionCreated}
+		cfg, err := tpl.Render(row)
+		if err == nil {
+			if report := ValidateBootstrapContent(cfg.Content); !report.Valid() {
+				err = errors.Wrap(ErrInvalidContent, errors.New(report.String()))
+			}
+		}
+		if err == nil {
+			res.ThingID, err = us.sdk.AddBootstrap(cfg, s.Token)
+		}
+		if err != nil {
+			res.Status = ProvisionFailed
+			res.Error = err.Error()
+		}
+		results[i] = res
+	}
+
+	return results
+}
+
+func provisionResultsCSV(results []ProvisionResult) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"line", "external_id", "name", "thing_id", "status", "error"}); err != nil {
+		return nil, err
+	}
+	for _, res := range results {
+		record := []string{strconv.Itoa(res.Line), res.ExternalID, res.Name, res.ThingID, res.Status, res.Error}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,115 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBootstrapTemplateValidate(t *testing.T) {
+	cases := []struct {
+		desc string
+		tpl  ui.BootstrapTemplate
+		err  error
+	}{
+		{
+			desc: "validate template",
+			tpl:  ui.BootstrapTemplate{Content: `{"agent":{"mqtt":{"url":"{{ .mqtt_url }}"}}}`, Channels: []string{"{{ .channel }}"}},
+		},
+		{
+			desc: "validate template with invalid content",
+			tpl:  ui.BootstrapTemplate{Content: `{"url":"{{ .mqtt_url }"}`},
+			err:  ui.ErrBootstrapTemplate,
+		},
+		{
+			desc: "validate template with invalid channel",
+			tpl:  ui.BootstrapTemplate{Content: `{}`, Channels: []string{"{{ .channel"}},
+			err:  ui.ErrBootstrapTemplate,
+		},
+		{
+			desc: "validate template with unknown function",
+			tpl:  ui.BootstrapTemplate{Content: `{{ yaml .name }}`},
+			err:  ui.ErrBootstrapTemplate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.tpl.Validate()
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+		})
+	}
+}
+
+func TestBootstrapTemplateRender(t *testing.T) {
+	tpl := ui.BootstrapTemplate{
+		Content:  `{"agent":{"mqtt":{"url":"{{ .mqtt_url }}"},"name":{{ json .name }},"id":"{{ .external_id }}"}}`,
+		Channels: []string{"{{ .control }}", "{{ .data }}"},
+	}
+	row := ui.BootstrapRow{
+		ExternalID:  "gw-001",
+		ExternalKey: "secret",
+		Name:        `gateway "one"`,
+		Vars: map[string]string{
+			"mqtt_url": "tcp://broker:1883",
+			"control":  "ctrl",
+			"data":     "data-1;data-2",
+		},
+	}
+
+	cases := []struct {
+		desc string
+		tpl  ui.BootstrapTemplate
+		row  ui.BootstrapRow
+		cfg  sdk.BootstrapConfig
+		err  error
+	}{
+		{
+			desc: "render template",
+			tpl:  tpl,
+			row:  row,
+			cfg: sdk.BootstrapConfig{
+				ExternalID:  "gw-001",
+				ExternalKey: "secret",
+				Name:        `gateway "one"`,
+				Content:     `{"agent":{"mqtt":{"url":"tcp://broker:1883"},"name":"gateway \"one\"","id":"gw-001"}}`,
+				Channels:    []string{"ctrl", "data-1", "data-2"},
+			},
+		},
+		{
+			desc: "render template with empty channel",
+			tpl:  ui.BootstrapTemplate{Content: `{}`, Channels: []string{"{{ .control }}", "{{ .missing_channel }}"}},
+			row:  ui.BootstrapRow{ExternalID: "gw-002", Vars: map[string]string{"control": "ctrl", "missing_channel": ""}},
+			cfg:  sdk.BootstrapConfig{ExternalID: "gw-002", Content: `{}`, Channels: []string{"ctrl"}},
+		},
+		{
+			desc: "render template with missing variable",
+			tpl:  tpl,
+			row:  ui.BootstrapRow{ExternalID: "gw-003", Vars: map[string]string{"control": "ctrl", "data": "data"}},
+			err:  ui.ErrBootstrapTemplate,
+		},
+		{
+			desc: "render template to invalid JSON",
+			tpl:  ui.BootstrapTemplate{Content: `{"name":{{ .name }}}`},
+			row:  row,
+			err:  ui.ErrBootstrapTemplate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg, err := tc.tpl.Render(tc.row)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				assert.Equal(t, tc.cfg, cfg)
+			}
+		})
+	}
+}
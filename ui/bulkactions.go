@@ -0,0 +1,279 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+	"golang.org/x/sync/errgroup"
+)
+
+// The actions applied to entities in bulk. Tags are only kept by users and
+// things.
+const (
+	BulkEnable    = "enable"
+	BulkDisable   = "disable"
+	BulkAddTag    = "add_tag"
+	BulkRemoveTag = "remove_tag"
+)
+
+const (
+	BulkApplied   = "applied"
+	BulkUnchanged = "unchanged"
+	BulkFailed    = "failed"
+
+	// MaxBulkEntities limits the number of entities a bulk action applies to.
+	MaxBulkEntities = 500
+
+	bulkActionConcurrency = 10
+)
+
+// BulkFilter selects the entities of a bulk action the way the listings do,
+// by part of their name, by tag and by status.
+type BulkFilter struct {
+	Name   string
+	Tag    string
+	Status string
+}
+
+// EntityBulkAction applies an action to the users, things, channels or groups
+// with the IDs or, when there are none, to those matching the filter. Tag is
+// the tag added or removed.
+type EntityBulkAction struct {
+	Entity string
+	Action string
+	Tag    string
+	IDs    []string
+	Filter BulkFilter
+}
+
+// BulkActionResult is the outcome of a bulk action on a single entity.
+type BulkActionResult struct {
+	ID     string
+	Name   string
+	Status string
+	Error  string
+}
+
+// bulkEntity is the part of an entity a bulk action looks at.
+type bulkEntity struct {
+	ID     string
+	Name   string
+	Status string
+	Tags   []string
+	// loaded is false for entities given by id only.
+	loaded bool
+}
+
+// bulkTargets returns the entities of the action, without duplicates. The
+// entities given by id are only loaded when the action is applied.
+func (us *uiService) bulkTargets(token string, action EntityBulkAction) ([]bulkEntity, error) {
+	var targets []bulkEntity
+	if len(action.IDs) > 0 {
+		seen := make(map[string]bool, len(action.IDs))
+		for _, id := range action.IDs {
+			if !seen[id] {
+				seen[id] = true
+				targets = append(targets, bulkEntity{ID: id})
+			}
+		}
+		return targets, nil
+	}
+
+	for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+		pm := sdk.PageMetadata{Offset: offset, Limit: batchPageSize, Name: action.Filter.Name, Tag: action.Filter.Tag, Status: action.Filter.Status}
+
+		var page []bulkEntity
+		switch action.Entity {
+		case UsersEntity:
+			res, err := us.sdk.Users(pm, token)
+			if err != nil {
+				return nil, err
+			}
+			for _, u := range res.Users {
+				page = append(page, bulkEntity{ID: u.ID, Name: u.Name, Status: u.Status, Tags: u.Tags, loaded: true})
+			}
+			total = res.Total
+		case ThingsEntity:
+			res, err := us.sdk.Things(pm, token)
+			if err != nil {
+				return nil, err
+			}
+			for _, th := range res.Things {
+				page = append(page, bulkEntity{ID: th.ID, Name: th.Name, Status: th.Status, Tags: th.Tags, loaded: true})
+			}
+			total = res.Total
+		case ChannelsEntity:
+			res, err := us.sdk.Channels(pm, token)
+			if err != nil {
+				return nil, err
+			}
+			for _, ch := range res.Channels {
+				page = append(page, bulkEntity{ID: ch.ID, Name: ch.Name, Status: ch.Status, loaded: true})
+			}
+			total = res.Total
+		case GroupsEntity:
+			res, err := us.sdk.Groups(pm, token)
+			if err != nil {
+				return nil, err
+			}
+			for _, g := range res.Groups {
+				page = append(page, bulkEntity{ID: g.ID, Name: g.Name, Status: g.Status, loaded: true})
+			}
+			total = res.Total
+		}
+		if total > MaxBulkEntities {
+			return nil, ErrTooManyEntities
+		}
+		if len(page) == 0 {
+			break
+		}
+		targets = append(targets, page...)
+	}
+
+	return targets, nil
+}
+
+func (us *uiService) loadBulkEntity(token, entity, id string) (bulkEntity, error) {
+	switch entity {
+	case UsersEntity:
+		u, err := us.sdk.User(id, token)
+		if err != nil {
+			return bulkEntity{}, err
+		}
+		return bulkEntity{ID: u.ID, Name: u.Name, Status: u.Status, Tags: u.Tags, loaded: true}, nil
+	case ThingsEntity:
+		th, err := us.sdk.Thing(id, token)
+		if err != nil {
+			return bulkEntity{}, err
+		}
+		return bulkEntity{ID: th.ID, Name: th.Name, Status: th.Status, Tags: th.Tags, loaded: true}, nil
+	case ChannelsEntity:
+		ch, err := us.sdk.Channel(id, token)
+		if err != nil {
+			return bulkEntity{}, err
+		}
+		return bulkEntity{ID: ch.ID, Name: ch.Name, Status: ch.Status, loaded: true}, nil
+	default:
+		g, err := us.sdk.Group(id, token)
+		if err != nil {
+			return bulkEntity{}, err
+		}
+		return bulkEntity{ID: g.ID, Name: g.Name, Status: g.Status, loaded: true}, nil
+	}
+}
+
+// applyBulkAction applies the action to its entities, at most
+// bulkActionConcurrency at a time. Entities the action would not change are
+// left as they are, and a failing entity does not stop the others. The
+// results are in the order of the entities.
+func (us *uiService) applyBulkAction(token string, action EntityBulkAction) ([]BulkActionResult, error) {
+	targets, err := us.bulkTargets(token, action)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkActionResult, len(targets))
+
+	var g errgroup.Group
+	g.SetLimit(bulkActionConcurrency)
+	for i, target := range targets {
+		i, target := i, target
+		g.Go(func() error {
+			results[i] = us.applyBulkActionTo(token, action, target)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return results, nil
+}
+
+func (us *uiService) applyBulkActionTo(token string, action EntityBulkAction, target bulkEntity) BulkActionResult {
+	if !target.loaded {
+		entity, err := us.loadBulkEntity(token, action.Entity, target.ID)
+		if err != nil {
+			return BulkActionResult{ID: target.ID, Status: BulkFailed, Error: fmt.Sprintf("failed to retrieve %s: %s", action.Entity, err)}
+		}
+		target = entity
+	}
+	res := BulkActionResult{ID: target.ID, Name: target.Name, Status: BulkApplied}
+
+	var err error
+	switch action.Action {
+	case BulkEnable:
+		if target.Status == sdk.EnabledStatus {
+			res.Status = BulkUnchanged
+			return res
+		}
+		err = us.setBulkStatus(token, action.Entity, target.ID, true)
+	case BulkDisable:
+		if target.Status == sdk.DisabledStatus {
+			res.Status = BulkUnchanged
+			return res
+		}
+		err = us.setBulkStatus(token, action.Entity, target.ID, false)
+	case BulkAddTag:
+		if slices.Contains(target.Tags, action.Tag) {
+			res.Status = BulkUnchanged
+			return res
+		}
+		err = us.updateBulkTags(token, action.Entity, target.ID, append(slices.Clone(target.Tags), action.Tag))
+	case BulkRemoveTag:
+		if !slices.Contains(target.Tags, action.Tag) {
+			res.Status = BulkUnchanged
+			return res
+		}
+		tags := slices.DeleteFunc(slices.Clone(target.Tags), func(tag string) bool { return tag == action.Tag })
+		err = us.updateBulkTags(token, action.Entity, target.ID, tags)
+	}
+	if err != nil {
+		res.Status, res.Error = BulkFailed, err.Error()
+	}
+
+	return res
+}
+
+func (us *uiService) setBulkStatus(token, entity, id string, enable bool) error {
+	var err error
+	switch {
+	case entity == UsersEntity && enable:
+		_, err = us.sdk.EnableUser(id, token)
+	case entity == UsersEntity:
+		_, err = us.sdk.DisableUser(id, token)
+	case entity == ThingsEntity && enable:
+		_, err = us.sdk.EnableThing(id, token)
+	case entity == ThingsEntity:
+		_, err = us.sdk.DisableThing(id, token)
+	case entity == ChannelsEntity && enable:
+		_, err = us.sdk.EnableChannel(id, token)
+	case entity == ChannelsEntity:
+		_, err = us.sdk.DisableChannel(id, token)
+	case enable:
+		_, err = us.sdk.EnableGroup(id, token)
+	default:
+		_, err = us.sdk.DisableGroup(id, token)
+	}
+
+	return err
+}
+
+func (us *uiService) updateBulkTags(token, entity, id string, tags []string) error {
+	if tags == nil {
+		tags = []string{}
+	}
+	var err error
+	switch entity {
+	case UsersEntity:
+		_, err = us.sdk.UpdateUserTags(sdk.User{ID: id, Tags: tags}, token)
+	case ThingsEntity:
+		_, err = us.sdk.UpdateThingTags(sdk.Thing{ID: id, Tags: tags}, token)
+	default:
+		err = fmt.Errorf("%s have no tags", entity)
+	}
+
+	return err
+}
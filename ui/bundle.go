@@ -0,0 +1,422 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	BundleVersion = 1
+
+	BundleJSON = "json"
+	BundleYAML = "yaml"
+
+	// MaxBundleConfigs limits the number of bootstrap configs exported to or
+	// imported from a single bundle.
+	MaxBundleConfigs = 1000
+
+	BundleChannelKind   = "channel"
+	BundleThingKind     = "thing"
+	BundleBootstrapKind = "bootstrap"
+
+	BundleCreated = "created"
+	BundleMapped  = "mapped"
+	BundlePlanned = "planned"
+	BundleFailed  = "failed"
+	BundleRemoved = "removed"
+)
+
+// Bundle holds bootstrap configs together with their things and the channels
+// they are connected to, so that they can be moved between deployments. The
+// configs refer to the channels of the bundle by their id. A redacted bundle
+// has neither thing secrets, external keys nor client keys.
+type Bundle struct {
+	Version    int             `json:"version" yaml:"version"`
+	ExportedAt time.Time       `json:"exported_at" yaml:"exported_at"`
+	Redacted   bool            `json:"redacted" yaml:"redacted"`
+	Channels   []BundleChannel `json:"channels" yaml:"channels"`
+	Configs    []BundleConfig  `json:"configs" yaml:"configs"`
+}
+
+type BundleChannel struct {
+	ID          string                 `json:"id" yaml:"id"`
+	Name        string                 `json:"name,omitempty" yaml:"name,omitempty"`
+	Description string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+type BundleThing struct {
+	ID       string                 `json:"id" yaml:"id"`
+	Name     string                 `json:"name,omitempty" yaml:"name,omitempty"`
+	Secret   string                 `json:"secret,omitempty" yaml:"secret,omitempty"`
+	Tags     []string               `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+type BundleConfig struct {
+	Thing       BundleThing `json:"thing" yaml:"thing"`
+	ExternalID  string      `json:"external_id" yaml:"external_id"`
+	ExternalKey string      `json:"external_key,omitempty" yaml:"external_key,omitempty"`
+	Name        string      `json:"name,omitempty" yaml:"name,omitempty"`
+	Content     string      `json:"content,omitempty" yaml:"content,omitempty"`
+	Channels    []string    `json:"channels,omitempty" yaml:"channels,omitempty"`
+	ClientCert  string      `json:"client_cert,omitempty" yaml:"client_cert,omitempty"`
+	ClientKey   string      `json:"client_key,omitempty" yaml:"client_key,omitempty"`
+	CACert      string      `json:"ca_cert,omitempty" yaml:"ca_cert,omitempty"`
+	State       int         `json:"state" yaml:"state"`
+}
+
+// BundleExport selects the bootstrap configs of a bundle and how it is
+// written.
+type BundleExport struct {
+	ThingIDs []string
+	Format   string
+	Redact   bool
+}
+
+// BundleImport describes how a bundle is imported. Remap maps the ids of
+// channels and things of the bundle to existing ones, which are used instead
+// of creating new ones. A dry run only checks the bundle.
+type BundleImport struct {
+	DryRun bool
+	Remap  map[string]string
+}
+
+// BundleResult is the outcome of importing a channel, thing or bootstrap
+// config of a bundle.
+type BundleResult struct {
+	Kind   string
+	Name   string
+	OldID  string
+	NewID  string
+	Status string
+	Error  string
+}
+
+// ParseBundle reads a JSON or YAML bundle.
+func ParseBundle(data []byte) (Bundle, error) {
+	var bundle Bundle
+	// JSON is valid YAML, so both formats are read the same way.
+	if err := yaml.Unmarshal(data, &bundle); err != nil {
+		return Bundle{}, errors.Wrap(ErrInvalidBundle, err)
+	}
+	if bundle.Version != BundleVersion {
+		return Bundle{}, errors.Wrap(ErrInvalidBundle, fmt.Errorf("unsupported bundle version %d", bundle.Version))
+	}
+	if len(bundle.Configs) > MaxBundleConfigs {
+		return Bundle{}, errors.Wrap(ErrInvalidBundle, fmt.Errorf("more than %d bootstrap configs", MaxBundleConfigs))
+	}
+	// The ids are replaced in the content of the configs, where an empty id
+	// would match everywhere.
+	for i, ch := range bundle.Channels {
+		if ch.ID == "" {
+			return Bundle{}, errors.Wrap(ErrInvalidBundle, fmt.Errorf("channel %d has no id", i+1))
+		}
+	}
+	for i, cfg := range bundle.Configs {
+		if cfg.Thing.ID == "" {
+			return Bundle{}, errors.Wrap(ErrInvalidBundle, fmt.Errorf("thing of bootstrap config %d has no id", i+1))
+		}
+		for _, ch := range cfg.Channels {
+			if ch == "" {
+				return Bundle{}, errors.Wrap(ErrInvalidBundle, fmt.Errorf("bootstrap config %d refers to a channel without id", i+1))
+			}
+		}
+	}
+
+	return bundle, nil
+}
+
+func encodeBundle(bundle Bundle, format string) ([]byte, error) {
+	switch format {
+	case BundleYAML:
+		return yaml.Marshal(bundle)
+	default:
+		return json.MarshalIndent(bundle, "", "  ")
+	}
+}
+
+// exportBundle reads the bootstrap configs of the things, their things and
+// the channels they are connected to.
+func (us *uiService) exportBundle(token string, export BundleExport) (Bundle, error) {
+	bundle := Bundle{
+		Version:    BundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Redacted:   export.Redact,
+		Channels:   []BundleChannel{},
+		Configs:    []BundleConfig{},
+	}
+
+	channels := make(map[string]bool)
+	for _, thingID := range export.ThingIDs {
+		cfg, err := us.sdk.ViewBootstrap(thingID, token)
+		if err != nil {
+			return Bundle{}, err
+		}
+		thing, err := us.sdk.Thing(thingID, token)
+		if err != nil {
+			return Bundle{}, err
+		}
+
+		bc := BundleConfig{
+			Thing: BundleThing{
+				ID:       thing.ID,
+				Name:     thing.Name,
+				Secret:   thing.Credentials.Secret,
+				Tags:     thing.Tags,
+				Metadata: thing.Metadata,
+			},
+			ExternalID:  cfg.ExternalID,
+			ExternalKey: cfg.ExternalKey,
+			Name:        cfg.Name,
+			Content:     cfg.Content,
+			ClientCert:  cfg.ClientCert,
+			ClientKey:   cfg.ClientKey,
+			CACert:      cfg.CACert,
+			State:       cfg.State,
+		}
+		if export.Redact {
+			bc.Thing.Secret, bc.ExternalKey, bc.ClientKey = "", "", ""
+		}
+
+		for _, ch := range bootstrapChannels(cfg) {
+			bc.Channels = append(bc.Channels, ch.ID)
+			if channels[ch.ID] {
+				continue
+			}
+			channels[ch.ID] = true
+			// Bootstrap configs do not always hold the details of their
+			// channels.
+			full, err := us.sdk.Channel(ch.ID, token)
+			if err != nil {
+				return Bundle{}, err
+			}
+			bundle.Channels = append(bundle.Channels, BundleChannel{
+				ID:          full.ID,
+				Name:        full.Name,
+				Description: full.Description,
+				Metadata:    full.Metadata,
+			})
+		}
+		bundle.Configs = append(bundle.Configs, bc)
+	}
+
+	return bundle, nil
+}
+
+// importBundle recreates the channels, things and bootstrap configs of a
+// bundle. A failure only stops the entities depending on the failing one
+// from being imported. Things and channels created only for configs that
+// failed are removed again, so that importing the bundle again does not
+// leave duplicates behind.
+func (us *uiService) importBundle(token string, bundle Bundle, opts BundleImport) []BundleResult {
+	var results []BundleResult
+
+	// ids maps the ids of the bundle to the ids of the imported entities.
+	ids := make(map[string]string)
+	// created maps the ids of the created channels to their results, and
+	// used holds those the imported configs are connected to.
+	created := make(map[string]int)
+	used := make(map[string]bool)
+	for _, ch := range bundle.Channels {
+		res := BundleResult{Kind: BundleChannelKind, Name: ch.Name, OldID: ch.ID}
+		switch id, ok := opts.Remap[ch.ID]; {
+		case ok:
+			if _, err := us.sdk.Channel(id, token); err != nil {
+				res.Status, res.Error = BundleFailed, err.Error()
+				break
+			}
+			res.Status, res.NewID = BundleMapped, id
+		case opts.DryRun:
+			res.Status = BundlePlanned
+		default:
+			channel, err := us.sdk.CreateChannel(sdk.Channel{Name: ch.Name, Description: ch.Description, Metadata: ch.Metadata}, token)
+			if err != nil {
+				res.Status, res.Error = BundleFailed, err.Error()
+				break
+			}
+			res.Status, res.NewID = BundleCreated, channel.ID
+		}
+		if res.Status == BundleCreated {
+			created[ch.ID] = len(results)
+		}
+		if res.Status != BundleFailed {
+			ids[ch.ID] = res.NewID
+		}
+		results = append(results, res)
+	}
+
+	externalIDs := make(map[string]bool)
+	for _, cfg := range bundle.Configs {
+		thing := BundleResult{Kind: BundleThingKind, Name: cfg.Thing.Name, OldID: cfg.Thing.ID}
+		config := BundleResult{Kind: BundleBootstrapKind, Name: cfg.Name, OldID: cfg.Thing.ID}
+
+		err := us.checkBundleConfig(cfg, ids, externalIDs)
+		externalIDs[cfg.ExternalID] = true
+		if err != nil {
+			thing.Status, thing.Error = BundleFailed, "bootstrap config can not be imported"
+			config.Status, config.Error = BundleFailed, err.Error()
+			results = append(results, thing, config)
+			continue
+		}
+
+		createdThing := false
+		switch id, ok := opts.Remap[cfg.Thing.ID]; {
+		case ok:
+			if _, err := us.sdk.Thing(id, token); err != nil {
+				thing.Status, thing.Error = BundleFailed, err.Error()
+				break
+			}
+			thing.Status, thing.NewID = BundleMapped, id
+		case opts.DryRun:
+			thing.Status = BundlePlanned
+		default:
+			t, err := us.sdk.CreateThing(sdk.Thing{
+				Name:        cfg.Thing.Name,
+				Credentials: sdk.Credentials{Secret: cfg.Thing.Secret},
+				Tags:        cfg.Thing.Tags,
+				Metadata:    cfg.Thing.Metadata,
+			}, token)
+			if err != nil {
+				thing.Status, thing.Error = BundleFailed, err.Error()
+				break
+			}
+			thing.Status, thing.NewID, createdThing = BundleCreated, t.ID, true
+		}
+		thingIdx := len(results)
+		results = append(results, thing)
+		if thing.Status == BundleFailed {
+			config.Status, config.Error = BundleFailed, "thing was not imported"
+			results = append(results, config)
+			continue
+		}
+		if opts.DryRun {
+			config.Status = BundlePlanned
+			results = append(results, config)
+			continue
+		}
+
+		added, err := us.addBundleConfig(token, cfg, thing.NewID, ids)
+		if added {
+			for _, ch := range cfg.Channels {
+				used[ch] = true
+			}
+		}
+		switch {
+		case err == nil:
+			config.Status, config.NewID = BundleCreated, thing.NewID
+		case added:
+			config.Status, config.NewID, config.Error = BundleFailed, thing.NewID, fmt.Sprintf("bootstrap config was not enabled: %s", err)
+		default:
+			config.Status, config.Error = BundleFailed, err.Error()
+			if createdThing {
+				us.removeBundleEntity(&results[thingIdx], func() error { return us.sdk.DeleteThing(thing.NewID, token) })
+			}
+		}
+		results = append(results, config)
+	}
+
+	// Channels no config of the bundle is connected to are kept.
+	referenced := make(map[string]bool)
+	for _, cfg := range bundle.Configs {
+		for _, ch := range cfg.Channels {
+			referenced[ch] = true
+		}
+	}
+	for id, i := range created {
+		if referenced[id] && !used[id] {
+			newID := results[i].NewID
+			us.removeBundleEntity(&results[i], func() error { return us.sdk.DeleteChannel(newID, token) })
+		}
+	}
+
+	return results
+}
+
+// removeBundleEntity removes an entity created for bootstrap configs that
+// failed to be imported, and updates its result.
+func (us *uiService) removeBundleEntity(res *BundleResult, remove func() error) {
+	if err := remove(); err != nil {
+		res.Error = fmt.Sprintf("no bootstrap config was imported and the %s could not be removed: %s", res.Kind, err)
+		return
+	}
+	res.Status, res.NewID, res.Error = BundleRemoved, "", "no bootstrap config was imported"
+}
+
+// checkBundleConfig reports whether a bootstrap config of a bundle can be
+// imported.
+func (us *uiService) checkBundleConfig(cfg BundleConfig, ids map[string]string, externalIDs map[string]bool) error {
+	switch {
+	case cfg.Thing.ID == "":
+		return errors.New("missing thing id")
+	case cfg.ExternalID == "":
+		return errors.New("missing external id")
+	case externalIDs[cfg.ExternalID]:
+		return fmt.Errorf("duplicate external id %s", cfg.ExternalID)
+	case cfg.ExternalKey == "":
+		return errors.New("missing external key, which redacted bundles leave out")
+	}
+	for _, ch := range cfg.Channels {
+		if _, ok := ids[ch]; !ok {
+			return fmt.Errorf("channel %s was not imported", ch)
+		}
+	}
+	if report := ValidateBootstrapContent(cfg.Content); !report.Valid() {
+		return errors.Wrap(ErrInvalidContent, errors.New(report.String()))
+	}
+
+	return nil
+}
+
+// addBundleConfig creates a bootstrap config of a bundle for the thing,
+// referring to the imported channels, and enables it when it was enabled. It
+// reports whether the config was created.
+func (us *uiService) addBundleConfig(token string, cfg BundleConfig, thingID string, ids map[string]string) (bool, error) {
+	channels := make([]string, len(cfg.Channels))
+	for i, ch := range cfg.Channels {
+		channels[i] = ids[ch]
+	}
+
+	// The content may refer to the channels and thing by their id, such as
+	// the control channel of the agent.
+	var pairs []string
+	for old, id := range ids {
+		if old != "" && old != id && id != "" {
+			pairs = append(pairs, old, id)
+		}
+	}
+	if cfg.Thing.ID != "" && cfg.Thing.ID != thingID {
+		pairs = append(pairs, cfg.Thing.ID, thingID)
+	}
+	content := strings.NewReplacer(pairs...).Replace(cfg.Content)
+
+	if _, err := us.sdk.AddBootstrap(sdk.BootstrapConfig{
+		ThingID:     thingID,
+		ExternalID:  cfg.ExternalID,
+		ExternalKey: cfg.ExternalKey,
+		Name:        cfg.Name,
+		Content:     content,
+		Channels:    channels,
+		ClientCert:  cfg.ClientCert,
+		ClientKey:   cfg.ClientKey,
+		CACert:      cfg.CACert,
+	}, token); err != nil {
+		return false, err
+	}
+	if cfg.State == 1 {
+		if err := us.sdk.Whitelist(sdk.BootstrapConfig{ThingID: thingID, State: 1}, token); err != nil {
+			return true, err
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBundle(t *testing.T) {
+	cases := []struct {
+		desc    string
+		data    string
+		configs int
+		err     error
+	}{
+		{
+			desc:    "parse JSON bundle",
+			data:    `{"version":1,"exported_at":"2024-03-05T11:12:55Z","channels":[{"id":"c1","name":"control"}],"configs":[{"thing":{"id":"t1"},"external_id":"e1","external_key":"k1","channels":["c1"],"state":1}]}`,
+			configs: 1,
+		},
+		{
+			desc:    "parse YAML bundle",
+			data:    "version: 1\nconfigs:\n  - thing:\n      id: t1\n    external_id: e1\n  - thing:\n      id: t2\n    external_id: e2\n",
+			configs: 2,
+		},
+		{
+			desc: "parse bundle with unsupported version",
+			data: `{"version":2}`,
+			err:  ui.ErrInvalidBundle,
+		},
+		{
+			desc: "parse malformed bundle",
+			data: `{"version":1,"configs":{}}`,
+			err:  ui.ErrInvalidBundle,
+		},
+		{
+			desc: "parse bundle with channel without id",
+			data: `{"version":1,"channels":[{"id":"","name":"control"}],"configs":[{"thing":{"id":"t1"},"external_id":"e1"}]}`,
+			err:  ui.ErrInvalidBundle,
+		},
+		{
+			desc: "parse bundle with thing without id",
+			data: `{"version":1,"configs":[{"thing":{"id":""},"external_id":"e1"}]}`,
+			err:  ui.ErrInvalidBundle,
+		},
+		{
+			desc: "parse bundle with config referring to channel without id",
+			data: `{"version":1,"configs":[{"thing":{"id":"t1"},"external_id":"e1","channels":[""]}]}`,
+			err:  ui.ErrInvalidBundle,
+		},
+		{
+			desc: "parse bundle with too many configs",
+			data: "version: 1\nconfigs:\n" + strings.Repeat("  - external_id: e\n", ui.MaxBundleConfigs+1),
+			err:  ui.ErrInvalidBundle,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			bundle, err := ui.ParseBundle([]byte(tc.data))
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			assert.Len(t, bundle.Configs, tc.configs)
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	ClientCertificate = "client"
+	CACertificate     = "ca"
+
+	certInspectConcurrency = 10
+)
+
+// Certificate describes a PEM encoded X.509 certificate. The fields depending
+// on time are relative to the moment the certificate was inspected.
+type Certificate struct {
+	Subject      string
+	Issuer       string
+	SANs         []string
+	SerialNumber string
+	Fingerprint  string
+	NotBefore    time.Time
+	NotAfter     time.Time
+	IsCA         bool
+	Expired      bool
+	NotYetValid  bool
+	DaysLeft     int
+}
+
+// BootstrapCerts describes the certificates of a bootstrap config. A
+// certificate is nil when the config has none, and the error of a
+// certificate is set when it can not be parsed. Warnings list the
+// inconsistencies between the certificates and the client key.
+type BootstrapCerts struct {
+	Client      *Certificate
+	ClientError string
+	CA          *Certificate
+	CAError     string
+	Warnings    []string
+}
+
+// ExpiringCert is a certificate of a bootstrap config expiring within the
+// window of the expiring certificates report. Configs that could not be
+// inspected are reported with an error.
+type ExpiringCert struct {
+	ThingID string
+	Name    string
+	Type    string
+	Cert    Certificate
+	Error   string
+}
+
+// InspectBootstrapCerts parses the certificates of a bootstrap config.
+func InspectBootstrapCerts(cfg sdk.BootstrapConfig, now time.Time) BootstrapCerts {
+	var certs BootstrapCerts
+
+	var client, ca *x509.Certificate
+	if strings.TrimSpace(cfg.ClientCert) != "" {
+		info, cert, err := parseCertificate(cfg.ClientCert, now)
+		if err != nil {
+			certs.ClientError = err.Error()
+		} else {
+			certs.Client, client = &info, cert
+		}
+	}
+	if strings.TrimSpace(cfg.CACert) != "" {
+		info, cert, err := parseCertificate(cfg.CACert, now)
+		if err != nil {
+			certs.CAError = err.Error()
+		} else {
+			certs.CA, ca = &info, cert
+		}
+	}
+
+	if client != nil && strings.TrimSpace(cfg.ClientKey) != "" {
+		if _, err := tls.X509KeyPair([]byte(cfg.ClientCert), []byte(cfg.ClientKey)); err != nil {
+			certs.Warnings = append(certs.Warnings, "The client key does not match the client certificate.")
+		}
+	}
+	if client != nil && ca != nil {
+		if err := client.CheckSignatureFrom(ca); err != nil {
+			certs.Warnings = append(certs.Warnings, "The client certificate is not signed by the CA certificate.")
+		}
+	}
+
+	return certs
+}
+
+// ValidateCerts checks that the certificates of a bootstrap config are PEM
+// encoded. A client key that does not match the client certificate is not
+// refused, since the key and the certificate are often replaced one after
+// the other, and is reported by InspectBootstrapCerts instead.
+func ValidateCerts(cfg sdk.BootstrapConfig) error {
+	now := time.Now()
+	if cfg.ClientCert != "" {
+		if _, _, err := parseCertificate(cfg.ClientCert, now); err != nil {
+			return errors.Wrap(ErrInvalidCert, fmt.Errorf("client certificate: %w", err))
+		}
+	}
+	if cfg.CACert != "" {
+		if _, _, err := parseCertificate(cfg.CACert, now); err != nil {
+			return errors.Wrap(ErrInvalidCert, fmt.Errorf("CA certificate: %w", err))
+		}
+	}
+
+	return nil
+}
+
+// parseCertificate parses the first certificate of PEM encoded data.
+func parseCertificate(data string, now time.Time) (Certificate, *x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(data)))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return Certificate{}, nil, errors.New("not a PEM encoded certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return Certificate{}, nil, err
+	}
+
+	var sans []string
+	sans = append(sans, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+
+	sum := sha256.Sum256(cert.Raw)
+	fingerprint := make([]string, len(sum))
+	for i, b := range sum {
+		fingerprint[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return Certificate{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SANs:         sans,
+		SerialNumber: cert.SerialNumber.String(),
+		Fingerprint:  strings.Join(fingerprint, ":"),
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		IsCA:         cert.IsCA,
+		Expired:      now.After(cert.NotAfter),
+		NotYetValid:  now.Before(cert.NotBefore),
+		DaysLeft:     int(cert.NotAfter.Sub(now).Hours() / 24),
+	}, cert, nil
+}
+
+// expiringCerts inspects the certificates of every bootstrap config of the
+// domain and returns the ones expiring before the deadline, those expiring
+// first coming first.
+func (us *uiService) expiringCerts(token string, deadline, now time.Time) ([]ExpiringCert, error) {
+	configs, err := us.bootstrapConfigs(token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Listing bootstrap configs leaves their certificates out, so every
+	// config is viewed on its own.
+	found := make([][]ExpiringCert, len(configs))
+	var g errgroup.Group
+	g.SetLimit(certInspectConcurrency)
+	for i, cfg := range configs {
+		i, cfg := i, cfg
+		g.Go(func() error {
+			found[i] = us.inspectExpiry(token, cfg, deadline, now)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	var expiring []ExpiringCert
+	for _, certs := range found {
+		expiring = append(expiring, certs...)
+	}
+	// Configs that could not be inspected are listed first.
+	sort.SliceStable(expiring, func(i, j int) bool {
+		if (expiring[i].Error != "") != (expiring[j].Error != "") {
+			return expiring[i].Error != ""
+		}
+		return expiring[i].Cert.NotAfter.Before(expiring[j].Cert.NotAfter)
+	})
+
+	return expiring, nil
+}
+
+func (us *uiService) inspectExpiry(token string, cfg sdk.BootstrapConfig, deadline, now time.Time) []ExpiringCert {
+	full, err := us.sdk.ViewBootstrap(cfg.ThingID, token)
+	if err != nil {
+		return []ExpiringCert{{ThingID: cfg.ThingID, Name: cfg.Name, Error: err.Error()}}
+	}
+
+	var expiring []ExpiringCert
+	certs := InspectBootstrapCerts(full, now)
+	for _, c := range []struct {
+		typ  string
+		cert *Certificate
+		err  string
+	}{
+		{ClientCertificate, certs.Client, certs.ClientError},
+		{CACertificate, certs.CA, certs.CAError},
+	} {
+		switch {
+		case c.err != "":
+			expiring = append(expiring, ExpiringCert{ThingID: cfg.ThingID, Name: cfg.Name, Type: c.typ, Error: c.err})
+		case c.cert != nil && c.cert.NotAfter.Before(deadline):
+			expiring = append(expiring, ExpiringCert{ThingID: cfg.ThingID, Name: cfg.Name, Type: c.typ, Cert: *c.cert})
+		}
+	}
+
+	return expiring
+}
@@ -0,0 +1,193 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+// issueCert creates a certificate valid from notBefore to notAfter. The
+// certificate is self-signed when the parent is nil.
+func issueCert(t *testing.T, name string, isCA bool, notBefore, notAfter time.Time, parent *testCert) testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, fmt.Sprintf("generate key unexpected error: %s", err))
+
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name, Organization: []string{"Abstract Machines"}},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		DNSNames:              []string{name + ".example.com"},
+		IPAddresses:           []net.IP{net.ParseIP("10.0.0.1")},
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+	if isCA {
+		tpl.KeyUsage = x509.KeyUsageCertSign
+	}
+	signer, signerKey := tpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, signer, &key.PublicKey, signerKey)
+	require.Nil(t, err, fmt.Sprintf("create certificate unexpected error: %s", err))
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err, fmt.Sprintf("parse certificate unexpected error: %s", err))
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err, fmt.Sprintf("marshal key unexpected error: %s", err))
+
+	return testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+func TestInspectBootstrapCerts(t *testing.T) {
+	now := time.Now()
+	ca := issueCert(t, "ca", true, now.Add(-time.Hour), now.Add(365*24*time.Hour), nil)
+	client := issueCert(t, "client", false, now.Add(-time.Hour), now.Add(10*24*time.Hour+time.Hour), &ca)
+	other := issueCert(t, "other", true, now.Add(-time.Hour), now.Add(time.Hour), nil)
+	expired := issueCert(t, "expired", false, now.Add(-48*time.Hour), now.Add(-24*time.Hour), &ca)
+
+	cases := []struct {
+		desc     string
+		cfg      sdk.BootstrapConfig
+		client   bool
+		ca       bool
+		expired  bool
+		daysLeft int
+		errors   bool
+		warnings int
+	}{
+		{
+			desc:     "inspect certificates signed by the CA with their key",
+			cfg:      sdk.BootstrapConfig{ClientCert: client.certPEM, ClientKey: client.keyPEM, CACert: ca.certPEM},
+			client:   true,
+			ca:       true,
+			daysLeft: 10,
+		},
+		{
+			desc:     "inspect certificate without key and CA",
+			cfg:      sdk.BootstrapConfig{ClientCert: client.certPEM},
+			client:   true,
+			daysLeft: 10,
+		},
+		{
+			desc:     "inspect certificate with mismatched key",
+			cfg:      sdk.BootstrapConfig{ClientCert: client.certPEM, ClientKey: other.keyPEM},
+			client:   true,
+			daysLeft: 10,
+			warnings: 1,
+		},
+		{
+			desc:     "inspect certificate not signed by the CA",
+			cfg:      sdk.BootstrapConfig{ClientCert: client.certPEM, CACert: other.certPEM},
+			client:   true,
+			ca:       true,
+			daysLeft: 10,
+			warnings: 1,
+		},
+		{
+			desc:     "inspect expired certificate",
+			cfg:      sdk.BootstrapConfig{ClientCert: expired.certPEM},
+			client:   true,
+			expired:  true,
+			daysLeft: -1,
+		},
+		{
+			desc:   "inspect invalid certificates",
+			cfg:    sdk.BootstrapConfig{ClientCert: "not a certificate", CACert: client.keyPEM},
+			errors: true,
+		},
+		{
+			desc: "inspect config without certificates",
+			cfg:  sdk.BootstrapConfig{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			certs := ui.InspectBootstrapCerts(tc.cfg, now)
+			assert.Equal(t, tc.client, certs.Client != nil)
+			assert.Equal(t, tc.ca, certs.CA != nil)
+			assert.Equal(t, tc.errors, certs.ClientError != "" && certs.CAError != "")
+			assert.Len(t, certs.Warnings, tc.warnings)
+			if certs.Client != nil {
+				assert.Equal(t, tc.expired, certs.Client.Expired)
+				assert.Equal(t, tc.daysLeft, certs.Client.DaysLeft)
+				assert.Contains(t, certs.Client.Subject, "CN=")
+				assert.Contains(t, certs.Client.Issuer, "CN=ca")
+				assert.Len(t, certs.Client.SANs, 2)
+				assert.Len(t, certs.Client.Fingerprint, 95)
+			}
+		})
+	}
+}
+
+func TestValidateCerts(t *testing.T) {
+	now := time.Now()
+	ca := issueCert(t, "ca", true, now.Add(-time.Hour), now.Add(time.Hour), nil)
+	client := issueCert(t, "client", false, now.Add(-time.Hour), now.Add(time.Hour), &ca)
+
+	cases := []struct {
+		desc string
+		cfg  sdk.BootstrapConfig
+		err  error
+	}{
+		{
+			desc: "validate certificates with their key",
+			cfg:  sdk.BootstrapConfig{ClientCert: client.certPEM, ClientKey: client.keyPEM, CACert: ca.certPEM},
+		},
+		{
+			desc: "validate key without certificate",
+			cfg:  sdk.BootstrapConfig{ClientKey: client.keyPEM},
+		},
+		{
+			desc: "validate invalid client certificate",
+			cfg:  sdk.BootstrapConfig{ClientCert: "not a certificate"},
+			err:  ui.ErrInvalidCert,
+		},
+		{
+			desc: "validate invalid CA certificate",
+			cfg:  sdk.BootstrapConfig{CACert: ca.keyPEM},
+			err:  ui.ErrInvalidCert,
+		},
+		{
+			desc: "validate certificate with mismatched key",
+			cfg:  sdk.BootstrapConfig{ClientCert: client.certPEM, ClientKey: ca.keyPEM},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := ui.ValidateCerts(tc.cfg)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	ConnectionConnected  = "connected"
+	ConnectionExists     = "exists"
+	ConnectionUnresolved = "unresolved"
+	ConnectionFailed     = "failed"
+
+	// MaxConnectionRows limits the number of connections made at once.
+	MaxConnectionRows = 1000
+
+	// connectConcurrency is the number of connections requested at a time.
+	// The things service connects a single pair per request.
+	connectConcurrency = 10
+)
+
+// ConnectionRow is a connection to make, with the thing and channel given
+// either by id or by name.
+type ConnectionRow struct {
+	Thing   string
+	Channel string
+}
+
+// ConnectionResult is the outcome of a connection. Row is the line of the
+// connection in the file, the header being the first one.
+type ConnectionResult struct {
+	Row       int
+	Thing     string
+	Channel   string
+	ThingID   string
+	ChannelID string
+	Status    string
+	Error     string
+}
+
+// entityIndex resolves entities by id or, failing that, by name.
+type entityIndex struct {
+	ids   map[string]bool
+	names map[string][]string
+}
+
+func newEntityIndex() entityIndex {
+	return entityIndex{ids: make(map[string]bool), names: make(map[string][]string)}
+}
+
+func (idx entityIndex) add(id, name string) {
+	idx.ids[id] = true
+	if name != "" {
+		idx.names[name] = append(idx.names[name], id)
+	}
+}
+
+func (idx entityIndex) resolve(kind, val string) (string, error) {
+	if idx.ids[val] {
+		return val, nil
+	}
+	switch ids := idx.names[val]; len(ids) {
+	case 0:
+		return "", fmt.Errorf("no %s with id or name %q", kind, val)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("%d %ss are named %q, use the id instead", len(ids), kind, val)
+	}
+}
+
+// connectionIndexes lists the things and channels of the domain.
+func (us *uiService) connectionIndexes(token string) (entityIndex, entityIndex, error) {
+	things, channels := newEntityIndex(), newEntityIndex()
+	for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+		page, err := us.sdk.Things(sdk.PageMetadata{Offset: offset, Limit: batchPageSize, Status: statusAll}, token)
+		if err != nil {
+			return entityIndex{}, entityIndex{}, err
+		}
+		if len(page.Things) == 0 {
+			break
+		}
+		for _, th := range page.Things {
+			things.add(th.ID, th.Name)
+		}
+		total = page.Total
+	}
+	for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+		page, err := us.sdk.Channels(sdk.PageMetadata{Offset: offset, Limit: batchPageSize, Status: statusAll}, token)
+		if err != nil {
+			return entityIndex{}, entityIndex{}, err
+		}
+		if len(page.Channels) == 0 {
+			break
+		}
+		for _, ch := range page.Channels {
+			channels.add(ch.ID, ch.Name)
+		}
+		total = page.Total
+	}
+
+	return things, channels, nil
+}
+
+// thingChannels returns the ids of the channels the thing is connected to.
+func (us *uiService) thingChannels(token, thingID string) (map[string]bool, error) {
+	channels := make(map[string]bool)
+	for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+		page, err := us.sdk.ChannelsByThing(thingID, sdk.PageMetadata{Offset: offset, Limit: batchPageSize}, token)
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Channels) == 0 {
+			break
+		}
+		for _, ch := range page.Channels {
+			channels[ch.ID] = true
+		}
+		total = page.Total
+	}
+
+	return channels, nil
+}
+
+// connectRows resolves the things and channels of the rows, leaves out the
+// connections that already exist and makes the others, connectConcurrency at a
+// time. A failing connection does not stop the others.
+func (us *uiService) connectRows(token string, rows []ConnectionRow) ([]ConnectionResult, error) {
+	things, channels, err := us.connectionIndexes(token)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ConnectionResult, len(rows))
+	connected := make(map[string]map[string]bool)
+	seen := make(map[sdk.Connection]int)
+	var pending []int
+	for i, row := range rows {
+		res := ConnectionResult{Row: i + 2, Thing: row.Thing, Channel: row.Channel}
+		thingID, thingErr := things.resolve("thing", strings.TrimSpace(row.Thing))
+		channelID, channelErr := channels.resolve("channel", strings.TrimSpace(row.Channel))
+		res.ThingID, res.ChannelID = thingID, channelID
+		if thingErr != nil || channelErr != nil {
+			var msgs []string
+			for _, e := range []error{thingErr, channelErr} {
+				if e != nil {
+					msgs = append(msgs, e.Error())
+				}
+			}
+			res.Status, res.Error = ConnectionUnresolved, strings.Join(msgs, "; ")
+			results[i] = res
+			continue
+		}
+
+		conn := sdk.Connection{ThingID: thingID, ChannelID: channelID}
+		if line, ok := seen[conn]; ok {
+			res.Status, res.Error = ConnectionExists, fmt.Sprintf("same connection as row %d", line)
+			results[i] = res
+			continue
+		}
+		seen[conn] = res.Row
+
+		if _, ok := connected[thingID]; !ok {
+			chs, err := us.thingChannels(token, thingID)
+			if err != nil {
+				return nil, err
+			}
+			connected[thingID] = chs
+		}
+		if connected[thingID][channelID] {
+			res.Status = ConnectionExists
+			results[i] = res
+			continue
+		}
+		results[i] = res
+		pending = append(pending, i)
+	}
+
+	var g errgroup.Group
+	g.SetLimit(connectConcurrency)
+	for _, i := range pending {
+		res := &results[i]
+		g.Go(func() error {
+			res.Status = ConnectionConnected
+			if err := us.sdk.Connect(sdk.Connection{ThingID: res.ThingID, ChannelID: res.ChannelID}, token); err != nil {
+				res.Status, res.Error = ConnectionFailed, err.Error()
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return results, nil
+}
@@ -0,0 +1,134 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+)
+
+// The entities exported and imported as CSV.
+const (
+	UsersEntity    = usersActive
+	ThingsEntity   = thingsActive
+	ChannelsEntity = channelsActive
+	GroupsEntity   = groupsActive
+)
+
+// The headers of the exported files are those of the sample files the bulk
+// import reads, so that an export can be imported again.
+var (
+	usersCSVHeader    = []string{"Name", "ID", "Secret", "Metadata", "Tags"}
+	thingsCSVHeader   = []string{"Name", "Identity", "Secret", "Tags", "Metadata"}
+	channelsCSVHeader = []string{"Name", "Metadata", "Description"}
+	groupsCSVHeader   = []string{"Name", "Metadata", "Description"}
+)
+
+// EntityExport selects the users, things, channels or groups exported as
+// CSV. Status filters them like the listings do, and Secrets includes the
+// secrets of things. Users are always exported without their secrets, which
+// are never returned, so their secrets have to be filled in before the
+// export is imported again.
+type EntityExport struct {
+	Entity  string
+	Status  string
+	Secrets bool
+}
+
+// writeEntities pages through the entities and writes them in the layout of
+// the bulk import, flushing every page so that the entities are not held in
+// memory.
+func (us *uiService) writeEntities(w *csv.Writer, token string, export EntityExport) error {
+	switch export.Entity {
+	case UsersEntity:
+		if err := w.Write(usersCSVHeader); err != nil {
+			return err
+		}
+	case ThingsEntity:
+		if err := w.Write(thingsCSVHeader); err != nil {
+			return err
+		}
+	case ChannelsEntity:
+		if err := w.Write(channelsCSVHeader); err != nil {
+			return err
+		}
+	case GroupsEntity:
+		if err := w.Write(groupsCSVHeader); err != nil {
+			return err
+		}
+	}
+
+	for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+		pm := sdk.PageMetadata{Offset: offset, Limit: batchPageSize, Status: export.Status}
+
+		var records [][]string
+		switch export.Entity {
+		case UsersEntity:
+			page, err := us.sdk.Users(pm, token)
+			if err != nil {
+				return err
+			}
+			for _, u := range page.Users {
+				records = append(records, []string{u.Name, u.Credentials.Identity, "", csvJSON(u.Metadata), csvJSON(u.Tags)})
+			}
+			total = page.Total
+		case ThingsEntity:
+			page, err := us.sdk.Things(pm, token)
+			if err != nil {
+				return err
+			}
+			for _, th := range page.Things {
+				secret := ""
+				if export.Secrets {
+					secret = th.Credentials.Secret
+				}
+				records = append(records, []string{th.Name, th.Credentials.Identity, secret, csvJSON(th.Tags), csvJSON(th.Metadata)})
+			}
+			total = page.Total
+		case ChannelsEntity:
+			page, err := us.sdk.Channels(pm, token)
+			if err != nil {
+				return err
+			}
+			for _, ch := range page.Channels {
+				records = append(records, []string{ch.Name, csvJSON(ch.Metadata), ch.Description})
+			}
+			total = page.Total
+		case GroupsEntity:
+			page, err := us.sdk.Groups(pm, token)
+			if err != nil {
+				return err
+			}
+			for _, g := range page.Groups {
+				records = append(records, []string{g.Name, csvJSON(g.Metadata), g.Description})
+			}
+			total = page.Total
+		}
+		if len(records) == 0 {
+			break
+		}
+		if err := w.WriteAll(records); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+
+	return w.Error()
+}
+
+// csvJSON encodes tags and metadata the way the bulk import reads them,
+// leaving the column empty when there are none.
+func csvJSON[T ~[]string | ~map[string]interface{}](val T) string {
+	if len(val) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
@@ -0,0 +1,208 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+)
+
+const (
+	ImportValid   = "valid"
+	ImportInvalid = "invalid"
+	ImportCreated = "created"
+	ImportFailed  = "failed"
+
+	// MaxImportRows limits the number of rows of a bulk import.
+	MaxImportRows = 1000
+)
+
+// EntityImport is a bulk import of users, things, channels or groups from
+// CSV. Records are the rows of the file after its header. A dry run only
+// checks the rows.
+type EntityImport struct {
+	Entity  string
+	Records [][]string
+	DryRun  bool
+}
+
+// ImportRowResult is the outcome of importing a row of a CSV file. Row is the
+// line of the row in the file, the header being the first one.
+type ImportRowResult struct {
+	Row      int
+	Name     string
+	Identity string
+	Status   string
+	ID       string
+	Errors   []string
+}
+
+// importRow is a row of a CSV file read as the entity it creates.
+type importRow struct {
+	result ImportRowResult
+	user   sdk.User
+	thing  sdk.Thing
+	group  sdk.Group
+	// keys identify the entity among the other rows of the file.
+	keys []string
+}
+
+// importRows reads the rows of a bulk import and checks them, including for
+// duplicates within the file. Empty rows are skipped.
+func importRows(imp EntityImport) []importRow {
+	var rows []importRow
+	seen := make(map[string]int)
+	for i, record := range imp.Records {
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		row := readImportRow(imp.Entity, record)
+		row.result.Row = i + 2
+		for _, key := range row.keys {
+			if line, ok := seen[key]; ok {
+				row.result.Errors = append(row.result.Errors, fmt.Sprintf("duplicate %s of row %d", strings.SplitN(key, ":", 2)[0], line))
+				continue
+			}
+			seen[key] = row.result.Row
+		}
+		row.result.Status = ImportValid
+		if len(row.result.Errors) > 0 {
+			row.result.Status = ImportInvalid
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func readImportRow(entity string, record []string) importRow {
+	var row importRow
+	columns := len(thingsCSVHeader)
+	if entity == ChannelsEntity || entity == GroupsEntity {
+		columns = len(groupsCSVHeader)
+	}
+	if len(record) != columns {
+		row.result.Name = record[0]
+		row.result.Errors = append(row.result.Errors, fmt.Sprintf("expected %d columns, got %d", columns, len(record)))
+		return row
+	}
+
+	errs := &row.result.Errors
+	switch entity {
+	case UsersEntity:
+		u := sdk.User{Name: record[0], Credentials: sdk.Credentials{Identity: record[1], Secret: record[2]}}
+		if u.Credentials.Identity == "" {
+			*errs = append(*errs, "missing identity")
+		}
+		if u.Credentials.Secret == "" {
+			*errs = append(*errs, "missing secret")
+		}
+		readImportJSON(record[3], "metadata", &u.Metadata, errs)
+		readImportJSON(record[4], "tags", &u.Tags, errs)
+		row.user = u
+		row.result.Name, row.result.Identity = u.Name, u.Credentials.Identity
+		if u.Credentials.Identity != "" {
+			row.keys = append(row.keys, "identity:"+u.Credentials.Identity)
+		}
+	case ThingsEntity:
+		th := sdk.Thing{Name: record[0], Credentials: sdk.Credentials{Identity: record[1], Secret: record[2]}}
+		readImportJSON(record[3], "tags", &th.Tags, errs)
+		readImportJSON(record[4], "metadata", &th.Metadata, errs)
+		row.thing = th
+		row.result.Name, row.result.Identity = th.Name, th.Credentials.Identity
+		if th.Credentials.Identity != "" {
+			row.keys = append(row.keys, "identity:"+th.Credentials.Identity)
+		}
+		if th.Credentials.Secret != "" {
+			row.keys = append(row.keys, "secret:"+th.Credentials.Secret)
+		}
+	default:
+		g := sdk.Group{Name: record[0], Description: record[2]}
+		if g.Name == "" {
+			*errs = append(*errs, "missing name")
+		}
+		readImportJSON(record[1], "metadata", &g.Metadata, errs)
+		row.group = g
+		row.result.Name = g.Name
+		if g.Name != "" {
+			row.keys = append(row.keys, "name:"+g.Name)
+		}
+	}
+
+	return row
+}
+
+func readImportJSON(val, field string, dst interface{}, errs *[]string) {
+	if val == "" {
+		return
+	}
+	if err := json.Unmarshal([]byte(val), dst); err != nil {
+		*errs = append(*errs, fmt.Sprintf("invalid %s: %s", field, err))
+	}
+}
+
+// importEntities creates the entities of the valid rows, carrying on past
+// the rows that fail.
+func (us *uiService) importEntities(token string, imp EntityImport) []ImportRowResult {
+	rows := importRows(imp)
+
+	results := make([]ImportRowResult, len(rows))
+	for i, row := range rows {
+		res := row.result
+		if imp.DryRun || res.Status != ImportValid {
+			results[i] = res
+			continue
+		}
+
+		var err error
+		switch imp.Entity {
+		case UsersEntity:
+			var u sdk.User
+			u, err = us.sdk.CreateUser(row.user, token)
+			res.ID = u.ID
+		case ThingsEntity:
+			var th sdk.Thing
+			th, err = us.sdk.CreateThing(row.thing, token)
+			res.ID = th.ID
+		case ChannelsEntity:
+			var ch sdk.Channel
+			ch, err = us.sdk.CreateChannel(sdk.Channel{Name: row.group.Name, Description: row.group.Description, Metadata: row.group.Metadata}, token)
+			res.ID = ch.ID
+		case GroupsEntity:
+			var g sdk.Group
+			g, err = us.sdk.CreateGroup(row.group, token)
+			res.ID = g.ID
+		}
+		res.Status = ImportCreated
+		if err != nil {
+			res.Status, res.Errors = ImportFailed, []string{err.Error()}
+		}
+		results[i] = res
+	}
+
+	return results
+}
+
+func importResultsCSV(results []ImportRowResult) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"row", "name", "identity", "status", "id", "error"}); err != nil {
+		return nil, err
+	}
+	for _, res := range results {
+		record := []string{strconv.Itoa(res.Row), res.Name, res.Identity, res.Status, res.ID, strings.Join(res.Errors, "; ")}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
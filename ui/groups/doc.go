@@ -0,0 +1,6 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// Package groups extends the Magistrala SDK with the calls that move groups
+// within the group hierarchy over the HTTP API of the users service.
+package groups
@@ -0,0 +1,76 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package groups
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+)
+
+var _ ui.SDK = (*groupsSDK)(nil)
+
+type groupsSDK struct {
+	sdk.SDK
+	usersURL string
+	client   *http.Client
+}
+
+// NewSDK returns the SDK of the configuration, which also moves groups
+// within the group hierarchy through the users service of the configuration.
+func NewSDK(conf sdk.Config) ui.SDK {
+	return &groupsSDK{
+		SDK:      sdk.NewSDK(conf),
+		usersURL: conf.UsersURL,
+		client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: !conf.TLSVerification,
+				},
+			},
+		},
+	}
+}
+
+func (gs *groupsSDK) AssignParent(ctx context.Context, token, parentID, groupID string) error {
+	return gs.assign(ctx, token, parentID, "assign", groupID, http.StatusCreated)
+}
+
+func (gs *groupsSDK) UnassignParent(ctx context.Context, token, parentID, groupID string) error {
+	return gs.assign(ctx, token, parentID, "unassign", groupID, http.StatusNoContent)
+}
+
+func (gs *groupsSDK) assign(ctx context.Context, token, parentID, action, groupID string, expected int) error {
+	data, err := json.Marshal(map[string][]string{"group_ids": {groupID}})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/groups/%s/groups/%s", gs.usersURL, parentID, action)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := gs.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if sdkerr := errors.CheckError(resp, expected); sdkerr != nil {
+		return sdkerr
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package groups_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/absmach/magistrala-ui/ui/groups"
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	token    = "token"
+	parentID = "parent"
+	groupID  = "group"
+)
+
+type request struct {
+	method string
+	path   string
+	auth   string
+	body   string
+}
+
+func newServer(t *testing.T, status int, body string, req *request) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		*req = request{
+			method: r.Method,
+			path:   r.URL.Path,
+			auth:   r.Header.Get("Authorization"),
+			body:   string(data),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+}
+
+func TestAssignParent(t *testing.T) {
+	cases := []struct {
+		desc   string
+		assign bool
+		status int
+		body   string
+		path   string
+		err    bool
+	}{
+		{
+			desc:   "assign parent",
+			assign: true,
+			status: http.StatusCreated,
+			path:   "/groups/parent/groups/assign",
+		},
+		{
+			desc:   "unassign parent",
+			status: http.StatusNoContent,
+			path:   "/groups/parent/groups/unassign",
+		},
+		{
+			desc:   "assign parent with failing service",
+			assign: true,
+			status: http.StatusForbidden,
+			body:   `{"error":"failed to perform authorization over the entity"}`,
+			path:   "/groups/parent/groups/assign",
+			err:    true,
+		},
+		{
+			desc:   "unassign parent with unexpected status",
+			status: http.StatusOK,
+			path:   "/groups/parent/groups/unassign",
+			err:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var req request
+			ts := newServer(t, tc.status, tc.body, &req)
+			defer ts.Close()
+
+			gs := groups.NewSDK(sdk.Config{UsersURL: ts.URL})
+			action := gs.UnassignParent
+			if tc.assign {
+				action = gs.AssignParent
+			}
+			err := action(context.Background(), token, parentID, groupID)
+			assert.Equal(t, tc.err, err != nil, fmt.Sprintf("unexpected error: %v", err))
+			assert.Equal(t, http.MethodPost, req.method)
+			assert.Equal(t, tc.path, req.path)
+			assert.Equal(t, "Bearer "+token, req.auth)
+			assert.JSONEq(t, `{"group_ids":["group"]}`, req.body)
+		})
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// MaxTreeCountGroups limits the number of groups whose members are
+	// counted for a level of the group tree. Larger subtrees are shown
+	// without counts.
+	MaxTreeCountGroups = 200
+
+	treeConcurrency = 10
+)
+
+// GroupHierarchy moves groups within the group hierarchy. The SDK lists the
+// parents and children of a group but cannot change them.
+type GroupHierarchy interface {
+	// AssignParent makes the group a child of the parent group.
+	AssignParent(ctx context.Context, token, parentID, groupID string) error
+
+	// UnassignParent detaches the group from its parent group.
+	UnassignParent(ctx context.Context, token, parentID, groupID string) error
+}
+
+// SDK is the Magistrala SDK together with the group hierarchy calls it lacks.
+//
+//go:generate mockery --name SDK --output=./mocks --filename sdk.go --quiet --note "Copyright (c) Abstract Machines"
+type SDK interface {
+	sdk.SDK
+	GroupHierarchy
+}
+
+// GroupTreeNode is a group of the group tree. Children counts its direct
+// subgroups and Descendants all the groups below it, down to the deepest
+// level listed by the groups service. Users and Channels count the distinct
+// members of the group and of its descendants, and are only set when Counted
+// is.
+type GroupTreeNode struct {
+	Group       sdk.Group
+	Children    int
+	Descendants int
+	Users       int
+	Channels    int
+	Counted     bool
+}
+
+// rootGroups lists a page of the groups of the domain and returns those whose
+// parent is not visible to the user, and whether there are more pages. The
+// groups service cannot filter the groups without a parent.
+func (us *uiService) rootGroups(token string, page, limit uint64) ([]sdk.Group, bool, error) {
+	offset := (page - 1) * limit
+	gp, err := us.sdk.Groups(sdk.PageMetadata{Offset: offset, Limit: limit}, token)
+	if err != nil {
+		return nil, false, err
+	}
+
+	visible := make(map[string]bool, len(gp.Groups))
+	for _, g := range gp.Groups {
+		visible[g.ID] = true
+	}
+	var roots []sdk.Group
+	for _, g := range gp.Groups {
+		if g.ParentID != "" {
+			if _, ok := visible[g.ParentID]; !ok {
+				_, err := us.sdk.Group(g.ParentID, token)
+				switch {
+				case err == nil:
+					visible[g.ParentID] = true
+				case err.StatusCode() == http.StatusForbidden, err.StatusCode() == http.StatusNotFound:
+					visible[g.ParentID] = false
+				default:
+					return nil, false, err
+				}
+			}
+			if visible[g.ParentID] {
+				continue
+			}
+		}
+		roots = append(roots, g)
+	}
+
+	return roots, offset+uint64(len(gp.Groups)) < gp.Total, nil
+}
+
+// rootSubtrees lists the groups below every root group, at most
+// treeConcurrency roots at a time.
+func (us *uiService) rootSubtrees(token string, roots []sdk.Group) ([]sdk.Group, error) {
+	descendants := make([][]sdk.Group, len(roots))
+
+	var g errgroup.Group
+	g.SetLimit(treeConcurrency)
+	for i, root := range roots {
+		i, root := i, root
+		g.Go(func() error {
+			groups, err := us.groupDescendants(token, root.ID)
+			if err != nil {
+				return err
+			}
+			descendants[i] = groups
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var groups []sdk.Group
+	for _, d := range descendants {
+		groups = append(groups, d...)
+	}
+
+	return groups, nil
+}
+
+// groupTreeLevel returns the nodes of the groups of a level, with the ids of
+// the groups of their subtrees out of the groups below the level.
+func groupTreeLevel(level, descendants []sdk.Group) ([]GroupTreeNode, map[string][]string) {
+	children := make(map[string][]string)
+	for _, g := range descendants {
+		children[g.ParentID] = append(children[g.ParentID], g.ID)
+	}
+
+	subtrees := make(map[string][]string, len(level))
+	nodes := make([]GroupTreeNode, len(level))
+	for i, g := range level {
+		subtree := []string{g.ID}
+		for j := 0; j < len(subtree); j++ {
+			subtree = append(subtree, children[subtree[j]]...)
+		}
+		subtrees[g.ID] = subtree
+		nodes[i] = GroupTreeNode{Group: g, Children: len(children[g.ID]), Descendants: len(subtree) - 1}
+	}
+
+	return nodes, subtrees
+}
+
+// groupMembers lists the ids of the users and channels of every group, at
+// most treeConcurrency groups at a time.
+func (us *uiService) groupMembers(token string, groupIDs []string) (map[string][]string, map[string][]string, error) {
+	users := make(map[string][]string, len(groupIDs))
+	channels := make(map[string][]string, len(groupIDs))
+
+	var mu sync.Mutex
+	var g errgroup.Group
+	g.SetLimit(treeConcurrency)
+	for _, id := range groupIDs {
+		id := id
+		g.Go(func() error {
+			var userIDs, channelIDs []string
+			for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+				page, err := us.sdk.ListGroupUsers(id, sdk.PageMetadata{Offset: offset, Limit: batchPageSize}, token)
+				if err != nil {
+					return err
+				}
+				if len(page.Users) == 0 {
+					break
+				}
+				for _, u := range page.Users {
+					userIDs = append(userIDs, u.ID)
+				}
+				total = page.Total
+			}
+			for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+				page, err := us.sdk.ListGroupChannels(id, sdk.PageMetadata{Offset: offset, Limit: batchPageSize}, token)
+				if err != nil {
+					return err
+				}
+				if len(page.Groups) == 0 {
+					break
+				}
+				for _, ch := range page.Groups {
+					channelIDs = append(channelIDs, ch.ID)
+				}
+				total = page.Total
+			}
+			mu.Lock()
+			users[id], channels[id] = userIDs, channelIDs
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	return users, channels, nil
+}
+
+// countGroupTree sets the member counts of the nodes, unless their subtrees
+// hold more than MaxTreeCountGroups groups together.
+func (us *uiService) countGroupTree(token string, nodes []GroupTreeNode, subtrees map[string][]string) error {
+	var groupIDs []string
+	for _, node := range nodes {
+		groupIDs = append(groupIDs, subtrees[node.Group.ID]...)
+	}
+	if len(groupIDs) == 0 || len(groupIDs) > MaxTreeCountGroups {
+		return nil
+	}
+
+	users, channels, err := us.groupMembers(token, groupIDs)
+	if err != nil {
+		return err
+	}
+
+	for i, node := range nodes {
+		userIDs, channelIDs := make(map[string]bool), make(map[string]bool)
+		for _, id := range subtrees[node.Group.ID] {
+			for _, u := range users[id] {
+				userIDs[u] = true
+			}
+			for _, ch := range channels[id] {
+				channelIDs[ch] = true
+			}
+		}
+		nodes[i].Users, nodes[i].Channels, nodes[i].Counted = len(userIDs), len(channelIDs), true
+	}
+
+	return nil
+}
+
+// groupDescendants lists the groups below the group, down to the deepest
+// level listed by the groups service.
+func (us *uiService) groupDescendants(token, id string) ([]sdk.Group, error) {
+	var groups []sdk.Group
+	for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+		page, err := us.sdk.Children(id, sdk.PageMetadata{Offset: offset, Limit: batchPageSize}, token)
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Groups) == 0 {
+			break
+		}
+		for _, g := range page.Groups {
+			if g.ID != id {
+				groups = append(groups, g)
+			}
+		}
+		total = page.Total
+	}
+
+	return groups, nil
+}
+
+// groupAncestors lists the group and the groups above it, up to the highest
+// level listed by the groups service.
+func (us *uiService) groupAncestors(token, id string) ([]sdk.Group, error) {
+	var groups []sdk.Group
+	for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+		page, err := us.sdk.Parents(id, sdk.PageMetadata{Offset: offset, Limit: batchPageSize}, token)
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Groups) == 0 {
+			break
+		}
+		groups = append(groups, page.Groups...)
+		total = page.Total
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,369 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+	"golang.org/x/sync/errgroup"
+)
+
+// The categories of the hygiene report.
+const (
+	HygieneUnconnectedThings = "unconnected_things"
+	HygieneEmptyChannels     = "empty_channels"
+	HygieneDisabledConnected = "disabled_connected_things"
+	HygieneEmptyGroups       = "empty_groups"
+)
+
+// The cleanup actions of the hygiene report. Disconnect only applies to
+// disabled things that are still connected.
+const (
+	HygieneDisable    = "disable"
+	HygieneDelete     = "delete"
+	HygieneDisconnect = "disconnect"
+)
+
+const hygieneConcurrency = 10
+
+// HygieneActions returns the cleanup actions of a category, or nil when the
+// category is unknown.
+func HygieneActions(category string) []string {
+	switch category {
+	case HygieneUnconnectedThings, HygieneEmptyChannels, HygieneEmptyGroups:
+		return []string{HygieneDisable, HygieneDelete}
+	case HygieneDisabledConnected:
+		return []string{HygieneDisconnect, HygieneDelete}
+	default:
+		return nil
+	}
+}
+
+// hygieneEntity returns the kind of entity of a category.
+func hygieneEntity(category string) string {
+	switch category {
+	case HygieneEmptyChannels:
+		return ChannelsEntity
+	case HygieneEmptyGroups:
+		return GroupsEntity
+	default:
+		return ThingsEntity
+	}
+}
+
+// HygieneFinding is an entity flagged by the hygiene report. Channels is the
+// number of channels of a disabled thing. LastActivity is the time of the
+// last message read from the channel or published by the thing, and is zero
+// when the reader has none.
+type HygieneFinding struct {
+	ID           string
+	Name         string
+	Status       string
+	Channels     int
+	LastActivity time.Time
+}
+
+// HygieneCategory is a category of the hygiene report with its findings.
+type HygieneCategory struct {
+	Name     string
+	Entity   string
+	Actions  []string
+	Findings []HygieneFinding
+}
+
+// HygieneCleanup applies a cleanup action to entities of a category.
+type HygieneCleanup struct {
+	Category string
+	Action   string
+	IDs      []string
+}
+
+// hygieneReport scans the domain for things connected to no channel,
+// channels with no things, disabled things that are still connected and
+// groups with no subgroups, channels or users.
+func (us *uiService) hygieneReport(token string) ([]HygieneCategory, error) {
+	things, err := us.allThings(token)
+	if err != nil {
+		return nil, err
+	}
+	channels, err := us.allChannels(token)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := us.allGroups(token)
+	if err != nil {
+		return nil, err
+	}
+	disabled, err := us.disabledThings(token)
+	if err != nil {
+		return nil, err
+	}
+
+	connected, err := us.channelThings(token, channels)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool)
+	var emptyChannels []HygieneFinding
+	for i, ch := range channels {
+		for _, th := range connected[i] {
+			used[th.ID] = true
+		}
+		if len(connected[i]) == 0 {
+			emptyChannels = append(emptyChannels, HygieneFinding{ID: ch.ID, Name: ch.Name, Status: ch.Status})
+		}
+	}
+	var unconnected []HygieneFinding
+	for _, th := range things {
+		if !used[th.ID] {
+			unconnected = append(unconnected, HygieneFinding{ID: th.ID, Name: th.Name, Status: th.Status})
+		}
+	}
+
+	disabledConnected, err := us.disabledConnectedThings(token, disabled)
+	if err != nil {
+		return nil, err
+	}
+	emptyGroups, err := us.emptyGroups(token, groups, channels)
+	if err != nil {
+		return nil, err
+	}
+
+	us.channelsActivity(token, emptyChannels)
+
+	return []HygieneCategory{
+		{Name: HygieneUnconnectedThings, Entity: ThingsEntity, Actions: HygieneActions(HygieneUnconnectedThings), Findings: unconnected},
+		{Name: HygieneEmptyChannels, Entity: ChannelsEntity, Actions: HygieneActions(HygieneEmptyChannels), Findings: emptyChannels},
+		{Name: HygieneDisabledConnected, Entity: ThingsEntity, Actions: HygieneActions(HygieneDisabledConnected), Findings: disabledConnected},
+		{Name: HygieneEmptyGroups, Entity: GroupsEntity, Actions: HygieneActions(HygieneEmptyGroups), Findings: emptyGroups},
+	}, nil
+}
+
+func (us *uiService) disabledThings(token string) ([]sdk.Thing, error) {
+	var things []sdk.Thing
+	for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+		page, err := us.sdk.Things(sdk.PageMetadata{Offset: offset, Limit: batchPageSize, Status: sdk.DisabledStatus}, token)
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Things) == 0 {
+			break
+		}
+		things = append(things, page.Things...)
+		total = page.Total
+		if total > MaxTopologyNodes {
+			return nil, ErrTooManyEntities
+		}
+	}
+
+	return things, nil
+}
+
+// disabledConnectedThings returns the disabled things that are connected to
+// channels, with the last message they published on them, looking at
+// hygieneConcurrency things at a time.
+func (us *uiService) disabledConnectedThings(token string, things []sdk.Thing) ([]HygieneFinding, error) {
+	findings := make([]HygieneFinding, len(things))
+
+	var g errgroup.Group
+	g.SetLimit(hygieneConcurrency)
+	for i, th := range things {
+		i, th := i, th
+		g.Go(func() error {
+			channels, err := us.thingChannels(token, th.ID)
+			if err != nil {
+				return err
+			}
+			findings[i] = HygieneFinding{ID: th.ID, Name: th.Name, Status: th.Status, Channels: len(channels)}
+			for chID := range channels {
+				if last := us.lastActivity(token, chID, th.ID); last.After(findings[i].LastActivity) {
+					findings[i].LastActivity = last
+				}
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var connected []HygieneFinding
+	for _, f := range findings {
+		if f.Channels > 0 {
+			connected = append(connected, f)
+		}
+	}
+
+	return connected, nil
+}
+
+// emptyGroups returns the groups with no subgroups, channels or users.
+func (us *uiService) emptyGroups(token string, groups []sdk.Group, channels []sdk.Channel) ([]HygieneFinding, error) {
+	parents := make(map[string]bool)
+	for _, g := range groups {
+		parents[g.ParentID] = true
+	}
+	for _, ch := range channels {
+		parents[ch.ParentID] = true
+	}
+	var candidates []sdk.Group
+	var ids []string
+	for _, g := range groups {
+		if !parents[g.ID] {
+			candidates = append(candidates, g)
+			ids = append(ids, g.ID)
+		}
+	}
+
+	users, groupChannels, err := us.groupMembers(token, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var empty []HygieneFinding
+	for _, g := range candidates {
+		if len(users[g.ID]) == 0 && len(groupChannels[g.ID]) == 0 {
+			empty = append(empty, HygieneFinding{ID: g.ID, Name: g.Name, Status: g.Status})
+		}
+	}
+
+	return empty, nil
+}
+
+// channelsActivity sets the time of the last message of every channel,
+// reading hygieneConcurrency channels at a time.
+func (us *uiService) channelsActivity(token string, channels []HygieneFinding) {
+	var g errgroup.Group
+	g.SetLimit(hygieneConcurrency)
+	for i := range channels {
+		i := i
+		g.Go(func() error {
+			channels[i].LastActivity = us.lastActivity(token, channels[i].ID, "")
+			return nil
+		})
+	}
+	_ = g.Wait()
+}
+
+// lastActivity returns the time of the last message of the channel, of the
+// publisher when it is set. The reader is optional, so its failures leave
+// the time unknown.
+func (us *uiService) lastActivity(token, channelID, publisher string) time.Time {
+	mpgm := sdk.MessagePageMetadata{PageMetadata: sdk.PageMetadata{Limit: 1}, Publisher: publisher}
+	page, err := us.sdk.ReadMessages(mpgm, channelID, token)
+	if err != nil || len(page.Messages) == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, int64(page.Messages[0].Time))
+}
+
+// cleanupHygiene applies the cleanup to its entities, at most
+// hygieneConcurrency at a time. Every entity is checked again before the
+// action, and entities that no longer belong to the category are left as
+// they are.
+func (us *uiService) cleanupHygiene(token string, cleanup HygieneCleanup) []BulkActionResult {
+	seen := make(map[string]bool, len(cleanup.IDs))
+	var ids []string
+	for _, id := range cleanup.IDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	results := make([]BulkActionResult, len(ids))
+
+	var g errgroup.Group
+	g.SetLimit(hygieneConcurrency)
+	for i, id := range ids {
+		i, id := i, id
+		g.Go(func() error {
+			results[i] = us.cleanupHygieneEntity(token, cleanup, id)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return results
+}
+
+func (us *uiService) cleanupHygieneEntity(token string, cleanup HygieneCleanup, id string) BulkActionResult {
+	entity := hygieneEntity(cleanup.Category)
+	target, err := us.loadBulkEntity(token, entity, id)
+	if err != nil {
+		return BulkActionResult{ID: id, Status: BulkFailed, Error: fmt.Sprintf("failed to retrieve %s: %s", entity, err)}
+	}
+	res := BulkActionResult{ID: id, Name: target.Name, Status: BulkApplied}
+
+	flagged, channels, err := us.stillFlagged(token, cleanup.Category, target)
+	if err != nil {
+		res.Status, res.Error = BulkFailed, err.Error()
+		return res
+	}
+	if !flagged {
+		res.Status = BulkUnchanged
+		return res
+	}
+
+	switch cleanup.Action {
+	case HygieneDisable:
+		if target.Status == sdk.DisabledStatus {
+			res.Status = BulkUnchanged
+			return res
+		}
+		err = us.setBulkStatus(token, entity, id, false)
+	case HygieneDisconnect:
+		for chID := range channels {
+			if err = us.sdk.DisconnectThing(id, chID, token); err != nil {
+				break
+			}
+		}
+	case HygieneDelete:
+		switch entity {
+		case ThingsEntity:
+			err = us.sdk.DeleteThing(id, token)
+		case ChannelsEntity:
+			err = us.sdk.DeleteChannel(id, token)
+		default:
+			err = us.sdk.DeleteGroup(id, token)
+		}
+	}
+	if err != nil {
+		res.Status, res.Error = BulkFailed, err.Error()
+	}
+
+	return res
+}
+
+// stillFlagged tells whether the entity still belongs to the category, and
+// returns the channels of things.
+func (us *uiService) stillFlagged(token, category string, target bulkEntity) (bool, map[string]bool, error) {
+	switch category {
+	case HygieneUnconnectedThings:
+		channels, err := us.thingChannels(token, target.ID)
+		return target.Status != sdk.DisabledStatus && len(channels) == 0, channels, err
+	case HygieneDisabledConnected:
+		channels, err := us.thingChannels(token, target.ID)
+		return target.Status == sdk.DisabledStatus && len(channels) > 0, channels, err
+	case HygieneEmptyChannels:
+		page, err := us.sdk.ThingsByChannel(target.ID, sdk.PageMetadata{Limit: 1}, token)
+		if err != nil {
+			return false, nil, err
+		}
+		return page.Total == 0 && len(page.Things) == 0, nil, nil
+	default:
+		children, err := us.groupDescendants(token, target.ID)
+		if err != nil {
+			return false, nil, err
+		}
+		users, channels, err := us.groupMembers(token, []string{target.ID})
+		if err != nil {
+			return false, nil, err
+		}
+		return len(children) == 0 && len(users[target.ID]) == 0 && len(channels[target.ID]) == 0, nil, nil
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/absmach/magistrala/pkg/errors"
+)
+
+// DiagnosticCommands are the only commands accepted by a terminal in
+// read-only mode. A "*" in a pattern matches any sequence of characters, and
+// an "<arg>" matches a single argument that is not an option. Commands that
+// change the state of the device with some arguments, such as ip, ifconfig
+// or journalctl, are only accepted in forms that cannot.
+var DiagnosticCommands = []string{
+	"uptime",
+	"uname", "uname *",
+	"hostname",
+	"date",
+	"whoami",
+	"df", "df *",
+	"free", "free *",
+	"ps", "ps *",
+	"ip addr", "ip addr show", "ip addr show *",
+	"ip route", "ip route show", "ip route show *",
+	"ifconfig", "ifconfig -a", "ifconfig <arg>",
+	"ping -c <arg> <arg>",
+	"netstat *",
+	"cat /proc/cpuinfo", "cat /proc/meminfo", "cat /proc/loadavg", "cat /proc/uptime",
+	"cat /proc/version", "cat /proc/mounts", "cat /proc/partitions", "cat /proc/diskstats",
+	"cat /proc/net/dev", "cat /proc/net/route", "cat /proc/net/arp",
+	"systemctl status *",
+	"journalctl", "journalctl -n <arg>",
+	"journalctl -u <arg>", "journalctl -u <arg> -n <arg>",
+	"journalctl -b", "journalctl -b -n <arg>",
+	"journalctl -k", "journalctl -k -n <arg>",
+	"journalctl -p <arg> -n <arg>",
+}
+
+// diagnosticArg stands for a single argument in the diagnostic commands.
+const diagnosticArg = "<arg>"
+
+// TerminalPolicy restricts the commands the members of a domain can send to
+// agents through the remote terminal. A command is refused if it matches a
+// deny pattern, if the terminal is read-only and the command is not a
+// diagnostic command, or if there are allow patterns and it matches none of
+// them. A "*" in a pattern matches any sequence of characters.
+type TerminalPolicy struct {
+	DomainID  string    `json:"domain_id" db:"domain_id"`
+	AdminOnly bool      `json:"admin_only" db:"admin_only"`
+	ReadOnly  bool      `json:"read_only" db:"read_only"`
+	Allow     []string  `json:"allow" db:"allow"`
+	Deny      []string  `json:"deny" db:"deny"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TerminalPolicyRepository provides an interface for interacting with the terminal policies storage.
+//
+//go:generate mockery --name TerminalPolicyRepository --output=./mocks --filename policy.go --quiet --note "Copyright (c) Abstract Machines"
+type TerminalPolicyRepository interface {
+	// Persists the terminal policy of a domain, replacing any existing one.
+	// A non-nil error is returned to indicate a failure to persist.
+	Save(ctx context.Context, policy TerminalPolicy) error
+
+	// Retrieves the terminal policy of a domain. A policy that permits every
+	// command is returned if the domain has none. A non-nil error is
+	// returned to indicate a failure to retrieve.
+	Retrieve(ctx context.Context, domainID string) (TerminalPolicy, error)
+}
+
+// Check returns an error wrapping ErrTerminalForbidden if the policy does
+// not permit the user of the session to send the command.
+func (p TerminalPolicy) Check(s Session, command string) error {
+	if p.AdminOnly && !isDomainAdmin(s) {
+		return errors.Wrap(ErrTerminalForbidden, errors.New("the terminal is limited to domain administrators"))
+	}
+
+	command = normalizeCommand(command)
+	for _, pattern := range p.Deny {
+		if matchCommand(pattern, command) {
+			return errors.Wrap(ErrTerminalForbidden, fmt.Errorf("the command matches the denied pattern %q", pattern))
+		}
+	}
+	if p.ReadOnly && (strings.Contains(command, "..") || !matchDiagnostic(command)) {
+		return errors.Wrap(ErrTerminalForbidden, errors.New("the terminal is read-only and only accepts diagnostic commands"))
+	}
+	if len(p.Allow) > 0 && !matchAny(p.Allow, command) {
+		return errors.Wrap(ErrTerminalForbidden, errors.New("the command matches none of the allowed patterns"))
+	}
+
+	return nil
+}
+
+func matchAny(patterns []string, command string) bool {
+	for _, pattern := range patterns {
+		if matchCommand(pattern, command) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchDiagnostic reports whether the command is one of the diagnostic
+// commands.
+func matchDiagnostic(command string) bool {
+	for _, pattern := range DiagnosticCommands {
+		if re, err := commandRegexp(pattern, true); err == nil && re.MatchString(command) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchCommand reports whether the whole command matches the pattern. Both
+// are compared with their arguments separated by single spaces.
+func matchCommand(pattern, command string) bool {
+	re, err := commandRegexp(pattern, false)
+	if err != nil {
+		return false
+	}
+
+	return re.MatchString(command)
+}
+
+// commandRegexp compiles a command pattern. With args set, the "<arg>"
+// placeholders of the pattern match a single argument that does not start
+// with a dash.
+func commandRegexp(pattern string, args bool) (*regexp.Regexp, error) {
+	parts := strings.Split(normalizeCommand(pattern), "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+		if args {
+			parts[i] = strings.ReplaceAll(parts[i], diagnosticArg, `[^\s-]\S*`)
+		}
+	}
+
+	return regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// normalizeCommand separates the arguments of a command with single spaces,
+// whether they were typed separated by spaces or by the commas the agent
+// splits them on.
+func normalizeCommand(command string) string {
+	return strings.Join(strings.FieldsFunc(command, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}), " ")
+}
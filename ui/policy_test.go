@@ -0,0 +1,185 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerminalPolicyCheck(t *testing.T) {
+	admin := validSession
+	admin.Domain.Permissions = []string{"admin", "view", "edit"}
+
+	cases := []struct {
+		desc    string
+		policy  ui.TerminalPolicy
+		session ui.Session
+		command string
+		err     error
+	}{
+		{
+			desc:    "check command without policy",
+			session: validSession,
+			command: "rm,-rf,/",
+		},
+		{
+			desc:    "check command of member in terminal limited to administrators",
+			policy:  ui.TerminalPolicy{AdminOnly: true},
+			session: validSession,
+			command: "uptime",
+			err:     ui.ErrTerminalForbidden,
+		},
+		{
+			desc:    "check command of administrator in terminal limited to administrators",
+			policy:  ui.TerminalPolicy{AdminOnly: true},
+			session: admin,
+			command: "uptime",
+		},
+		{
+			desc:    "check command matching denied pattern",
+			policy:  ui.TerminalPolicy{Deny: []string{"rm *"}},
+			session: admin,
+			command: "rm,-rf,/tmp",
+			err:     ui.ErrTerminalForbidden,
+		},
+		{
+			desc:    "check command matching allowed pattern",
+			policy:  ui.TerminalPolicy{Allow: []string{"systemctl restart *"}},
+			session: validSession,
+			command: "systemctl restart  agent",
+		},
+		{
+			desc:    "check command matching no allowed pattern",
+			policy:  ui.TerminalPolicy{Allow: []string{"systemctl restart *"}},
+			session: validSession,
+			command: "systemctl,stop,agent",
+			err:     ui.ErrTerminalForbidden,
+		},
+		{
+			desc:    "check command matching both allowed and denied patterns",
+			policy:  ui.TerminalPolicy{Allow: []string{"systemctl *"}, Deny: []string{"systemctl stop *"}},
+			session: validSession,
+			command: "systemctl,stop,agent",
+			err:     ui.ErrTerminalForbidden,
+		},
+		{
+			desc:    "check diagnostic command in read-only terminal",
+			policy:  ui.TerminalPolicy{ReadOnly: true},
+			session: validSession,
+			command: "df,-h",
+		},
+		{
+			desc:    "check command in read-only terminal",
+			policy:  ui.TerminalPolicy{ReadOnly: true},
+			session: validSession,
+			command: "reboot",
+			err:     ui.ErrTerminalForbidden,
+		},
+		{
+			desc:    "check command escaping diagnostic pattern in read-only terminal",
+			policy:  ui.TerminalPolicy{ReadOnly: true},
+			session: validSession,
+			command: "cat,/proc/../etc/shadow",
+			err:     ui.ErrTerminalForbidden,
+		},
+		{
+			desc:    "check diagnostic command with argument in read-only terminal",
+			policy:  ui.TerminalPolicy{ReadOnly: true},
+			session: validSession,
+			command: "journalctl,-u,agent,-n,50",
+		},
+		{
+			desc:    "check interface diagnostic command in read-only terminal",
+			policy:  ui.TerminalPolicy{ReadOnly: true},
+			session: validSession,
+			command: "ifconfig,eth0",
+		},
+		{
+			desc:    "check route diagnostic command in read-only terminal",
+			policy:  ui.TerminalPolicy{ReadOnly: true},
+			session: validSession,
+			command: "ip,route,show,dev,eth0",
+		},
+		{
+			desc:    "check proc diagnostic command in read-only terminal",
+			policy:  ui.TerminalPolicy{ReadOnly: true},
+			session: validSession,
+			command: "cat,/proc/meminfo",
+		},
+		{
+			desc:    "check route deletion in read-only terminal",
+			policy:  ui.TerminalPolicy{ReadOnly: true},
+			session: validSession,
+			command: "ip,route,del,default",
+			err:     ui.ErrTerminalForbidden,
+		},
+		{
+			desc:    "check address flush in read-only terminal",
+			policy:  ui.TerminalPolicy{ReadOnly: true},
+			session: validSession,
+			command: "ip,addr,flush,dev,eth0",
+			err:     ui.ErrTerminalForbidden,
+		},
+		{
+			desc:    "check interface shutdown in read-only terminal",
+			policy:  ui.TerminalPolicy{ReadOnly: true},
+			session: validSession,
+			command: "ifconfig,eth0,down",
+			err:     ui.ErrTerminalForbidden,
+		},
+		{
+			desc:    "check journal vacuum in read-only terminal",
+			policy:  ui.TerminalPolicy{ReadOnly: true},
+			session: validSession,
+			command: "journalctl,--vacuum-time=1s",
+			err:     ui.ErrTerminalForbidden,
+		},
+		{
+			desc:    "check journal rotation in read-only terminal",
+			policy:  ui.TerminalPolicy{ReadOnly: true},
+			session: validSession,
+			command: "journalctl,--rotate",
+			err:     ui.ErrTerminalForbidden,
+		},
+		{
+			desc:    "check journal option passed as argument in read-only terminal",
+			policy:  ui.TerminalPolicy{ReadOnly: true},
+			session: validSession,
+			command: "journalctl,-u,--rotate",
+			err:     ui.ErrTerminalForbidden,
+		},
+		{
+			desc:    "check proc root escape in read-only terminal",
+			policy:  ui.TerminalPolicy{ReadOnly: true},
+			session: validSession,
+			command: "cat,/proc/self/root/etc/shadow",
+			err:     ui.ErrTerminalForbidden,
+		},
+		{
+			desc:    "check argument placeholder in allowed pattern",
+			policy:  ui.TerminalPolicy{Allow: []string{"ifconfig <arg>"}},
+			session: validSession,
+			command: "ifconfig,eth0",
+			err:     ui.ErrTerminalForbidden,
+		},
+		{
+			desc:    "check pattern with regular expression characters",
+			policy:  ui.TerminalPolicy{Allow: []string{"echo (a|b)"}},
+			session: validSession,
+			command: "echo,a",
+			err:     ui.ErrTerminalForbidden,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.policy.Check(tc.session, tc.command)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+)
+
+const (
+	// MaxProvisionChannels limits the channels a thing is connected to by
+	// the provisioning wizard.
+	MaxProvisionChannels = 20
+
+	// The value cards of a provisioned dashboard are laid out in rows.
+	provisionWidgetWidth    = 400
+	provisionWidgetHeight   = 220
+	provisionWidgetsPerRow  = 3
+	provisionUpdateInterval = "5000"
+)
+
+// ThingProvision is a thing to onboard with the provisioning wizard. The
+// thing is connected to the existing channels and to the new channels
+// created with the names. The bootstrap config and the dashboard are
+// optional. The dashboard shows the last value with the value name
+// published by the thing on each channel.
+type ThingProvision struct {
+	Name         string
+	Tags         []string
+	ChannelIDs   []string
+	ChannelNames []string
+	Bootstrap    bool
+	ExternalID   string
+	ExternalKey  string
+	Content      string
+	Dashboard    bool
+	ValueName    string
+}
+
+// ProvisionedThing summarizes a thing onboarded with the provisioning
+// wizard, with its credentials.
+type ProvisionedThing struct {
+	Thing       sdk.Thing     `json:"thing"`
+	Channels    []sdk.Channel `json:"channels"`
+	Bootstrap   bool          `json:"bootstrap"`
+	ExternalID  string        `json:"external_id,omitempty"`
+	ExternalKey string        `json:"external_key,omitempty"`
+	DashboardID string        `json:"dashboard_id,omitempty"`
+}
+
+// provisionStep is a step of the wizard that created an entity, with the
+// way to remove it.
+type provisionStep struct {
+	entity string
+	undo   func() error
+}
+
+// provisionThing creates the thing, its new channels, its connections, its
+// bootstrap config and its dashboard, in this order. Everything that can be
+// checked is checked before the first step. When a step fails the entities
+// created before are removed in reverse order, so that the domain is left
+// as it was.
+func (us *uiService) provisionThing(ctx context.Context, s Session, p ThingProvision) (ProvisionedThing, error) {
+	if p.Bootstrap {
+		if report := ValidateBootstrapContent(p.Content); !report.Valid() {
+			return ProvisionedThing{}, errors.Wrap(ErrInvalidContent, errors.New(report.String()))
+		}
+	}
+	var channels []sdk.Channel
+	for _, id := range p.ChannelIDs {
+		ch, err := us.sdk.Channel(id, s.Token)
+		if err != nil {
+			return ProvisionedThing{}, errors.Wrap(ErrFailedProvision, fmt.Errorf("failed to retrieve channel %s: %s", id, err))
+		}
+		channels = append(channels, ch)
+	}
+	secret, err := randomToken(rotationSecretSize)
+	if err != nil {
+		return ProvisionedThing{}, errors.Wrap(ErrFailedProvision, err)
+	}
+
+	var steps []provisionStep
+	thing, sdkerr := us.sdk.CreateThing(sdk.Thing{
+		Name:        p.Name,
+		Tags:        p.Tags,
+		Credentials: sdk.Credentials{Secret: secret},
+		Status:      sdk.EnabledStatus,
+	}, s.Token)
+	if sdkerr != nil {
+		return ProvisionedThing{}, errors.Wrap(ErrFailedProvision, fmt.Errorf("failed to create thing: %s", sdkerr))
+	}
+	steps = append(steps, provisionStep{
+		entity: fmt.Sprintf("thing %s", thing.ID),
+		undo:   func() error { return us.sdk.DeleteThing(thing.ID, s.Token) },
+	})
+
+	for _, name := range p.ChannelNames {
+		ch, err := us.sdk.CreateChannel(sdk.Channel{Name: name, Status: sdk.EnabledStatus}, s.Token)
+		if err != nil {
+			return ProvisionedThing{}, us.rollbackProvision(steps, fmt.Errorf("failed to create channel %s: %s", name, err))
+		}
+		steps = append(steps, provisionStep{
+			entity: fmt.Sprintf("channel %s", ch.ID),
+			undo:   func() error { return us.sdk.DeleteChannel(ch.ID, s.Token) },
+		})
+		channels = append(channels, ch)
+	}
+
+	// Connections are removed together with the thing, so they need no step
+	// of their own.
+	ids := make([]string, len(channels))
+	for i, ch := range channels {
+		if err := us.sdk.ConnectThing(thing.ID, ch.ID, s.Token); err != nil {
+			return ProvisionedThing{}, us.rollbackProvision(steps, fmt.Errorf("failed to connect to channel %s: %s", ch.ID, err))
+		}
+		ids[i] = ch.ID
+	}
+
+	res := ProvisionedThing{Thing: thing, Channels: channels}
+	if p.Bootstrap {
+		cfg := sdk.BootstrapConfig{
+			ThingID:     thing.ID,
+			ExternalID:  p.ExternalID,
+			ExternalKey: p.ExternalKey,
+			Name:        thing.Name,
+			Content:     p.Content,
+			Channels:    ids,
+		}
+		if _, err := us.sdk.AddBootstrap(cfg, s.Token); err != nil {
+			return ProvisionedThing{}, us.rollbackProvision(steps, fmt.Errorf("failed to create bootstrap config: %s", err))
+		}
+		steps = append(steps, provisionStep{
+			entity: fmt.Sprintf("bootstrap config %s", thing.ID),
+			undo:   func() error { return us.sdk.RemoveBootstrap(thing.ID, s.Token) },
+		})
+		res.Bootstrap, res.ExternalID, res.ExternalKey = true, p.ExternalID, p.ExternalKey
+	}
+
+	if p.Dashboard {
+		dashboard, err := us.provisionDashboard(s, thing, channels, p.ValueName)
+		if err != nil {
+			return ProvisionedThing{}, us.rollbackProvision(steps, fmt.Errorf("failed to create dashboard: %s", err))
+		}
+		if _, err := us.drepo.Create(ctx, dashboard); err != nil {
+			return ProvisionedThing{}, us.rollbackProvision(steps, fmt.Errorf("failed to create dashboard: %s", err))
+		}
+		res.DashboardID = dashboard.ID
+	}
+
+	return res, nil
+}
+
+// rollbackProvision removes the entities created by the steps in reverse
+// order. Entities that cannot be removed are listed in the error.
+func (us *uiService) rollbackProvision(steps []provisionStep, cause error) error {
+	var left []string
+	for i := len(steps) - 1; i >= 0; i-- {
+		if err := steps[i].undo(); err != nil {
+			left = append(left, steps[i].entity)
+		}
+	}
+	if len(left) > 0 {
+		return errors.Wrap(ErrProvisionRollback, fmt.Errorf("%s, and failed to remove %s", cause, strings.Join(left, ", ")))
+	}
+
+	return errors.Wrap(ErrFailedProvision, fmt.Errorf("%s, the created entities were removed", cause))
+}
+
+type dashboardItem struct {
+	WidgetID       string            `json:"widgetID"`
+	WidgetSize     map[string]string `json:"widgetSize"`
+	WidgetPosition map[string]string `json:"widgetPosition"`
+}
+
+// provisionDashboard returns a dashboard with a value card for every
+// channel of the thing, in the layout saved by the dashboard editor.
+func (us *uiService) provisionDashboard(s Session, thing sdk.Thing, channels []sdk.Channel, valueName string) (Dashboard, error) {
+	id, err := us.idProvider.ID()
+	if err != nil {
+		return Dashboard{}, err
+	}
+
+	now := time.Now()
+	width, height := fmt.Sprintf("%dpx", provisionWidgetWidth), fmt.Sprintf("%dpx", provisionWidgetHeight)
+	items := make([]dashboardItem, len(channels))
+	metadata := make(map[string]map[string]string, len(channels))
+	for i, ch := range channels {
+		widgetID := fmt.Sprintf("valueCard-%d", now.UnixMilli()+int64(i))
+		x, y := (i%provisionWidgetsPerRow)*provisionWidgetWidth, (i/provisionWidgetsPerRow)*provisionWidgetHeight
+		items[i] = dashboardItem{
+			WidgetID:   widgetID,
+			WidgetSize: map[string]string{"width": width, "height": height, "minWidth": width, "minHeight": height},
+			WidgetPosition: map[string]string{
+				"left":      "0px",
+				"top":       "0px",
+				"transform": fmt.Sprintf("translateX(%dpx) translateY(%dpx)", x, y),
+			},
+		}
+		metadata[widgetID] = map[string]string{
+			"Type":           "valueCard",
+			"channel":        ch.ID,
+			"thing":          thing.ID,
+			"valueName":      valueName,
+			"updateInterval": provisionUpdateInterval,
+			"title":          fmt.Sprintf("%s on %s", valueName, ch.Name),
+			"thingName":      thing.Name,
+			"valueUnits":     "",
+		}
+	}
+
+	layout, err := json.Marshal(map[string][]dashboardItem{"items": items})
+	if err != nil {
+		return Dashboard{}, err
+	}
+	md, err := json.Marshal(metadata)
+	if err != nil {
+		return Dashboard{}, err
+	}
+
+	return Dashboard{
+		ID:          id,
+		CreatedBy:   s.User.ID,
+		Name:        thing.Name,
+		Description: fmt.Sprintf("Dashboard of thing %s", thing.ID),
+		Layout:      string(layout),
+		Metadata:    string(md),
+		CreatedAt:   now,
+	}, nil
+}
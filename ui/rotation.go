@@ -0,0 +1,349 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/absmach/magistrala/pkg/errors"
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+	"golang.org/x/sync/errgroup"
+)
+
+// The triggers of a secret rotation.
+const (
+	RotationManual    = "manual"
+	RotationScheduled = "scheduled"
+)
+
+// The statuses of a secret rotation. A rotation is recorded as started
+// before the secret is changed, and keeps that status when its outcome could
+// not be recorded.
+const (
+	RotationStarted = "started"
+	RotationRotated = "rotated"
+	RotationFailed  = "failed"
+)
+
+const (
+
+	// MinRotationInterval is the shortest interval of a rotation schedule.
+	MinRotationInterval = time.Hour
+
+	rotationSecretSize  = 32
+	rotationConcurrency = 10
+
+	// A claimed schedule is claimed again after rotationClaim, when the
+	// instance that claimed it stopped before its run was recorded.
+	rotationClaim = time.Hour
+)
+
+// SecretRotation records the rotation of the secret of a thing. Things
+// rotated together share a run. The secrets of scheduled rotations are kept
+// encrypted until they are downloaded once, and Pending tells whether they
+// still are. Secrets of manual rotations are only shown to the user.
+type SecretRotation struct {
+	ID         string    `json:"id" db:"id"`
+	RunID      string    `json:"run_id" db:"run_id"`
+	DomainID   string    `json:"domain_id" db:"domain_id"`
+	ThingID    string    `json:"thing_id" db:"thing_id"`
+	ThingName  string    `json:"thing_name" db:"thing_name"`
+	UserID     string    `json:"user_id" db:"user_id"`
+	ScheduleID string    `json:"schedule_id,omitempty" db:"schedule_id"`
+	Trigger    string    `json:"trigger" db:"trigger"`
+	Status     string    `json:"status" db:"status"`
+	Error      string    `json:"error,omitempty" db:"error"`
+	Secret     string    `json:"-" db:"secret"`
+	Pending    bool      `json:"pending" db:"pending"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+type SecretRotationPage struct {
+	Total     uint64           `json:"total"`
+	Offset    uint64           `json:"offset"`
+	Limit     uint64           `json:"limit"`
+	Rotations []SecretRotation `json:"rotations"`
+}
+
+type SecretRotationPageMeta struct {
+	Offset   uint64 `json:"offset" db:"offset"`
+	Limit    uint64 `json:"limit" db:"limit"`
+	DomainID string `json:"domain_id" db:"domain_id"`
+}
+
+// RotationSchedule periodically rotates the secrets of the things of a
+// domain with a tag. Like personal access tokens, a schedule is backed by an
+// upstream refresh token, kept encrypted, so that it acts on behalf of the
+// user who created it. The token is refreshed before it expires, and a
+// schedule whose token is refused is Expired and no longer run.
+type RotationSchedule struct {
+	ID               string        `json:"id" db:"id"`
+	DomainID         string        `json:"domain_id" db:"domain_id"`
+	UserID           string        `json:"user_id" db:"user_id"`
+	Tag              string        `json:"tag" db:"tag"`
+	Interval         time.Duration `json:"interval" db:"rotation_interval"`
+	RefreshToken     string        `json:"-" db:"refresh_token"`
+	TokenRefreshedAt time.Time     `json:"token_refreshed_at" db:"token_refreshed_at"`
+	Expired          bool          `json:"expired" db:"expired"`
+	LastRunAt        time.Time     `json:"last_run_at,omitempty" db:"last_run_at"`
+	NextRunAt        time.Time     `json:"next_run_at" db:"next_run_at"`
+	LastError        string        `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt        time.Time     `json:"created_at" db:"created_at"`
+}
+
+// RotatedSecret is the new secret of a thing, shown once after a rotation.
+type RotatedSecret struct {
+	ThingID string
+	Name    string
+	Secret  string
+	Error   string
+}
+
+// SecretRotationRepository provides an interface for interacting with the secret rotations storage.
+//
+//go:generate mockery --name SecretRotationRepository --output=./mocks --filename rotation.go --quiet --note "Copyright (c) Abstract Machines"
+type SecretRotationRepository interface {
+	// Persists the rotations of a run. A non-nil error is returned to
+	// indicate a failure to persist.
+	Save(ctx context.Context, rotations ...SecretRotation) error
+
+	// Updates the statuses, errors and secrets of the rotations of a run. A
+	// non-nil error is returned to indicate a failure to update.
+	UpdateRotations(ctx context.Context, rotations ...SecretRotation) error
+
+	// Retrieves the rotations of a domain, most recent first. A non-nil error
+	// is returned to indicate a failure to retrieve.
+	RetrieveAll(ctx context.Context, pm SecretRotationPageMeta) (SecretRotationPage, error)
+
+	// Retrieves the rotations of a run that still hold their secrets and
+	// removes the secrets, so that they are only retrieved once. A non-nil
+	// error is returned to indicate a failure to retrieve.
+	TakeSecrets(ctx context.Context, domainID, runID string) ([]SecretRotation, error)
+
+	// Persists a new rotation schedule. A non-nil error is returned to
+	// indicate a failure to persist.
+	SaveSchedule(ctx context.Context, schedule RotationSchedule) error
+
+	// Retrieves the rotation schedules of a domain. A non-nil error is
+	// returned to indicate a failure to retrieve.
+	RetrieveSchedules(ctx context.Context, domainID string) ([]RotationSchedule, error)
+
+	// Claims the rotation schedules of all domains that have not expired
+	// and that are due at the given time or whose token was refreshed before
+	// refreshBefore, until the given claim expiry, so that they are not
+	// claimed again before they are updated or the claim expires. A non-nil
+	// error is returned to indicate a failure to retrieve.
+	ClaimSchedules(ctx context.Context, now, refreshBefore, until time.Time) ([]RotationSchedule, error)
+
+	// Updates the refresh token and the runs of a rotation schedule of its
+	// domain and releases its claim. A non-nil error is returned to indicate
+	// a failure to update.
+	UpdateSchedule(ctx context.Context, schedule RotationSchedule) error
+
+	// Removes a rotation schedule of a domain. A non-nil error is returned
+	// to indicate a failure to remove.
+	RemoveSchedule(ctx context.Context, domainID, id string) error
+}
+
+// RunRotationWorker runs the due rotation schedules every interval until
+// the context is done, and refreshes the tokens of the schedules older than
+// tokenMaxAge. Failures are reported by the service middleware.
+func RunRotationWorker(ctx context.Context, svc Service, interval, tokenMaxAge time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			_ = svc.RunRotationSchedules(ctx, now, tokenMaxAge)
+		}
+	}
+}
+
+// rotateSecrets gives the things new secrets generated by the UI, at most
+// rotationConcurrency at a time, and returns the secrets with the records of
+// the rotations in the order of the things. Things given by id only are
+// loaded first when load is set. A failing thing does not stop the others.
+// The rotations are saved before any secret is changed, with the encrypted
+// secrets of scheduled rotations, so that no secret is changed without a
+// record of it. Once the secrets are changed they are returned even when
+// their outcome cannot be recorded.
+func (us *uiService) rotateSecrets(ctx context.Context, token string, things []sdk.Thing, load bool, record SecretRotation) ([]RotatedSecret, []SecretRotation, error) {
+	failures := make([]string, len(things))
+	if load {
+		things = append([]sdk.Thing(nil), things...)
+		var g errgroup.Group
+		g.SetLimit(rotationConcurrency)
+		for i, th := range things {
+			i, th := i, th
+			g.Go(func() error {
+				loaded, err := us.sdk.Thing(th.ID, token)
+				if err != nil {
+					failures[i] = fmt.Sprintf("failed to retrieve thing: %s", err)
+					return nil
+				}
+				things[i] = loaded
+				return nil
+			})
+		}
+		_ = g.Wait()
+	}
+
+	rotated := make([]RotatedSecret, len(things))
+	rotations := make([]SecretRotation, len(things))
+	secrets := make([]string, len(things))
+	for i, th := range things {
+		id, err := us.idProvider.ID()
+		if err != nil {
+			return nil, nil, errors.Wrap(ErrFailedGenerateID, err)
+		}
+		rotation := record
+		rotation.ID, rotation.ThingID, rotation.ThingName, rotation.Status = id, th.ID, th.Name, RotationStarted
+		rotated[i].ThingID, rotated[i].Name = th.ID, th.Name
+		if failures[i] != "" {
+			rotation.Status, rotation.Error = RotationFailed, failures[i]
+			rotated[i].Error = failures[i]
+			rotations[i] = rotation
+			continue
+		}
+		if secrets[i], err = randomToken(rotationSecretSize); err != nil {
+			return nil, nil, err
+		}
+		if record.Trigger == RotationScheduled {
+			if rotation.Secret, err = encrypt(us.encKey, secrets[i]); err != nil {
+				return nil, nil, err
+			}
+		}
+		rotations[i] = rotation
+	}
+	if err := us.rrepo.Save(ctx, rotations...); err != nil {
+		return nil, nil, errors.Wrap(ErrFailedCreate, err)
+	}
+
+	var g errgroup.Group
+	g.SetLimit(rotationConcurrency)
+	for i := range things {
+		i := i
+		if secrets[i] == "" {
+			continue
+		}
+		g.Go(func() error {
+			if _, err := us.sdk.UpdateThingSecret(things[i].ID, secrets[i], token); err != nil {
+				rotations[i].Status, rotations[i].Error, rotations[i].Secret = RotationFailed, err.Error(), ""
+				rotated[i].Error = err.Error()
+				return nil
+			}
+			rotations[i].Status = RotationRotated
+			rotated[i].Secret = secrets[i]
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	if err := us.rrepo.UpdateRotations(ctx, rotations...); err != nil {
+		return rotated, rotations, errors.Wrap(ErrFailedUpdate, err)
+	}
+
+	return rotated, rotations, nil
+}
+
+// taggedThings lists the things of the domain with the tag.
+func (us *uiService) taggedThings(token, tag string) ([]sdk.Thing, error) {
+	var things []sdk.Thing
+	for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+		page, err := us.sdk.Things(sdk.PageMetadata{Offset: offset, Limit: batchPageSize, Tag: tag}, token)
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Things) == 0 {
+			break
+		}
+		things = append(things, page.Things...)
+		total = page.Total
+	}
+
+	return things, nil
+}
+
+// runRotationSchedule refreshes the token of the schedule and, when the
+// schedule is due, rotates the secrets of the things with its tag and keeps
+// them encrypted until they are downloaded. The refresh token of the
+// schedule is rotated on use, so it is saved before anything else. A token
+// that cannot be decrypted or is refused expires the schedule.
+func (us *uiService) runRotationSchedule(ctx context.Context, schedule RotationSchedule, now time.Time) error {
+	due := !schedule.NextRunAt.After(now)
+	if due {
+		schedule.LastRunAt, schedule.NextRunAt = now, now.Add(schedule.Interval)
+	}
+
+	refreshToken, err := decrypt(us.encKey, schedule.RefreshToken)
+	if err != nil {
+		schedule.Expired = true
+		return us.failRotationSchedule(ctx, schedule, err)
+	}
+	upstream, sdkerr := us.sdk.RefreshToken(sdk.Login{DomainID: schedule.DomainID}, refreshToken)
+	if sdkerr != nil {
+		switch sdkerr.StatusCode() {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			schedule.Expired = true
+		}
+		return us.failRotationSchedule(ctx, schedule, errors.Wrap(ErrTokenRefresh, sdkerr))
+	}
+	if schedule.RefreshToken, err = encrypt(us.encKey, upstream.RefreshToken); err != nil {
+		return us.failRotationSchedule(ctx, schedule, err)
+	}
+	schedule.TokenRefreshedAt = now
+	if due {
+		schedule.LastError = ""
+	}
+	if err := us.rrepo.UpdateSchedule(ctx, schedule); err != nil {
+		return errors.Wrap(ErrFailedUpdate, err)
+	}
+	if !due {
+		return nil
+	}
+
+	things, err := us.taggedThings(upstream.AccessToken, schedule.Tag)
+	if err != nil {
+		return us.failRotationSchedule(ctx, schedule, err)
+	}
+	if len(things) == 0 {
+		return nil
+	}
+
+	runID, err := us.idProvider.ID()
+	if err != nil {
+		return errors.Wrap(ErrFailedGenerateID, err)
+	}
+	record := SecretRotation{
+		RunID:      runID,
+		DomainID:   schedule.DomainID,
+		UserID:     schedule.UserID,
+		ScheduleID: schedule.ID,
+		Trigger:    RotationScheduled,
+		CreatedAt:  now,
+	}
+	if _, _, err := us.rotateSecrets(ctx, upstream.AccessToken, things, false, record); err != nil {
+		return us.failRotationSchedule(ctx, schedule, err)
+	}
+
+	return nil
+}
+
+// failRotationSchedule records the failure of a run on the schedule, which
+// is tried again at its next run unless it expired. A token that could not
+// be refreshed is refreshed again at the next check.
+func (us *uiService) failRotationSchedule(ctx context.Context, schedule RotationSchedule, err error) error {
+	schedule.LastError = err.Error()
+	if uerr := us.rrepo.UpdateSchedule(ctx, schedule); uerr != nil {
+		return errors.Wrap(ErrFailedUpdate, uerr)
+	}
+
+	return errors.Wrap(ErrFailedRotation, err)
+}
@@ -0,0 +1,216 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+)
+
+const (
+	// DomainsEntity is searched along with the entities of the domain.
+	DomainsEntity = domainsActive
+
+	// searchLimit is the number of entities of each kind returned by a
+	// single search query.
+	searchLimit = 20
+)
+
+// The parts of an entity a search query matches.
+const (
+	MatchName     = "name"
+	MatchTag      = "tag"
+	MatchMetadata = "metadata"
+)
+
+var (
+	uuidRegexp = regexp.MustCompile(uuidPattern)
+
+	searchEntities = []string{UsersEntity, ThingsEntity, ChannelsEntity, GroupsEntity, DomainsEntity}
+)
+
+// SearchResult is an entity found by a search. URL is empty for domains,
+// which are entered rather than viewed.
+type SearchResult struct {
+	Entity  string
+	ID      string
+	Name    string
+	Matches []string
+	URL     string
+}
+
+// searchQuery is a query against a single kind of entity.
+type searchQuery struct {
+	entity string
+	match  string
+	pm     sdk.PageMetadata
+}
+
+// searchQueries returns the queries of a search. Names are matched in
+// part, tags exactly, and a query of the form key=value matches the
+// metadata. Metadata is filtered by containment, so a value alone cannot
+// match it. Only users, things and domains have tags, and users are only
+// searched by administrators.
+func searchQueries(s Session, query string) []searchQuery {
+	var queries []searchQuery
+	for _, entity := range searchEntities {
+		if entity == UsersEntity && s.User.Role != "admin" {
+			continue
+		}
+		queries = append(queries, searchQuery{entity, MatchName, sdk.PageMetadata{Name: query}})
+		switch entity {
+		case UsersEntity, ThingsEntity, DomainsEntity:
+			queries = append(queries, searchQuery{entity, MatchTag, sdk.PageMetadata{Tag: query}})
+		}
+		if key, val, ok := strings.Cut(query, "="); ok && strings.TrimSpace(key) != "" {
+			metadata := sdk.Metadata{strings.TrimSpace(key): strings.TrimSpace(val)}
+			queries = append(queries, searchQuery{entity, MatchMetadata, sdk.PageMetadata{Metadata: metadata}})
+		}
+	}
+
+	return queries
+}
+
+// search runs the queries of a search concurrently and merges their results
+// by entity, in the order of searchEntities. The queries that fail are
+// reported by entity and do not stop the others.
+func (us *uiService) search(s Session, query string) ([]SearchResult, map[string]string) {
+	queries := searchQueries(s, query)
+	found := make([][]SearchResult, len(queries))
+	errs := make([]error, len(queries))
+
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Add(1)
+		go func(i int, q searchQuery) {
+			defer wg.Done()
+			q.pm.Limit = searchLimit
+			q.pm.Status = statusAll
+			found[i], errs[i] = us.searchEntity(s.Token, q)
+		}(i, q)
+	}
+	wg.Wait()
+
+	var results []SearchResult
+	failures := make(map[string]string)
+	byID := make(map[string]int)
+	for i, q := range queries {
+		if errs[i] != nil {
+			failures[q.entity] = errs[i].Error()
+			continue
+		}
+		for _, res := range found[i] {
+			key := q.entity + "/" + res.ID
+			if j, ok := byID[key]; ok {
+				results[j].Matches = append(results[j].Matches, q.match)
+				continue
+			}
+			res.Matches = []string{q.match}
+			byID[key] = len(results)
+			results = append(results, res)
+		}
+	}
+
+	return results, failures
+}
+
+func (us *uiService) searchEntity(token string, q searchQuery) ([]SearchResult, error) {
+	var results []SearchResult
+	add := func(id, name string) {
+		res := SearchResult{Entity: q.entity, ID: id, Name: name}
+		if q.entity != DomainsEntity {
+			res.URL = fmt.Sprintf("%s/%s/%s", us.prefix, q.entity, id)
+		}
+		results = append(results, res)
+	}
+
+	switch q.entity {
+	case UsersEntity:
+		page, err := us.sdk.Users(q.pm, token)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range page.Users {
+			add(u.ID, u.Name)
+		}
+	case ThingsEntity:
+		page, err := us.sdk.Things(q.pm, token)
+		if err != nil {
+			return nil, err
+		}
+		for _, th := range page.Things {
+			add(th.ID, th.Name)
+		}
+	case ChannelsEntity:
+		page, err := us.sdk.Channels(q.pm, token)
+		if err != nil {
+			return nil, err
+		}
+		for _, ch := range page.Channels {
+			add(ch.ID, ch.Name)
+		}
+	case GroupsEntity:
+		page, err := us.sdk.Groups(q.pm, token)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range page.Groups {
+			add(g.ID, g.Name)
+		}
+	case DomainsEntity:
+		page, err := us.sdk.Domains(q.pm, token)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range page.Domains {
+			add(d.ID, d.Name)
+		}
+	}
+
+	return results, nil
+}
+
+// findEntity looks the id up among the things, channels, groups and, for
+// administrators, users concurrently, and returns the page of the first
+// kind of entity that has it.
+func (us *uiService) findEntity(s Session, id string) string {
+	entities := []string{ThingsEntity, ChannelsEntity, GroupsEntity}
+	if s.User.Role == "admin" {
+		entities = append(entities, UsersEntity)
+	}
+	found := make([]bool, len(entities))
+
+	var wg sync.WaitGroup
+	for i, entity := range entities {
+		wg.Add(1)
+		go func(i int, entity string) {
+			defer wg.Done()
+			var err error
+			switch entity {
+			case ThingsEntity:
+				_, err = us.sdk.Thing(id, s.Token)
+			case ChannelsEntity:
+				_, err = us.sdk.Channel(id, s.Token)
+			case GroupsEntity:
+				_, err = us.sdk.Group(id, s.Token)
+			case UsersEntity:
+				_, err = us.sdk.User(id, s.Token)
+			}
+			found[i] = err == nil
+		}(i, entity)
+	}
+	wg.Wait()
+
+	for i, entity := range entities {
+		if found[i] {
+			return fmt.Sprintf("%s/%s/%s", us.prefix, entity, id)
+		}
+	}
+
+	return ""
+}
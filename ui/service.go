@@ -9,14 +9,15 @@ import (
 	"bytes"
 	"context"
 	"embed"
+	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"html/template"
-	"log"
+	"io"
 	"math"
 	"reflect"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/absmach/agent/pkg/bootstrap"
@@ -28,6 +29,7 @@ import (
 	mgsenml "github.com/absmach/senml"
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"golang.org/x/exp/slices"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
@@ -55,6 +57,14 @@ const (
 	membersActive           = "members"
 	invitationsActive       = "invitations"
 	domainInvitationsActive = "domaininvitations"
+	personalTokensActive    = "tokens"
+	twoFactorActive         = "two-factor"
+	searchActive            = "search"
+	topologyActive          = "topology"
+	hygieneActive           = "hygiene"
+	rotationsActive         = "rotations"
+	provisionActive         = "provision"
+	terminalAuditExportSize = 100
 )
 
 type LoginStatus string
@@ -106,10 +116,12 @@ type Domain struct {
 }
 
 type Session struct {
-	User        User        `json:"user"`
-	Domain      Domain      `json:"domain"`
-	LoginStatus LoginStatus `json:"login_status"`
-	Token       string      `json:"token"`
+	User         User        `json:"user"`
+	Domain       Domain      `json:"domain"`
+	LoginStatus  LoginStatus `json:"login_status"`
+	Token        string      `json:"token"`
+	LoginAt      time.Time   `json:"login_at"`
+	LastActivity time.Time   `json:"last_activity"`
 }
 
 var (
@@ -157,6 +169,41 @@ var (
 	ErrFailedDashboardUpdate   = errors.New("failed to update dashboard")
 	ErrFailedDashboardDelete   = errors.New("failed to delete dashboard")
 
+	ErrPersonalToken        = errors.New("invalid personal access token")
+	ErrPersonalTokenScope   = errors.New("personal access token lacks the required scope")
+	ErrFailedPersonalToken  = errors.New("failed to create personal access token")
+	ErrFailedRevokeToken    = errors.New("failed to revoke personal access token")
+	ErrPersonalTokenExpired = errors.New("personal access token has expired")
+
+	ErrTwoFactorCode      = errors.New("invalid two-factor authentication code")
+	ErrTwoFactorEnrolment = errors.New("no pending two-factor authentication enrolment")
+	ErrTwoFactorEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorRequired  = errors.New("the domain requires two-factor authentication, please enable it to continue")
+	ErrTwoFactorAdmin     = errors.New("only domain administrators can change the two-factor authentication requirement")
+	ErrFailedTwoFactor    = errors.New("failed to update two-factor authentication")
+
+	ErrTerminalClosed      = errors.New("terminal is closed")
+	ErrTerminalCanceled    = errors.New("terminal command canceled")
+	ErrTerminalTimeout     = errors.New("timed out waiting for the agent broker")
+	ErrTerminalNoChannel   = errors.New("bootstrap config has no channels")
+	ErrTerminalChannel     = errors.New("channel is not connected to the bootstrap config")
+	ErrTerminalBroker      = errors.New("failed to reach the agent broker")
+	ErrFailedAudit         = errors.New("failed to record terminal command")
+	ErrTooManyTargets      = errors.New("too many batch command targets")
+	ErrTerminalForbidden   = errors.New("terminal command is not permitted by the domain policy")
+	ErrTerminalPolicyAdmin = errors.New("only domain administrators can change the terminal policy")
+	ErrBootstrapTemplate   = errors.New("invalid bootstrap template")
+	ErrTooManyRows         = errors.New("too many rows")
+	ErrInvalidCert         = errors.New("invalid certificate")
+	ErrInvalidContent      = errors.New("invalid bootstrap content")
+	ErrInvalidBundle       = errors.New("invalid provisioning bundle")
+	ErrTooManyEntities     = errors.New("too many entities")
+	ErrInvalidMove         = errors.New("a group cannot be moved below itself or its subgroups")
+	ErrFailedRotation      = errors.New("failed to rotate thing secrets")
+	ErrSecretsTaken        = errors.New("the secrets were already downloaded or are not available")
+	ErrFailedProvision     = errors.New("failed to provision thing")
+	ErrProvisionRollback   = errors.New("failed to provision thing and to remove the created entities")
+
 	domainRelations  = []string{"administrator", "editor", "viewer", "member"}
 	groupRelations   = []string{"administrator", "editor", "viewer"}
 	statusOptions    = []string{"all", "enabled", "disabled"}
@@ -195,6 +242,8 @@ type Service interface {
 	DomainLogin(login sdk.Login, refreshToken string) (sdk.Token, error)
 	// Session retrieves the details of the user's session.
 	Session(s Session) (Session, error)
+	// SessionExpired displays the session expired page.
+	SessionExpired() ([]byte, error)
 
 	// CreateUsers creates new users.
 	CreateUsers(token string, users ...sdk.User) error
@@ -311,11 +360,16 @@ type Service interface {
 	CreateBootstrap(token string, config ...sdk.BootstrapConfig) error
 	// ListBootstrap retrieves all bootstrap configs.
 	ListBootstrap(s Session, page, limit uint64) ([]byte, error)
-	// UpdateBootstrap allows update of bootstrap name and content.
+	// UpdateBootstrap allows update of bootstrap name and content. The content
+	// must match the agent config.
 	UpdateBootstrap(token string, config sdk.BootstrapConfig) error
+	// BootstrapContentDiff displays the current and proposed content of a
+	// bootstrap config side by side, with the problems of the proposed content.
+	BootstrapContentDiff(s Session, thingID, content string) ([]byte, error)
 	// UpdateBootstrapConnections updates connected channels on bootstrap configs.
 	UpdateBootstrapConnections(token string, config sdk.BootstrapConfig) error
-	// UpdateBootstrapCerts updates bootstrap certs.
+	// UpdateBootstrapCerts updates bootstrap certs once they are checked to be
+	// PEM encoded.
 	UpdateBootstrapCerts(token string, config sdk.BootstrapConfig) error
 	// DeleteBootstrap deletes bootstrap config given an id.
 	DeleteBootstrap(token, thingID string) error
@@ -325,8 +379,120 @@ type Service interface {
 	ViewBootstrap(s Session, id string) ([]byte, error)
 	// GetRemoteTerminal returns remote terminal for a bootstrap config with magistrala agent installed.
 	GetRemoteTerminal(s Session, thingID string) ([]byte, error)
-	// ProcessTerminalCommand sends mqtt command to agent and retrieves a response asynchronously.
-	ProcessTerminalCommand(ctx context.Context, thingID, token, command string, res chan string) error
+	// OpenTerminal connects to the MQTT broker of the agent of a bootstrapped thing and
+	// subscribes to its responses. The terminal must be closed once it is no longer used.
+	OpenTerminal(ctx context.Context, s Session, thingID, channelID string) (*Terminal, error)
+	// ProcessTerminalCommand sends an exec command to the agent over an open terminal and
+	// waits for its response until the context is canceled.
+	ProcessTerminalCommand(ctx context.Context, s Session, term *Terminal, command string) (string, error)
+	// TerminalAudit displays the terminal commands run in the domain matching the filters.
+	TerminalAudit(ctx context.Context, s Session, page, limit uint64, pm TerminalCommandPageMeta) ([]byte, error)
+	// ExportTerminalAudit returns the terminal commands run in the domain matching the filters as CSV.
+	ExportTerminalAudit(ctx context.Context, s Session, pm TerminalCommandPageMeta) ([]byte, error)
+	// UpdateTerminalPolicy sets the terminal policy of a domain. Only domain
+	// administrators can change it.
+	UpdateTerminalPolicy(ctx context.Context, s Session, policy TerminalPolicy) error
+	// BatchTerminal displays the form to send a terminal command to many agents.
+	BatchTerminal(s Session) ([]byte, error)
+	// RunBatchCommand sends a terminal command to the agents of the targeted
+	// bootstrap configs and displays the result of every agent.
+	RunBatchCommand(ctx context.Context, s Session, batch BatchCommand) ([]byte, error)
+	// BootstrapTemplates displays the bootstrap templates of the domain.
+	BootstrapTemplates(ctx context.Context, s Session, page, limit uint64) ([]byte, error)
+	// CreateBootstrapTemplate creates a new bootstrap template in the domain.
+	CreateBootstrapTemplate(ctx context.Context, s Session, tpl BootstrapTemplate) error
+	// UpdateBootstrapTemplate updates the name, description, content and
+	// channels of a bootstrap template.
+	UpdateBootstrapTemplate(ctx context.Context, s Session, tpl BootstrapTemplate) error
+	// DeleteBootstrapTemplate deletes a bootstrap template.
+	DeleteBootstrapTemplate(ctx context.Context, s Session, id string) error
+	// ProvisionBootstraps renders a bootstrap template into a bootstrap config for
+	// every row and creates it, then displays the result of every row.
+	ProvisionBootstraps(ctx context.Context, s Session, templateID string, rows []BootstrapRow) ([]byte, error)
+	// ExpiringCertificates displays the certificates of the bootstrap configs
+	// of the domain that expire within the window or have already expired.
+	ExpiringCertificates(ctx context.Context, s Session, within time.Duration) ([]byte, error)
+	// BootstrapBundle displays the forms to export bootstrap configs as a
+	// provisioning bundle and to import one.
+	BootstrapBundle(s Session) ([]byte, error)
+	// ExportBundle returns the bootstrap configs of the things, with their
+	// things and channels, as a JSON or YAML provisioning bundle.
+	ExportBundle(ctx context.Context, s Session, export BundleExport) ([]byte, error)
+	// ImportBundle recreates the channels, things and bootstrap configs of a
+	// provisioning bundle, or only checks them in a dry run, then displays the
+	// result of every entity.
+	ImportBundle(ctx context.Context, s Session, bundle Bundle, opts BundleImport) ([]byte, error)
+	// BootstrapStates displays the bootstrap configs of the domain matching
+	// the filter together with the number of configs in each state.
+	BootstrapStates(s Session, filter BootstrapStateFilter) ([]byte, error)
+	// UpdateBootstrapStates activates or deactivates the bootstrap configs of
+	// the things, then displays the result of every config.
+	UpdateBootstrapStates(ctx context.Context, s Session, change BootstrapStateChange) ([]byte, error)
+	// ExportEntities writes the users, things, channels or groups matching
+	// the status as CSV, in the layout read by their bulk import, one page
+	// at a time.
+	ExportEntities(ctx context.Context, s Session, export EntityExport, w io.Writer) error
+	// ImportEntities creates the users, things, channels or groups of the rows
+	// of a CSV file, or only checks the rows in a dry run, then displays the
+	// result of every row.
+	ImportEntities(ctx context.Context, s Session, imp EntityImport) ([]byte, error)
+	// BulkConnect connects the things to the channels of the rows, given by
+	// id or name, then displays the result of every connection.
+	BulkConnect(ctx context.Context, s Session, rows []ConnectionRow) ([]byte, error)
+	// BulkAction enables, disables, tags or untags the users, things,
+	// channels or groups of the action, then displays the outcome for every
+	// entity.
+	BulkAction(ctx context.Context, s Session, action EntityBulkAction) ([]byte, error)
+	// Search finds the users, things, channels, groups and domains matching
+	// the query by name, tag or metadata.
+	Search(ctx context.Context, s Session, query string) ([]byte, error)
+	// FindEntity returns the page of the thing, channel, group or user whose
+	// id is the query, or an empty URL when the query is not such an id.
+	FindEntity(ctx context.Context, s Session, query string) (string, error)
+	// Topology displays the graph of the things, channels and groups of the
+	// domain.
+	Topology(ctx context.Context, s Session, filter TopologyFilter) ([]byte, error)
+	// TopologyGraph returns the nodes and edges of the graph of the things,
+	// channels and groups of the domain as JSON.
+	TopologyGraph(ctx context.Context, s Session, filter TopologyFilter) ([]byte, error)
+	// GroupTree displays a page of the root groups of the domain as a tree,
+	// or, given a parent, the groups directly below it, with their subgroup
+	// and member counts.
+	GroupTree(ctx context.Context, s Session, parentID string, page, limit uint64) ([]byte, error)
+	// MoveGroup moves the group below the parent group, or to the root of the
+	// tree when the parent is empty.
+	MoveGroup(ctx context.Context, s Session, groupID, parentID string) error
+	// HygieneReport displays the things connected to no channel, the
+	// channels with no things, the disabled things that are still connected
+	// and the empty groups of the domain.
+	HygieneReport(ctx context.Context, s Session) ([]byte, error)
+	// HygieneCleanup applies a cleanup action to entities of the hygiene
+	// report and displays the outcome for each of them.
+	HygieneCleanup(ctx context.Context, s Session, cleanup HygieneCleanup) ([]byte, error)
+	// SecretRotations displays the rotation schedules and the history of the
+	// secret rotations of the domain.
+	SecretRotations(ctx context.Context, s Session, page, limit uint64) ([]byte, error)
+	// RotateThingSecrets gives the things new generated secrets and displays
+	// them once.
+	RotateThingSecrets(ctx context.Context, s Session, thingIDs []string) ([]byte, error)
+	// CreateRotationSchedule schedules the periodic rotation of the secrets
+	// of the things with a tag. The password of the user is required to act
+	// on their behalf when the schedule runs.
+	CreateRotationSchedule(ctx context.Context, s Session, password string, schedule RotationSchedule) error
+	// DeleteRotationSchedule removes a rotation schedule.
+	DeleteRotationSchedule(ctx context.Context, s Session, id string) error
+	// DownloadRotatedSecrets returns the secrets of a scheduled rotation run
+	// as CSV, once.
+	DownloadRotatedSecrets(ctx context.Context, s Session, runID string) ([]byte, error)
+	// RunRotationSchedules runs the rotation schedules that are due, and
+	// refreshes the refresh tokens of the schedules older than tokenMaxAge.
+	RunRotationSchedules(ctx context.Context, now time.Time, tokenMaxAge time.Duration) error
+	// ProvisionWizard displays the form to onboard a thing in one step.
+	ProvisionWizard(s Session) ([]byte, error)
+	// ProvisionThing creates a thing with its channels, connections,
+	// bootstrap config and dashboard, removing what was created when a step
+	// fails, then displays the thing with its credentials.
+	ProvisionThing(ctx context.Context, s Session, p ThingProvision) ([]byte, error)
 
 	// GetEntities retrieves all entities.
 	GetEntities(token, entity, entityName, domainID, permission string, page, limit uint64) ([]byte, error)
@@ -340,7 +506,7 @@ type Service interface {
 	// UpdateDomain updates the domain with the given ID.
 	UpdateDomain(token string, domain sdk.Domain) error
 	// Domain displays the domain page.
-	Domain(s Session) ([]byte, error)
+	Domain(ctx context.Context, s Session) ([]byte, error)
 	// EnableDomain updates the status of the domain to enabled.
 	EnableDomain(token, id string) error
 	// DisableDomain updates the status of the domain to disabled.
@@ -375,21 +541,64 @@ type Service interface {
 	UpdateDashboard(ctx context.Context, token, dashboardID string, dashboardReq DashboardReq) error
 	// Delete a dashboard for a user.
 	DeleteDashboard(ctx context.Context, token, dashboardID string) error
+
+	// PersonalTokens displays the personal access tokens page.
+	PersonalTokens(ctx context.Context, s Session, page, limit uint64) ([]byte, error)
+	// CreatePersonalToken creates a personal access token after confirming the user's password.
+	// The token value is returned only once and is never stored in plain text.
+	CreatePersonalToken(ctx context.Context, s Session, password string, ptReq PersonalTokenReq) ([]byte, error)
+	// RevokePersonalToken revokes a personal access token of the user.
+	RevokePersonalToken(ctx context.Context, s Session, id string) error
+	// AuthenticatePersonalToken resolves a personal access token with the given scope into a session.
+	AuthenticatePersonalToken(ctx context.Context, token string, scope TokenScope) (Session, error)
+	// RefreshPersonalTokens renews the refresh tokens of the personal access
+	// tokens older than tokenMaxAge.
+	RefreshPersonalTokens(ctx context.Context, now time.Time, tokenMaxAge time.Duration) error
+
+	// TwoFactor displays the two-factor authentication settings page.
+	TwoFactor(ctx context.Context, s Session) ([]byte, error)
+	// EnrollTwoFactor generates a new TOTP secret for the user, pending confirmation.
+	EnrollTwoFactor(ctx context.Context, s Session) error
+	// ConfirmTwoFactor enables two-factor authentication once a code generated from the
+	// pending secret is provided. The recovery codes are returned only once.
+	ConfirmTwoFactor(ctx context.Context, s Session, code string) ([]byte, error)
+	// DisableTwoFactor disables two-factor authentication after validating a code or a recovery code.
+	DisableTwoFactor(ctx context.Context, s Session, code string) error
+	// TwoFactorEnabled reports whether the user has two-factor authentication enabled.
+	TwoFactorEnabled(ctx context.Context, userID string) (bool, error)
+	// TwoFactorLogin displays the second step of the login.
+	TwoFactorLogin() ([]byte, error)
+	// VerifyTwoFactor validates a TOTP code of the user, or consumes one of their recovery codes.
+	VerifyTwoFactor(ctx context.Context, userID, code string) error
+	// UpdateDomainTwoFactor sets whether members of a domain must use two-factor authentication.
+	UpdateDomainTwoFactor(ctx context.Context, s Session, domainID string, required bool) error
+	// CheckDomainTwoFactor returns an error if the domain requires two-factor authentication
+	// and the user has not enabled it.
+	CheckDomainTwoFactor(ctx context.Context, domainID, userID string) error
 }
 
 var _ Service = (*uiService)(nil)
 
 type uiService struct {
-	sdk        sdk.SDK
+	sdk        SDK
 	tpls       *template.Template
 	drepo      DashboardRepository
+	trepo      PersonalTokenRepository
+	patTokens  *accessTokenCache
+	patRefresh singleflight.Group
+	tfrepo     TwoFactorRepository
+	arepo      TerminalAuditRepository
+	prepo      TerminalPolicyRepository
+	btrepo     BootstrapTemplateRepository
+	rrepo      SecretRotationRepository
+	encKey     []byte
 	idProvider magistrala.IDProvider
 	providers  []oauth2.Provider
 	prefix     string
 }
 
 // New instantiates the HTTP adapter implementation.
-func New(sdk sdk.SDK, db DashboardRepository, idp magistrala.IDProvider, prefix string, providers ...oauth2.Provider) (Service, error) {
+func New(sdk SDK, db DashboardRepository, tokens PersonalTokenRepository, twoFactor TwoFactorRepository, audit TerminalAuditRepository, policies TerminalPolicyRepository, templates BootstrapTemplateRepository, rotations SecretRotationRepository, encKey []byte, idp magistrala.IDProvider, prefix string, providers ...oauth2.Provider) (Service, error) {
 	tpl, err := parseTemplates(sdk, prefix)
 	if err != nil {
 		return nil, errors.Wrap(ErrParseTemplate, err)
@@ -398,6 +607,14 @@ func New(sdk sdk.SDK, db DashboardRepository, idp magistrala.IDProvider, prefix
 		sdk:        sdk,
 		tpls:       tpl,
 		drepo:      db,
+		trepo:      tokens,
+		patTokens:  newAccessTokenCache(),
+		tfrepo:     twoFactor,
+		arepo:      audit,
+		prepo:      policies,
+		btrepo:     templates,
+		rrepo:      rotations,
+		encKey:     encKey,
 		idProvider: idp,
 		providers:  providers,
 		prefix:     prefix,
@@ -625,7 +842,9 @@ func (us *uiService) Session(s Session) (Session, error) {
 			Identity: user.Credentials.Identity,
 			Role:     user.Role,
 		},
-		LoginStatus: s.LoginStatus,
+		LoginStatus:  s.LoginStatus,
+		LoginAt:      s.LoginAt,
+		LastActivity: s.LastActivity,
 	}
 
 	if s.LoginStatus == DomainLoginStatus {
@@ -645,6 +864,15 @@ func (us *uiService) Session(s Session) (Session, error) {
 	return session, nil
 }
 
+func (us *uiService) SessionExpired() ([]byte, error) {
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "sessionExpired", nil); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
 func (us *uiService) CreateUsers(token string, users ...sdk.User) error {
 	for i := range users {
 		_, err := us.sdk.CreateUser(users[i], token)
@@ -1805,6 +2033,11 @@ func (us *uiService) Publish(channelID, thingKey string, message Message) error
 }
 
 func (us *uiService) CreateBootstrap(token string, configs ...sdk.BootstrapConfig) error {
+	for _, cfg := range configs {
+		if report := ValidateBootstrapContent(cfg.Content); !report.Valid() {
+			return errors.Wrap(ErrInvalidContent, errors.New(report.String()))
+		}
+	}
 	for _, cfg := range configs {
 		if _, err := us.sdk.AddBootstrap(cfg, token); err != nil {
 			return errors.Wrap(ErrFailedCreate, err)
@@ -1875,6 +2108,9 @@ func (us *uiService) ListBootstrap(s Session, page, limit uint64) ([]byte, error
 }
 
 func (us *uiService) UpdateBootstrap(token string, config sdk.BootstrapConfig) error {
+	if report := ValidateBootstrapContent(config.Content); !report.Valid() {
+		return errors.Wrap(ErrInvalidContent, errors.New(report.String()))
+	}
 	if err := us.sdk.UpdateBootstrap(config, token); err != nil {
 		return errors.Wrap(ErrFailedUpdate, err)
 	}
@@ -1882,6 +2118,46 @@ func (us *uiService) UpdateBootstrap(token string, config sdk.BootstrapConfig) e
 	return nil
 }
 
+func (us *uiService) BootstrapContentDiff(s Session, thingID, content string) ([]byte, error) {
+	bootstrap, err := us.sdk.ViewBootstrap(thingID, s.Token)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	crumbs := []breadcrumb{
+		{Name: bootstrapsActive, URL: fmt.Sprintf("%s/%s", us.prefix, bootstrapsActive)},
+		{Name: thingID, URL: fmt.Sprintf("%s/%s/%s", us.prefix, bootstrapsActive, thingID)},
+		{Name: "Content Changes"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Bootstrap      sdk.BootstrapConfig
+		Content        string
+		Report         ContentReport
+		Diff           []DiffLine
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		bootstrapsActive,
+		bootstrapsActive,
+		bootstrap,
+		content,
+		ValidateBootstrapContent(content),
+		DiffBootstrapContent(bootstrap.Content, content),
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "bootstrapContentDiff", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
 func (us *uiService) UpdateBootstrapConnections(token string, config sdk.BootstrapConfig) error {
 	channels, ok := config.Channels.([]string)
 	if !ok {
@@ -1895,6 +2171,10 @@ func (us *uiService) UpdateBootstrapConnections(token string, config sdk.Bootstr
 }
 
 func (us *uiService) UpdateBootstrapCerts(token string, config sdk.BootstrapConfig) error {
+	if err := ValidateCerts(config); err != nil {
+		return err
+	}
+
 	if _, err := us.sdk.UpdateBootstrapCerts(config.ThingID, config.ClientCert, config.ClientKey, config.CACert, token); err != nil {
 		return errors.Wrap(ErrFailedUpdate, err)
 	}
@@ -1953,6 +2233,7 @@ func (us *uiService) ViewBootstrap(s Session, thingID string) ([]byte, error) {
 		NavbarActive   string
 		CollapseActive string
 		Bootstrap      sdk.BootstrapConfig
+		Certs          BootstrapCerts
 		Thing          sdk.Thing
 		Breadcrumbs    []breadcrumb
 		Session        Session
@@ -1960,6 +2241,7 @@ func (us *uiService) ViewBootstrap(s Session, thingID string) ([]byte, error) {
 		bootstrapsActive,
 		bootstrapsActive,
 		bootstrap,
+		InspectBootstrapCerts(bootstrap, time.Now()),
 		thing,
 		crumbs,
 		s,
@@ -1980,16 +2262,35 @@ func (us *uiService) GetRemoteTerminal(s Session, thingID string) ([]byte, error
 		{Name: "Remote Terminal"},
 	}
 
+	cfg, err := us.sdk.ViewBootstrap(thingID, s.Token)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	var content bootstrap.ServicesConfig
+	if cfg.Content != "" {
+		if err := json.Unmarshal([]byte(cfg.Content), &content); err != nil {
+			return []byte{}, errors.Wrap(ErrJSONUnmarshal, err)
+		}
+	}
+	// A config without channels is still rendered, the terminal reports the
+	// error once it is opened.
+	control, _ := controlChannel(cfg, content, "")
+
 	data := struct {
 		NavbarActive   string
 		CollapseActive string
 		ThingID        string
+		Channels       []sdk.Channel
+		ControlChannel string
 		Breadcrumbs    []breadcrumb
 		Session        Session
 	}{
 		bootstrapsActive,
 		bootstrapsActive,
 		thingID,
+		bootstrapChannels(cfg),
+		control,
 		crumbs,
 		s,
 	}
@@ -2001,24 +2302,38 @@ func (us *uiService) GetRemoteTerminal(s Session, thingID string) ([]byte, error
 	return btpl.Bytes(), nil
 }
 
-func (us *uiService) ProcessTerminalCommand(ctx context.Context, thingID, tkn, command string, res chan string) error {
-	cfg, err := us.sdk.ViewBootstrap(thingID, tkn)
-	if err != nil {
-		return errors.Wrap(ErrFailedRetreive, err)
+func (us *uiService) OpenTerminal(ctx context.Context, s Session, thingID, channelID string) (term *Terminal, err error) {
+	policy, rerr := us.prepo.Retrieve(ctx, s.Domain.ID)
+	if rerr != nil {
+		return nil, errors.Wrap(ErrFailedRetreive, rerr)
+	}
+	if policy.AdminOnly && !isDomainAdmin(s) {
+		return nil, policy.Check(s, "")
+	}
+
+	cfg, sdkErr := us.sdk.ViewBootstrap(thingID, s.Token)
+	if sdkErr != nil {
+		return nil, errors.Wrap(ErrFailedRetreive, sdkErr)
 	}
 
 	var content bootstrap.ServicesConfig
-	if err := json.Unmarshal([]byte(cfg.Content), &content); err != nil {
-		return errors.Wrap(ErrJSONUnmarshal, err)
+	if cfg.Content != "" {
+		if err := json.Unmarshal([]byte(cfg.Content), &content); err != nil {
+			return nil, errors.Wrap(ErrJSONUnmarshal, err)
+		}
+	}
+	if content.Agent.MQTT.URL == "" {
+		return nil, errors.Wrap(ErrTerminalBroker, errors.New("missing agent broker url"))
 	}
 
-	channels, ok := cfg.Channels.([]sdk.Channel)
-	if !ok {
-		return errors.Wrap(ErrFailedRetreive, errors.New("invalid channels"))
+	channelID, err = controlChannel(cfg, content, channelID)
+	if err != nil {
+		return nil, err
 	}
 
-	pubTopic := fmt.Sprintf("channels/%s/messages/req", channels[0].ID)
-	subTopic := fmt.Sprintf("channels/%s/messages/res/#", channels[0].ID)
+	pubTopic := fmt.Sprintf("channels/%s/messages/req", channelID)
+	subTopic := fmt.Sprintf("channels/%s/messages/res/#", channelID)
+	term = newTerminal(cfg.ThingID, channelID, pubTopic, subTopic)
 
 	opts := mqtt.NewClientOptions().SetCleanSession(true).SetAutoReconnect(true)
 
@@ -2031,54 +2346,326 @@ func (us *uiService) ProcessTerminalCommand(ctx context.Context, thingID, tkn, c
 		opts.SetPassword(content.Agent.MQTT.Password)
 	}
 
-	opts.SetClientID(fmt.Sprintf("ui-terminal-%s", cfg.ThingID))
+	// Every open terminal has its own client, so the client id must be unique
+	// for the broker not to drop the other terminals of the same thing.
+	clientID, err := us.idProvider.ID()
+	if err != nil {
+		return nil, err
+	}
+	opts.SetClientID(fmt.Sprintf("ui-terminal-%s-%s", cfg.ThingID, clientID))
+	// Subscriptions are not kept across reconnects of a clean session.
+	opts.SetOnConnectHandler(func(c mqtt.Client) {
+		c.Subscribe(subTopic, 0, term.handle)
+	})
+	if deadline, ok := ctx.Deadline(); ok {
+		opts.SetConnectTimeout(time.Until(deadline))
+	}
+
 	client := mqtt.NewClient(opts)
+	// The client is torn down on every error, so that no connection or
+	// reconnect loop outlives a terminal that failed to open.
+	defer func() {
+		if err != nil {
+			client.Disconnect(terminalDisconnectQuiesce)
+		}
+	}()
+
 	if token := client.Connect(); token.Wait() && token.Error() != nil {
-		return errors.Wrap(ErrFailedConnect, token.Error())
+		return nil, errors.Wrap(ErrTerminalBroker, token.Error())
 	}
 
-	req := []mgsenml.Record{
-		{BaseName: "1", Name: "exec", StringValue: &command},
+	// The subscription made by the connect handler is not awaited, subscribe
+	// again to make sure no response is missed once the terminal is returned.
+	token := client.Subscribe(subTopic, 0, term.handle)
+	if !token.WaitTimeout(terminalBrokerWait) {
+		return nil, errors.Wrap(ErrTerminalBroker, ErrTerminalTimeout)
 	}
-	reqByte, err1 := json.Marshal(req)
-	if err1 != nil {
-		return errors.Wrap(ErrJSONMarshal, err1)
+	if err := token.Error(); err != nil {
+		return nil, errors.Wrap(ErrTerminalBroker, err)
 	}
+	term.client = client
 
-	token := client.Publish(pubTopic, 0, false, string(reqByte))
-	token.Wait()
+	return term, nil
+}
 
-	if token.Error() != nil {
-		return errors.Wrap(ErrFailedPublish, token.Error())
+func (us *uiService) ProcessTerminalCommand(ctx context.Context, s Session, term *Terminal, command string) (string, error) {
+	id, err := us.idProvider.ID()
+	if err != nil {
+		return "", err
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(1)
-	errChan := make(chan error)
+	policy, err := us.prepo.Retrieve(ctx, s.Domain.ID)
+	if err != nil {
+		return "", errors.Wrap(ErrFailedRetreive, err)
+	}
 
-	client.Subscribe(subTopic, 0, func(_ mqtt.Client, m mqtt.Message) {
-		var data []mgsenml.Record
-		if err := json.Unmarshal(m.Payload(), &data); err != nil {
-			errChan <- errors.Wrap(ErrJSONUnmarshal, err)
-		}
-		res <- *data[0].StringValue
-		wg.Done()
-	})
+	// The command is recorded before it is sent so that no command reaches
+	// the agent without an audit record. Refused commands are recorded too,
+	// so that attempts to get around the policy show up in the audit log.
+	record := TerminalCommand{
+		ID:           id,
+		UserID:       s.User.ID,
+		UserIdentity: s.User.Identity,
+		DomainID:     s.Domain.ID,
+		ThingID:      term.ThingID,
+		Command:      command,
+		CreatedAt:    time.Now().UTC(),
+	}
+	perr := policy.Check(s, command)
+	if perr != nil {
+		record.Error = perr.Error()
+	}
+	if err := us.arepo.Save(ctx, record); err != nil {
+		return "", errors.Wrap(ErrFailedAudit, err)
+	}
+	if perr != nil {
+		return "", perr
+	}
+
+	res, err := us.sendTerminalCommand(ctx, term, id, command)
+	record.Response = res
+	record.Duration = time.Since(record.CreatedAt)
+	if err != nil {
+		record.Error = err.Error()
+	}
+	// The outcome is recorded even when the command was canceled.
+	if uerr := us.arepo.Update(context.WithoutCancel(ctx), record); uerr != nil && err == nil {
+		err = errors.Wrap(ErrFailedAudit, uerr)
+	}
+
+	return res, err
+}
+
+func (us *uiService) sendTerminalCommand(ctx context.Context, term *Terminal, id, command string) (string, error) {
+	res, err := term.register(id)
+	if err != nil {
+		return "", err
+	}
+	defer term.unregister(id)
+
+	req := []mgsenml.Record{
+		{BaseName: id, Name: "exec", StringValue: &command},
+	}
+	reqByte, err := json.Marshal(req)
+	if err != nil {
+		return "", errors.Wrap(ErrJSONMarshal, err)
+	}
+
+	token := term.client.Publish(term.pubTopic, 0, false, string(reqByte))
+	if !token.WaitTimeout(terminalBrokerWait) {
+		return "", errors.Wrap(ErrTerminalBroker, ErrTerminalTimeout)
+	}
+	if err := token.Error(); err != nil {
+		return "", errors.Wrap(ErrTerminalBroker, err)
+	}
 
 	select {
+	case result := <-res:
+		return result, nil
 	case <-ctx.Done():
-		log.Println("ProcessTerminalCommand canceled")
-	case <-time.After(time.Second * 5):
-		log.Println("Timeout occurred")
-		res <- "timeout"
-	case err := <-errChan:
-		return err
-	case <-res:
-		wg.Wait()
+		return "", errors.Wrap(ErrTerminalCanceled, ctx.Err())
+	}
+}
+
+func (us *uiService) TerminalAudit(ctx context.Context, s Session, page, limit uint64, pm TerminalCommandPageMeta) ([]byte, error) {
+	pm = auditPageMeta(s, pm)
+	pm.Offset = (page - 1) * limit
+	pm.Limit = limit
+
+	commandsPage, err := us.arepo.RetrieveAll(ctx, pm)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
 	}
 
-	client.Disconnect(250)
-	return nil
+	noOfPages := int(math.Ceil(float64(commandsPage.Total) / float64(limit)))
+
+	crumbs := []breadcrumb{
+		{Name: bootstrapsActive, URL: fmt.Sprintf("%s/%s", us.prefix, bootstrapsActive)},
+		{Name: "Terminal Audit"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Commands       []TerminalCommand
+		Filter         TerminalCommandPageMeta
+		CurrentPage    int
+		Pages          int
+		Limit          int
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		bootstrapsActive,
+		bootstrapsActive,
+		commandsPage.Commands,
+		pm,
+		int(page),
+		noOfPages,
+		int(limit),
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "terminalAudit", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) ExportTerminalAudit(ctx context.Context, s Session, pm TerminalCommandPageMeta) ([]byte, error) {
+	pm = auditPageMeta(s, pm)
+	pm.Limit = terminalAuditExportSize
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"created_at", "user_id", "user_identity", "thing_id", "command", "response", "error", "duration"}); err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	for pm.Offset = 0; ; pm.Offset += pm.Limit {
+		commandsPage, err := us.arepo.RetrieveAll(ctx, pm)
+		if err != nil {
+			return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+		}
+		for _, cmd := range commandsPage.Commands {
+			record := []string{
+				cmd.CreatedAt.Format(time.RFC3339),
+				cmd.UserID,
+				cmd.UserIdentity,
+				cmd.ThingID,
+				cmd.Command,
+				cmd.Response,
+				cmd.Error,
+				cmd.Duration.String(),
+			}
+			if err := w.Write(record); err != nil {
+				return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+			}
+		}
+		if len(commandsPage.Commands) == 0 || pm.Offset+pm.Limit >= commandsPage.Total {
+			break
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (us *uiService) BatchTerminal(s Session) ([]byte, error) {
+	configs, err := us.bootstrapConfigs(s.Token)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	crumbs := []breadcrumb{
+		{Name: bootstrapsActive, URL: fmt.Sprintf("%s/%s", us.prefix, bootstrapsActive)},
+		{Name: "Batch Command"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Bootstraps     []sdk.BootstrapConfig
+		MaxTargets     int
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		bootstrapsActive,
+		bootstrapsActive,
+		configs,
+		MaxBatchTargets,
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "batchTerminal", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) RunBatchCommand(ctx context.Context, s Session, batch BatchCommand) ([]byte, error) {
+	targets, err := us.batchTargets(s, batch)
+	if err != nil {
+		return []byte{}, err
+	}
+	if len(targets) > MaxBatchTargets {
+		return []byte{}, ErrTooManyTargets
+	}
+
+	results := us.runBatch(ctx, s, targets, batch)
+
+	summary := make(map[string]int)
+	for _, res := range results {
+		summary[res.Status]++
+	}
+
+	export, err := batchResultsCSV(batch.Command, results)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	crumbs := []breadcrumb{
+		{Name: bootstrapsActive, URL: fmt.Sprintf("%s/%s", us.prefix, bootstrapsActive)},
+		{Name: "Batch Command", URL: fmt.Sprintf("%s/%s/terminal/batch", us.prefix, bootstrapsActive)},
+		{Name: "Results"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Command        string
+		Results        []BatchCommandResult
+		Summary        map[string]int
+		Export         template.URL
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		bootstrapsActive,
+		bootstrapsActive,
+		batch.Command,
+		results,
+		summary,
+		exportURL("text/csv", export),
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "batchTerminalResults", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+// auditPageMeta limits the audit log to the domain of the session. Members
+// who are not domain administrators only see their own commands.
+func auditPageMeta(s Session, pm TerminalCommandPageMeta) TerminalCommandPageMeta {
+	pm.DomainID = s.Domain.ID
+	if !isDomainAdmin(s) {
+		pm.UserID = s.User.ID
+	}
+
+	return pm
+}
+
+func isDomainAdmin(s Session) bool {
+	return slices.Contains(s.Domain.Permissions, "admin")
+}
+
+// exportURL embeds an export in the page that offers it for download, for
+// results that are not kept once the page is rendered.
+func exportURL(mediaType string, content []byte) template.URL {
+	return template.URL("data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(content))
 }
 
 func (us *uiService) GetEntities(token, entity, entityName, domainID, permission string, page, limit uint64) ([]byte, error) {
@@ -2222,7 +2809,7 @@ func (us *uiService) UpdateDomain(token string, domain sdk.Domain) error {
 	return nil
 }
 
-func (us *uiService) Domain(s Session) ([]byte, error) {
+func (us *uiService) Domain(ctx context.Context, s Session) ([]byte, error) {
 	domain, err := us.sdk.Domain(s.Domain.ID, s.Token)
 	if err != nil {
 		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
@@ -2233,24 +2820,40 @@ func (us *uiService) Domain(s Session) ([]byte, error) {
 		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
 	}
 
+	twoFactorRequired, rerr := us.tfrepo.RetrieveDomainRequirement(ctx, s.Domain.ID)
+	if rerr != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, rerr)
+	}
+
+	policy, rerr := us.prepo.Retrieve(ctx, s.Domain.ID)
+	if rerr != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, rerr)
+	}
+
 	crumbs := []breadcrumb{
 		{Name: domain.Name},
 	}
 
 	data := struct {
-		NavbarActive   string
-		CollapseActive string
-		Entity         sdk.Domain
-		Breadcrumbs    []breadcrumb
-		Permissions    []string
-		Path           string
-		Session        Session
+		NavbarActive       string
+		CollapseActive     string
+		Entity             sdk.Domain
+		Breadcrumbs        []breadcrumb
+		Permissions        []string
+		TwoFactorRequired  bool
+		TerminalPolicy     TerminalPolicy
+		DiagnosticCommands []string
+		Path               string
+		Session            Session
 	}{
 		domainActive,
 		domainActive,
 		domain,
 		crumbs,
 		permissions.Permissions,
+		twoFactorRequired,
+		policy,
+		DiagnosticCommands,
 		domainsActive,
 		s,
 	}
@@ -2637,26 +3240,249 @@ func (us *uiService) DeleteDashboard(ctx context.Context, token, dashboardID str
 	return nil
 }
 
-func parseTemplates(mfsdk sdk.SDK, prefix string) (tpl *template.Template, err error) {
-	tpl = template.New("magistrala")
-	tpl = tpl.Funcs(template.FuncMap{
-		"toJSON": func(data map[string]interface{}) string {
-			if data == nil {
-				return "{}"
-			}
-			ret, err := json.Marshal(data)
-			if err != nil {
-				return "{}"
-			}
-			return string(ret)
-		},
-		"toSlice": func(data []string) string {
-			if len(data) == 0 {
-				return "[]"
-			}
-			ret, err := json.Marshal(data)
-			if err != nil {
-				return "[]"
+func (us *uiService) PersonalTokens(ctx context.Context, s Session, page, limit uint64) ([]byte, error) {
+	offset := (page - 1) * limit
+
+	pgm := PersonalTokenPageMeta{
+		Offset: offset,
+		Limit:  limit,
+		UserID: s.User.ID,
+	}
+	tokensPage, err := us.trepo.RetrieveAll(ctx, pgm)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	noOfPages := int(math.Ceil(float64(tokensPage.Total) / float64(limit)))
+
+	crumbs := []breadcrumb{
+		{Name: personalTokensActive},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Tokens         []PersonalToken
+		Scopes         []TokenScope
+		CurrentPage    int
+		Pages          int
+		Limit          int
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		personalTokensActive,
+		personalTokensActive,
+		tokensPage.Tokens,
+		TokenScopes,
+		int(page),
+		noOfPages,
+		int(limit),
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "personalTokens", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) CreatePersonalToken(ctx context.Context, s Session, password string, ptReq PersonalTokenReq) ([]byte, error) {
+	// The personal access token is backed by an upstream refresh token so
+	// that requests made with it can act on behalf of the user.
+	login := sdk.Login{
+		Identity: s.User.Identity,
+		Secret:   password,
+		DomainID: s.Domain.ID,
+	}
+	upstream, sdkerr := us.sdk.CreateToken(login)
+	if sdkerr != nil {
+		return []byte{}, errors.Wrap(ErrToken, sdkerr)
+	}
+
+	refreshToken, err := encrypt(us.encKey, upstream.RefreshToken)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedPersonalToken, err)
+	}
+
+	secret, err := randomToken(32)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedPersonalToken, err)
+	}
+	value := PersonalTokenPrefix + secret
+
+	id, err := us.idProvider.ID()
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedGenerateID, err)
+	}
+
+	now := time.Now()
+	pt := PersonalToken{
+		ID:           id,
+		UserID:       s.User.ID,
+		DomainID:     s.Domain.ID,
+		Name:         ptReq.Name,
+		Hash:         hashToken(value),
+		Scopes:       ptReq.Scopes,
+		RefreshToken: refreshToken,
+		ExpiresAt:    ptReq.ExpiresAt,
+		CreatedAt:    now,
+		RefreshedAt:  now,
+	}
+	pt, err = us.trepo.Create(ctx, pt)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedPersonalToken, err)
+	}
+
+	item := make(map[string]interface{})
+	item["token"] = value
+	item["personal_token"] = pt
+	data, err := json.Marshal(item)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrJSONMarshal, err)
+	}
+
+	return data, nil
+}
+
+func (us *uiService) RevokePersonalToken(ctx context.Context, s Session, id string) error {
+	if err := us.trepo.Revoke(ctx, id, s.User.ID); err != nil {
+		return errors.Wrap(ErrFailedRevokeToken, err)
+	}
+	us.patTokens.remove(id)
+
+	return nil
+}
+
+func (us *uiService) AuthenticatePersonalToken(ctx context.Context, token string, scope TokenScope) (Session, error) {
+	if !strings.HasPrefix(token, PersonalTokenPrefix) {
+		return Session{}, ErrPersonalToken
+	}
+
+	pt, err := us.trepo.RetrieveByHash(ctx, hashToken(token))
+	if err != nil {
+		return Session{}, errors.Wrap(ErrPersonalToken, err)
+	}
+	if pt.Revoked {
+		return Session{}, ErrPersonalToken
+	}
+	if pt.Expired() {
+		return Session{}, ErrPersonalTokenExpired
+	}
+	if !pt.HasScope(scope) {
+		return Session{}, ErrPersonalTokenScope
+	}
+
+	accessToken, err := us.personalAccessToken(ctx, pt)
+	if err != nil {
+		return Session{}, err
+	}
+	if err := us.trepo.UpdateLastUsed(ctx, pt.ID, time.Now()); err != nil {
+		return Session{}, errors.Wrap(ErrFailedUpdate, err)
+	}
+
+	loginStatus := UserLoginStatus
+	if pt.DomainID != "" {
+		loginStatus = DomainLoginStatus
+	}
+
+	return Session{
+		User:        User{ID: pt.UserID},
+		Domain:      Domain{ID: pt.DomainID},
+		LoginStatus: loginStatus,
+		Token:       accessToken,
+	}, nil
+}
+
+func (us *uiService) RefreshPersonalTokens(ctx context.Context, now time.Time, tokenMaxAge time.Duration) error {
+	tokens, err := us.trepo.RetrieveStale(ctx, now.UTC().Add(-tokenMaxAge))
+	if err != nil {
+		return errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	// A failing token does not stop the others.
+	var failure error
+	for _, pt := range tokens {
+		if _, err := us.refreshPersonalToken(ctx, pt); err != nil {
+			failure = errors.Wrap(err, fmt.Errorf("personal access token %s", pt.ID))
+		}
+	}
+
+	return failure
+}
+
+// personalAccessToken returns an upstream access token for the personal
+// access token. The access token is cached until it expires.
+func (us *uiService) personalAccessToken(ctx context.Context, pt PersonalToken) (string, error) {
+	if token, ok := us.patTokens.get(pt.ID); ok {
+		return token, nil
+	}
+
+	return us.refreshPersonalToken(ctx, pt)
+}
+
+// refreshPersonalToken renews the upstream tokens of the personal access
+// token and caches the new access token. Concurrent refreshes of the same
+// personal access token share one refresh.
+func (us *uiService) refreshPersonalToken(ctx context.Context, pt PersonalToken) (string, error) {
+	token, err, _ := us.patRefresh.Do(pt.ID, func() (interface{}, error) {
+		if token, ok := us.patTokens.get(pt.ID); ok {
+			return token, nil
+		}
+
+		refreshToken, err := decrypt(us.encKey, pt.RefreshToken)
+		if err != nil {
+			return "", errors.Wrap(ErrPersonalToken, err)
+		}
+		upstream, sdkerr := us.sdk.RefreshToken(sdk.Login{DomainID: pt.DomainID}, refreshToken)
+		if sdkerr != nil {
+			return "", errors.Wrap(ErrTokenRefresh, sdkerr)
+		}
+
+		// Refresh tokens are rotated on use, so the latest one has to be
+		// kept for the next refresh. The stored token is only replaced if it
+		// is the one that was used. Otherwise another instance refreshed it
+		// first, and its refresh token is kept.
+		refreshToken, err = encrypt(us.encKey, upstream.RefreshToken)
+		if err != nil {
+			return "", errors.Wrap(ErrPersonalToken, err)
+		}
+		if _, err := us.trepo.UpdateRefreshToken(ctx, pt.ID, pt.RefreshToken, refreshToken, time.Now()); err != nil {
+			return "", errors.Wrap(ErrFailedUpdate, err)
+		}
+		us.patTokens.set(pt.ID, upstream.AccessToken)
+
+		return upstream.AccessToken, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token.(string), nil
+}
+
+func parseTemplates(mfsdk sdk.SDK, prefix string) (tpl *template.Template, err error) {
+	tpl = template.New("magistrala")
+	tpl = tpl.Funcs(template.FuncMap{
+		"toJSON": func(data map[string]interface{}) string {
+			if data == nil {
+				return "{}"
+			}
+			ret, err := json.Marshal(data)
+			if err != nil {
+				return "{}"
+			}
+			return string(ret)
+		},
+		"toSlice": func(data []string) string {
+			if len(data) == 0 {
+				return "[]"
+			}
+			ret, err := json.Marshal(data)
+			if err != nil {
+				return "[]"
 			}
 			return string(ret)
 		},
@@ -2750,3 +3576,1381 @@ func parseTemplates(mfsdk sdk.SDK, prefix string) (tpl *template.Template, err e
 
 	return template, nil
 }
+
+func (us *uiService) TwoFactor(ctx context.Context, s Session) ([]byte, error) {
+	tf, err := us.tfrepo.Retrieve(ctx, s.User.ID)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	// A pending enrolment is shown again until it is confirmed, so that the
+	// QR code can be scanned after a failed confirmation.
+	var secret string
+	var qrCode template.URL
+	if tf.UserID != "" && !tf.Enabled {
+		if secret, err = decrypt(us.encKey, tf.Secret); err != nil {
+			return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+		}
+		if qrCode, err = totpQRCode(secret, s.User.Identity); err != nil {
+			return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+		}
+	}
+
+	crumbs := []breadcrumb{
+		{Name: twoFactorActive},
+	}
+
+	data := struct {
+		NavbarActive      string
+		CollapseActive    string
+		Enabled           bool
+		Pending           bool
+		Secret            string
+		QRCode            template.URL
+		RecoveryCodesLeft int
+		Breadcrumbs       []breadcrumb
+		Session           Session
+	}{
+		twoFactorActive,
+		twoFactorActive,
+		tf.Enabled,
+		secret != "",
+		secret,
+		qrCode,
+		len(tf.RecoveryCodes),
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "twoFactor", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) EnrollTwoFactor(ctx context.Context, s Session) error {
+	tf, err := us.tfrepo.Retrieve(ctx, s.User.ID)
+	if err != nil {
+		return errors.Wrap(ErrFailedTwoFactor, err)
+	}
+	if tf.Enabled {
+		return errors.Wrap(ErrFailedTwoFactor, ErrTwoFactorEnabled)
+	}
+
+	secret, err := totpSecret(s.User.Identity)
+	if err != nil {
+		return errors.Wrap(ErrFailedTwoFactor, err)
+	}
+	encrypted, err := encrypt(us.encKey, secret)
+	if err != nil {
+		return errors.Wrap(ErrFailedTwoFactor, err)
+	}
+
+	now := time.Now()
+	tf = TwoFactor{
+		UserID:    s.User.ID,
+		Secret:    encrypted,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := us.tfrepo.Save(ctx, tf); err != nil {
+		return errors.Wrap(ErrFailedTwoFactor, err)
+	}
+
+	return nil
+}
+
+func (us *uiService) ConfirmTwoFactor(ctx context.Context, s Session, code string) ([]byte, error) {
+	tf, err := us.tfrepo.Retrieve(ctx, s.User.ID)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedTwoFactor, err)
+	}
+	if tf.UserID == "" || tf.Enabled {
+		return []byte{}, ErrTwoFactorEnrolment
+	}
+
+	secret, err := decrypt(us.encKey, tf.Secret)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedTwoFactor, err)
+	}
+	step, ok := totpStep(code, secret, time.Now())
+	if !ok {
+		return []byte{}, ErrTwoFactorCode
+	}
+
+	codes := make([]string, RecoveryCodesCount)
+	hashes := make([]string, RecoveryCodesCount)
+	for i := range codes {
+		if codes[i], err = recoveryCode(); err != nil {
+			return []byte{}, errors.Wrap(ErrFailedTwoFactor, err)
+		}
+		hashes[i] = hashToken(normalizeRecoveryCode(codes[i]))
+	}
+
+	tf.Enabled = true
+	tf.RecoveryCodes = hashes
+	tf.LastStep = step
+	tf.UpdatedAt = time.Now()
+	if err := us.tfrepo.Save(ctx, tf); err != nil {
+		return []byte{}, errors.Wrap(ErrFailedTwoFactor, err)
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"recovery_codes": codes})
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrJSONMarshal, err)
+	}
+
+	return data, nil
+}
+
+func (us *uiService) DisableTwoFactor(ctx context.Context, s Session, code string) error {
+	if err := us.VerifyTwoFactor(ctx, s.User.ID, code); err != nil {
+		return err
+	}
+
+	if err := us.tfrepo.Remove(ctx, s.User.ID); err != nil {
+		return errors.Wrap(ErrFailedTwoFactor, err)
+	}
+
+	return nil
+}
+
+func (us *uiService) TwoFactorEnabled(ctx context.Context, userID string) (bool, error) {
+	tf, err := us.tfrepo.Retrieve(ctx, userID)
+	if err != nil {
+		return false, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	return tf.Enabled, nil
+}
+
+func (us *uiService) TwoFactorLogin() ([]byte, error) {
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "twoFactorLogin", nil); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) VerifyTwoFactor(ctx context.Context, userID, code string) error {
+	tf, err := us.tfrepo.Retrieve(ctx, userID)
+	if err != nil {
+		return errors.Wrap(ErrFailedRetreive, err)
+	}
+	if !tf.Enabled {
+		return ErrTwoFactorCode
+	}
+
+	secret, err := decrypt(us.encKey, tf.Secret)
+	if err != nil {
+		return errors.Wrap(ErrFailedRetreive, err)
+	}
+	// Codes are accepted once, so that they cannot be replayed within their
+	// period, and recovery codes can be used only once.
+	if step, ok := totpStep(code, secret, time.Now()); ok {
+		accepted, err := us.tfrepo.AcceptStep(ctx, userID, step)
+		if err != nil {
+			return errors.Wrap(ErrFailedTwoFactor, err)
+		}
+		if !accepted {
+			return ErrTwoFactorCode
+		}
+		return nil
+	}
+
+	used, err := us.tfrepo.UseRecoveryCode(ctx, userID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return errors.Wrap(ErrFailedTwoFactor, err)
+	}
+	if !used {
+		return ErrTwoFactorCode
+	}
+
+	return nil
+}
+
+func (us *uiService) UpdateDomainTwoFactor(ctx context.Context, s Session, domainID string, required bool) error {
+	permissions, err := us.sdk.DomainPermissions(domainID, s.Token)
+	if err != nil {
+		return errors.Wrap(ErrFailedRetreive, err)
+	}
+	if !slices.Contains(permissions.Permissions, "admin") {
+		return errors.Wrap(ErrFailedUpdate, ErrTwoFactorAdmin)
+	}
+
+	if err := us.tfrepo.SaveDomainRequirement(ctx, domainID, required); err != nil {
+		return errors.Wrap(ErrFailedUpdate, err)
+	}
+
+	return nil
+}
+
+func (us *uiService) UpdateTerminalPolicy(ctx context.Context, s Session, policy TerminalPolicy) error {
+	permissions, err := us.sdk.DomainPermissions(policy.DomainID, s.Token)
+	if err != nil {
+		return errors.Wrap(ErrFailedRetreive, err)
+	}
+	if !slices.Contains(permissions.Permissions, "admin") {
+		return errors.Wrap(ErrFailedUpdate, ErrTerminalPolicyAdmin)
+	}
+
+	policy.UpdatedAt = time.Now().UTC()
+	if err := us.prepo.Save(ctx, policy); err != nil {
+		return errors.Wrap(ErrFailedUpdate, err)
+	}
+
+	return nil
+}
+
+func (us *uiService) BootstrapTemplates(ctx context.Context, s Session, page, limit uint64) ([]byte, error) {
+	pm := BootstrapTemplatePageMeta{
+		Offset:   (page - 1) * limit,
+		Limit:    limit,
+		DomainID: s.Domain.ID,
+	}
+	tplPage, err := us.btrepo.RetrieveAll(ctx, pm)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	noOfPages := int(math.Ceil(float64(tplPage.Total) / float64(limit)))
+
+	crumbs := []breadcrumb{
+		{Name: bootstrapsActive, URL: fmt.Sprintf("%s/%s", us.prefix, bootstrapsActive)},
+		{Name: "Templates"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Templates      []BootstrapTemplate
+		MaxRows        int
+		CurrentPage    int
+		Pages          int
+		Limit          int
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		bootstrapsActive,
+		bootstrapsActive,
+		tplPage.Templates,
+		MaxProvisionRows,
+		int(page),
+		noOfPages,
+		int(limit),
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "bootstrapTemplates", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) CreateBootstrapTemplate(ctx context.Context, s Session, tpl BootstrapTemplate) error {
+	if err := tpl.Validate(); err != nil {
+		return errors.Wrap(ErrFailedCreate, err)
+	}
+
+	id, err := us.idProvider.ID()
+	if err != nil {
+		return errors.Wrap(ErrFailedCreate, err)
+	}
+	tpl.ID = id
+	tpl.DomainID = s.Domain.ID
+	tpl.CreatedBy = s.User.ID
+	tpl.CreatedAt = time.Now().UTC()
+	tpl.UpdatedAt = tpl.CreatedAt
+
+	if err := us.btrepo.Save(ctx, tpl); err != nil {
+		return errors.Wrap(ErrFailedCreate, err)
+	}
+
+	return nil
+}
+
+func (us *uiService) UpdateBootstrapTemplate(ctx context.Context, s Session, tpl BootstrapTemplate) error {
+	if err := tpl.Validate(); err != nil {
+		return errors.Wrap(ErrFailedUpdate, err)
+	}
+
+	tpl.DomainID = s.Domain.ID
+	tpl.UpdatedAt = time.Now().UTC()
+	if err := us.btrepo.Update(ctx, tpl); err != nil {
+		return errors.Wrap(ErrFailedUpdate, err)
+	}
+
+	return nil
+}
+
+func (us *uiService) DeleteBootstrapTemplate(ctx context.Context, s Session, id string) error {
+	if err := us.btrepo.Delete(ctx, s.Domain.ID, id); err != nil {
+		return errors.Wrap(ErrFailedDelete, err)
+	}
+
+	return nil
+}
+
+func (us *uiService) ProvisionBootstraps(ctx context.Context, s Session, templateID string, rows []BootstrapRow) ([]byte, error) {
+	if len(rows) > MaxProvisionRows {
+		return []byte{}, ErrTooManyRows
+	}
+
+	tpl, err := us.btrepo.Retrieve(ctx, s.Domain.ID, templateID)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	results := us.provision(s, tpl, rows)
+
+	summary := make(map[string]int)
+	for _, res := range results {
+		summary[res.Status]++
+	}
+
+	export, err := provisionResultsCSV(results)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	crumbs := []breadcrumb{
+		{Name: bootstrapsActive, URL: fmt.Sprintf("%s/%s", us.prefix, bootstrapsActive)},
+		{Name: "Templates", URL: fmt.Sprintf("%s/%s/templates", us.prefix, bootstrapsActive)},
+		{Name: tpl.Name},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Template       BootstrapTemplate
+		Results        []ProvisionResult
+		Summary        map[string]int
+		Export         template.URL
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		bootstrapsActive,
+		bootstrapsActive,
+		tpl,
+		results,
+		summary,
+		exportURL("text/csv", export),
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "provisionResults", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) ExpiringCertificates(ctx context.Context, s Session, within time.Duration) ([]byte, error) {
+	now := time.Now()
+	certs, err := us.expiringCerts(s.Token, now.Add(within), now)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	crumbs := []breadcrumb{
+		{Name: bootstrapsActive, URL: fmt.Sprintf("%s/%s", us.prefix, bootstrapsActive)},
+		{Name: "Expiring Certificates"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Certs          []ExpiringCert
+		Days           int
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		bootstrapsActive,
+		bootstrapsActive,
+		certs,
+		int(within.Hours() / 24),
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "expiringCerts", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) BootstrapBundle(s Session) ([]byte, error) {
+	configs, err := us.bootstrapConfigs(s.Token)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	crumbs := []breadcrumb{
+		{Name: bootstrapsActive, URL: fmt.Sprintf("%s/%s", us.prefix, bootstrapsActive)},
+		{Name: "Bundle"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Bootstraps     []sdk.BootstrapConfig
+		MaxConfigs     int
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		bootstrapsActive,
+		bootstrapsActive,
+		configs,
+		MaxBundleConfigs,
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "bootstrapBundle", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) ExportBundle(ctx context.Context, s Session, export BundleExport) ([]byte, error) {
+	if len(export.ThingIDs) > MaxBundleConfigs {
+		return []byte{}, errors.Wrap(ErrInvalidBundle, fmt.Errorf("more than %d bootstrap configs", MaxBundleConfigs))
+	}
+
+	bundle, err := us.exportBundle(s.Token, export)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	data, err := encodeBundle(bundle, export.Format)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrJSONMarshal, err)
+	}
+
+	return data, nil
+}
+
+func (us *uiService) ImportBundle(ctx context.Context, s Session, bundle Bundle, opts BundleImport) ([]byte, error) {
+	results := us.importBundle(s.Token, bundle, opts)
+
+	summary := make(map[string]int)
+	for _, res := range results {
+		summary[res.Status]++
+	}
+
+	crumbs := []breadcrumb{
+		{Name: bootstrapsActive, URL: fmt.Sprintf("%s/%s", us.prefix, bootstrapsActive)},
+		{Name: "Bundle", URL: fmt.Sprintf("%s/%s/bundle", us.prefix, bootstrapsActive)},
+		{Name: "Import"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		DryRun         bool
+		Results        []BundleResult
+		Summary        map[string]int
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		bootstrapsActive,
+		bootstrapsActive,
+		opts.DryRun,
+		results,
+		summary,
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "bundleResults", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) BootstrapStates(s Session, filter BootstrapStateFilter) ([]byte, error) {
+	configs, err := us.bootstrapConfigs(s.Token)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	crumbs := []breadcrumb{
+		{Name: bootstrapsActive, URL: fmt.Sprintf("%s/%s", us.prefix, bootstrapsActive)},
+		{Name: "States"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Counts         BootstrapStateCounts
+		Bootstraps     []sdk.BootstrapConfig
+		Filter         BootstrapStateFilter
+		MaxChanges     int
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		bootstrapsActive,
+		bootstrapsActive,
+		countBootstrapStates(configs),
+		filterBootstrapStates(configs, filter),
+		filter,
+		MaxStateChanges,
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "bootstrapStates", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) UpdateBootstrapStates(ctx context.Context, s Session, change BootstrapStateChange) ([]byte, error) {
+	configs, err := us.bootstrapConfigs(s.Token)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	results := us.changeBootstrapStates(s.Token, configs, change)
+
+	summary := make(map[string]int)
+	for _, res := range results {
+		summary[res.Status]++
+	}
+
+	crumbs := []breadcrumb{
+		{Name: bootstrapsActive, URL: fmt.Sprintf("%s/%s", us.prefix, bootstrapsActive)},
+		{Name: "States", URL: fmt.Sprintf("%s/%s/states", us.prefix, bootstrapsActive)},
+		{Name: "Results"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		State          string
+		Results        []BootstrapStateResult
+		Summary        map[string]int
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		bootstrapsActive,
+		bootstrapsActive,
+		change.State,
+		results,
+		summary,
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "bootstrapStateResults", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) ExportEntities(ctx context.Context, s Session, export EntityExport, w io.Writer) error {
+	if err := us.writeEntities(csv.NewWriter(w), s.Token, export); err != nil {
+		return errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	return nil
+}
+
+func (us *uiService) ImportEntities(ctx context.Context, s Session, imp EntityImport) ([]byte, error) {
+	if len(imp.Records) > MaxImportRows {
+		return []byte{}, ErrTooManyRows
+	}
+
+	results := us.importEntities(s.Token, imp)
+
+	summary := make(map[string]int)
+	for _, res := range results {
+		summary[res.Status]++
+	}
+
+	report, err := importResultsCSV(results)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	crumbs := []breadcrumb{
+		{Name: imp.Entity, URL: fmt.Sprintf("%s/%s", us.prefix, imp.Entity)},
+		{Name: "Import"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Entity         string
+		DryRun         bool
+		Results        []ImportRowResult
+		Summary        map[string]int
+		Report         template.URL
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		imp.Entity,
+		imp.Entity,
+		imp.Entity,
+		imp.DryRun,
+		results,
+		summary,
+		exportURL("text/csv", report),
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "importResults", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) BulkConnect(ctx context.Context, s Session, rows []ConnectionRow) ([]byte, error) {
+	if len(rows) > MaxConnectionRows {
+		return []byte{}, ErrTooManyRows
+	}
+
+	results, err := us.connectRows(s.Token, rows)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	summary := make(map[string]int)
+	for _, res := range results {
+		summary[res.Status]++
+	}
+
+	crumbs := []breadcrumb{
+		{Name: thingsActive, URL: fmt.Sprintf("%s/%s", us.prefix, thingsActive)},
+		{Name: "Connections"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Results        []ConnectionResult
+		Summary        map[string]int
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		thingsActive,
+		thingsActive,
+		results,
+		summary,
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "connectionResults", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) BulkAction(ctx context.Context, s Session, action EntityBulkAction) ([]byte, error) {
+	if len(action.IDs) > MaxBulkEntities {
+		return []byte{}, ErrTooManyEntities
+	}
+
+	results, err := us.applyBulkAction(s.Token, action)
+	if err != nil {
+		if errors.Contains(err, ErrTooManyEntities) {
+			return []byte{}, err
+		}
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	summary := make(map[string]int)
+	for _, res := range results {
+		summary[res.Status]++
+	}
+
+	crumbs := []breadcrumb{
+		{Name: action.Entity, URL: fmt.Sprintf("%s/%s", us.prefix, action.Entity)},
+		{Name: "Bulk Action"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Entity         string
+		Action         EntityBulkAction
+		Results        []BulkActionResult
+		Summary        map[string]int
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		action.Entity,
+		action.Entity,
+		action.Entity,
+		action,
+		results,
+		summary,
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "bulkActionResults", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) Search(ctx context.Context, s Session, query string) ([]byte, error) {
+	query = strings.TrimSpace(query)
+
+	var results []SearchResult
+	failures := make(map[string]string)
+	if query != "" {
+		results, failures = us.search(s, query)
+	}
+
+	counts := make(map[string]int)
+	for _, res := range results {
+		counts[res.Entity]++
+	}
+
+	crumbs := []breadcrumb{
+		{Name: "Search"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Query          string
+		Results        []SearchResult
+		Counts         map[string]int
+		Failures       map[string]string
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		searchActive,
+		searchActive,
+		query,
+		results,
+		counts,
+		failures,
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "search", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) FindEntity(ctx context.Context, s Session, query string) (string, error) {
+	query = strings.TrimSpace(query)
+	if !uuidRegexp.MatchString(query) {
+		return "", nil
+	}
+
+	return us.findEntity(s, query), nil
+}
+
+func (us *uiService) Topology(ctx context.Context, s Session, filter TopologyFilter) ([]byte, error) {
+	groups, err := us.allGroups(s.Token)
+	if err != nil {
+		if errors.Contains(err, ErrTooManyEntities) {
+			return []byte{}, err
+		}
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	crumbs := []breadcrumb{
+		{Name: "Topology"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Filter         TopologyFilter
+		Groups         []sdk.Group
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		topologyActive,
+		topologyActive,
+		filter,
+		groups,
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "topology", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) TopologyGraph(ctx context.Context, s Session, filter TopologyFilter) ([]byte, error) {
+	topo, err := us.topology(s.Token, filter)
+	if err != nil {
+		if errors.Contains(err, ErrTooManyEntities) {
+			return []byte{}, err
+		}
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	data, err := json.Marshal(topo)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrJSONMarshal, err)
+	}
+
+	return data, nil
+}
+
+func (us *uiService) GroupTree(ctx context.Context, s Session, parentID string, page, limit uint64) ([]byte, error) {
+	var level, descendants []sdk.Group
+	var more bool
+	var err error
+	switch parentID {
+	case "":
+		if level, more, err = us.rootGroups(s.Token, page, limit); err == nil {
+			descendants, err = us.rootSubtrees(s.Token, level)
+		}
+	default:
+		descendants, err = us.groupDescendants(s.Token, parentID)
+		for _, g := range descendants {
+			if g.ParentID == parentID {
+				level = append(level, g)
+			}
+		}
+	}
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	nodes, subtrees := groupTreeLevel(level, descendants)
+	if err := us.countGroupTree(s.Token, nodes, subtrees); err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	var nextPage uint64
+	if more {
+		nextPage = page + 1
+	}
+
+	var btpl bytes.Buffer
+	switch {
+	case parentID != "":
+		if err := us.tpls.ExecuteTemplate(&btpl, "groupTreeNodes", nodes); err != nil {
+			return []byte{}, errors.Wrap(ErrExecTemplate, err)
+		}
+		return btpl.Bytes(), nil
+	case page > 1:
+		roots := struct {
+			Nodes    []GroupTreeNode
+			NextPage uint64
+		}{nodes, nextPage}
+		if err := us.tpls.ExecuteTemplate(&btpl, "groupTreeRoots", roots); err != nil {
+			return []byte{}, errors.Wrap(ErrExecTemplate, err)
+		}
+		return btpl.Bytes(), nil
+	}
+
+	crumbs := []breadcrumb{
+		{Name: groupsActive, URL: fmt.Sprintf("%s/%s", us.prefix, groupsActive)},
+		{Name: "Tree"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Nodes          []GroupTreeNode
+		NextPage       uint64
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		groupsActive,
+		groupsActive,
+		nodes,
+		nextPage,
+		crumbs,
+		s,
+	}
+
+	if err := us.tpls.ExecuteTemplate(&btpl, "groupTree", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) MoveGroup(ctx context.Context, s Session, groupID, parentID string) error {
+	group, err := us.sdk.Group(groupID, s.Token)
+	if err != nil {
+		return errors.Wrap(ErrFailedRetreive, err)
+	}
+	if group.ParentID == parentID {
+		return nil
+	}
+
+	if parentID != "" {
+		// The group cannot be moved below a group of its subtree, at any
+		// depth. The ancestors are listed a few levels at a time, so they are
+		// listed again from the highest one until the root is reached.
+		for id, seen := parentID, map[string]bool{}; id != "" && !seen[id]; {
+			ancestors, err := us.groupAncestors(s.Token, id)
+			if err != nil {
+				return errors.Wrap(ErrFailedRetreive, err)
+			}
+			seen[id] = true
+			for _, a := range ancestors {
+				if a.ID == groupID {
+					return ErrInvalidMove
+				}
+				seen[a.ID] = true
+			}
+			for _, a := range ancestors {
+				if !seen[a.ParentID] {
+					id = a.ParentID
+				}
+			}
+		}
+	}
+
+	if group.ParentID != "" {
+		if err := us.sdk.UnassignParent(ctx, s.Token, group.ParentID, groupID); err != nil {
+			return errors.Wrap(ErrFailedUpdate, err)
+		}
+	}
+	if parentID != "" {
+		if err := us.sdk.AssignParent(ctx, s.Token, parentID, groupID); err != nil {
+			// Put the group back under its former parent.
+			if group.ParentID != "" {
+				if rerr := us.sdk.AssignParent(ctx, s.Token, group.ParentID, groupID); rerr != nil {
+					err = errors.Wrap(err, rerr)
+				}
+			}
+			return errors.Wrap(ErrFailedUpdate, err)
+		}
+	}
+
+	return nil
+}
+
+func (us *uiService) HygieneReport(ctx context.Context, s Session) ([]byte, error) {
+	categories, err := us.hygieneReport(s.Token)
+	if err != nil {
+		if errors.Contains(err, ErrTooManyEntities) {
+			return []byte{}, err
+		}
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	crumbs := []breadcrumb{
+		{Name: "Hygiene"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Categories     []HygieneCategory
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		hygieneActive,
+		hygieneActive,
+		categories,
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "hygiene", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) HygieneCleanup(ctx context.Context, s Session, cleanup HygieneCleanup) ([]byte, error) {
+	if len(cleanup.IDs) > MaxBulkEntities {
+		return []byte{}, ErrTooManyEntities
+	}
+
+	results := us.cleanupHygiene(s.Token, cleanup)
+
+	summary := make(map[string]int)
+	for _, res := range results {
+		summary[res.Status]++
+	}
+
+	crumbs := []breadcrumb{
+		{Name: "Hygiene", URL: fmt.Sprintf("%s/%s", us.prefix, hygieneActive)},
+		{Name: "Cleanup"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Entity         string
+		Cleanup        HygieneCleanup
+		Results        []BulkActionResult
+		Summary        map[string]int
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		hygieneActive,
+		hygieneActive,
+		hygieneEntity(cleanup.Category),
+		cleanup,
+		results,
+		summary,
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "hygieneCleanupResults", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) SecretRotations(ctx context.Context, s Session, page, limit uint64) ([]byte, error) {
+	schedules, err := us.rrepo.RetrieveSchedules(ctx, s.Domain.ID)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	pm := SecretRotationPageMeta{
+		Offset:   (page - 1) * limit,
+		Limit:    limit,
+		DomainID: s.Domain.ID,
+	}
+	rotPage, err := us.rrepo.RetrieveAll(ctx, pm)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	noOfPages := int(math.Ceil(float64(rotPage.Total) / float64(limit)))
+
+	crumbs := []breadcrumb{
+		{Name: thingsActive, URL: fmt.Sprintf("%s/%s", us.prefix, thingsActive)},
+		{Name: "Secret Rotations"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Schedules      []RotationSchedule
+		Rotations      []SecretRotation
+		MinInterval    time.Duration
+		CurrentPage    int
+		Pages          int
+		Limit          int
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		thingsActive,
+		rotationsActive,
+		schedules,
+		rotPage.Rotations,
+		MinRotationInterval,
+		int(page),
+		noOfPages,
+		int(limit),
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "secretRotations", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) RotateThingSecrets(ctx context.Context, s Session, thingIDs []string) ([]byte, error) {
+	if len(thingIDs) > MaxBulkEntities {
+		return []byte{}, ErrTooManyEntities
+	}
+
+	seen := make(map[string]bool, len(thingIDs))
+	var things []sdk.Thing
+	for _, id := range thingIDs {
+		if !seen[id] {
+			seen[id] = true
+			things = append(things, sdk.Thing{ID: id})
+		}
+	}
+
+	runID, err := us.idProvider.ID()
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedGenerateID, err)
+	}
+	record := SecretRotation{
+		RunID:     runID,
+		DomainID:  s.Domain.ID,
+		UserID:    s.User.ID,
+		Trigger:   RotationManual,
+		CreatedAt: time.Now().UTC(),
+	}
+	rotated, rotations, err := us.rotateSecrets(ctx, s.Token, things, true, record)
+	if rotated == nil {
+		return []byte{}, errors.Wrap(ErrFailedRotation, err)
+	}
+	// The secrets are changed by now, so they are shown even when the
+	// history could not be updated.
+	var warning string
+	if err != nil {
+		warning = fmt.Sprintf("The rotations are kept as started in the history, which could not be updated: %s", err)
+	}
+
+	summary := make(map[string]int)
+	for _, r := range rotations {
+		summary[r.Status]++
+	}
+
+	crumbs := []breadcrumb{
+		{Name: thingsActive, URL: fmt.Sprintf("%s/%s", us.prefix, thingsActive)},
+		{Name: "Secret Rotations", URL: fmt.Sprintf("%s/%s/%s", us.prefix, thingsActive, rotationsActive)},
+		{Name: "Rotated Secrets"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Secrets        []RotatedSecret
+		Summary        map[string]int
+		Warning        string
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		thingsActive,
+		rotationsActive,
+		rotated,
+		summary,
+		warning,
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "rotatedSecrets", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) CreateRotationSchedule(ctx context.Context, s Session, password string, schedule RotationSchedule) error {
+	if schedule.Interval < MinRotationInterval {
+		return errors.Wrap(ErrFailedCreate, fmt.Errorf("the interval is shorter than %s", MinRotationInterval))
+	}
+
+	// The schedule is backed by an upstream refresh token so that it can
+	// rotate secrets on behalf of the user, like personal access tokens.
+	login := sdk.Login{
+		Identity: s.User.Identity,
+		Secret:   password,
+		DomainID: s.Domain.ID,
+	}
+	upstream, sdkerr := us.sdk.CreateToken(login)
+	if sdkerr != nil {
+		return errors.Wrap(ErrToken, sdkerr)
+	}
+	refreshToken, err := encrypt(us.encKey, upstream.RefreshToken)
+	if err != nil {
+		return errors.Wrap(ErrFailedCreate, err)
+	}
+
+	id, err := us.idProvider.ID()
+	if err != nil {
+		return errors.Wrap(ErrFailedGenerateID, err)
+	}
+	now := time.Now().UTC()
+	schedule.ID = id
+	schedule.DomainID = s.Domain.ID
+	schedule.UserID = s.User.ID
+	schedule.RefreshToken = refreshToken
+	schedule.NextRunAt = now.Add(schedule.Interval)
+	schedule.TokenRefreshedAt = now
+	schedule.CreatedAt = now
+	if err := us.rrepo.SaveSchedule(ctx, schedule); err != nil {
+		return errors.Wrap(ErrFailedCreate, err)
+	}
+
+	return nil
+}
+
+func (us *uiService) DeleteRotationSchedule(ctx context.Context, s Session, id string) error {
+	if err := us.rrepo.RemoveSchedule(ctx, s.Domain.ID, id); err != nil {
+		return errors.Wrap(ErrFailedDelete, err)
+	}
+
+	return nil
+}
+
+func (us *uiService) DownloadRotatedSecrets(ctx context.Context, s Session, runID string) ([]byte, error) {
+	rotations, err := us.rrepo.TakeSecrets(ctx, s.Domain.ID, runID)
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+	if len(rotations) == 0 {
+		return []byte{}, ErrSecretsTaken
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"Name", "ID", "Secret"}); err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+	for _, r := range rotations {
+		secret, err := decrypt(us.encKey, r.Secret)
+		if err != nil {
+			return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+		}
+		if err := w.Write([]string{r.ThingName, r.ThingID, secret}); err != nil {
+			return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return []byte{}, errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (us *uiService) RunRotationSchedules(ctx context.Context, now time.Time, tokenMaxAge time.Duration) error {
+	now = now.UTC()
+	schedules, err := us.rrepo.ClaimSchedules(ctx, now, now.Add(-tokenMaxAge), now.Add(rotationClaim))
+	if err != nil {
+		return errors.Wrap(ErrFailedRetreive, err)
+	}
+
+	// A failing schedule does not stop the others.
+	var failure error
+	for _, schedule := range schedules {
+		if err := us.runRotationSchedule(ctx, schedule, now); err != nil {
+			failure = errors.Wrap(err, fmt.Errorf("schedule %s", schedule.ID))
+		}
+	}
+
+	return failure
+}
+
+func (us *uiService) ProvisionWizard(s Session) ([]byte, error) {
+	crumbs := []breadcrumb{
+		{Name: thingsActive, URL: fmt.Sprintf("%s/%s", us.prefix, thingsActive)},
+		{Name: "Provision"},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		MaxChannels    int
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		thingsActive,
+		provisionActive,
+		MaxProvisionChannels,
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "provisionThing", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) ProvisionThing(ctx context.Context, s Session, p ThingProvision) ([]byte, error) {
+	res, err := us.provisionThing(ctx, s, p)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	export, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return []byte{}, errors.Wrap(ErrJSONMarshal, err)
+	}
+
+	crumbs := []breadcrumb{
+		{Name: thingsActive, URL: fmt.Sprintf("%s/%s", us.prefix, thingsActive)},
+		{Name: "Provision", URL: fmt.Sprintf("%s/%s/%s", us.prefix, thingsActive, provisionActive)},
+		{Name: res.Thing.Name},
+	}
+
+	data := struct {
+		NavbarActive   string
+		CollapseActive string
+		Provisioned    ProvisionedThing
+		Export         template.URL
+		Breadcrumbs    []breadcrumb
+		Session        Session
+	}{
+		thingsActive,
+		provisionActive,
+		res,
+		exportURL("application/json", export),
+		crumbs,
+		s,
+	}
+
+	var btpl bytes.Buffer
+	if err := us.tpls.ExecuteTemplate(&btpl, "thingProvisioned", data); err != nil {
+		return []byte{}, errors.Wrap(ErrExecTemplate, err)
+	}
+
+	return btpl.Bytes(), nil
+}
+
+func (us *uiService) CheckDomainTwoFactor(ctx context.Context, domainID, userID string) error {
+	required, err := us.tfrepo.RetrieveDomainRequirement(ctx, domainID)
+	if err != nil {
+		return errors.Wrap(ErrFailedRetreive, err)
+	}
+	if !required {
+		return nil
+	}
+
+	enabled, err := us.TwoFactorEnabled(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return ErrTwoFactorRequired
+	}
+
+	return nil
+}
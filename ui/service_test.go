@@ -4,9 +4,13 @@
 package ui_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -18,28 +22,37 @@ import (
 	oauth2mocks "github.com/absmach/magistrala-ui/ui/oauth2/mocks"
 	"github.com/absmach/magistrala/pkg/errors"
 	sdk "github.com/absmach/magistrala/pkg/sdk/go"
-	sdkmocks "github.com/absmach/magistrala/pkg/sdk/mocks"
 	"github.com/absmach/magistrala/pkg/transformers/senml"
 	"github.com/absmach/magistrala/pkg/uuid"
+	"github.com/golang-jwt/jwt"
+	"github.com/pquerna/otp/totp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slices"
 )
 
 var (
-	idProvider   = uuid.New()
-	prefix       = ""
-	sdkmock      = new(sdkmocks.SDK)
-	repo         = new(mocks.DashboardRepository)
-	provider     = new(oauth2mocks.Provider)
-	sdkerr       = errors.NewSDKError(fmt.Errorf("sdk error"))
-	emailSuffix  = "@example.com"
-	password     = "$tr0ngPassw0rd"
-	namesgen     = namegenerator.NewGenerator()
-	accessToken  = strings.Repeat("a", 32)
-	name         = namesgen.Generate()
-	id           = generateID(&testing.T{})
-	validSession = ui.Session{
+	idProvider    = uuid.New()
+	prefix        = ""
+	sdkmock       = new(mocks.SDK)
+	repo          = new(mocks.DashboardRepository)
+	tokenRepo     = new(mocks.PersonalTokenRepository)
+	twoFactorRepo = new(mocks.TwoFactorRepository)
+	auditRepo     = new(mocks.TerminalAuditRepository)
+	policyRepo    = new(mocks.TerminalPolicyRepository)
+	templateRepo  = new(mocks.BootstrapTemplateRepository)
+	rotationRepo  = new(mocks.SecretRotationRepository)
+	encKey        = []byte(strings.Repeat("k", 32))
+	provider      = new(oauth2mocks.Provider)
+	sdkerr        = errors.NewSDKError(fmt.Errorf("sdk error"))
+	emailSuffix   = "@example.com"
+	password      = "$tr0ngPassw0rd"
+	namesgen      = namegenerator.NewGenerator()
+	accessToken   = strings.Repeat("a", 32)
+	name          = namesgen.Generate()
+	id            = generateID(&testing.T{})
+	validSession  = ui.Session{
 		User: ui.User{
 			ID:       generateID(&testing.T{}),
 			Name:     namesgen.Generate(),
@@ -205,7 +218,7 @@ func init() {
 }
 
 func TestIndex(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -316,7 +329,7 @@ func TestViewRegistration(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			svc, err := ui.New(sdkmock, repo, idProvider, prefix, tc.prov)
+			svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, tc.prov)
 			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 			pcall := provider.On("IsEnabled").Return(true)
@@ -335,7 +348,7 @@ func TestViewRegistration(t *testing.T) {
 }
 
 func TestRegisterUser(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -414,7 +427,7 @@ func TestLogin(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			svc, err := ui.New(sdkmock, repo, idProvider, prefix, tc.prov)
+			svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, tc.prov)
 			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 			page, err := svc.Login()
@@ -427,7 +440,7 @@ func TestLogin(t *testing.T) {
 }
 
 func TestPasswordResetRequest(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -462,7 +475,7 @@ func TestPasswordResetRequest(t *testing.T) {
 }
 
 func TestPasswordReset(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -496,7 +509,7 @@ func TestPasswordReset(t *testing.T) {
 }
 
 func TestShowPasswordReset(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	provider.On("IsEnabled").Return(true)
 	provider.On("Name").Return(name)
@@ -524,7 +537,7 @@ func TestShowPasswordReset(t *testing.T) {
 }
 
 func TestPasswordUpdate(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	provider.On("IsEnabled").Return(true)
 	provider.On("Name").Return("test")
@@ -557,7 +570,7 @@ func TestPasswordUpdate(t *testing.T) {
 }
 
 func TestUpdatePassword(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -591,7 +604,7 @@ func TestUpdatePassword(t *testing.T) {
 }
 
 func TestToken(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -626,7 +639,7 @@ func TestToken(t *testing.T) {
 }
 
 func TestRefreshToken(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -660,7 +673,7 @@ func TestRefreshToken(t *testing.T) {
 }
 
 func TestDomainLogin(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -694,7 +707,7 @@ func TestDomainLogin(t *testing.T) {
 }
 
 func TestSession(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -743,8 +756,36 @@ func TestSession(t *testing.T) {
 	}
 }
 
+func TestSessionExpired(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	provider.On("IsEnabled").Return(true)
+	provider.On("Name").Return(name)
+	provider.On("Icon").Return("fa-test")
+
+	cases := []struct {
+		desc string
+		err  error
+	}{
+		{
+			desc: "success",
+			err:  nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			page, err := svc.SessionExpired()
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				assert.NotEmpty(t, page, "expected page to be not empty")
+			}
+		})
+	}
+}
+
 func TestCreateUsers(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -779,7 +820,7 @@ func TestCreateUsers(t *testing.T) {
 }
 
 func TestListUsers(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -813,7 +854,7 @@ func TestListUsers(t *testing.T) {
 }
 
 func TestViewUser(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -847,7 +888,7 @@ func TestViewUser(t *testing.T) {
 }
 
 func TestUpdateUser(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -881,7 +922,7 @@ func TestUpdateUser(t *testing.T) {
 }
 
 func TestUpdateUserTags(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -915,7 +956,7 @@ func TestUpdateUserTags(t *testing.T) {
 }
 
 func TestUpdateUserIdentity(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -949,7 +990,7 @@ func TestUpdateUserIdentity(t *testing.T) {
 }
 
 func TestUpdateUserRole(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -983,7 +1024,7 @@ func TestUpdateUserRole(t *testing.T) {
 }
 
 func TestEnableUser(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1017,7 +1058,7 @@ func TestEnableUser(t *testing.T) {
 }
 
 func TestDisableUser(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1051,7 +1092,7 @@ func TestDisableUser(t *testing.T) {
 }
 
 func TestCreateThing(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1085,7 +1126,7 @@ func TestCreateThing(t *testing.T) {
 }
 
 func TestCreateThings(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1120,7 +1161,7 @@ func TestCreateThings(t *testing.T) {
 }
 
 func TestListThings(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1154,7 +1195,7 @@ func TestListThings(t *testing.T) {
 }
 
 func TestViewThing(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1194,7 +1235,7 @@ func TestViewThing(t *testing.T) {
 }
 
 func TestUpdateThing(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1228,7 +1269,7 @@ func TestUpdateThing(t *testing.T) {
 }
 
 func TestUpdateThingTags(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1262,7 +1303,7 @@ func TestUpdateThingTags(t *testing.T) {
 }
 
 func TestUpdateThingSecret(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1296,7 +1337,7 @@ func TestUpdateThingSecret(t *testing.T) {
 }
 
 func TestEnableThing(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1330,7 +1371,7 @@ func TestEnableThing(t *testing.T) {
 }
 
 func TestDisableThing(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1364,7 +1405,7 @@ func TestDisableThing(t *testing.T) {
 }
 
 func TestShareThing(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1398,7 +1439,7 @@ func TestShareThing(t *testing.T) {
 }
 
 func TestUnshareThing(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1432,7 +1473,7 @@ func TestUnshareThing(t *testing.T) {
 }
 
 func TestListThingUsers(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1477,7 +1518,7 @@ func TestListThingUsers(t *testing.T) {
 }
 
 func TestListChannelsByThing(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1527,7 +1568,7 @@ func TestListChannelsByThing(t *testing.T) {
 }
 
 func TestCreateChannel(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1561,7 +1602,7 @@ func TestCreateChannel(t *testing.T) {
 }
 
 func TestCreateChannels(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1596,7 +1637,7 @@ func TestCreateChannels(t *testing.T) {
 }
 
 func TestListChannels(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1630,7 +1671,7 @@ func TestListChannels(t *testing.T) {
 }
 
 func TestViewChannel(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1670,7 +1711,7 @@ func TestViewChannel(t *testing.T) {
 }
 
 func TestUpdateChannel(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1704,7 +1745,7 @@ func TestUpdateChannel(t *testing.T) {
 }
 
 func TestListThingsByChannel(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1750,7 +1791,7 @@ func TestListThingsByChannel(t *testing.T) {
 }
 
 func TestEnableChannel(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1784,7 +1825,7 @@ func TestEnableChannel(t *testing.T) {
 }
 
 func TestDisableChannel(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1818,7 +1859,7 @@ func TestDisableChannel(t *testing.T) {
 }
 
 func TestConnect(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1852,7 +1893,7 @@ func TestConnect(t *testing.T) {
 }
 
 func TestDisconnect(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1886,7 +1927,7 @@ func TestDisconnect(t *testing.T) {
 }
 
 func TestConnectThing(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1920,7 +1961,7 @@ func TestConnectThing(t *testing.T) {
 }
 
 func TestDisconnectThing(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1954,7 +1995,7 @@ func TestDisconnectThing(t *testing.T) {
 }
 
 func TestAddUserToChannel(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -1988,7 +2029,7 @@ func TestAddUserToChannel(t *testing.T) {
 }
 
 func TestRemoveUserFromChannel(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2022,7 +2063,7 @@ func TestRemoveUserFromChannel(t *testing.T) {
 }
 
 func TestListChannelUsers(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2067,7 +2108,7 @@ func TestListChannelUsers(t *testing.T) {
 }
 
 func TestAddUserGroupToChannel(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2101,7 +2142,7 @@ func TestAddUserGroupToChannel(t *testing.T) {
 }
 
 func TestRemoveUserGroupFromChannel(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2135,7 +2176,7 @@ func TestRemoveUserGroupFromChannel(t *testing.T) {
 }
 
 func TestListChannelUserGroups(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2179,7 +2220,7 @@ func TestListChannelUserGroups(t *testing.T) {
 }
 
 func TestAssign(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2213,7 +2254,7 @@ func TestAssign(t *testing.T) {
 }
 
 func TestUnassign(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2247,7 +2288,7 @@ func TestUnassign(t *testing.T) {
 }
 
 func TestCreateGroups(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2282,7 +2323,7 @@ func TestCreateGroups(t *testing.T) {
 }
 
 func TestListGroupUsers(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2328,7 +2369,7 @@ func TestListGroupUsers(t *testing.T) {
 }
 
 func TestViewGroup(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2378,7 +2419,7 @@ func TestViewGroup(t *testing.T) {
 }
 
 func TestListGroups(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2412,7 +2453,7 @@ func TestListGroups(t *testing.T) {
 }
 
 func TestUpdateGroup(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2446,7 +2487,7 @@ func TestUpdateGroup(t *testing.T) {
 }
 
 func TestEnableGroup(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2480,7 +2521,7 @@ func TestEnableGroup(t *testing.T) {
 }
 
 func TestDisableGroup(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2514,7 +2555,7 @@ func TestDisableGroup(t *testing.T) {
 }
 
 func TestListUserGroupChannels(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2558,7 +2599,7 @@ func TestListUserGroupChannels(t *testing.T) {
 }
 
 func TestReadMessages(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2592,7 +2633,7 @@ func TestReadMessages(t *testing.T) {
 }
 
 func TestFetchChartData(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2626,7 +2667,7 @@ func TestFetchChartData(t *testing.T) {
 }
 
 func TestPublish(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2663,33 +2704,47 @@ func TestPublish(t *testing.T) {
 }
 
 func TestCreateBootstrap(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	invalidConfig := validBootstrapConfig
+	invalidConfig.Content = `{"agent":{"mqtt":{"urll":"tcp://broker:1883"}}}`
+
 	cases := []struct {
 		desc   string
+		config sdk.BootstrapConfig
 		sdkerr errors.SDKError
 		err    error
 	}{
 		{
 			desc:   "success",
+			config: validBootstrapConfig,
 			sdkerr: nil,
 			err:    nil,
 		},
 		{
 			desc:   "sdk error",
+			config: validBootstrapConfig,
 			sdkerr: sdkerr,
 			err:    ui.ErrFailedCreate,
 		},
+		{
+			desc:   "invalid content",
+			config: invalidConfig,
+			err:    ui.ErrInvalidContent,
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("AddBootstrap", validBootstrapConfig, accessToken).Return("", tc.sdkerr)
-			err := svc.CreateBootstrap(accessToken, validBootstrapConfig)
+			sdkCall := sdkmock.On("AddBootstrap", tc.config, accessToken).Return("", tc.sdkerr)
+			err := svc.CreateBootstrap(accessToken, tc.config)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
 			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "AddBootstrap", validBootstrapConfig, accessToken)
+				sdkCall.Parent.AssertCalled(t, "AddBootstrap", tc.config, accessToken)
+			}
+			if tc.err == ui.ErrInvalidContent {
+				sdkCall.Parent.AssertNotCalled(t, "AddBootstrap", tc.config, accessToken)
 			}
 			sdkCall.Unset()
 		})
@@ -2697,7 +2752,7 @@ func TestCreateBootstrap(t *testing.T) {
 }
 
 func TestListBootstrap(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2748,33 +2803,89 @@ func TestListBootstrap(t *testing.T) {
 }
 
 func TestUpdateBootstrap(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	invalidConfig := validBootstrapConfig
+	invalidConfig.Content = `{"agent":{"heartbeat":{}}}`
+
 	cases := []struct {
 		desc   string
+		config sdk.BootstrapConfig
 		sdkerr errors.SDKError
 		err    error
 	}{
 		{
 			desc:   "success",
+			config: validBootstrapConfig,
 			sdkerr: nil,
 			err:    nil,
 		},
 		{
 			desc:   "sdk error",
+			config: validBootstrapConfig,
 			sdkerr: sdkerr,
 			err:    ui.ErrFailedUpdate,
 		},
+		{
+			desc:   "invalid content",
+			config: invalidConfig,
+			err:    ui.ErrInvalidContent,
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("UpdateBootstrap", validBootstrapConfig, validSession.Token).Return(tc.sdkerr)
-			err := svc.UpdateBootstrap(validSession.Token, validBootstrapConfig)
+			sdkCall := sdkmock.On("UpdateBootstrap", tc.config, validSession.Token).Return(tc.sdkerr)
+			err := svc.UpdateBootstrap(validSession.Token, tc.config)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
 			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "UpdateBootstrap", validBootstrapConfig, validSession.Token)
+				sdkCall.Parent.AssertCalled(t, "UpdateBootstrap", tc.config, validSession.Token)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestBootstrapContentDiff(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cfg := validBootstrapConfig
+	cfg.Content = `{"agent":{"mqtt":{"url":"tcp://broker:1883"}}}`
+
+	cases := []struct {
+		desc     string
+		content  string
+		sdkerr   errors.SDKError
+		contains []string
+		err      error
+	}{
+		{
+			desc:     "diff valid content",
+			content:  `{"agent":{"mqtt":{"url":"tcp://other:1883"}}}`,
+			contains: []string{"table-danger", "tcp://broker:1883", "tcp://other:1883", "matches the agent config"},
+		},
+		{
+			desc:     "diff content with unknown fields",
+			content:  `{"agent":{"mqtt":{"url":"tcp://broker:1883","port":"1883"}}}`,
+			contains: []string{"agent.mqtt.port", "disabled"},
+		},
+		{
+			desc:    "diff content with sdk error",
+			content: cfg.Content,
+			sdkerr:  sdkerr,
+			err:     ui.ErrFailedRetreive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("ViewBootstrap", cfg.ThingID, validSession.Token).Return(cfg, tc.sdkerr)
+			res, err := svc.BootstrapContentDiff(validSession, cfg.ThingID, tc.content)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
 			}
 			sdkCall.Unset()
 		})
@@ -2782,7 +2893,7 @@ func TestUpdateBootstrap(t *testing.T) {
 }
 
 func TestUpdateBootstrapConnections(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2826,7 +2937,7 @@ func TestUpdateBootstrapConnections(t *testing.T) {
 }
 
 func TestUpdateBootstrapCerts(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2859,8 +2970,67 @@ func TestUpdateBootstrapCerts(t *testing.T) {
 	}
 }
 
+func TestUpdateBootstrapCertsWarnings(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	now := time.Now()
+	ca := issueCert(t, "ca", true, now.Add(-time.Hour), now.Add(time.Hour), nil)
+	client := issueCert(t, "client", false, now.Add(-time.Hour), now.Add(time.Hour), &ca)
+	renewed := issueCert(t, "client", false, now.Add(-time.Hour), now.Add(time.Hour), &ca)
+	thingID := generateID(t)
+
+	cases := []struct {
+		desc    string
+		update  sdk.BootstrapConfig
+		stored  sdk.BootstrapConfig
+		warning bool
+		err     error
+	}{
+		{
+			desc:   "update certificates with their key",
+			update: sdk.BootstrapConfig{ThingID: thingID, ClientCert: client.certPEM, ClientKey: client.keyPEM, CACert: ca.certPEM},
+			stored: sdk.BootstrapConfig{ThingID: thingID, ClientCert: client.certPEM, ClientKey: client.keyPEM, CACert: ca.certPEM},
+		},
+		{
+			desc:    "update key before the certificate",
+			update:  sdk.BootstrapConfig{ThingID: thingID, ClientKey: renewed.keyPEM},
+			stored:  sdk.BootstrapConfig{ThingID: thingID, ClientCert: client.certPEM, ClientKey: renewed.keyPEM, CACert: ca.certPEM},
+			warning: true,
+		},
+		{
+			desc:   "update certificate after the key",
+			update: sdk.BootstrapConfig{ThingID: thingID, ClientCert: renewed.certPEM},
+			stored: sdk.BootstrapConfig{ThingID: thingID, ClientCert: renewed.certPEM, ClientKey: renewed.keyPEM, CACert: ca.certPEM},
+		},
+		{
+			desc:   "update invalid certificate",
+			update: sdk.BootstrapConfig{ThingID: thingID, CACert: "not a certificate"},
+			err:    ui.ErrInvalidCert,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("UpdateBootstrapCerts", tc.update.ThingID, tc.update.ClientCert, tc.update.ClientKey, tc.update.CACert, validSession.Token).Return(tc.stored, nil)
+			sdkCall1 := sdkmock.On("ViewBootstrap", thingID, validSession.Token).Return(tc.stored, nil)
+			sdkCall2 := sdkmock.On("Thing", thingID, validSession.Token).Return(validThing, nil)
+			err := svc.UpdateBootstrapCerts(validSession.Token, tc.update)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				res, err := svc.ViewBootstrap(validSession, thingID)
+				assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+				assert.Equal(t, tc.warning, strings.Contains(string(res), "The client key does not match the client certificate."))
+			}
+			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
+		})
+	}
+}
+
 func TestDeleteBootstrap(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2894,7 +3064,7 @@ func TestDeleteBootstrap(t *testing.T) {
 }
 
 func TestUpdateBootstrapState(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := []struct {
@@ -2928,14 +3098,19 @@ func TestUpdateBootstrapState(t *testing.T) {
 }
 
 func TestViewBootstrap(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	now := time.Now()
+	ca := issueCert(t, "ca", true, now.Add(-time.Hour), now.Add(time.Hour), nil)
+	client := issueCert(t, "client", false, now.Add(-time.Hour), now.Add(time.Hour), &ca)
+
 	cases := []struct {
 		desc         string
 		conf         sdk.BootstrapConfig
 		errBootstrap errors.SDKError
 		errThing     errors.SDKError
+		contains     []string
 		err          error
 	}{
 		{
@@ -2959,6 +3134,15 @@ func TestViewBootstrap(t *testing.T) {
 				Channels: nil,
 			},
 		},
+		{
+			desc: "success with certificates",
+			conf: sdk.BootstrapConfig{
+				ClientCert: client.certPEM,
+				ClientKey:  ca.keyPEM,
+				CACert:     "not a certificate",
+			},
+			contains: []string{"CN=client", "does not match", "not a PEM encoded certificate"},
+		},
 		{
 			desc: "success with invalid channel",
 			conf: sdk.BootstrapConfig{
@@ -2982,11 +3166,14 @@ func TestViewBootstrap(t *testing.T) {
 		t.Run(tc.desc, func(t *testing.T) {
 			sdkCall := sdkmock.On("ViewBootstrap", id, validSession.Token).Return(tc.conf, tc.errBootstrap)
 			sdkCall1 := sdkmock.On("Thing", id, validSession.Token).Return(validThing, tc.errThing)
-			_, err := svc.ViewBootstrap(validSession, id)
+			res, err := svc.ViewBootstrap(validSession, id)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
 			if err == nil {
 				sdkCall.Parent.AssertCalled(t, "ViewBootstrap", id, validSession.Token)
 				sdkCall1.Parent.AssertCalled(t, "Thing", id, validSession.Token)
+				for _, c := range tc.contains {
+					assert.Contains(t, string(res), c)
+				}
 			}
 			sdkCall.Unset()
 			sdkCall1.Unset()
@@ -2995,227 +3182,623 @@ func TestViewBootstrap(t *testing.T) {
 }
 
 func TestGetRemoteTerminal(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	thingID := generateID(t)
+	channels := []sdk.Channel{
+		{ID: generateID(t), Name: "data", Metadata: sdk.Metadata{"type": "data"}},
+		{ID: generateID(t), Name: "control"},
+	}
+
 	cases := []struct {
-		desc string
-		prov oauth2.Provider
-		err  error
+		desc   string
+		cfg    sdk.BootstrapConfig
+		sdkerr errors.SDKError
+		err    error
 	}{
 		{
-			desc: "success",
-			prov: provider,
+			desc: "get remote terminal successfully",
+			cfg:  sdk.BootstrapConfig{ThingID: thingID, Channels: channels},
+			err:  nil,
+		},
+		{
+			desc: "get remote terminal without channels",
+			cfg:  sdk.BootstrapConfig{ThingID: thingID},
 			err:  nil,
 		},
+		{
+			desc: "get remote terminal with invalid bootstrap content",
+			cfg:  sdk.BootstrapConfig{ThingID: thingID, Content: "invalid", Channels: channels},
+			err:  ui.ErrJSONUnmarshal,
+		},
+		{
+			desc:   "get remote terminal with sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedRetreive,
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			page, err := svc.GetRemoteTerminal(validSession, "test")
+			sdkCall := sdkmock.On("ViewBootstrap", thingID, validSession.Token).Return(tc.cfg, tc.sdkerr)
+			page, err := svc.GetRemoteTerminal(validSession, thingID)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
 			if err == nil {
 				assert.NotEmpty(t, page, "expected page to be not empty")
 			}
+			sdkCall.Unset()
 		})
 	}
 }
 
-func TestGetEntities(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestOpenTerminal(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	thingID := generateID(t)
+	channels := []sdk.Channel{{ID: generateID(t)}}
+	// Nothing listens on port 1, so connecting to the broker fails.
+	content := `{"agent":{"mqtt":{"url":"tcp://127.0.0.1:1"}}}`
+
 	cases := []struct {
-		desc   string
-		entity string
-		sdkerr errors.SDKError
-		err    error
+		desc      string
+		policy    ui.TerminalPolicy
+		policyErr error
+		cfg       sdk.BootstrapConfig
+		channelID string
+		sdkerr    errors.SDKError
+		err       error
 	}{
 		{
-			desc:   "success fetching users",
-			entity: "users",
-			sdkerr: nil,
-			err:    nil,
-		},
-		{
-			desc:   "sdk error when fetching users",
-			entity: "users",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedRetreive,
-		},
-		{
-			desc:   "success fetching groups",
-			entity: "groups",
-			sdkerr: nil,
-			err:    nil,
-		},
-		{
-			desc:   "sdk error when fetching groups",
-			entity: "groups",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedRetreive,
+			desc:   "open terminal limited to domain administrators",
+			policy: ui.TerminalPolicy{AdminOnly: true},
+			err:    ui.ErrTerminalForbidden,
 		},
 		{
-			desc:   "success fetching things",
-			entity: "things",
-			sdkerr: nil,
-			err:    nil,
+			desc:      "open terminal with repository error",
+			policyErr: fmt.Errorf("failed to retrieve"),
+			err:       ui.ErrFailedRetreive,
 		},
 		{
-			desc:   "sdk error when fetching things",
-			entity: "things",
+			desc:   "open terminal with sdk error",
 			sdkerr: sdkerr,
 			err:    ui.ErrFailedRetreive,
 		},
 		{
-			desc:   "success fetching channels",
-			entity: "channels",
-			sdkerr: nil,
-			err:    nil,
+			desc: "open terminal with invalid bootstrap content",
+			cfg:  sdk.BootstrapConfig{ThingID: thingID, Content: "invalid", Channels: channels},
+			err:  ui.ErrJSONUnmarshal,
 		},
 		{
-			desc:   "sdk error when fetching channels",
-			entity: "channels",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedRetreive,
+			desc: "open terminal without channels",
+			cfg:  sdk.BootstrapConfig{ThingID: thingID, Content: content, Channels: []sdk.Channel{}},
+			err:  ui.ErrTerminalNoChannel,
 		},
 		{
-			desc:   "success fetching members",
-			entity: "members",
-			sdkerr: nil,
-			err:    nil,
+			desc:      "open terminal on channel not connected to the config",
+			cfg:       sdk.BootstrapConfig{ThingID: thingID, Content: content, Channels: channels},
+			channelID: generateID(t),
+			err:       ui.ErrTerminalChannel,
 		},
 		{
-			desc:   "sdk error when fetching members",
-			entity: "members",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedRetreive,
+			desc: "open terminal without broker url",
+			cfg:  sdk.BootstrapConfig{ThingID: thingID, Content: `{"agent":{}}`, Channels: channels},
+			err:  ui.ErrTerminalBroker,
 		},
 		{
-			desc:   "success fetching domains",
-			entity: "domains",
-			sdkerr: nil,
-			err:    nil,
+			desc: "open terminal with empty bootstrap content",
+			cfg:  sdk.BootstrapConfig{ThingID: thingID, Content: "", Channels: channels},
+			err:  ui.ErrTerminalBroker,
 		},
 		{
-			desc:   "sdk error when fetching domains",
-			entity: "domains",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedRetreive,
+			desc: "open terminal with unreachable broker",
+			cfg:  sdk.BootstrapConfig{ThingID: thingID, Content: content, Channels: channels},
+			err:  ui.ErrTerminalBroker,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			page := sdk.PageMetadata{
-				Offset:     0,
-				Limit:      10,
-				Name:       name,
-				Permission: "view",
-			}
-			switch tc.entity {
-			case "users":
-				sdkCall := sdkmock.On("Users", page, accessToken).Return(validUsersPage, tc.sdkerr)
-				defer sdkCall.Unset()
-			case "groups":
-				sdkCall := sdkmock.On("Groups", page, accessToken).Return(validGroupsPage, tc.sdkerr)
-				defer sdkCall.Unset()
-			case "things":
-				sdkCall := sdkmock.On("Things", page, accessToken).Return(validThingsPage, tc.sdkerr)
-				defer sdkCall.Unset()
-			case "channels":
-				sdkCall := sdkmock.On("Channels", page, accessToken).Return(validChannelsPage, tc.sdkerr)
-				defer sdkCall.Unset()
-			case "members":
-				sdkCall := sdkmock.On("ListDomainUsers", id, page, accessToken).Return(validUsersPage, tc.sdkerr)
-				defer sdkCall.Unset()
-			case "domains":
-				sdkCall := sdkmock.On("Domains", page, accessToken).Return(validDomainsPage, tc.sdkerr)
-				defer sdkCall.Unset()
-			}
-			_, err := svc.GetEntities(accessToken, tc.entity, name, id, "view", 1, 10)
+			repoCall := policyRepo.On("Retrieve", context.Background(), validSession.Domain.ID).Return(tc.policy, tc.policyErr)
+			sdkCall := sdkmock.On("ViewBootstrap", thingID, validSession.Token).Return(tc.cfg, tc.sdkerr)
+			term, err := svc.OpenTerminal(context.Background(), validSession, thingID, tc.channelID)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			assert.Nil(t, term, "expected no terminal on error")
+			repoCall.Unset()
+			sdkCall.Unset()
 		})
 	}
 }
 
-func TestErrorPage(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestProcessTerminalCommand(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	// Refused commands never reach the agent, so the terminal needs no client.
+	term := &ui.Terminal{ThingID: generateID(t)}
+
 	cases := []struct {
-		desc    string
-		message string
-		url     string
-		err     error
+		desc      string
+		policy    ui.TerminalPolicy
+		policyErr error
+		saveErr   error
+		saved     bool
+		err       error
 	}{
 		{
-			desc:    "success",
-			message: "test",
-			url:     "test",
-			err:     nil,
+			desc:   "process command refused by policy",
+			policy: ui.TerminalPolicy{Deny: []string{"reboot"}},
+			saved:  true,
+			err:    ui.ErrTerminalForbidden,
 		},
 		{
-			desc:    "with empty message and url",
-			message: "",
-			url:     "",
-			err:     nil,
+			desc:    "process command refused by policy with audit error",
+			policy:  ui.TerminalPolicy{Deny: []string{"reboot"}},
+			saveErr: fmt.Errorf("failed to save"),
+			saved:   true,
+			err:     ui.ErrFailedAudit,
+		},
+		{
+			desc:      "process command with policy repository error",
+			policyErr: fmt.Errorf("failed to retrieve"),
+			err:       ui.ErrFailedRetreive,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			page, err := svc.ErrorPage(tc.message, tc.url)
+			repoCall := policyRepo.On("Retrieve", context.Background(), validSession.Domain.ID).Return(tc.policy, tc.policyErr)
+			repoCall1 := auditRepo.On("Save", context.Background(), mock.Anything).Return(tc.saveErr)
+			res, err := svc.ProcessTerminalCommand(context.Background(), validSession, term, "reboot")
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				assert.NotEmpty(t, page, "expected page to be not empty")
+			assert.Empty(t, res)
+			if tc.saved {
+				record := repoCall1.Parent.Calls[len(repoCall1.Parent.Calls)-1].Arguments.Get(1).(ui.TerminalCommand)
+				assert.Equal(t, term.ThingID, record.ThingID)
+				assert.Contains(t, record.Error, ui.ErrTerminalForbidden.Error())
 			}
+			repoCall.Unset()
+			repoCall1.Unset()
 		})
 	}
 }
 
-func TestCreateDomain(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestTerminalAudit(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	adminSession := validSession
+	adminSession.Domain.Permissions = []string{"admin", "view", "edit"}
+	otherUser := generateID(t)
+	page := ui.TerminalCommandPage{
+		Total: 1,
+		Commands: []ui.TerminalCommand{
+			{
+				ID:           generateID(t),
+				UserID:       validSession.User.ID,
+				UserIdentity: validSession.User.Identity,
+				DomainID:     validSession.Domain.ID,
+				ThingID:      generateID(t),
+				Command:      "uptime",
+				Response:     "up 1 day",
+				Duration:     time.Second,
+				CreatedAt:    time.Now().UTC(),
+			},
+		},
+	}
+
 	cases := []struct {
-		desc   string
-		sdkerr errors.SDKError
-		err    error
+		desc    string
+		session ui.Session
+		pm      ui.TerminalCommandPageMeta
+		userID  string
+		page    ui.TerminalCommandPage
+		repoErr error
+		err     error
 	}{
 		{
-			desc:   "success",
-			sdkerr: nil,
-			err:    nil,
+			desc:    "view terminal audit as domain admin",
+			session: adminSession,
+			pm:      ui.TerminalCommandPageMeta{UserID: otherUser},
+			userID:  otherUser,
+			page:    page,
 		},
 		{
-			desc:   "sdk error",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedCreate,
+			desc:    "view terminal audit as domain member",
+			session: validSession,
+			pm:      ui.TerminalCommandPageMeta{UserID: otherUser},
+			userID:  validSession.User.ID,
+			page:    page,
+		},
+		{
+			desc:    "view terminal audit with repository error",
+			session: adminSession,
+			repoErr: fmt.Errorf("failed to retrieve"),
+			err:     ui.ErrFailedRetreive,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("CreateDomain", validDomain, accessToken).Return(validDomain, tc.sdkerr)
-			err := svc.CreateDomain(accessToken, validDomain)
+			pm := ui.TerminalCommandPageMeta{Limit: 10, DomainID: tc.session.Domain.ID, UserID: tc.userID}
+			repoCall := auditRepo.On("RetrieveAll", context.Background(), pm).Return(tc.page, tc.repoErr)
+			b, err := svc.TerminalAudit(context.Background(), tc.session, 1, 10, tc.pm)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
 			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "CreateDomain", validDomain, accessToken)
+				assert.Contains(t, string(b), "uptime")
 			}
-			sdkCall.Unset()
+			repoCall.Unset()
 		})
 	}
 }
 
-func TestListDomains(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestExportTerminalAudit(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	cmd := ui.TerminalCommand{
+		ID:           generateID(t),
+		UserID:       validSession.User.ID,
+		UserIdentity: validSession.User.Identity,
+		DomainID:     validSession.Domain.ID,
+		ThingID:      generateID(t),
+		Command:      "ls,-la",
+		Error:        "timeout",
+		Duration:     2 * time.Second,
+		CreatedAt:    time.Now().UTC(),
+	}
+
 	cases := []struct {
-		desc   string
-		sdkerr errors.SDKError
-		err    error
+		desc    string
+		page    ui.TerminalCommandPage
+		repoErr error
+		rows    int
+		err     error
+	}{
+		{
+			desc: "export terminal audit",
+			page: ui.TerminalCommandPage{Total: 1, Commands: []ui.TerminalCommand{cmd}},
+			rows: 2,
+		},
+		{
+			desc: "export empty terminal audit",
+			page: ui.TerminalCommandPage{},
+			rows: 1,
+		},
+		{
+			desc:    "export terminal audit with repository error",
+			repoErr: fmt.Errorf("failed to retrieve"),
+			err:     ui.ErrFailedRetreive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repoCall := auditRepo.On("RetrieveAll", mock.Anything, mock.Anything).Return(tc.page, tc.repoErr)
+			b, err := svc.ExportTerminalAudit(context.Background(), validSession, ui.TerminalCommandPageMeta{})
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				records, err := csv.NewReader(bytes.NewReader(b)).ReadAll()
+				require.Nil(t, err, fmt.Sprintf("unexpected csv error: %s", err))
+				assert.Len(t, records, tc.rows)
+				assert.Equal(t, "created_at", records[0][0])
+				if tc.rows > 1 {
+					assert.Equal(t, []string{cmd.CreatedAt.Format(time.RFC3339), cmd.UserID, cmd.UserIdentity, cmd.ThingID, cmd.Command, "", cmd.Error, "2s"}, records[1])
+				}
+			}
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestBatchTerminal(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	thingID := generateID(t)
+	configs := sdk.BootstrapPage{
+		Configs: []sdk.BootstrapConfig{{ThingID: thingID, Name: "gateway", State: 1}},
+	}
+	configs.Total = 1
+
+	cases := []struct {
+		desc   string
+		sdkerr errors.SDKError
+		err    error
+	}{
+		{
+			desc: "view batch terminal",
+		},
+		{
+			desc:   "view batch terminal with sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedRetreive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("Bootstraps", sdk.PageMetadata{Limit: 100}, validSession.Token).Return(configs, tc.sdkerr)
+			b, err := svc.BatchTerminal(validSession)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				assert.Contains(t, string(b), thingID)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestRunBatchCommand(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	active := sdk.BootstrapConfig{ThingID: generateID(t), Name: "active-gateway", State: 1}
+	inactive := sdk.BootstrapConfig{ThingID: generateID(t), Name: "inactive-gateway"}
+	configs := sdk.BootstrapPage{Configs: []sdk.BootstrapConfig{active, inactive}}
+	configs.Total = 2
+	tagged := sdk.ThingsPage{Things: []sdk.Thing{{ID: inactive.ThingID}}}
+	tagged.Total = 1
+	// Every page holds the same configs, which is enough to go over the limit.
+	tooMany := sdk.BootstrapPage{Configs: make([]sdk.BootstrapConfig, 100)}
+	tooMany.Total = ui.MaxBatchTargets + 1
+
+	cases := []struct {
+		desc      string
+		batch     ui.BatchCommand
+		configs   sdk.BootstrapPage
+		configErr errors.SDKError
+		things    sdk.ThingsPage
+		thingsErr errors.SDKError
+		targets   []string
+		skipped   []string
+		err       error
+	}{
+		{
+			desc:    "run batch command on selected things",
+			batch:   ui.BatchCommand{ThingIDs: []string{inactive.ThingID, inactive.ThingID}},
+			configs: configs,
+			targets: []string{inactive.ThingID},
+			skipped: []string{active.ThingID},
+		},
+		{
+			desc:    "run batch command on active things",
+			batch:   ui.BatchCommand{State: ui.BatchStateActive},
+			configs: configs,
+			targets: []string{active.ThingID},
+			skipped: []string{inactive.ThingID},
+		},
+		{
+			desc:    "run batch command on tagged things",
+			batch:   ui.BatchCommand{Tag: "gateway"},
+			configs: configs,
+			things:  tagged,
+			targets: []string{inactive.ThingID},
+			skipped: []string{active.ThingID},
+		},
+		{
+			desc:    "run batch command on tagged active things",
+			batch:   ui.BatchCommand{Tag: "gateway", State: ui.BatchStateActive},
+			configs: configs,
+			things:  tagged,
+			skipped: []string{active.ThingID, inactive.ThingID},
+		},
+		{
+			desc:      "run batch command with sdk error retrieving bootstraps",
+			batch:     ui.BatchCommand{State: ui.BatchStateActive},
+			configErr: sdkerr,
+			err:       ui.ErrFailedRetreive,
+		},
+		{
+			desc:      "run batch command with sdk error retrieving things",
+			batch:     ui.BatchCommand{Tag: "gateway"},
+			configs:   configs,
+			thingsErr: sdkerr,
+			err:       ui.ErrFailedRetreive,
+		},
+		{
+			desc:    "run batch command on too many things",
+			batch:   ui.BatchCommand{State: ui.BatchStateInactive},
+			configs: tooMany,
+			err:     ui.ErrTooManyTargets,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.batch.Command = "uptime"
+			tc.batch.Timeout = time.Second
+			tc.batch.Concurrency = 2
+			sdkCall := sdkmock.On("Bootstraps", mock.Anything, validSession.Token).Return(tc.configs, tc.configErr)
+			sdkCall1 := sdkmock.On("Things", mock.Anything, validSession.Token).Return(tc.things, tc.thingsErr)
+			// Without a bootstrap config the terminal cannot be opened, so every
+			// target is reported as failed.
+			sdkCall2 := sdkmock.On("ViewBootstrap", mock.Anything, validSession.Token).Return(sdk.BootstrapConfig{}, sdkerr)
+			repoCall := policyRepo.On("Retrieve", mock.Anything, validSession.Domain.ID).Return(ui.TerminalPolicy{}, nil)
+			b, err := svc.RunBatchCommand(context.Background(), validSession, tc.batch)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				res := string(b)
+				assert.Contains(t, res, fmt.Sprintf("%d targets", len(tc.targets)))
+				assert.Contains(t, res, fmt.Sprintf("%d failed", len(tc.targets)))
+				for _, id := range tc.targets {
+					assert.Contains(t, res, id)
+					sdkCall2.Parent.AssertCalled(t, "ViewBootstrap", id, validSession.Token)
+				}
+				for _, id := range tc.skipped {
+					assert.NotContains(t, res, id)
+				}
+			}
+			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestGetEntities(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		entity string
+		sdkerr errors.SDKError
+		err    error
+	}{
+		{
+			desc:   "success fetching users",
+			entity: "users",
+			sdkerr: nil,
+			err:    nil,
+		},
+		{
+			desc:   "sdk error when fetching users",
+			entity: "users",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedRetreive,
+		},
+		{
+			desc:   "success fetching groups",
+			entity: "groups",
+			sdkerr: nil,
+			err:    nil,
+		},
+		{
+			desc:   "sdk error when fetching groups",
+			entity: "groups",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedRetreive,
+		},
+		{
+			desc:   "success fetching things",
+			entity: "things",
+			sdkerr: nil,
+			err:    nil,
+		},
+		{
+			desc:   "sdk error when fetching things",
+			entity: "things",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedRetreive,
+		},
+		{
+			desc:   "success fetching channels",
+			entity: "channels",
+			sdkerr: nil,
+			err:    nil,
+		},
+		{
+			desc:   "sdk error when fetching channels",
+			entity: "channels",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedRetreive,
+		},
+		{
+			desc:   "success fetching members",
+			entity: "members",
+			sdkerr: nil,
+			err:    nil,
+		},
+		{
+			desc:   "sdk error when fetching members",
+			entity: "members",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedRetreive,
+		},
+		{
+			desc:   "success fetching domains",
+			entity: "domains",
+			sdkerr: nil,
+			err:    nil,
+		},
+		{
+			desc:   "sdk error when fetching domains",
+			entity: "domains",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedRetreive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			page := sdk.PageMetadata{
+				Offset:     0,
+				Limit:      10,
+				Name:       name,
+				Permission: "view",
+			}
+			switch tc.entity {
+			case "users":
+				sdkCall := sdkmock.On("Users", page, accessToken).Return(validUsersPage, tc.sdkerr)
+				defer sdkCall.Unset()
+			case "groups":
+				sdkCall := sdkmock.On("Groups", page, accessToken).Return(validGroupsPage, tc.sdkerr)
+				defer sdkCall.Unset()
+			case "things":
+				sdkCall := sdkmock.On("Things", page, accessToken).Return(validThingsPage, tc.sdkerr)
+				defer sdkCall.Unset()
+			case "channels":
+				sdkCall := sdkmock.On("Channels", page, accessToken).Return(validChannelsPage, tc.sdkerr)
+				defer sdkCall.Unset()
+			case "members":
+				sdkCall := sdkmock.On("ListDomainUsers", id, page, accessToken).Return(validUsersPage, tc.sdkerr)
+				defer sdkCall.Unset()
+			case "domains":
+				sdkCall := sdkmock.On("Domains", page, accessToken).Return(validDomainsPage, tc.sdkerr)
+				defer sdkCall.Unset()
+			}
+			_, err := svc.GetEntities(accessToken, tc.entity, name, id, "view", 1, 10)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+		})
+	}
+}
+
+func TestErrorPage(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		message string
+		url     string
+		err     error
+	}{
+		{
+			desc:    "success",
+			message: "test",
+			url:     "test",
+			err:     nil,
+		},
+		{
+			desc:    "with empty message and url",
+			message: "",
+			url:     "",
+			err:     nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			page, err := svc.ErrorPage(tc.message, tc.url)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				assert.NotEmpty(t, page, "expected page to be not empty")
+			}
+		})
+	}
+}
+
+func TestCreateDomain(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		sdkerr errors.SDKError
+		err    error
 	}{
 		{
 			desc:   "success",
@@ -3223,562 +3806,3464 @@ func TestListDomains(t *testing.T) {
 			err:    nil,
 		},
 		{
-			desc:   "sdk error",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedRetreive,
+			desc:   "sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedCreate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("CreateDomain", validDomain, accessToken).Return(validDomain, tc.sdkerr)
+			err := svc.CreateDomain(accessToken, validDomain)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "CreateDomain", validDomain, accessToken)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestListDomains(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		sdkerr errors.SDKError
+		err    error
+	}{
+		{
+			desc:   "success",
+			sdkerr: nil,
+			err:    nil,
+		},
+		{
+			desc:   "sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedRetreive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("Domains", sdk.PageMetadata{Offset: 0, Limit: 10, Status: "enabled"}, validSession.Token).Return(validDomainsPage, tc.sdkerr)
+			_, err := svc.ListDomains(validSession, "enabled", 1, 10)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "Domains", sdk.PageMetadata{Offset: 0, Limit: 10, Status: "enabled"}, validSession.Token)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestDomain(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc           string
+		errDomain      errors.SDKError
+		errPermissions errors.SDKError
+		errRequirement error
+		errPolicy      error
+		err            error
+	}{
+		{
+			desc: "success",
+		},
+		{
+			desc:      "sdk error on fetching domain",
+			errDomain: sdkerr,
+			err:       ui.ErrFailedRetreive,
+		},
+		{
+			desc:           "sdk error on fetching domain permission",
+			errPermissions: sdkerr,
+			err:            ui.ErrFailedRetreive,
+		},
+		{
+			desc:           "repository error on fetching two factor requirement",
+			errRequirement: fmt.Errorf("failed to retrieve"),
+			err:            ui.ErrFailedRetreive,
+		},
+		{
+			desc:      "repository error on fetching terminal policy",
+			errPolicy: fmt.Errorf("failed to retrieve"),
+			err:       ui.ErrFailedRetreive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("Domain", validSession.Domain.ID, validSession.Token).Return(validDomain, tc.errDomain)
+			sdkCall1 := sdkmock.On("DomainPermissions", validSession.Domain.ID, validSession.Token).Return(validDomain, tc.errPermissions)
+			repoCall := twoFactorRepo.On("RetrieveDomainRequirement", context.Background(), validSession.Domain.ID).Return(false, tc.errRequirement)
+			repoCall1 := policyRepo.On("Retrieve", context.Background(), validSession.Domain.ID).Return(ui.TerminalPolicy{DomainID: validSession.Domain.ID}, tc.errPolicy)
+			_, err := svc.Domain(context.Background(), validSession)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "Domain", validSession.Domain.ID, validSession.Token)
+			}
+			sdkCall.Unset()
+			sdkCall1.Unset()
+			repoCall.Unset()
+			repoCall1.Unset()
+		})
+	}
+}
+
+func TestUpdateDomain(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		sdkerr errors.SDKError
+		err    error
+	}{
+		{
+			desc:   "success",
+			sdkerr: nil,
+			err:    nil,
+		},
+		{
+			desc:   "sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedUpdate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("UpdateDomain", validDomain, validSession.Token).Return(validDomain, tc.sdkerr)
+			err := svc.UpdateDomain(validSession.Token, validDomain)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "UpdateDomain", validDomain, validSession.Token)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestEnableDomain(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		sdkerr errors.SDKError
+		err    error
+	}{
+		{
+			desc:   "success",
+			sdkerr: nil,
+			err:    nil,
+		},
+		{
+			desc:   "sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedEnable,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("EnableDomain", validDomain.ID, validSession.Token).Return(tc.sdkerr)
+			err := svc.EnableDomain(validSession.Token, validDomain.ID)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "EnableDomain", validDomain.ID, validSession.Token)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestDisableDomain(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		sdkerr errors.SDKError
+		err    error
+	}{
+		{
+			desc:   "success",
+			sdkerr: nil,
+			err:    nil,
+		},
+		{
+			desc:   "sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedDisable,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("DisableDomain", validDomain.ID, validSession.Token).Return(tc.sdkerr)
+			err := svc.DisableDomain(validSession.Token, validDomain.ID)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "DisableDomain", validDomain.ID, validSession.Token)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestAssignMember(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		sdkerr errors.SDKError
+		err    error
+	}{
+		{
+			desc:   "success",
+			sdkerr: nil,
+			err:    nil,
+		},
+		{
+			desc:   "sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedAssign,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("AddUserToDomain", validDomain.ID, validUsersRelationReq, validSession.Token).Return(tc.sdkerr)
+			err := svc.AssignMember(validSession.Token, validDomain.ID, validUsersRelationReq)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "AddUserToDomain", validDomain.ID, validUsersRelationReq, validSession.Token)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestUnassignMember(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		sdkerr errors.SDKError
+		err    error
+	}{
+		{
+			desc:   "success",
+			sdkerr: nil,
+			err:    nil,
+		},
+		{
+			desc:   "sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedUnassign,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("RemoveUserFromDomain", validDomain.ID, validUsersRelationReq, validSession.Token).Return(tc.sdkerr)
+			err := svc.UnassignMember(validSession.Token, validDomain.ID, validUsersRelationReq)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "RemoveUserFromDomain", validDomain.ID, validUsersRelationReq, validSession.Token)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestViewMember(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc      string
+		usersPage sdk.UsersPage
+		sdkerr    errors.SDKError
+		err       error
+	}{
+		{
+			desc:      "success",
+			usersPage: sdk.UsersPage{Users: []sdk.User{validUser}},
+			sdkerr:    nil,
+			err:       nil,
+		},
+		{
+			desc:   "sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedRetreive,
+		},
+		{
+			desc: "empty users",
+			err:  ui.ErrFailedRetreive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("Users", sdk.PageMetadata{Identity: "test", Limit: 1}, validSession.Token).Return(tc.usersPage, tc.sdkerr)
+			_, err := svc.ViewMember(validSession, "test")
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "Users", sdk.PageMetadata{Identity: "test", Limit: 1}, validSession.Token)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestMembers(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		sdkerr errors.SDKError
+		err    error
+	}{
+		{
+			desc: "success",
+		},
+		{
+			desc:   "sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedRetreive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("ListDomainUsers", validSession.Domain.ID, sdk.PageMetadata{Offset: 0, Limit: 10}, validSession.Token).Return(validUsersPage, tc.sdkerr)
+			_, err := svc.Members(validSession, 1, 10)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "ListDomainUsers", validSession.Domain.ID, sdk.PageMetadata{Offset: 0, Limit: 10}, validSession.Token)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestSendInvitation(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		sdkerr errors.SDKError
+		err    error
+	}{
+		{
+			desc: "success",
+		},
+		{
+			desc:   "sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedSend,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("SendInvitation", sdk.Invitation{}, validSession.Token).Return(tc.sdkerr)
+			err := svc.SendInvitation(validSession.Token, sdk.Invitation{})
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "SendInvitation", sdk.Invitation{}, validSession.Token)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestInvitations(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		sdkerr errors.SDKError
+		err    error
+	}{
+		{
+			desc: "success",
+		},
+		{
+			desc:   "sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedRetreive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			page := sdk.PageMetadata{
+				Offset:   0,
+				Limit:    10,
+				DomainID: "test",
+				State:    "pending",
+			}
+			sdkCall := sdkmock.On("Invitations", page, validSession.Token).Return(sdk.InvitationPage{}, tc.sdkerr)
+			_, err := svc.Invitations(validSession, "test", 1, 10)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "Invitations", page, validSession.Token)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		sdkerr errors.SDKError
+		err    error
+	}{
+		{
+			desc: "success",
+		},
+		{
+			desc:   "sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedAccept,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("AcceptInvitation", "test", validSession.Token).Return(tc.sdkerr)
+			err := svc.AcceptInvitation(validSession.Token, "test")
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "AcceptInvitation", "test", validSession.Token)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestDeleteInvitation(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		sdkerr errors.SDKError
+		err    error
+	}{
+		{
+			desc: "success",
+		},
+		{
+			desc:   "sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedDelete,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("DeleteInvitation", "test", "test", validSession.Token).Return(tc.sdkerr)
+			err := svc.DeleteInvitation(validSession.Token, "test", "test")
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "DeleteInvitation", "test", "test", validSession.Token)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestCreateDashboard(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc           string
+		errUserProfile errors.SDKError
+		errCreate      error
+		err            error
+	}{
+		{
+			desc: "success",
+		},
+		{
+			desc:           "sdk error",
+			errUserProfile: sdkerr,
+			err:            ui.ErrFailedRetrieveUserID,
+		},
+		{
+			desc:      "failed to create dashboard",
+			errCreate: fmt.Errorf("failed to create dashboard"),
+			err:       ui.ErrFailedDashboardSave,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("UserProfile", validSession.Token).Return(validUser, tc.errUserProfile)
+			repoCall := repo.On("Create", context.Background(), mock.Anything).Return(ui.Dashboard{}, tc.errCreate)
+			_, err := svc.CreateDashboard(context.Background(), validSession.Token, validDashboardReq)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "UserProfile", validSession.Token)
+				repoCall.Parent.AssertCalled(t, "Create", context.Background(), mock.Anything)
+			}
+			sdkCall.Unset()
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestViewDashboard(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc           string
+		errUserProfile errors.SDKError
+		errView        error
+		err            error
+	}{
+		{
+			desc: "success",
+		},
+		{
+			desc:           "sdk error",
+			errUserProfile: sdkerr,
+			err:            ui.ErrFailedRetrieveUserID,
+		},
+		{
+			desc:    "failed to view dashboard",
+			errView: fmt.Errorf("failed to view dashboard"),
+			err:     ui.ErrFailedDashboardRetrieve,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("UserProfile", validSession.Token).Return(validUser, tc.errUserProfile)
+			repoCall := repo.On("Retrieve", context.Background(), "test", mock.Anything).Return(ui.Dashboard{}, tc.errView)
+			_, err := svc.ViewDashboard(context.Background(), validSession, "test")
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "UserProfile", validSession.Token)
+				repoCall.Parent.AssertCalled(t, "Retrieve", context.Background(), "test", mock.Anything)
+			}
+			sdkCall.Unset()
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestListDashboards(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc           string
+		errUserProfile errors.SDKError
+		errRetrieve    error
+		err            error
+	}{
+		{
+			desc: "success",
+		},
+		{
+			desc:           "sdk error",
+			errUserProfile: sdkerr,
+			err:            ui.ErrFailedRetrieveUserID,
+		},
+		{
+			desc:        "failed to retrieve dashboard",
+			errRetrieve: fmt.Errorf("failed to retrieve dashboard"),
+			err:         ui.ErrFailedRetreive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			page := ui.DashboardPageMeta{
+				Offset:    0,
+				Limit:     10,
+				CreatedBy: validUser.ID,
+			}
+			sdkCall := sdkmock.On("UserProfile", validSession.Token).Return(validUser, tc.errUserProfile)
+			repoCall := repo.On("RetrieveAll", context.Background(), page).Return(ui.DashboardPage{}, tc.errRetrieve)
+			_, err := svc.ListDashboards(context.Background(), validSession.Token, 1, 10)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "UserProfile", validSession.Token)
+				repoCall.Parent.AssertCalled(t, "RetrieveAll", context.Background(), page)
+			}
+			sdkCall.Unset()
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestDashboards(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		prov oauth2.Provider
+		err  error
+	}{
+		{
+			desc: "success",
+			prov: provider,
+			err:  nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			page, err := svc.Dashboards(validSession)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				assert.NotEmpty(t, page, "expected page to be not empty")
+			}
+		})
+	}
+}
+
+func TestUpdateDashboard(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc           string
+		errUserProfile errors.SDKError
+		errUpdate      error
+		err            error
+	}{
+		{
+			desc: "success",
+		},
+		{
+			desc:           "sdk error",
+			errUserProfile: sdkerr,
+			err:            ui.ErrFailedRetrieveUserID,
+		},
+		{
+			desc:      "failed to update dashboard",
+			errUpdate: fmt.Errorf("failed to update dashboard"),
+			err:       ui.ErrFailedDashboardUpdate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("UserProfile", validSession.Token).Return(validUser, tc.errUserProfile)
+			repoCall := repo.On("Update", context.Background(), "test", validUser.ID, validDashboardReq).Return(tc.errUpdate)
+			err := svc.UpdateDashboard(context.Background(), validSession.Token, "test", validDashboardReq)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "UserProfile", validSession.Token)
+				repoCall.Parent.AssertCalled(t, "Update", context.Background(), "test", validUser.ID, validDashboardReq)
+			}
+			sdkCall.Unset()
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestDeleteDashboard(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc           string
+		errUserProfile errors.SDKError
+		errDelete      error
+		err            error
+	}{
+		{
+			desc: "success",
+		},
+		{
+			desc:           "sdk error",
+			errUserProfile: sdkerr,
+			err:            ui.ErrFailedRetrieveUserID,
+		},
+		{
+			desc:      "failed to delete dashboard",
+			errDelete: fmt.Errorf("failed to delete dashboard"),
+			err:       ui.ErrFailedDashboardDelete,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("UserProfile", validSession.Token).Return(validUser, tc.errUserProfile)
+			repoCall := repo.On("Delete", context.Background(), "test", validUser.ID).Return(tc.errDelete)
+			err := svc.DeleteDashboard(context.Background(), validSession.Token, "test")
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				sdkCall.Parent.AssertCalled(t, "UserProfile", validSession.Token)
+				repoCall.Parent.AssertCalled(t, "Delete", context.Background(), "test", validUser.ID)
+			}
+			sdkCall.Unset()
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestPersonalTokens(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	provider.On("IsEnabled").Return(true)
+	provider.On("Name").Return("test")
+	provider.On("Icon").Return("fa-test")
+	sdkmock.On("Health", "users").Return(sdk.HealthInfo{}, nil)
+	sdkmock.On("Health", "things").Return(sdk.HealthInfo{}, nil)
+	sdkmock.On("Health", "bootstrap").Return(sdk.HealthInfo{}, nil)
+
+	validPage := ui.PersonalTokenPage{
+		Total: 1,
+		Tokens: []ui.PersonalToken{
+			{
+				ID:        generateID(t),
+				UserID:    validSession.User.ID,
+				Name:      namesgen.Generate(),
+				Scopes:    []ui.TokenScope{ui.EntitiesReadScope},
+				CreatedAt: time.Now(),
+			},
+		},
+	}
+
+	cases := []struct {
+		desc        string
+		page        ui.PersonalTokenPage
+		retrieveErr error
+		err         error
+	}{
+		{
+			desc: "list personal tokens successfully",
+			page: validPage,
+		},
+		{
+			desc:        "list personal tokens with repository error",
+			retrieveErr: fmt.Errorf("failed to retrieve"),
+			err:         ui.ErrFailedRetreive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			pgm := ui.PersonalTokenPageMeta{
+				Offset: 0,
+				Limit:  10,
+				UserID: validSession.User.ID,
+			}
+			repoCall := tokenRepo.On("RetrieveAll", context.Background(), pgm).Return(tc.page, tc.retrieveErr)
+			page, err := svc.PersonalTokens(context.Background(), validSession, 1, 10)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				assert.NotEmpty(t, page, "expected page to be not empty")
+			}
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestCreatePersonalToken(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	ptReq := ui.PersonalTokenReq{
+		Name:      namesgen.Generate(),
+		Scopes:    []ui.TokenScope{ui.EntitiesReadScope, ui.DashboardsReadScope},
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	login := sdk.Login{
+		Identity: validSession.User.Identity,
+		Secret:   password,
+		DomainID: validSession.Domain.ID,
+	}
+
+	cases := []struct {
+		desc      string
+		token     sdk.Token
+		tokenErr  errors.SDKError
+		createErr error
+		err       error
+	}{
+		{
+			desc:  "create personal token successfully",
+			token: sdk.Token{AccessToken: accessToken, RefreshToken: accessToken},
+		},
+		{
+			desc:     "create personal token with invalid password",
+			tokenErr: sdkerr,
+			err:      ui.ErrToken,
+		},
+		{
+			desc:      "create personal token with repository error",
+			token:     sdk.Token{AccessToken: accessToken, RefreshToken: accessToken},
+			createErr: fmt.Errorf("failed to create"),
+			err:       ui.ErrFailedPersonalToken,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("CreateToken", login).Return(tc.token, tc.tokenErr)
+			repoCall := tokenRepo.On("Create", context.Background(), mock.Anything).Return(
+				func(_ context.Context, pt ui.PersonalToken) (ui.PersonalToken, error) {
+					return pt, tc.createErr
+				})
+			res, err := svc.CreatePersonalToken(context.Background(), validSession, password, ptReq)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				var data struct {
+					Token         string           `json:"token"`
+					PersonalToken ui.PersonalToken `json:"personal_token"`
+				}
+				err := json.Unmarshal(res, &data)
+				require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+				assert.True(t, strings.HasPrefix(data.Token, ui.PersonalTokenPrefix), "expected token to have personal token prefix")
+				assert.Equal(t, ptReq.Name, data.PersonalToken.Name)
+				assert.Equal(t, ptReq.Scopes, data.PersonalToken.Scopes)
+				pt := repoCall.Parent.Calls[len(repoCall.Parent.Calls)-1].Arguments.Get(1).(ui.PersonalToken)
+				assert.NotContains(t, pt.Hash, data.Token, "expected token not to be stored in plain text")
+				assert.NotEqual(t, tc.token.RefreshToken, pt.RefreshToken, "expected refresh token to be encrypted")
+			}
+			sdkCall.Unset()
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestRevokePersonalToken(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc      string
+		revokeErr error
+		err       error
+	}{
+		{
+			desc: "revoke personal token successfully",
+		},
+		{
+			desc:      "revoke personal token with repository error",
+			revokeErr: fmt.Errorf("failed to revoke"),
+			err:       ui.ErrFailedRevokeToken,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repoCall := tokenRepo.On("Revoke", context.Background(), id, validSession.User.ID).Return(tc.revokeErr)
+			err := svc.RevokePersonalToken(context.Background(), validSession, id)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestAuthenticatePersonalToken(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	ptReq := ui.PersonalTokenReq{
+		Name:   namesgen.Generate(),
+		Scopes: []ui.TokenScope{ui.EntitiesReadScope},
+	}
+	login := sdk.Login{
+		Identity: validSession.User.Identity,
+		Secret:   password,
+		DomainID: validSession.Domain.ID,
+	}
+	refreshToken := strings.Repeat("r", 32)
+
+	var stored ui.PersonalToken
+	sdkCall := sdkmock.On("CreateToken", login).Return(sdk.Token{AccessToken: accessToken, RefreshToken: refreshToken}, nil)
+	repoCall := tokenRepo.On("Create", context.Background(), mock.Anything).Return(
+		func(_ context.Context, pt ui.PersonalToken) (ui.PersonalToken, error) {
+			stored = pt
+			return pt, nil
+		})
+	res, err := svc.CreatePersonalToken(context.Background(), validSession, password, ptReq)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sdkCall.Unset()
+	repoCall.Unset()
+
+	var data map[string]interface{}
+	err = json.Unmarshal(res, &data)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	token := data["token"].(string)
+
+	revoked := stored
+	revoked.Revoked = true
+	expired := stored
+	expired.ExpiresAt = time.Now().Add(-time.Hour)
+
+	cases := []struct {
+		desc        string
+		token       string
+		scope       ui.TokenScope
+		stored      ui.PersonalToken
+		retrieveErr error
+		refreshErr  errors.SDKError
+		lastUsedErr error
+		err         error
+	}{
+		{
+			desc:   "authenticate personal token successfully",
+			token:  token,
+			scope:  ui.EntitiesReadScope,
+			stored: stored,
+		},
+		{
+			desc:        "authenticate personal token with failed last use update",
+			token:       token,
+			scope:       ui.EntitiesReadScope,
+			stored:      stored,
+			lastUsedErr: fmt.Errorf("failed to update"),
+			err:         ui.ErrFailedUpdate,
+		},
+		{
+			desc:  "authenticate token without personal token prefix",
+			token: accessToken,
+			scope: ui.EntitiesReadScope,
+			err:   ui.ErrPersonalToken,
+		},
+		{
+			desc:        "authenticate unknown personal token",
+			token:       token,
+			scope:       ui.EntitiesReadScope,
+			retrieveErr: fmt.Errorf("not found"),
+			err:         ui.ErrPersonalToken,
+		},
+		{
+			desc:   "authenticate revoked personal token",
+			token:  token,
+			scope:  ui.EntitiesReadScope,
+			stored: revoked,
+			err:    ui.ErrPersonalToken,
+		},
+		{
+			desc:   "authenticate expired personal token",
+			token:  token,
+			scope:  ui.EntitiesReadScope,
+			stored: expired,
+			err:    ui.ErrPersonalTokenExpired,
+		},
+		{
+			desc:   "authenticate personal token without required scope",
+			token:  token,
+			scope:  ui.DataReadScope,
+			stored: stored,
+			err:    ui.ErrPersonalTokenScope,
+		},
+		{
+			desc:       "authenticate personal token with failed upstream refresh",
+			token:      token,
+			scope:      ui.EntitiesReadScope,
+			stored:     stored,
+			refreshErr: sdkerr,
+			err:        ui.ErrTokenRefresh,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			retrieveCall := tokenRepo.On("RetrieveByHash", context.Background(), mock.Anything).Return(tc.stored, tc.retrieveErr)
+			refreshCall := sdkmock.On("RefreshToken", sdk.Login{DomainID: validSession.Domain.ID}, refreshToken).Return(sdk.Token{AccessToken: accessToken, RefreshToken: refreshToken}, tc.refreshErr)
+			updateCall := tokenRepo.On("UpdateRefreshToken", context.Background(), stored.ID, stored.RefreshToken, mock.Anything, mock.Anything).Return(true, nil)
+			lastUsedCall := tokenRepo.On("UpdateLastUsed", context.Background(), stored.ID, mock.Anything).Return(tc.lastUsedErr)
+			session, err := svc.AuthenticatePersonalToken(context.Background(), tc.token, tc.scope)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				assert.Equal(t, accessToken, session.Token)
+				assert.Equal(t, stored.UserID, session.User.ID)
+				assert.Equal(t, stored.DomainID, session.Domain.ID)
+				updateCall.Parent.AssertCalled(t, "UpdateRefreshToken", context.Background(), stored.ID, stored.RefreshToken, mock.Anything, mock.Anything)
+				lastUsedCall.Parent.AssertCalled(t, "UpdateLastUsed", context.Background(), stored.ID, mock.Anything)
+			}
+			retrieveCall.Unset()
+			refreshCall.Unset()
+			updateCall.Unset()
+			lastUsedCall.Unset()
+		})
+	}
+}
+
+func TestAuthenticatePersonalTokenRefresh(t *testing.T) {
+	ptReq := ui.PersonalTokenReq{
+		Name:   namesgen.Generate(),
+		Scopes: []ui.TokenScope{ui.EntitiesReadScope},
+	}
+	login := sdk.Login{
+		Identity: validSession.User.Identity,
+		Secret:   password,
+		DomainID: validSession.Domain.ID,
+	}
+	refreshToken := strings.Repeat("r", 32)
+	upstreamToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("secret"))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	expiringToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp": time.Now().Add(time.Second).Unix(),
+	}).SignedString([]byte("secret"))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc      string
+		upstream  string
+		replaced  bool
+		requests  int
+		refreshes int
+	}{
+		{
+			desc:      "reuse the access token until it expires",
+			upstream:  upstreamToken,
+			replaced:  true,
+			requests:  5,
+			refreshes: 1,
+		},
+		{
+			desc:      "refresh the access token when it is about to expire",
+			upstream:  expiringToken,
+			replaced:  true,
+			requests:  3,
+			refreshes: 3,
+		},
+		{
+			desc:      "keep the refresh token replaced by another instance",
+			upstream:  upstreamToken,
+			replaced:  false,
+			requests:  2,
+			refreshes: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+			var stored ui.PersonalToken
+			sdkCall := sdkmock.On("CreateToken", login).Return(sdk.Token{AccessToken: accessToken, RefreshToken: refreshToken}, nil)
+			repoCall := tokenRepo.On("Create", context.Background(), mock.Anything).Return(
+				func(_ context.Context, pt ui.PersonalToken) (ui.PersonalToken, error) {
+					stored = pt
+					return pt, nil
+				})
+			res, err := svc.CreatePersonalToken(context.Background(), validSession, password, ptReq)
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			sdkCall.Unset()
+			repoCall.Unset()
+
+			var data map[string]interface{}
+			err = json.Unmarshal(res, &data)
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			token := data["token"].(string)
+
+			retrieveCall := tokenRepo.On("RetrieveByHash", context.Background(), mock.Anything).Return(stored, nil)
+			refreshes := 0
+			refreshCall := sdkmock.On("RefreshToken", sdk.Login{DomainID: validSession.Domain.ID}, refreshToken).Return(
+				func(sdk.Login, string) (sdk.Token, errors.SDKError) {
+					refreshes++
+					return sdk.Token{AccessToken: tc.upstream, RefreshToken: refreshToken}, nil
+				})
+			updateCall := tokenRepo.On("UpdateRefreshToken", context.Background(), stored.ID, stored.RefreshToken, mock.Anything, mock.Anything).Return(tc.replaced, nil)
+			uses := 0
+			lastUsedCall := tokenRepo.On("UpdateLastUsed", context.Background(), stored.ID, mock.Anything).Return(nil).Run(func(mock.Arguments) {
+				uses++
+			})
+			for i := 0; i < tc.requests; i++ {
+				session, err := svc.AuthenticatePersonalToken(context.Background(), token, ui.EntitiesReadScope)
+				assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+				assert.Equal(t, tc.upstream, session.Token)
+			}
+			assert.Equal(t, tc.refreshes, refreshes)
+			assert.Equal(t, tc.requests, uses, "expected every use to be recorded")
+			retrieveCall.Unset()
+			refreshCall.Unset()
+			updateCall.Unset()
+			lastUsedCall.Unset()
+		})
+	}
+}
+
+func TestRefreshPersonalTokens(t *testing.T) {
+	login := sdk.Login{
+		Identity: validSession.User.Identity,
+		Secret:   password,
+		DomainID: validSession.Domain.ID,
+	}
+	refreshToken := strings.Repeat("r", 32)
+	renewedToken := strings.Repeat("n", 32)
+	now := time.Now().UTC()
+	maxAge := 6 * time.Hour
+
+	cases := []struct {
+		desc        string
+		retrieveErr error
+		refreshErr  errors.SDKError
+		updateErr   error
+		refreshes   int
+		err         error
+	}{
+		{
+			desc:      "refresh stale personal tokens",
+			refreshes: 2,
+		},
+		{
+			desc:        "refresh personal tokens with failed retrieval",
+			retrieveErr: fmt.Errorf("failed to retrieve"),
+			err:         ui.ErrFailedRetreive,
+		},
+		{
+			desc:       "refresh personal tokens with failed upstream refresh",
+			refreshErr: sdkerr,
+			refreshes:  2,
+			err:        ui.ErrTokenRefresh,
+		},
+		{
+			desc:      "refresh personal tokens with failed update",
+			updateErr: fmt.Errorf("failed to update"),
+			refreshes: 2,
+			err:       ui.ErrFailedUpdate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+			var stale []ui.PersonalToken
+			sdkCall := sdkmock.On("CreateToken", login).Return(sdk.Token{AccessToken: accessToken, RefreshToken: refreshToken}, nil)
+			repoCall := tokenRepo.On("Create", context.Background(), mock.Anything).Return(
+				func(_ context.Context, pt ui.PersonalToken) (ui.PersonalToken, error) {
+					stale = append(stale, pt)
+					return pt, nil
+				})
+			for i := 0; i < 2; i++ {
+				_, err := svc.CreatePersonalToken(context.Background(), validSession, password, ui.PersonalTokenReq{Name: namesgen.Generate()})
+				require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			}
+			sdkCall.Unset()
+			repoCall.Unset()
+			for _, pt := range stale {
+				assert.False(t, pt.RefreshedAt.IsZero(), "expected refresh time to be set on creation")
+			}
+
+			refreshes := 0
+			retrieveCall := tokenRepo.On("RetrieveStale", context.Background(), now.Add(-maxAge)).Return(stale, tc.retrieveErr)
+			refreshCall := sdkmock.On("RefreshToken", sdk.Login{DomainID: validSession.Domain.ID}, refreshToken).Return(
+				func(sdk.Login, string) (sdk.Token, errors.SDKError) {
+					refreshes++
+					return sdk.Token{AccessToken: accessToken, RefreshToken: renewedToken}, tc.refreshErr
+				})
+			updateCall := tokenRepo.On("UpdateRefreshToken", context.Background(), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(true, tc.updateErr)
+			err = svc.RefreshPersonalTokens(context.Background(), now, maxAge)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			assert.Equal(t, tc.refreshes, refreshes)
+			if tc.err == nil {
+				for _, pt := range stale {
+					updateCall.Parent.AssertCalled(t, "UpdateRefreshToken", context.Background(), pt.ID, pt.RefreshToken, mock.Anything, mock.Anything)
+				}
+			}
+			retrieveCall.Unset()
+			refreshCall.Unset()
+			updateCall.Unset()
+		})
+	}
+}
+
+func generateID(t *testing.T) string {
+	id, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	return id
+}
+
+func TestEnrollTwoFactor(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		tf          ui.TwoFactor
+		retrieveErr error
+		saveErr     error
+		err         error
+	}{
+		{
+			desc: "enroll two factor successfully",
+		},
+		{
+			desc: "enroll two factor when already enabled",
+			tf:   ui.TwoFactor{UserID: validSession.User.ID, Enabled: true},
+			err:  ui.ErrTwoFactorEnabled,
+		},
+		{
+			desc:        "enroll two factor with repository retrieve error",
+			retrieveErr: fmt.Errorf("failed to retrieve"),
+			err:         ui.ErrFailedTwoFactor,
+		},
+		{
+			desc:    "enroll two factor with repository save error",
+			saveErr: fmt.Errorf("failed to save"),
+			err:     ui.ErrFailedTwoFactor,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repoCall := twoFactorRepo.On("Retrieve", context.Background(), validSession.User.ID).Return(tc.tf, tc.retrieveErr)
+			repoCall1 := twoFactorRepo.On("Save", context.Background(), mock.Anything).Return(tc.saveErr)
+			err := svc.EnrollTwoFactor(context.Background(), validSession)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				repoCall1.Parent.AssertCalled(t, "Save", context.Background(), mock.MatchedBy(func(tf ui.TwoFactor) bool {
+					return tf.UserID == validSession.User.ID && !tf.Enabled && tf.Secret != ""
+				}))
+			}
+			repoCall.Unset()
+			repoCall1.Unset()
+		})
+	}
+}
+
+func TestConfirmTwoFactor(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	pending, secret := pendingTwoFactor(t, svc)
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		tf      ui.TwoFactor
+		code    string
+		saveErr error
+		err     error
+	}{
+		{
+			desc: "confirm two factor successfully",
+			tf:   pending,
+			code: code,
+		},
+		{
+			desc: "confirm two factor with invalid code",
+			tf:   pending,
+			code: "000000",
+			err:  ui.ErrTwoFactorCode,
+		},
+		{
+			desc: "confirm two factor without enrolment",
+			code: code,
+			err:  ui.ErrTwoFactorEnrolment,
+		},
+		{
+			desc:    "confirm two factor with repository save error",
+			tf:      pending,
+			code:    code,
+			saveErr: fmt.Errorf("failed to save"),
+			err:     ui.ErrFailedTwoFactor,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repoCall := twoFactorRepo.On("Retrieve", context.Background(), validSession.User.ID).Return(tc.tf, nil)
+			repoCall1 := twoFactorRepo.On("Save", context.Background(), mock.Anything).Return(tc.saveErr)
+			b, err := svc.ConfirmTwoFactor(context.Background(), validSession, tc.code)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				var res struct {
+					RecoveryCodes []string `json:"recovery_codes"`
+				}
+				err = json.Unmarshal(b, &res)
+				require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+				assert.Len(t, res.RecoveryCodes, ui.RecoveryCodesCount)
+				assert.Len(t, strings.ReplaceAll(res.RecoveryCodes[0], "-", ""), 20)
+				repoCall1.Parent.AssertCalled(t, "Save", context.Background(), mock.MatchedBy(func(tf ui.TwoFactor) bool {
+					return tf.Enabled && tf.LastStep > 0 && len(tf.RecoveryCodes) == ui.RecoveryCodesCount && !slices.Contains(tf.RecoveryCodes, res.RecoveryCodes[0])
+				}))
+			}
+			repoCall.Unset()
+			repoCall1.Unset()
+		})
+	}
+}
+
+func TestVerifyTwoFactor(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	enabled, secret, codes := enabledTwoFactor(t, svc)
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc      string
+		tf        ui.TwoFactor
+		code      string
+		accepted  bool
+		acceptErr error
+		used      bool
+		useErr    error
+		use       bool
+		err       error
+	}{
+		{
+			desc:     "verify two factor with valid code",
+			tf:       enabled,
+			code:     code,
+			accepted: true,
+		},
+		{
+			desc:     "verify two factor with replayed code",
+			tf:       enabled,
+			code:     code,
+			accepted: false,
+			err:      ui.ErrTwoFactorCode,
+		},
+		{
+			desc:      "verify two factor with repository accept error",
+			tf:        enabled,
+			code:      code,
+			acceptErr: fmt.Errorf("failed to update"),
+			err:       ui.ErrFailedTwoFactor,
+		},
+		{
+			desc: "verify two factor with recovery code",
+			tf:   enabled,
+			code: codes[0],
+			used: true,
+			use:  true,
+		},
+		{
+			desc: "verify two factor with upper case recovery code without separator",
+			tf:   enabled,
+			code: strings.ToUpper(strings.ReplaceAll(codes[1], "-", "")),
+			used: true,
+			use:  true,
+		},
+		{
+			desc: "verify two factor with used recovery code",
+			tf:   enabled,
+			code: codes[0],
+			used: false,
+			use:  true,
+			err:  ui.ErrTwoFactorCode,
+		},
+		{
+			desc: "verify two factor with invalid code",
+			tf:   enabled,
+			code: "000000",
+			err:  ui.ErrTwoFactorCode,
+		},
+		{
+			desc: "verify two factor when not enabled",
+			code: code,
+			err:  ui.ErrTwoFactorCode,
+		},
+		{
+			desc:   "verify two factor with repository use error",
+			tf:     enabled,
+			code:   codes[2],
+			use:    true,
+			useErr: fmt.Errorf("failed to update"),
+			err:    ui.ErrFailedTwoFactor,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repoCall := twoFactorRepo.On("Retrieve", context.Background(), validSession.User.ID).Return(tc.tf, nil)
+			repoCall1 := twoFactorRepo.On("AcceptStep", context.Background(), validSession.User.ID, mock.Anything).Return(tc.accepted, tc.acceptErr)
+			repoCall2 := twoFactorRepo.On("UseRecoveryCode", context.Background(), validSession.User.ID, mock.Anything).Return(tc.used, tc.useErr)
+			err := svc.VerifyTwoFactor(context.Background(), validSession.User.ID, tc.code)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if tc.use {
+				repoCall2.Parent.AssertCalled(t, "UseRecoveryCode", context.Background(), validSession.User.ID, mock.MatchedBy(func(hash string) bool {
+					return slices.Contains(tc.tf.RecoveryCodes, hash)
+				}))
+			}
+			repoCall.Unset()
+			repoCall1.Unset()
+			repoCall2.Unset()
+		})
+	}
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	enabled, secret, _ := enabledTwoFactor(t, svc)
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc      string
+		code      string
+		removeErr error
+		err       error
+	}{
+		{
+			desc: "disable two factor successfully",
+			code: code,
+		},
+		{
+			desc: "disable two factor with invalid code",
+			code: "000000",
+			err:  ui.ErrTwoFactorCode,
+		},
+		{
+			desc:      "disable two factor with repository remove error",
+			code:      code,
+			removeErr: fmt.Errorf("failed to remove"),
+			err:       ui.ErrFailedTwoFactor,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repoCall := twoFactorRepo.On("Retrieve", context.Background(), validSession.User.ID).Return(enabled, nil)
+			repoCall1 := twoFactorRepo.On("Remove", context.Background(), validSession.User.ID).Return(tc.removeErr)
+			repoCall2 := twoFactorRepo.On("AcceptStep", context.Background(), validSession.User.ID, mock.Anything).Return(true, nil)
+			repoCall3 := twoFactorRepo.On("UseRecoveryCode", context.Background(), validSession.User.ID, mock.Anything).Return(false, nil)
+			err := svc.DisableTwoFactor(context.Background(), validSession, tc.code)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			repoCall.Unset()
+			repoCall1.Unset()
+			repoCall2.Unset()
+			repoCall3.Unset()
+		})
+	}
+}
+
+func TestUpdateDomainTwoFactor(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc           string
+		permissions    []string
+		errPermissions errors.SDKError
+		saveErr        error
+		err            error
+	}{
+		{
+			desc:        "require two factor as domain admin",
+			permissions: []string{"admin", "edit", "view"},
+		},
+		{
+			desc:        "require two factor without admin permission",
+			permissions: []string{"edit", "view"},
+			err:         ui.ErrTwoFactorAdmin,
+		},
+		{
+			desc:           "require two factor with sdk error",
+			errPermissions: sdkerr,
+			err:            ui.ErrFailedRetreive,
+		},
+		{
+			desc:        "require two factor with repository error",
+			permissions: []string{"admin"},
+			saveErr:     fmt.Errorf("failed to save"),
+			err:         ui.ErrFailedUpdate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("DomainPermissions", validSession.Domain.ID, validSession.Token).Return(sdk.Domain{Permissions: tc.permissions}, tc.errPermissions)
+			repoCall := twoFactorRepo.On("SaveDomainRequirement", context.Background(), validSession.Domain.ID, true).Return(tc.saveErr)
+			err := svc.UpdateDomainTwoFactor(context.Background(), validSession, validSession.Domain.ID, true)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			sdkCall.Unset()
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestUpdateTerminalPolicy(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	policy := ui.TerminalPolicy{
+		DomainID: validSession.Domain.ID,
+		ReadOnly: true,
+		Deny:     []string{"rm *"},
+	}
+
+	cases := []struct {
+		desc           string
+		permissions    []string
+		errPermissions errors.SDKError
+		saveErr        error
+		err            error
+	}{
+		{
+			desc:        "update terminal policy as domain admin",
+			permissions: []string{"admin", "edit", "view"},
+		},
+		{
+			desc:        "update terminal policy without admin permission",
+			permissions: []string{"edit", "view"},
+			err:         ui.ErrTerminalPolicyAdmin,
+		},
+		{
+			desc:           "update terminal policy with sdk error",
+			errPermissions: sdkerr,
+			err:            ui.ErrFailedRetreive,
+		},
+		{
+			desc:        "update terminal policy with repository error",
+			permissions: []string{"admin"},
+			saveErr:     fmt.Errorf("failed to save"),
+			err:         ui.ErrFailedUpdate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("DomainPermissions", validSession.Domain.ID, validSession.Token).Return(sdk.Domain{Permissions: tc.permissions}, tc.errPermissions)
+			repoCall := policyRepo.On("Save", context.Background(), mock.Anything).Return(tc.saveErr)
+			err := svc.UpdateTerminalPolicy(context.Background(), validSession, policy)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				saved := repoCall.Parent.Calls[len(repoCall.Parent.Calls)-1].Arguments.Get(1).(ui.TerminalPolicy)
+				assert.Equal(t, policy.Deny, saved.Deny)
+				assert.False(t, saved.UpdatedAt.IsZero(), "expected the update time to be set")
+			}
+			sdkCall.Unset()
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestCheckDomainTwoFactor(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc           string
+		required       bool
+		requirementErr error
+		tf             ui.TwoFactor
+		err            error
+	}{
+		{
+			desc: "check domain that does not require two factor",
+		},
+		{
+			desc:     "check domain that requires two factor with user enrolled",
+			required: true,
+			tf:       ui.TwoFactor{UserID: validSession.User.ID, Enabled: true},
+		},
+		{
+			desc:     "check domain that requires two factor with user not enrolled",
+			required: true,
+			err:      ui.ErrTwoFactorRequired,
+		},
+		{
+			desc:           "check domain with repository error",
+			requirementErr: fmt.Errorf("failed to retrieve"),
+			err:            ui.ErrFailedRetreive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repoCall := twoFactorRepo.On("RetrieveDomainRequirement", context.Background(), validSession.Domain.ID).Return(tc.required, tc.requirementErr)
+			repoCall1 := twoFactorRepo.On("Retrieve", context.Background(), validSession.User.ID).Return(tc.tf, nil)
+			err := svc.CheckDomainTwoFactor(context.Background(), validSession.Domain.ID, validSession.User.ID)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			repoCall.Unset()
+			repoCall1.Unset()
+		})
+	}
+}
+
+// pendingTwoFactor enrolls the valid session user and returns the saved
+// enrolment together with the plaintext secret shown on the settings page.
+func pendingTwoFactor(t *testing.T, svc ui.Service) (ui.TwoFactor, string) {
+	var pending ui.TwoFactor
+	repoCall := twoFactorRepo.On("Retrieve", context.Background(), validSession.User.ID).Return(ui.TwoFactor{}, nil)
+	repoCall1 := twoFactorRepo.On("Save", context.Background(), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		pending = args.Get(1).(ui.TwoFactor)
+	})
+	err := svc.EnrollTwoFactor(context.Background(), validSession)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	repoCall.Unset()
+	repoCall1.Unset()
+
+	provider.On("IsEnabled").Return(true)
+	provider.On("Name").Return("test")
+	provider.On("Icon").Return("fa-test")
+	sdkmock.On("Health", "users").Return(sdk.HealthInfo{}, nil)
+	sdkmock.On("Health", "things").Return(sdk.HealthInfo{}, nil)
+	sdkmock.On("Health", "bootstrap").Return(sdk.HealthInfo{}, nil)
+	repoCall = twoFactorRepo.On("Retrieve", context.Background(), validSession.User.ID).Return(pending, nil)
+	page, err := svc.TwoFactor(context.Background(), validSession)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	repoCall.Unset()
+
+	matches := regexp.MustCompile(`id="totp-secret">([A-Z2-7]+)<`).FindSubmatch(page)
+	require.Len(t, matches, 2, "expected the secret on the two factor page")
+
+	return pending, string(matches[1])
+}
+
+// enabledTwoFactor confirms the enrolment of the valid session user and
+// returns the saved enrolment, the plaintext secret and the recovery codes.
+func enabledTwoFactor(t *testing.T, svc ui.Service) (ui.TwoFactor, string, []string) {
+	pending, secret := pendingTwoFactor(t, svc)
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	var enabled ui.TwoFactor
+	repoCall := twoFactorRepo.On("Retrieve", context.Background(), validSession.User.ID).Return(pending, nil)
+	repoCall1 := twoFactorRepo.On("Save", context.Background(), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		enabled = args.Get(1).(ui.TwoFactor)
+	})
+	b, err := svc.ConfirmTwoFactor(context.Background(), validSession, code)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	repoCall.Unset()
+	repoCall1.Unset()
+
+	var res struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	err = json.Unmarshal(b, &res)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	return enabled, secret, res.RecoveryCodes
+}
+
+func TestBootstrapTemplates(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	pm := ui.BootstrapTemplatePageMeta{Offset: 0, Limit: 10, DomainID: validSession.Domain.ID}
+	page := ui.BootstrapTemplatePage{
+		Total: 1,
+		Limit: 10,
+		Templates: []ui.BootstrapTemplate{{
+			ID:       generateID(t),
+			DomainID: validSession.Domain.ID,
+			Name:     "gateway",
+			Content:  `{"agent":{"mqtt":{"url":"{{ .mqtt_url }}"}}}`,
+			Channels: []string{"{{ .control }}"},
+		}},
+	}
+
+	cases := []struct {
+		desc    string
+		page    ui.BootstrapTemplatePage
+		repoErr error
+		err     error
+	}{
+		{
+			desc: "view bootstrap templates successfully",
+			page: page,
+		},
+		{
+			desc:    "view bootstrap templates with repository error",
+			repoErr: fmt.Errorf("failed to retrieve"),
+			err:     ui.ErrFailedRetreive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repoCall := templateRepo.On("RetrieveAll", context.Background(), pm).Return(tc.page, tc.repoErr)
+			res, err := svc.BootstrapTemplates(context.Background(), validSession, 1, 10)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				assert.Contains(t, string(res), "gateway")
+			}
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestCreateBootstrapTemplate(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	tpl := ui.BootstrapTemplate{
+		Name:     "gateway",
+		Content:  `{"agent":{"mqtt":{"url":"{{ .mqtt_url }}"}}}`,
+		Channels: []string{"{{ .control }}"},
+	}
+
+	cases := []struct {
+		desc    string
+		tpl     ui.BootstrapTemplate
+		saveErr error
+		saved   bool
+		err     error
+	}{
+		{
+			desc:  "create bootstrap template successfully",
+			tpl:   tpl,
+			saved: true,
+		},
+		{
+			desc: "create bootstrap template with invalid content",
+			tpl:  ui.BootstrapTemplate{Name: "invalid", Content: `{{ .mqtt_url`},
+			err:  ui.ErrBootstrapTemplate,
+		},
+		{
+			desc:    "create bootstrap template with repository error",
+			tpl:     tpl,
+			saveErr: fmt.Errorf("failed to save"),
+			saved:   true,
+			err:     ui.ErrFailedCreate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repoCall := templateRepo.On("Save", context.Background(), mock.Anything).Return(tc.saveErr)
+			err := svc.CreateBootstrapTemplate(context.Background(), validSession, tc.tpl)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if tc.saved {
+				saved := repoCall.Parent.Calls[len(repoCall.Parent.Calls)-1].Arguments.Get(1).(ui.BootstrapTemplate)
+				assert.NotEmpty(t, saved.ID, "expected the template id to be set")
+				assert.Equal(t, validSession.Domain.ID, saved.DomainID)
+				assert.Equal(t, validSession.User.ID, saved.CreatedBy)
+				assert.Equal(t, tc.tpl.Content, saved.Content)
+			}
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestUpdateBootstrapTemplate(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	tpl := ui.BootstrapTemplate{
+		ID:      generateID(t),
+		Name:    "gateway",
+		Content: `{"agent":{"mqtt":{"url":"{{ .mqtt_url }}"}}}`,
+	}
+
+	cases := []struct {
+		desc      string
+		tpl       ui.BootstrapTemplate
+		updateErr error
+		err       error
+	}{
+		{
+			desc: "update bootstrap template successfully",
+			tpl:  tpl,
+		},
+		{
+			desc: "update bootstrap template with invalid channel",
+			tpl:  ui.BootstrapTemplate{ID: tpl.ID, Name: tpl.Name, Content: tpl.Content, Channels: []string{"{{ .control"}},
+			err:  ui.ErrBootstrapTemplate,
+		},
+		{
+			desc:      "update bootstrap template with repository error",
+			tpl:       tpl,
+			updateErr: fmt.Errorf("failed to update"),
+			err:       ui.ErrFailedUpdate,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repoCall := templateRepo.On("Update", context.Background(), mock.Anything).Return(tc.updateErr)
+			err := svc.UpdateBootstrapTemplate(context.Background(), validSession, tc.tpl)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				updated := repoCall.Parent.Calls[len(repoCall.Parent.Calls)-1].Arguments.Get(1).(ui.BootstrapTemplate)
+				assert.Equal(t, validSession.Domain.ID, updated.DomainID)
+				assert.False(t, updated.UpdatedAt.IsZero(), "expected the update time to be set")
+			}
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestDeleteBootstrapTemplate(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	id := generateID(t)
+
+	cases := []struct {
+		desc      string
+		deleteErr error
+		err       error
+	}{
+		{
+			desc: "delete bootstrap template successfully",
+		},
+		{
+			desc:      "delete bootstrap template with repository error",
+			deleteErr: fmt.Errorf("failed to delete"),
+			err:       ui.ErrFailedDelete,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repoCall := templateRepo.On("Delete", context.Background(), validSession.Domain.ID, id).Return(tc.deleteErr)
+			err := svc.DeleteBootstrapTemplate(context.Background(), validSession, id)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			repoCall.Unset()
+		})
+	}
+}
+
+func TestProvisionBootstraps(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	tpl := ui.BootstrapTemplate{
+		ID:       generateID(t),
+		DomainID: validSession.Domain.ID,
+		Name:     "gateway",
+		Content:  `{"agent":{"mqtt":{"url":"{{ .mqtt_url }}"}}}`,
+		Channels: []string{"{{ .control }}"},
+	}
+	created := ui.BootstrapRow{Line: 2, ExternalID: "gw-001", ExternalKey: "key-1", Name: "gateway 1", Vars: map[string]string{"mqtt_url": "tcp://broker:1883", "control": generateID(t)}}
+	missing := ui.BootstrapRow{Line: 3, ExternalID: "gw-002", ExternalKey: "key-2", Name: "gateway 2", Vars: map[string]string{"control": generateID(t)}}
+	failed := ui.BootstrapRow{Line: 4, ExternalID: "gw-003", ExternalKey: "key-3", Name: "gateway 3", Vars: map[string]string{"mqtt_url": "tcp://broker:1883", "control": generateID(t)}}
+	createdCfg, err := tpl.Render(created)
+	require.Nil(t, err, fmt.Sprintf("render template unexpected error: %s", err))
+	failedCfg, err := tpl.Render(failed)
+	require.Nil(t, err, fmt.Sprintf("render template unexpected error: %s", err))
+	thingID := generateID(t)
+
+	cases := []struct {
+		desc        string
+		rows        []ui.BootstrapRow
+		retrieveErr error
+		statuses    []string
+		err         error
+	}{
+		{
+			desc:     "provision bootstraps with every outcome",
+			rows:     []ui.BootstrapRow{created, missing, failed},
+			statuses: []string{ui.ProvisionCreated, ui.ProvisionFailed, ui.ProvisionFailed},
+		},
+		{
+			desc:        "provision bootstraps with repository error",
+			rows:        []ui.BootstrapRow{created},
+			retrieveErr: fmt.Errorf("failed to retrieve"),
+			err:         ui.ErrFailedRetreive,
+		},
+		{
+			desc: "provision too many bootstraps",
+			rows: make([]ui.BootstrapRow, ui.MaxProvisionRows+1),
+			err:  ui.ErrTooManyRows,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repoCall := templateRepo.On("Retrieve", context.Background(), validSession.Domain.ID, tpl.ID).Return(tpl, tc.retrieveErr)
+			sdkCall := sdkmock.On("AddBootstrap", createdCfg, validSession.Token).Return(thingID, nil)
+			sdkCall1 := sdkmock.On("AddBootstrap", failedCfg, validSession.Token).Return("", sdkerr)
+			res, err := svc.ProvisionBootstraps(context.Background(), validSession, tpl.ID, tc.rows)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				page := string(res)
+				assert.Contains(t, page, thingID)
+				assert.Contains(t, page, "1 created")
+				assert.Contains(t, page, fmt.Sprintf("%d failed", len(tc.statuses)-1))
+			}
+			repoCall.Unset()
+			sdkCall.Unset()
+			sdkCall1.Unset()
+		})
+	}
+}
+
+func TestExpiringCertificates(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	now := time.Now()
+	ca := issueCert(t, "ca", true, now.Add(-time.Hour), now.Add(365*24*time.Hour), nil)
+	soon := issueCert(t, "soon", false, now.Add(-time.Hour), now.Add(10*24*time.Hour), &ca)
+	expired := issueCert(t, "expired", false, now.Add(-48*time.Hour), now.Add(-24*time.Hour), &ca)
+	configs := []sdk.BootstrapConfig{
+		{ThingID: generateID(t), Name: "soon", ClientCert: soon.certPEM, CACert: ca.certPEM},
+		{ThingID: generateID(t), Name: "expired", ClientCert: expired.certPEM, CACert: ca.certPEM},
+		{ThingID: generateID(t), Name: "unreachable"},
+	}
+	page := sdk.BootstrapPage{Configs: configs}
+	page.Total = uint64(len(configs))
+
+	cases := []struct {
+		desc     string
+		within   time.Duration
+		listErr  errors.SDKError
+		contains []string
+		excludes []string
+		err      error
+	}{
+		{
+			desc:     "list certificates expiring within a month",
+			within:   30 * 24 * time.Hour,
+			contains: []string{"CN=soon", "CN=expired", "unreachable"},
+			excludes: []string{"CN=ca"},
+		},
+		{
+			desc:     "list expired certificates",
+			contains: []string{"CN=expired", "unreachable"},
+			excludes: []string{"CN=soon", "CN=ca"},
+		},
+		{
+			desc:     "list certificates expiring within two years",
+			within:   2 * 365 * 24 * time.Hour,
+			contains: []string{"CN=soon", "CN=expired", "CN=ca"},
+		},
+		{
+			desc:    "list certificates with sdk error",
+			within:  30 * 24 * time.Hour,
+			listErr: sdkerr,
+			err:     ui.ErrFailedRetreive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("Bootstraps", mock.Anything, validSession.Token).Return(page, tc.listErr)
+			sdkCall1 := sdkmock.On("ViewBootstrap", configs[0].ThingID, validSession.Token).Return(configs[0], nil)
+			sdkCall2 := sdkmock.On("ViewBootstrap", configs[1].ThingID, validSession.Token).Return(configs[1], nil)
+			sdkCall3 := sdkmock.On("ViewBootstrap", configs[2].ThingID, validSession.Token).Return(sdk.BootstrapConfig{}, sdkerr)
+			res, err := svc.ExpiringCertificates(context.Background(), validSession, tc.within)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				page := string(res)
+				for _, c := range tc.contains {
+					assert.Contains(t, page, c)
+				}
+				for _, c := range tc.excludes {
+					assert.NotContains(t, page, c)
+				}
+			}
+			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
+			sdkCall3.Unset()
+		})
+	}
+}
+
+func TestExportBundle(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cfg := validBootstrapConfig
+	cfg.ClientKey = "client-key"
+	cfg.Content = `{"agent":{"mqtt":{"url":"tcp://broker:1883"}}}`
+	chID := cfg.Channels.([]string)[0]
+	channel := sdk.Channel{ID: chID, Name: "control"}
+	thing := sdk.Thing{ID: cfg.ThingID, Name: "gateway", Credentials: sdk.Credentials{Secret: "thing-secret"}}
+
+	cases := []struct {
+		desc     string
+		export   ui.BundleExport
+		sdkerr   errors.SDKError
+		contains []string
+		excludes []string
+		err      error
+	}{
+		{
+			desc:     "export JSON bundle",
+			export:   ui.BundleExport{ThingIDs: []string{cfg.ThingID}, Format: ui.BundleJSON},
+			contains: []string{`"version": 1`, chID, "control", "thing-secret", cfg.ExternalKey, "client-key", "tcp://broker:1883"},
+		},
+		{
+			desc:     "export redacted YAML bundle",
+			export:   ui.BundleExport{ThingIDs: []string{cfg.ThingID}, Format: ui.BundleYAML, Redact: true},
+			contains: []string{"version: 1", "redacted: true", chID, "external_id: " + cfg.ExternalID},
+			excludes: []string{"thing-secret", cfg.ExternalKey, "client-key"},
+		},
+		{
+			desc:   "export bundle with sdk error",
+			export: ui.BundleExport{ThingIDs: []string{cfg.ThingID}, Format: ui.BundleJSON},
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedRetreive,
+		},
+		{
+			desc:   "export bundle with too many configs",
+			export: ui.BundleExport{ThingIDs: make([]string, ui.MaxBundleConfigs+1), Format: ui.BundleJSON},
+			err:    ui.ErrInvalidBundle,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("ViewBootstrap", cfg.ThingID, validSession.Token).Return(cfg, tc.sdkerr)
+			sdkCall1 := sdkmock.On("Thing", cfg.ThingID, validSession.Token).Return(thing, nil)
+			sdkCall2 := sdkmock.On("Channel", chID, validSession.Token).Return(channel, nil)
+			res, err := svc.ExportBundle(context.Background(), validSession, tc.export)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			if err == nil {
+				_, err := ui.ParseBundle(res)
+				assert.Nil(t, err, fmt.Sprintf("parse exported bundle unexpected error: %s", err))
+			}
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
+			}
+			for _, c := range tc.excludes {
+				assert.NotContains(t, string(res), c)
+			}
+			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
+		})
+	}
+}
+
+func TestImportBundle(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	oldChannel, newChannel := generateID(t), generateID(t)
+	oldThing, newThing := generateID(t), generateID(t)
+	config := ui.BundleConfig{
+		Thing:       ui.BundleThing{ID: oldThing, Name: "gateway", Secret: "thing-secret"},
+		ExternalID:  "external-id",
+		ExternalKey: "external-key",
+		Name:        "gateway",
+		Content:     fmt.Sprintf(`{"agent":{"channels":{"control":"%s"},"mqtt":{"url":"tcp://broker:1883"}}}`, oldChannel),
+		Channels:    []string{oldChannel},
+		State:       1,
+	}
+	bundle := ui.Bundle{
+		Version:  ui.BundleVersion,
+		Channels: []ui.BundleChannel{{ID: oldChannel, Name: "control"}},
+		Configs:  []ui.BundleConfig{config},
+	}
+	redacted := config
+	redacted.ExternalKey = ""
+	redactedBundle := bundle
+	redactedBundle.Configs = []ui.BundleConfig{redacted}
+	// An empty id must not be replaced in the content of the configs.
+	emptyIDBundle := bundle
+	emptyIDBundle.Channels = append([]ui.BundleChannel{{ID: "", Name: "unnamed"}}, bundle.Channels...)
+	cases := []struct {
+		desc           string
+		bundle         ui.Bundle
+		opts           ui.BundleImport
+		channelErr     errors.SDKError
+		addErr         errors.SDKError
+		creates        bool
+		deletes        bool
+		deletesChannel bool
+		contains       []string
+	}{
+		{
+			desc:     "dry run bundle import",
+			bundle:   bundle,
+			opts:     ui.BundleImport{DryRun: true},
+			contains: []string{"Dry Run", "3 to create", "0 failed"},
+		},
+		{
+			desc:     "import bundle",
+			bundle:   bundle,
+			creates:  true,
+			contains: []string{"3 created", "0 failed", newChannel, newThing},
+		},
+		{
+			desc:     "import bundle with remapped channel and thing",
+			bundle:   bundle,
+			opts:     ui.BundleImport{Remap: map[string]string{oldChannel: newChannel, oldThing: newThing}},
+			contains: []string{"1 created", "2 mapped", "0 failed"},
+		},
+		{
+			desc:       "import bundle with failing channel",
+			bundle:     bundle,
+			channelErr: sdkerr,
+			contains:   []string{"3 failed", "was not imported"},
+		},
+		{
+			desc:           "import bundle with failing bootstrap config",
+			bundle:         bundle,
+			creates:        true,
+			addErr:         sdkerr,
+			deletes:        true,
+			deletesChannel: true,
+			contains:       []string{"0 created", "2 removed", "1 failed"},
+		},
+		{
+			desc:     "import bundle with channel without id",
+			bundle:   emptyIDBundle,
+			creates:  true,
+			contains: []string{"4 created", "0 failed"},
+		},
+		{
+			desc:     "import redacted bundle",
+			bundle:   redactedBundle,
+			opts:     ui.BundleImport{DryRun: true},
+			contains: []string{"1 to create", "2 failed", "missing external key"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("CreateChannel", mock.Anything, validSession.Token).Return(sdk.Channel{ID: newChannel}, tc.channelErr)
+			sdkCall1 := sdkmock.On("Channel", newChannel, validSession.Token).Return(sdk.Channel{ID: newChannel}, nil)
+			sdkCall2 := sdkmock.On("CreateThing", mock.Anything, validSession.Token).Return(sdk.Thing{ID: newThing}, nil)
+			sdkCall3 := sdkmock.On("Thing", newThing, validSession.Token).Return(sdk.Thing{ID: newThing}, nil)
+			var added sdk.BootstrapConfig
+			sdkCall4 := sdkmock.On("AddBootstrap", mock.Anything, validSession.Token).Return(newThing, tc.addErr).Run(func(args mock.Arguments) {
+				added = args.Get(0).(sdk.BootstrapConfig)
+			})
+			sdkCall5 := sdkmock.On("Whitelist", mock.Anything, validSession.Token).Return(nil)
+			deleted := false
+			sdkCall6 := sdkmock.On("DeleteThing", newThing, validSession.Token).Return(nil).Run(func(args mock.Arguments) {
+				deleted = true
+			})
+			deletedChannel := false
+			sdkCall7 := sdkmock.On("DeleteChannel", newChannel, validSession.Token).Return(nil).Run(func(args mock.Arguments) {
+				deletedChannel = true
+			})
+			res, err := svc.ImportBundle(context.Background(), validSession, tc.bundle, tc.opts)
+			assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
+			}
+			assert.Equal(t, tc.deletes, deleted)
+			assert.Equal(t, tc.deletesChannel, deletedChannel)
+			if tc.creates {
+				// The bootstrap config must refer to the imported channel,
+				// also in its content.
+				assert.Equal(t, newThing, added.ThingID)
+				assert.Equal(t, []string{newChannel}, added.Channels)
+				assert.Contains(t, added.Content, newChannel)
+				assert.NotContains(t, added.Content, oldChannel)
+				assert.Equal(t, strings.ReplaceAll(config.Content, oldChannel, newChannel), added.Content)
+			}
+			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
+			sdkCall3.Unset()
+			sdkCall4.Unset()
+			sdkCall5.Unset()
+			sdkCall6.Unset()
+			sdkCall7.Unset()
+		})
+	}
+}
+
+func TestBootstrapStates(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	configs := []sdk.BootstrapConfig{
+		{ThingID: generateID(t), ExternalID: "gateway-01", State: 1},
+		{ThingID: generateID(t), ExternalID: "gateway-02"},
+		{ThingID: generateID(t), ExternalID: "sensor-01"},
+	}
+	page := sdk.BootstrapPage{Configs: configs}
+	page.Total = uint64(len(configs))
+
+	cases := []struct {
+		desc     string
+		filter   ui.BootstrapStateFilter
+		sdkerr   errors.SDKError
+		contains []string
+		excludes []string
+		err      error
+	}{
+		{
+			desc:     "view all bootstrap states",
+			contains: []string{`id="total-count">3<`, `id="active-count">1<`, `id="inactive-count">2<`, "gateway-01", "gateway-02", "sensor-01"},
+		},
+		{
+			desc:     "view inactive bootstrap configs",
+			filter:   ui.BootstrapStateFilter{State: ui.BatchStateInactive},
+			contains: []string{`id="total-count">3<`, "gateway-02", "sensor-01"},
+			excludes: []string{"gateway-01"},
+		},
+		{
+			desc:     "view bootstrap configs by external id",
+			filter:   ui.BootstrapStateFilter{ExternalID: "GATEWAY"},
+			contains: []string{"gateway-01", "gateway-02"},
+			excludes: []string{"sensor-01"},
+		},
+		{
+			desc:   "view bootstrap states with sdk error",
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedRetreive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sdkCall := sdkmock.On("Bootstraps", mock.Anything, validSession.Token).Return(page, tc.sdkerr)
+			res, err := svc.BootstrapStates(validSession, tc.filter)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
+			}
+			for _, c := range tc.excludes {
+				assert.NotContains(t, string(res), c)
+			}
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestUpdateBootstrapStates(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	active := sdk.BootstrapConfig{ThingID: generateID(t), ExternalID: "active", State: 1}
+	inactive := sdk.BootstrapConfig{ThingID: generateID(t), ExternalID: "inactive"}
+	failing := sdk.BootstrapConfig{ThingID: generateID(t), ExternalID: "failing"}
+	configs := []sdk.BootstrapConfig{active, inactive, failing}
+	page := sdk.BootstrapPage{Configs: configs}
+	page.Total = uint64(len(configs))
+
+	cases := []struct {
+		desc     string
+		change   ui.BootstrapStateChange
+		listErr  errors.SDKError
+		contains []string
+		err      error
+	}{
+		{
+			desc:     "activate bootstrap configs",
+			change:   ui.BootstrapStateChange{ThingIDs: []string{active.ThingID, inactive.ThingID, inactive.ThingID}, State: ui.BatchStateActive},
+			contains: []string{"2 selected", "1 changed", "1 already active", "0 failed"},
+		},
+		{
+			desc:     "deactivate bootstrap configs",
+			change:   ui.BootstrapStateChange{ThingIDs: []string{active.ThingID, inactive.ThingID}, State: ui.BatchStateInactive},
+			contains: []string{"1 changed", "1 already inactive", "0 failed"},
+		},
+		{
+			desc:     "activate bootstrap configs with failures",
+			change:   ui.BootstrapStateChange{ThingIDs: []string{inactive.ThingID, failing.ThingID, generateID(t)}, State: ui.BatchStateActive},
+			contains: []string{"1 changed", "2 failed", "state was not changed", "thing has no bootstrap config"},
+		},
+		{
+			desc:    "activate bootstrap configs with sdk error",
+			change:  ui.BootstrapStateChange{ThingIDs: []string{inactive.ThingID}, State: ui.BatchStateActive},
+			listErr: sdkerr,
+			err:     ui.ErrFailedRetreive,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("Domains", sdk.PageMetadata{Offset: 0, Limit: 10, Status: "enabled"}, validSession.Token).Return(validDomainsPage, tc.sdkerr)
-			_, err := svc.ListDomains(validSession, "enabled", 1, 10)
+			sdkCall := sdkmock.On("Bootstraps", mock.Anything, validSession.Token).Return(page, tc.listErr)
+			sdkCall1 := sdkmock.On("Whitelist", sdk.BootstrapConfig{ThingID: active.ThingID, State: 0}, validSession.Token).Return(nil)
+			sdkCall2 := sdkmock.On("Whitelist", sdk.BootstrapConfig{ThingID: inactive.ThingID, State: 1}, validSession.Token).Return(nil)
+			sdkCall3 := sdkmock.On("Whitelist", sdk.BootstrapConfig{ThingID: failing.ThingID, State: 1}, validSession.Token).Return(sdkerr)
+			res, err := svc.UpdateBootstrapStates(context.Background(), validSession, tc.change)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "Domains", sdk.PageMetadata{Offset: 0, Limit: 10, Status: "enabled"}, validSession.Token)
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
 			}
 			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
+			sdkCall3.Unset()
 		})
 	}
 }
 
-func TestDomain(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestExportEntities(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	usersPage := sdk.UsersPage{Users: []sdk.User{{
+		Name:        "user",
+		Credentials: sdk.Credentials{Identity: "user@example.com", Secret: "user-secret"},
+		Tags:        []string{"tag"},
+	}}}
+	usersPage.Total = 1
+	thingsPage := sdk.ThingsPage{Things: []sdk.Thing{
+		{Name: "thing", Credentials: sdk.Credentials{Identity: "thing-identity", Secret: "thing-secret"}, Metadata: sdk.Metadata{"key": "value"}},
+		{Name: "plain"},
+	}}
+	thingsPage.Total = 2
+	channelsPage := sdk.ChannelsPage{Channels: []sdk.Channel{{Name: "channel", Description: "Channel, with comma"}}}
+	channelsPage.Total = 1
+	groupsPage := sdk.GroupsPage{Groups: []sdk.Group{{Name: "group", Metadata: sdk.Metadata{"key": "value"}}}}
+	groupsPage.Total = 1
+
 	cases := []struct {
-		desc           string
-		errDomain      errors.SDKError
-		errPermissions errors.SDKError
-		err            error
+		desc   string
+		export ui.EntityExport
+		sdkerr errors.SDKError
+		csv    string
+		err    error
 	}{
 		{
-			desc: "success",
+			desc:   "export users",
+			export: ui.EntityExport{Entity: ui.UsersEntity, Secrets: true},
+			csv:    "Name,ID,Secret,Metadata,Tags\nuser,user@example.com,,,\"[\"\"tag\"\"]\"\n",
 		},
 		{
-			desc:      "sdk error on fetching domain",
-			errDomain: sdkerr,
-			err:       ui.ErrFailedRetreive,
+			desc:   "export things with secrets",
+			export: ui.EntityExport{Entity: ui.ThingsEntity, Status: "all", Secrets: true},
+			csv:    "Name,Identity,Secret,Tags,Metadata\nthing,thing-identity,thing-secret,,\"{\"\"key\"\":\"\"value\"\"}\"\nplain,,,,\n",
 		},
 		{
-			desc:           "sdk error on fetching domain permission",
-			errPermissions: sdkerr,
-			err:            ui.ErrFailedRetreive,
+			desc:   "export things without secrets",
+			export: ui.EntityExport{Entity: ui.ThingsEntity},
+			csv:    "Name,Identity,Secret,Tags,Metadata\nthing,thing-identity,,,\"{\"\"key\"\":\"\"value\"\"}\"\nplain,,,,\n",
+		},
+		{
+			desc:   "export channels",
+			export: ui.EntityExport{Entity: ui.ChannelsEntity},
+			csv:    "Name,Metadata,Description\nchannel,,\"Channel, with comma\"\n",
+		},
+		{
+			desc:   "export groups",
+			export: ui.EntityExport{Entity: ui.GroupsEntity},
+			csv:    "Name,Metadata,Description\ngroup,\"{\"\"key\"\":\"\"value\"\"}\",\n",
+		},
+		{
+			desc:   "export things with sdk error",
+			export: ui.EntityExport{Entity: ui.ThingsEntity},
+			sdkerr: sdkerr,
+			err:    ui.ErrFailedRetreive,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("Domain", validSession.Domain.ID, validSession.Token).Return(validDomain, tc.errDomain)
-			sdkCall1 := sdkmock.On("DomainPermissions", validSession.Domain.ID, validSession.Token).Return(validDomain, tc.errPermissions)
-			_, err := svc.Domain(validSession)
+			pm := sdk.PageMetadata{Limit: 100, Status: tc.export.Status}
+			sdkCall := sdkmock.On("Users", pm, validSession.Token).Return(usersPage, tc.sdkerr)
+			sdkCall1 := sdkmock.On("Things", pm, validSession.Token).Return(thingsPage, tc.sdkerr)
+			sdkCall2 := sdkmock.On("Channels", pm, validSession.Token).Return(channelsPage, tc.sdkerr)
+			sdkCall3 := sdkmock.On("Groups", pm, validSession.Token).Return(groupsPage, tc.sdkerr)
+			var res bytes.Buffer
+			err := svc.ExportEntities(context.Background(), validSession, tc.export, &res)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
 			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "Domain", validSession.Domain.ID, validSession.Token)
+				assert.Equal(t, tc.csv, res.String())
 			}
 			sdkCall.Unset()
 			sdkCall1.Unset()
+			sdkCall2.Unset()
+			sdkCall3.Unset()
 		})
 	}
 }
 
-func TestUpdateDomain(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestImportEntities(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	thingID := generateID(t)
+	failing := sdk.Thing{Name: "failing", Credentials: sdk.Credentials{Identity: "failing-identity"}}
+
 	cases := []struct {
-		desc   string
-		sdkerr errors.SDKError
-		err    error
+		desc     string
+		imp      ui.EntityImport
+		contains []string
+		excludes []string
+		err      error
 	}{
 		{
-			desc:   "success",
-			sdkerr: nil,
-			err:    nil,
+			desc: "dry run users import",
+			imp: ui.EntityImport{
+				Entity: ui.UsersEntity,
+				DryRun: true,
+				Records: [][]string{
+					{"user1", "user1@example.com", "secret", `{"key":"value"}`, `["tag"]`},
+					{"user2", "", "", "", ""},
+					{"user3", "user3@example.com", "secret", "{bad", ""},
+					{"", "", "", "", ""},
+					{"user4", "user1@example.com", "secret", "", ""},
+					{"user5", "user5@example.com", "", "", ""},
+					{"user6", "", "secret", "", ""},
+				},
+			},
+			contains: []string{"Dry Run", "6 rows", "1 valid", "5 invalid", "missing identity", "missing secret", "invalid metadata", "duplicate identity of row 2"},
 		},
 		{
-			desc:   "sdk error",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedUpdate,
+			desc: "import things past failures",
+			imp: ui.EntityImport{
+				Entity: ui.ThingsEntity,
+				Records: [][]string{
+					{"thing", "thing-identity", "", "", ""},
+					{"failing", "failing-identity", "", "", ""},
+					{"bad", "", "", "tag", ""},
+				},
+			},
+			contains: []string{"3 rows", "1 created", "1 failed", "1 invalid", thingID, "invalid tags", "Download Report"},
+			excludes: []string{"Dry Run"},
+		},
+		{
+			desc: "import channels with missing columns",
+			imp: ui.EntityImport{
+				Entity:  ui.ChannelsEntity,
+				Records: [][]string{{"channel"}},
+			},
+			contains: []string{"0 created", "1 invalid", "expected 3 columns, got 1"},
+		},
+		{
+			desc: "import too many groups",
+			imp: ui.EntityImport{
+				Entity:  ui.GroupsEntity,
+				Records: make([][]string, ui.MaxImportRows+1),
+			},
+			err: ui.ErrTooManyRows,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("UpdateDomain", validDomain, validSession.Token).Return(validDomain, tc.sdkerr)
-			err := svc.UpdateDomain(validSession.Token, validDomain)
+			sdkCall := sdkmock.On("CreateThing", sdk.Thing{Name: "thing", Credentials: sdk.Credentials{Identity: "thing-identity"}}, validSession.Token).Return(sdk.Thing{ID: thingID}, nil)
+			sdkCall1 := sdkmock.On("CreateThing", failing, validSession.Token).Return(sdk.Thing{}, sdkerr)
+			res, err := svc.ImportEntities(context.Background(), validSession, tc.imp)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "UpdateDomain", validDomain, validSession.Token)
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
+			}
+			for _, c := range tc.excludes {
+				assert.NotContains(t, string(res), c)
 			}
 			sdkCall.Unset()
+			sdkCall1.Unset()
 		})
 	}
 }
 
-func TestEnableDomain(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestBulkConnect(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	thingID, thingID1 := generateID(t), generateID(t)
+	channelID, channelID1 := generateID(t), generateID(t)
+	thingsPage := sdk.ThingsPage{Things: []sdk.Thing{{ID: thingID, Name: "thing"}, {ID: thingID1, Name: "twin"}, {ID: generateID(t), Name: "twin"}}}
+	thingsPage.Total = 3
+	channelsPage := sdk.ChannelsPage{Channels: []sdk.Channel{{ID: channelID, Name: "channel"}, {ID: channelID1, Name: "other"}}}
+	channelsPage.Total = 2
+	connectedPage := sdk.ChannelsPage{Channels: []sdk.Channel{{ID: channelID1}}}
+	connectedPage.Total = 1
+
 	cases := []struct {
-		desc   string
-		sdkerr errors.SDKError
-		err    error
+		desc     string
+		rows     []ui.ConnectionRow
+		contains []string
+		err      error
 	}{
 		{
-			desc:   "success",
-			sdkerr: nil,
-			err:    nil,
+			desc: "connect by id and name",
+			rows: []ui.ConnectionRow{
+				{Thing: "thing", Channel: channelID},
+				{Thing: thingID, Channel: "channel"},
+				{Thing: "thing", Channel: "other"},
+				{Thing: thingID1, Channel: "channel"},
+				{Thing: "twin", Channel: "channel"},
+				{Thing: "missing", Channel: "channel"},
+			},
+			contains: []string{"6 rows", "1 connected", "2 already connected", "2 unresolved", "1 failed", "same connection as row 2", "2 things are named", "no thing with id or name"},
 		},
 		{
-			desc:   "sdk error",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedEnable,
+			desc: "connect too many rows",
+			rows: make([]ui.ConnectionRow, ui.MaxConnectionRows+1),
+			err:  ui.ErrTooManyRows,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("EnableDomain", validDomain.ID, validSession.Token).Return(tc.sdkerr)
-			err := svc.EnableDomain(validSession.Token, validDomain.ID)
+			sdkCall := sdkmock.On("Things", mock.Anything, validSession.Token).Return(thingsPage, nil)
+			sdkCall1 := sdkmock.On("Channels", mock.Anything, validSession.Token).Return(channelsPage, nil)
+			sdkCall2 := sdkmock.On("ChannelsByThing", mock.Anything, mock.Anything, validSession.Token).Return(connectedPage, nil)
+			sdkCall3 := sdkmock.On("Connect", sdk.Connection{ThingID: thingID, ChannelID: channelID}, validSession.Token).Return(nil)
+			sdkCall4 := sdkmock.On("Connect", sdk.Connection{ThingID: thingID1, ChannelID: channelID}, validSession.Token).Return(sdkerr)
+			res, err := svc.BulkConnect(context.Background(), validSession, tc.rows)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "EnableDomain", validDomain.ID, validSession.Token)
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
 			}
 			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
+			sdkCall3.Unset()
+			sdkCall4.Unset()
 		})
 	}
 }
 
-func TestDisableDomain(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestBulkAction(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	disabledID, enabledID, missingID := generateID(t), generateID(t), generateID(t)
+	taggedID, untaggedID := generateID(t), generateID(t)
+	usersPage := sdk.UsersPage{Users: []sdk.User{
+		{ID: taggedID, Name: "tagged", Tags: []string{"tag"}},
+		{ID: untaggedID, Name: "untagged", Tags: []string{"other"}},
+	}}
+	usersPage.Total = 2
+	channelsPage := sdk.ChannelsPage{Channels: []sdk.Channel{{ID: generateID(t)}}}
+	channelsPage.Total = ui.MaxBulkEntities + 1
+
 	cases := []struct {
-		desc   string
-		sdkerr errors.SDKError
-		err    error
+		desc     string
+		action   ui.EntityBulkAction
+		contains []string
+		tags     []string
+		err      error
 	}{
 		{
-			desc:   "success",
-			sdkerr: nil,
-			err:    nil,
+			desc: "enable things by id",
+			action: ui.EntityBulkAction{
+				Entity: ui.ThingsEntity,
+				Action: ui.BulkEnable,
+				IDs:    []string{disabledID, enabledID, missingID, disabledID},
+			},
+			contains: []string{"3 things", "1 applied", "1 unchanged", "1 failed", "failed to retrieve things"},
 		},
 		{
-			desc:   "sdk error",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedDisable,
+			desc: "add tag to filtered users",
+			action: ui.EntityBulkAction{
+				Entity: ui.UsersEntity,
+				Action: ui.BulkAddTag,
+				Tag:    "tag",
+				Filter: ui.BulkFilter{Name: "tagged"},
+			},
+			contains: []string{"2 users", "1 applied", "1 unchanged"},
+			tags:     []string{"other", "tag"},
+		},
+		{
+			desc: "disable too many filtered channels",
+			action: ui.EntityBulkAction{
+				Entity: ui.ChannelsEntity,
+				Action: ui.BulkDisable,
+				Filter: ui.BulkFilter{Status: "all"},
+			},
+			err: ui.ErrTooManyEntities,
+		},
+		{
+			desc: "disable too many groups by id",
+			action: ui.EntityBulkAction{
+				Entity: ui.GroupsEntity,
+				Action: ui.BulkDisable,
+				IDs:    make([]string, ui.MaxBulkEntities+1),
+			},
+			err: ui.ErrTooManyEntities,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("DisableDomain", validDomain.ID, validSession.Token).Return(tc.sdkerr)
-			err := svc.DisableDomain(validSession.Token, validDomain.ID)
+			var tags []string
+			sdkCall := sdkmock.On("Thing", disabledID, validSession.Token).Return(sdk.Thing{ID: disabledID, Status: sdk.DisabledStatus}, nil)
+			sdkCall1 := sdkmock.On("Thing", enabledID, validSession.Token).Return(sdk.Thing{ID: enabledID, Status: sdk.EnabledStatus}, nil)
+			sdkCall2 := sdkmock.On("Thing", missingID, validSession.Token).Return(sdk.Thing{}, sdkerr)
+			sdkCall3 := sdkmock.On("EnableThing", disabledID, validSession.Token).Return(sdk.Thing{ID: disabledID}, nil)
+			sdkCall4 := sdkmock.On("Users", mock.Anything, validSession.Token).Return(usersPage, nil)
+			sdkCall5 := sdkmock.On("UpdateUserTags", mock.Anything, validSession.Token).Return(sdk.User{}, nil).Run(func(args mock.Arguments) {
+				tags = args.Get(0).(sdk.User).Tags
+			})
+			sdkCall6 := sdkmock.On("Channels", mock.Anything, validSession.Token).Return(channelsPage, nil)
+			res, err := svc.BulkAction(context.Background(), validSession, tc.action)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "DisableDomain", validDomain.ID, validSession.Token)
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
 			}
+			assert.Equal(t, tc.tags, tags)
 			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
+			sdkCall3.Unset()
+			sdkCall4.Unset()
+			sdkCall5.Unset()
+			sdkCall6.Unset()
 		})
 	}
 }
 
-func TestAssignMember(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestSearch(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	thingID, userID, domainID := generateID(t), generateID(t), generateID(t)
+	thingsPage := sdk.ThingsPage{Things: []sdk.Thing{{ID: thingID, Name: "sensor-thing"}}}
+	usersPage := sdk.UsersPage{Users: []sdk.User{{ID: userID, Name: "sensor-user"}}}
+	domainsPage := sdk.DomainsPage{Domains: []sdk.Domain{{ID: domainID, Name: "sensor-domain"}}}
+	member := validSession
+	member.User.Role = "user"
+
 	cases := []struct {
-		desc   string
-		sdkerr errors.SDKError
-		err    error
+		desc     string
+		session  ui.Session
+		query    string
+		contains []string
+		excludes []string
 	}{
 		{
-			desc:   "success",
-			sdkerr: nil,
-			err:    nil,
+			desc:     "search as administrator",
+			session:  validSession,
+			query:    "sensor",
+			contains: []string{"3 results", fmt.Sprintf("%s/things/%s", prefix, thingID), "sensor-user", "sensor-domain", "could not be searched"},
 		},
 		{
-			desc:   "sdk error",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedAssign,
+			desc:     "search by metadata as member",
+			session:  member,
+			query:    "location=lab",
+			contains: []string{"2 results", "metadata", "sensor-thing"},
+			excludes: []string{"sensor-user", "could not be searched: users"},
+		},
+		{
+			desc:     "empty search",
+			session:  validSession,
+			query:    " ",
+			excludes: []string{"results", "sensor-thing"},
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("AddUserToDomain", validDomain.ID, validUsersRelationReq, validSession.Token).Return(tc.sdkerr)
-			err := svc.AssignMember(validSession.Token, validDomain.ID, validUsersRelationReq)
-			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "AddUserToDomain", validDomain.ID, validUsersRelationReq, validSession.Token)
+			sdkCall := sdkmock.On("Things", mock.Anything, validSession.Token).Return(thingsPage, nil)
+			sdkCall1 := sdkmock.On("Users", mock.Anything, validSession.Token).Return(usersPage, nil)
+			sdkCall2 := sdkmock.On("Channels", mock.Anything, validSession.Token).Return(sdk.ChannelsPage{}, sdkerr)
+			sdkCall3 := sdkmock.On("Groups", mock.Anything, validSession.Token).Return(sdk.GroupsPage{}, nil)
+			sdkCall4 := sdkmock.On("Domains", mock.Anything, validSession.Token).Return(domainsPage, nil)
+			res, err := svc.Search(context.Background(), tc.session, tc.query)
+			assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
+			}
+			for _, c := range tc.excludes {
+				assert.NotContains(t, string(res), c)
 			}
 			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
+			sdkCall3.Unset()
+			sdkCall4.Unset()
 		})
 	}
 }
 
-func TestUnassignMember(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestFindEntity(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	channelID, missingID := generateID(t), generateID(t)
+
 	cases := []struct {
-		desc   string
-		sdkerr errors.SDKError
-		err    error
+		desc  string
+		query string
+		url   string
 	}{
 		{
-			desc:   "success",
-			sdkerr: nil,
-			err:    nil,
+			desc:  "find channel by id",
+			query: channelID,
+			url:   fmt.Sprintf("%s/channels/%s", prefix, channelID),
 		},
 		{
-			desc:   "sdk error",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedUnassign,
+			desc:  "find missing id",
+			query: missingID,
+		},
+		{
+			desc:  "find name",
+			query: "channel",
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("RemoveUserFromDomain", validDomain.ID, validUsersRelationReq, validSession.Token).Return(tc.sdkerr)
-			err := svc.UnassignMember(validSession.Token, validDomain.ID, validUsersRelationReq)
-			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "RemoveUserFromDomain", validDomain.ID, validUsersRelationReq, validSession.Token)
-			}
+			sdkCall := sdkmock.On("Thing", mock.Anything, validSession.Token).Return(sdk.Thing{}, sdkerr)
+			sdkCall1 := sdkmock.On("Channel", channelID, validSession.Token).Return(sdk.Channel{ID: channelID}, nil)
+			sdkCall2 := sdkmock.On("Channel", missingID, validSession.Token).Return(sdk.Channel{}, sdkerr)
+			sdkCall3 := sdkmock.On("Group", mock.Anything, validSession.Token).Return(sdk.Group{}, sdkerr)
+			sdkCall4 := sdkmock.On("User", mock.Anything, validSession.Token).Return(sdk.User{}, sdkerr)
+			url, err := svc.FindEntity(context.Background(), validSession, tc.query)
+			assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+			assert.Equal(t, tc.url, url)
 			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
+			sdkCall3.Unset()
+			sdkCall4.Unset()
 		})
 	}
 }
 
-func TestViewMember(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestTopologyGraph(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	root, child, other := generateID(t), generateID(t), generateID(t)
+	groupsPage := sdk.GroupsPage{Groups: []sdk.Group{{ID: root}, {ID: child, ParentID: root}, {ID: other}}}
+	groupsPage.Total = 3
+	channel, otherChannel, emptyChannel := generateID(t), generateID(t), generateID(t)
+	channelsPage := sdk.ChannelsPage{Channels: []sdk.Channel{{ID: channel, ParentID: child}, {ID: otherChannel, ParentID: other}, {ID: emptyChannel}}}
+	channelsPage.Total = 3
+	tagged := sdk.Thing{ID: generateID(t), Tags: []string{"tag"}}
+	untagged := sdk.Thing{ID: generateID(t)}
+	thingsPage := sdk.ThingsPage{Things: []sdk.Thing{tagged, untagged, {ID: generateID(t)}}}
+	thingsPage.Total = 3
+	channelThings := sdk.ThingsPage{Things: []sdk.Thing{tagged}}
+	channelThings.Total = 1
+	otherThings := sdk.ThingsPage{Things: []sdk.Thing{untagged}}
+	otherThings.Total = 1
+
 	cases := []struct {
-		desc      string
-		usersPage sdk.UsersPage
-		sdkerr    errors.SDKError
-		err       error
+		desc   string
+		filter ui.TopologyFilter
+		nodes  int
+		edges  int
+		err    error
 	}{
 		{
-			desc:      "success",
-			usersPage: sdk.UsersPage{Users: []sdk.User{validUser}},
-			sdkerr:    nil,
-			err:       nil,
+			desc:  "graph of the domain",
+			nodes: 9,
+			edges: 5,
 		},
 		{
-			desc:   "sdk error",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedRetreive,
+			desc:   "graph of a group",
+			filter: ui.TopologyFilter{GroupID: root},
+			nodes:  4,
+			edges:  3,
 		},
 		{
-			desc: "empty users",
-			err:  ui.ErrFailedRetreive,
+			desc:   "graph of a tag",
+			filter: ui.TopologyFilter{Tag: "tag"},
+			nodes:  4,
+			edges:  3,
+		},
+		{
+			desc:   "graph with failing channel",
+			filter: ui.TopologyFilter{GroupID: other},
+			err:    ui.ErrFailedRetreive,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("Users", sdk.PageMetadata{Identity: "test", Limit: 1}, validSession.Token).Return(tc.usersPage, tc.sdkerr)
-			_, err := svc.ViewMember(validSession, "test")
+			sdkCall := sdkmock.On("Groups", mock.Anything, validSession.Token).Return(groupsPage, nil)
+			sdkCall1 := sdkmock.On("Channels", mock.Anything, validSession.Token).Return(channelsPage, nil)
+			sdkCall2 := sdkmock.On("Things", mock.Anything, validSession.Token).Return(thingsPage, nil)
+			sdkCall3 := sdkmock.On("ThingsByChannel", channel, mock.Anything, validSession.Token).Return(channelThings, nil)
+			sdkCall4 := sdkmock.On("ThingsByChannel", emptyChannel, mock.Anything, validSession.Token).Return(sdk.ThingsPage{}, nil)
+			sdkCall5 := sdkmock.On("ThingsByChannel", otherChannel, mock.Anything, validSession.Token).Return(otherThings, nil)
+			if tc.err != nil {
+				sdkCall5.Unset()
+				sdkCall5 = sdkmock.On("ThingsByChannel", otherChannel, mock.Anything, validSession.Token).Return(sdk.ThingsPage{}, sdkerr)
+			}
+			res, err := svc.TopologyGraph(context.Background(), validSession, tc.filter)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "Users", sdk.PageMetadata{Identity: "test", Limit: 1}, validSession.Token)
+			if tc.err == nil {
+				var topo ui.Topology
+				require.Nil(t, json.Unmarshal(res, &topo))
+				assert.Len(t, topo.Nodes, tc.nodes)
+				assert.Len(t, topo.Edges, tc.edges)
 			}
 			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
+			sdkCall3.Unset()
+			sdkCall4.Unset()
+			sdkCall5.Unset()
 		})
 	}
 }
 
-func TestMembers(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestTopology(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	group := sdk.Group{ID: generateID(t), Name: "topology-group"}
+	groupsPage := sdk.GroupsPage{Groups: []sdk.Group{group}}
+	groupsPage.Total = 1
+
 	cases := []struct {
-		desc   string
-		sdkerr errors.SDKError
-		err    error
+		desc     string
+		filter   ui.TopologyFilter
+		groupErr error
+		contains []string
+		err      error
 	}{
 		{
-			desc: "success",
+			desc:     "view topology of a group and tag",
+			filter:   ui.TopologyFilter{GroupID: group.ID, Tag: "a&b"},
+			contains: []string{"topology-group", "selected", "tag=a%26b"},
 		},
 		{
-			desc:   "sdk error",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedRetreive,
+			desc:     "view topology with failing groups",
+			groupErr: sdkerr,
+			err:      ui.ErrFailedRetreive,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("ListDomainUsers", validSession.Domain.ID, sdk.PageMetadata{Offset: 0, Limit: 10}, validSession.Token).Return(validUsersPage, tc.sdkerr)
-			_, err := svc.Members(validSession, 1, 10)
+			sdkCall := sdkmock.On("Groups", mock.Anything, validSession.Token).Return(groupsPage, tc.groupErr)
+			res, err := svc.Topology(context.Background(), validSession, tc.filter)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "ListDomainUsers", validSession.Domain.ID, sdk.PageMetadata{Offset: 0, Limit: 10}, validSession.Token)
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
 			}
 			sdkCall.Unset()
 		})
 	}
 }
 
-func TestSendInvitation(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestGroupTree(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	hidden := generateID(t)
+	root := sdk.Group{ID: generateID(t), Name: "tree-root"}
+	child := sdk.Group{ID: generateID(t), Name: "tree-child", ParentID: root.ID}
+	grandchild := sdk.Group{ID: generateID(t), Name: "tree-grandchild", ParentID: child.ID}
+	orphan := sdk.Group{ID: generateID(t), Name: "tree-orphan", ParentID: hidden}
+	groupsPage := sdk.GroupsPage{Groups: []sdk.Group{root, child, grandchild, orphan}}
+	groupsPage.Total = 5
+	descendants := map[string][]sdk.Group{
+		root.ID:  {root, child, grandchild},
+		child.ID: {child, grandchild},
+	}
+	// The root and the child share a user, so the subtree of the root has
+	// two distinct users.
+	shared, own := sdk.User{ID: generateID(t)}, sdk.User{ID: generateID(t)}
+	members := map[string][]sdk.User{
+		root.ID:       {shared},
+		child.ID:      {shared},
+		grandchild.ID: {own},
+	}
+	channelsPage := sdk.GroupsPage{Groups: []sdk.Group{{ID: generateID(t)}}}
+	channelsPage.Total = 1
+
 	cases := []struct {
-		desc   string
-		sdkerr errors.SDKError
-		err    error
+		desc        string
+		parentID    string
+		page        uint64
+		groupsErr   error
+		parentErr   errors.SDKError
+		childrenErr error
+		contains    []string
+		absent      []string
+		err         error
 	}{
 		{
-			desc: "success",
+			desc:     "view root of the tree",
+			page:     1,
+			contains: []string{"tree-root", "tree-orphan", "1 subgroups", "2 below", "2 users", "1 channels", "Load more", `data-page="2"`},
+			absent:   []string{"tree-child</a>", "tree-grandchild</a>"},
 		},
 		{
-			desc:   "sdk error",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedSend,
+			desc:     "view last page of the root of the tree",
+			page:     2,
+			contains: []string{"tree-root"},
+			absent:   []string{"<html", "Load more"},
+		},
+		{
+			desc:     "view subgroups of a group",
+			parentID: root.ID,
+			page:     1,
+			contains: []string{"tree-child", "1 subgroups", "1 below", "2 users", "1 channels"},
+			absent:   []string{"<html", "tree-grandchild</a>", "tree-orphan"},
+		},
+		{
+			desc:      "view tree with failing groups",
+			page:      1,
+			groupsErr: sdkerr,
+			err:       ui.ErrFailedRetreive,
+		},
+		{
+			desc:      "view tree with failing parent",
+			page:      1,
+			parentErr: sdkerr,
+			err:       ui.ErrFailedRetreive,
+		},
+		{
+			desc:        "view subgroups with failing children",
+			parentID:    root.ID,
+			page:        1,
+			childrenErr: sdkerr,
+			err:         ui.ErrFailedRetreive,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("SendInvitation", sdk.Invitation{}, validSession.Token).Return(tc.sdkerr)
-			err := svc.SendInvitation(validSession.Token, sdk.Invitation{})
+			parentErr := tc.parentErr
+			if parentErr == nil {
+				parentErr = errors.NewSDKErrorWithStatus(fmt.Errorf("forbidden"), http.StatusForbidden)
+			}
+			sdkCall := sdkmock.On("Groups", mock.Anything, validSession.Token).Return(groupsPage, tc.groupsErr)
+			sdkCall1 := sdkmock.On("Group", hidden, validSession.Token).Return(sdk.Group{}, parentErr)
+			sdkCall2 := sdkmock.On("Children", mock.Anything, mock.Anything, validSession.Token).Return(func(id string, _ sdk.PageMetadata, _ string) (sdk.GroupsPage, errors.SDKError) {
+				page := sdk.GroupsPage{Groups: descendants[id]}
+				page.Total = uint64(len(page.Groups))
+				if tc.childrenErr != nil {
+					return sdk.GroupsPage{}, sdkerr
+				}
+				return page, nil
+			})
+			sdkCall3 := sdkmock.On("ListGroupUsers", mock.Anything, mock.Anything, validSession.Token).Return(func(id string, _ sdk.PageMetadata, _ string) (sdk.UsersPage, errors.SDKError) {
+				page := sdk.UsersPage{Users: members[id]}
+				page.Total = uint64(len(page.Users))
+				return page, nil
+			})
+			sdkCall4 := sdkmock.On("ListGroupChannels", mock.Anything, mock.Anything, validSession.Token).Return(channelsPage, nil)
+			res, err := svc.GroupTree(context.Background(), validSession, tc.parentID, tc.page, 4)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "SendInvitation", sdk.Invitation{}, validSession.Token)
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
+			}
+			for _, a := range tc.absent {
+				assert.NotContains(t, string(res), a)
 			}
 			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
+			sdkCall3.Unset()
+			sdkCall4.Unset()
 		})
 	}
 }
 
-func TestInvitations(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestMoveGroup(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	oldParent, newParent := generateID(t), generateID(t)
+	group := sdk.Group{ID: generateID(t), ParentID: oldParent}
+	descendant := sdk.Group{ID: generateID(t), ParentID: group.ID}
+	ancestors := []sdk.Group{{ID: newParent}, descendant}
+	// The deep descendant is further below the group than the groups
+	// service lists ancestors.
+	deep := descendant
+	for i := uint64(0); i < sdk.MaxLevel; i++ {
+		deep = sdk.Group{ID: generateID(t), ParentID: deep.ID}
+		ancestors = append(ancestors, deep)
+	}
+
 	cases := []struct {
-		desc   string
-		sdkerr errors.SDKError
-		err    error
+		desc        string
+		parentID    string
+		unassignErr error
+		assignErr   error
+		unassigned  bool
+		assigned    []string
+		err         error
 	}{
 		{
-			desc: "success",
+			desc:       "move group to a new parent",
+			parentID:   newParent,
+			unassigned: true,
+			assigned:   []string{newParent},
+		},
+		{
+			desc:       "move group to the root",
+			unassigned: true,
+		},
+		{
+			desc:     "move group to its parent",
+			parentID: oldParent,
+		},
+		{
+			desc:     "move group below itself",
+			parentID: group.ID,
+			err:      ui.ErrInvalidMove,
+		},
+		{
+			desc:     "move group below a descendant",
+			parentID: descendant.ID,
+			err:      ui.ErrInvalidMove,
+		},
+		{
+			desc:     "move group below a deep descendant",
+			parentID: deep.ID,
+			err:      ui.ErrInvalidMove,
+		},
+		{
+			desc:     "move group below an unknown group",
+			parentID: generateID(t),
+			err:      ui.ErrFailedRetreive,
+		},
+		{
+			desc:        "move group with failing unassign",
+			parentID:    newParent,
+			unassignErr: sdkerr,
+			unassigned:  true,
+			err:         ui.ErrFailedUpdate,
 		},
 		{
-			desc:   "sdk error",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedRetreive,
+			desc:       "move group with failing assign",
+			parentID:   newParent,
+			assignErr:  sdkerr,
+			unassigned: true,
+			assigned:   []string{newParent, oldParent},
+			err:        ui.ErrFailedUpdate,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			page := sdk.PageMetadata{
-				Offset:   0,
-				Limit:    10,
-				DomainID: "test",
-				State:    "pending",
+			var unassigned bool
+			var assigned []string
+			groups := make(map[string]sdk.Group)
+			for _, g := range append(ancestors, group) {
+				groups[g.ID] = g
 			}
-			sdkCall := sdkmock.On("Invitations", page, validSession.Token).Return(sdk.InvitationPage{}, tc.sdkerr)
-			_, err := svc.Invitations(validSession, "test", 1, 10)
+			sdkCall := sdkmock.On("Group", group.ID, validSession.Token).Return(group, nil)
+			// The groups service lists the group and at most MaxLevel
+			// groups above it.
+			sdkCall1 := sdkmock.On("Parents", mock.Anything, mock.Anything, validSession.Token).Return(func(id string, _ sdk.PageMetadata, _ string) (sdk.GroupsPage, errors.SDKError) {
+				var page sdk.GroupsPage
+				for g, ok := groups[id]; ok && uint64(len(page.Groups)) <= sdk.MaxLevel; g, ok = groups[g.ParentID] {
+					page.Groups = append(page.Groups, g)
+				}
+				if len(page.Groups) == 0 {
+					return sdk.GroupsPage{}, sdkerr
+				}
+				page.Total = uint64(len(page.Groups))
+				return page, nil
+			})
+			repoCall := sdkmock.On("UnassignParent", mock.Anything, validSession.Token, oldParent, group.ID).Return(tc.unassignErr).Run(func(args mock.Arguments) {
+				unassigned = true
+			})
+			repoCall1 := sdkmock.On("AssignParent", mock.Anything, validSession.Token, newParent, group.ID).Return(tc.assignErr).Run(func(args mock.Arguments) {
+				assigned = append(assigned, newParent)
+			})
+			repoCall2 := sdkmock.On("AssignParent", mock.Anything, validSession.Token, oldParent, group.ID).Return(nil).Run(func(args mock.Arguments) {
+				assigned = append(assigned, oldParent)
+			})
+			err := svc.MoveGroup(context.Background(), validSession, group.ID, tc.parentID)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "Invitations", page, validSession.Token)
-			}
+			assert.Equal(t, tc.unassigned, unassigned)
+			assert.Equal(t, tc.assigned, assigned)
 			sdkCall.Unset()
+			sdkCall1.Unset()
+			repoCall.Unset()
+			repoCall1.Unset()
+			repoCall2.Unset()
 		})
 	}
 }
 
-func TestAcceptInvitation(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestHygieneReport(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	wired := sdk.Thing{ID: generateID(t), Name: "hygiene-wired", Status: sdk.EnabledStatus}
+	lonely := sdk.Thing{ID: generateID(t), Name: "hygiene-lonely", Status: sdk.EnabledStatus}
+	thingsPage := sdk.ThingsPage{Things: []sdk.Thing{wired, lonely}}
+	thingsPage.Total = 2
+	stale := sdk.Thing{ID: generateID(t), Name: "hygiene-stale", Status: sdk.DisabledStatus}
+	retired := sdk.Thing{ID: generateID(t), Name: "hygiene-retired", Status: sdk.DisabledStatus}
+	disabledPage := sdk.ThingsPage{Things: []sdk.Thing{stale, retired}}
+	disabledPage.Total = 2
+
+	parent := sdk.Group{ID: generateID(t), Name: "hygiene-parent"}
+	member := sdk.Group{ID: generateID(t), Name: "hygiene-member", ParentID: parent.ID}
+	holder := sdk.Group{ID: generateID(t), Name: "hygiene-holder"}
+	empty := sdk.Group{ID: generateID(t), Name: "hygiene-empty"}
+	groupsPage := sdk.GroupsPage{Groups: []sdk.Group{parent, member, holder, empty}}
+	groupsPage.Total = 4
+
+	used := sdk.Channel{ID: generateID(t), Name: "hygiene-used"}
+	unused := sdk.Channel{ID: generateID(t), Name: "hygiene-unused", ParentID: holder.ID}
+	channelsPage := sdk.ChannelsPage{Channels: []sdk.Channel{used, unused}}
+	channelsPage.Total = 2
+	usedThings := sdk.ThingsPage{Things: []sdk.Thing{wired}}
+	usedThings.Total = 1
+	staleChannels := sdk.ChannelsPage{Channels: []sdk.Channel{used}}
+	staleChannels.Total = 1
+	memberUsers := sdk.UsersPage{Users: []sdk.User{{ID: generateID(t)}}}
+	memberUsers.Total = 1
+
+	listThings := func(pm sdk.PageMetadata, token string) sdk.ThingsPage {
+		if pm.Status == sdk.DisabledStatus {
+			return disabledPage
+		}
+		return thingsPage
+	}
+
 	cases := []struct {
-		desc   string
-		sdkerr errors.SDKError
-		err    error
+		desc        string
+		channelsErr error
+		contains    []string
+		absent      []string
+		err         error
 	}{
 		{
-			desc: "success",
+			desc:     "view hygiene report",
+			contains: []string{"hygiene-lonely", "hygiene-unused", "hygiene-stale", "hygiene-empty"},
+			absent:   []string{"hygiene-wired", "hygiene-used<", "hygiene-retired", "hygiene-parent", "hygiene-member", "hygiene-holder"},
 		},
 		{
-			desc:   "sdk error",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedAccept,
+			desc:        "view hygiene report with failing channels",
+			channelsErr: sdkerr,
+			err:         ui.ErrFailedRetreive,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("AcceptInvitation", "test", validSession.Token).Return(tc.sdkerr)
-			err := svc.AcceptInvitation(validSession.Token, "test")
+			sdkCall := sdkmock.On("Things", mock.Anything, validSession.Token).Return(listThings, nil)
+			sdkCall1 := sdkmock.On("Channels", mock.Anything, validSession.Token).Return(channelsPage, tc.channelsErr)
+			sdkCall2 := sdkmock.On("Groups", mock.Anything, validSession.Token).Return(groupsPage, nil)
+			sdkCall3 := sdkmock.On("ThingsByChannel", used.ID, mock.Anything, validSession.Token).Return(usedThings, nil)
+			sdkCall4 := sdkmock.On("ThingsByChannel", unused.ID, mock.Anything, validSession.Token).Return(sdk.ThingsPage{}, nil)
+			sdkCall5 := sdkmock.On("ChannelsByThing", stale.ID, mock.Anything, validSession.Token).Return(staleChannels, nil)
+			sdkCall6 := sdkmock.On("ChannelsByThing", retired.ID, mock.Anything, validSession.Token).Return(sdk.ChannelsPage{}, nil)
+			sdkCall7 := sdkmock.On("ListGroupUsers", member.ID, mock.Anything, validSession.Token).Return(memberUsers, nil)
+			sdkCall8 := sdkmock.On("ListGroupUsers", empty.ID, mock.Anything, validSession.Token).Return(sdk.UsersPage{}, nil)
+			sdkCall9 := sdkmock.On("ListGroupChannels", member.ID, mock.Anything, validSession.Token).Return(sdk.GroupsPage{}, nil)
+			sdkCall10 := sdkmock.On("ListGroupChannels", empty.ID, mock.Anything, validSession.Token).Return(sdk.GroupsPage{}, nil)
+			sdkCall11 := sdkmock.On("ReadMessages", mock.Anything, mock.Anything, validSession.Token).Return(sdk.MessagesPage{}, sdkerr)
+			res, err := svc.HygieneReport(context.Background(), validSession)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "AcceptInvitation", "test", validSession.Token)
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
+			}
+			for _, a := range tc.absent {
+				assert.NotContains(t, string(res), a)
 			}
 			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
+			sdkCall3.Unset()
+			sdkCall4.Unset()
+			sdkCall5.Unset()
+			sdkCall6.Unset()
+			sdkCall7.Unset()
+			sdkCall8.Unset()
+			sdkCall9.Unset()
+			sdkCall10.Unset()
+			sdkCall11.Unset()
 		})
 	}
 }
 
-func TestDeleteInvitation(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestHygieneCleanup(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	lonely := sdk.Thing{ID: generateID(t), Status: sdk.EnabledStatus}
+	wired := sdk.Thing{ID: generateID(t), Status: sdk.EnabledStatus}
+	stale := sdk.Thing{ID: generateID(t), Status: sdk.DisabledStatus}
+	channel := sdk.Channel{ID: generateID(t), Status: sdk.EnabledStatus}
+	group := sdk.Group{ID: generateID(t), Status: sdk.EnabledStatus}
+	connected := sdk.ChannelsPage{Channels: []sdk.Channel{{ID: generateID(t)}}}
+	connected.Total = 1
+
 	cases := []struct {
-		desc   string
-		sdkerr errors.SDKError
-		err    error
+		desc      string
+		cleanup   ui.HygieneCleanup
+		deleteErr error
+		contains  []string
+		err       error
 	}{
 		{
-			desc: "success",
+			desc:     "delete unconnected things",
+			cleanup:  ui.HygieneCleanup{Category: ui.HygieneUnconnectedThings, Action: ui.HygieneDelete, IDs: []string{lonely.ID, wired.ID, lonely.ID}},
+			contains: []string{"1 applied", "1 unchanged", "0 failed"},
 		},
 		{
-			desc:   "sdk error",
-			sdkerr: sdkerr,
-			err:    ui.ErrFailedDelete,
+			desc:     "disconnect disabled things",
+			cleanup:  ui.HygieneCleanup{Category: ui.HygieneDisabledConnected, Action: ui.HygieneDisconnect, IDs: []string{stale.ID}},
+			contains: []string{"1 applied"},
+		},
+		{
+			desc:      "delete empty channels with failing delete",
+			cleanup:   ui.HygieneCleanup{Category: ui.HygieneEmptyChannels, Action: ui.HygieneDelete, IDs: []string{channel.ID}},
+			deleteErr: sdkerr,
+			contains:  []string{"1 failed", "sdk error"},
+		},
+		{
+			desc:     "disable empty groups",
+			cleanup:  ui.HygieneCleanup{Category: ui.HygieneEmptyGroups, Action: ui.HygieneDisable, IDs: []string{group.ID}},
+			contains: []string{"1 applied"},
+		},
+		{
+			desc:    "clean up too many entities",
+			cleanup: ui.HygieneCleanup{Category: ui.HygieneEmptyGroups, Action: ui.HygieneDisable, IDs: make([]string, ui.MaxBulkEntities+1)},
+			err:     ui.ErrTooManyEntities,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("DeleteInvitation", "test", "test", validSession.Token).Return(tc.sdkerr)
-			err := svc.DeleteInvitation(validSession.Token, "test", "test")
+			sdkCall := sdkmock.On("Thing", lonely.ID, validSession.Token).Return(lonely, nil)
+			sdkCall1 := sdkmock.On("Thing", wired.ID, validSession.Token).Return(wired, nil)
+			sdkCall2 := sdkmock.On("Thing", stale.ID, validSession.Token).Return(stale, nil)
+			sdkCall3 := sdkmock.On("ChannelsByThing", lonely.ID, mock.Anything, validSession.Token).Return(sdk.ChannelsPage{}, nil)
+			sdkCall4 := sdkmock.On("ChannelsByThing", wired.ID, mock.Anything, validSession.Token).Return(connected, nil)
+			sdkCall5 := sdkmock.On("ChannelsByThing", stale.ID, mock.Anything, validSession.Token).Return(connected, nil)
+			sdkCall6 := sdkmock.On("DeleteThing", lonely.ID, validSession.Token).Return(nil)
+			sdkCall7 := sdkmock.On("DisconnectThing", stale.ID, connected.Channels[0].ID, validSession.Token).Return(nil)
+			sdkCall8 := sdkmock.On("Channel", channel.ID, validSession.Token).Return(channel, nil)
+			sdkCall9 := sdkmock.On("ThingsByChannel", channel.ID, mock.Anything, validSession.Token).Return(sdk.ThingsPage{}, nil)
+			sdkCall10 := sdkmock.On("DeleteChannel", channel.ID, validSession.Token).Return(tc.deleteErr)
+			sdkCall11 := sdkmock.On("Group", group.ID, validSession.Token).Return(group, nil)
+			sdkCall12 := sdkmock.On("Children", group.ID, mock.Anything, validSession.Token).Return(sdk.GroupsPage{}, nil)
+			sdkCall13 := sdkmock.On("ListGroupUsers", group.ID, mock.Anything, validSession.Token).Return(sdk.UsersPage{}, nil)
+			sdkCall14 := sdkmock.On("ListGroupChannels", group.ID, mock.Anything, validSession.Token).Return(sdk.GroupsPage{}, nil)
+			sdkCall15 := sdkmock.On("DisableGroup", group.ID, validSession.Token).Return(group, nil)
+			res, err := svc.HygieneCleanup(context.Background(), validSession, tc.cleanup)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "DeleteInvitation", "test", "test", validSession.Token)
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
 			}
 			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
+			sdkCall3.Unset()
+			sdkCall4.Unset()
+			sdkCall5.Unset()
+			sdkCall6.Unset()
+			sdkCall7.Unset()
+			sdkCall8.Unset()
+			sdkCall9.Unset()
+			sdkCall10.Unset()
+			sdkCall11.Unset()
+			sdkCall12.Unset()
+			sdkCall13.Unset()
+			sdkCall14.Unset()
+			sdkCall15.Unset()
 		})
 	}
 }
 
-func TestCreateDashboard(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestSecretRotations(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	schedule := ui.RotationSchedule{ID: generateID(t), Tag: "gateways", Interval: 24 * time.Hour, NextRunAt: time.Now()}
+	rotation := ui.SecretRotation{RunID: generateID(t), ThingID: generateID(t), ThingName: namesgen.Generate(), Trigger: ui.RotationScheduled, Status: ui.RotationRotated, Pending: true}
+	pm := ui.SecretRotationPageMeta{Offset: 0, Limit: 10, DomainID: validSession.Domain.ID}
+
 	cases := []struct {
-		desc           string
-		errUserProfile errors.SDKError
-		errCreate      error
-		err            error
+		desc         string
+		schedulesErr error
+		rotationsErr error
+		contains     []string
+		err          error
 	}{
 		{
-			desc: "success",
+			desc:     "view secret rotations",
+			contains: []string{schedule.Tag, "24 hours", rotation.ThingName, fmt.Sprintf("/things/rotations/%s/secrets", rotation.RunID)},
 		},
 		{
-			desc:           "sdk error",
-			errUserProfile: sdkerr,
-			err:            ui.ErrFailedRetrieveUserID,
+			desc:         "view secret rotations with schedules error",
+			schedulesErr: fmt.Errorf("failed to retrieve"),
+			err:          ui.ErrFailedRetreive,
 		},
 		{
-			desc:      "failed to create dashboard",
-			errCreate: fmt.Errorf("failed to create dashboard"),
-			err:       ui.ErrFailedDashboardSave,
+			desc:         "view secret rotations with history error",
+			rotationsErr: fmt.Errorf("failed to retrieve"),
+			err:          ui.ErrFailedRetreive,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("UserProfile", validSession.Token).Return(validUser, tc.errUserProfile)
-			repoCall := repo.On("Create", context.Background(), mock.Anything).Return(ui.Dashboard{}, tc.errCreate)
-			_, err := svc.CreateDashboard(context.Background(), validSession.Token, validDashboardReq)
+			repoCall := rotationRepo.On("RetrieveSchedules", context.Background(), validSession.Domain.ID).Return([]ui.RotationSchedule{schedule}, tc.schedulesErr)
+			repoCall1 := rotationRepo.On("RetrieveAll", context.Background(), pm).Return(ui.SecretRotationPage{Total: 1, Rotations: []ui.SecretRotation{rotation}}, tc.rotationsErr)
+			res, err := svc.SecretRotations(context.Background(), validSession, 1, 10)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "UserProfile", validSession.Token)
-				repoCall.Parent.AssertCalled(t, "Create", context.Background(), mock.Anything)
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
 			}
-			sdkCall.Unset()
 			repoCall.Unset()
+			repoCall1.Unset()
 		})
 	}
 }
 
-func TestViewDashboard(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestRotateThingSecrets(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	thing := sdk.Thing{ID: generateID(t), Name: namesgen.Generate()}
+	missing := generateID(t)
+
 	cases := []struct {
-		desc           string
-		errUserProfile errors.SDKError
-		errView        error
-		err            error
+		desc       string
+		thingIDs   []string
+		updateErr  errors.SDKError
+		saveErr    error
+		historyErr error
+		updates    int
+		contains   []string
+		err        error
 	}{
 		{
-			desc: "success",
+			desc:     "rotate secret of a thing",
+			thingIDs: []string{thing.ID},
+			updates:  1,
+			contains: []string{thing.Name, "1 rotated", "0 failed"},
 		},
 		{
-			desc:           "sdk error",
-			errUserProfile: sdkerr,
-			err:            ui.ErrFailedRetrieveUserID,
+			desc:     "rotate secret of a thing given twice",
+			thingIDs: []string{thing.ID, thing.ID},
+			updates:  1,
+			contains: []string{"1 things", "1 rotated"},
 		},
 		{
-			desc:    "failed to view dashboard",
-			errView: fmt.Errorf("failed to view dashboard"),
-			err:     ui.ErrFailedDashboardRetrieve,
+			desc:      "rotate secret with failing update",
+			thingIDs:  []string{thing.ID},
+			updateErr: sdkerr,
+			updates:   1,
+			contains:  []string{"0 rotated", "1 failed", "sdk error"},
+		},
+		{
+			desc:     "rotate secret of a missing thing",
+			thingIDs: []string{missing},
+			contains: []string{"1 failed", "failed to retrieve thing"},
+		},
+		{
+			desc:     "rotate secrets with repository error",
+			thingIDs: []string{thing.ID},
+			saveErr:  fmt.Errorf("failed to save"),
+			err:      ui.ErrFailedCreate,
+		},
+		{
+			desc:       "rotate secrets with failing history update",
+			thingIDs:   []string{thing.ID},
+			historyErr: fmt.Errorf("failed to update"),
+			updates:    1,
+			contains:   []string{"1 rotated", "could not be updated"},
+		},
+		{
+			desc:     "rotate secrets of too many things",
+			thingIDs: make([]string, ui.MaxBulkEntities+1),
+			err:      ui.ErrTooManyEntities,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("UserProfile", validSession.Token).Return(validUser, tc.errUserProfile)
-			repoCall := repo.On("Retrieve", context.Background(), "test", mock.Anything).Return(ui.Dashboard{}, tc.errView)
-			_, err := svc.ViewDashboard(context.Background(), validSession, "test")
+			var secrets []string
+			var saved, updated []ui.SecretRotation
+			sdkCall := sdkmock.On("Thing", thing.ID, validSession.Token).Return(thing, nil)
+			sdkCall1 := sdkmock.On("Thing", missing, validSession.Token).Return(sdk.Thing{}, sdkerr)
+			sdkCall2 := sdkmock.On("UpdateThingSecret", thing.ID, mock.Anything, validSession.Token).Return(thing, tc.updateErr).Run(func(args mock.Arguments) {
+				secrets = append(secrets, args.String(1))
+			})
+			repoCall := rotationRepo.On("Save", context.Background(), mock.Anything).Return(tc.saveErr).Run(func(args mock.Arguments) {
+				saved = append(saved, args.Get(1).(ui.SecretRotation))
+			})
+			repoCall1 := rotationRepo.On("UpdateRotations", context.Background(), mock.Anything).Return(tc.historyErr).Run(func(args mock.Arguments) {
+				updated = append(updated, args.Get(1).(ui.SecretRotation))
+			})
+			res, err := svc.RotateThingSecrets(context.Background(), validSession, tc.thingIDs)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "UserProfile", validSession.Token)
-				repoCall.Parent.AssertCalled(t, "Retrieve", context.Background(), "test", mock.Anything)
+			assert.Len(t, secrets, tc.updates)
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
+			}
+			if err == nil && tc.updateErr == nil && tc.updates > 0 {
+				assert.Contains(t, string(res), secrets[0], "expected new secret to be shown")
+			}
+			for _, r := range saved {
+				assert.Equal(t, ui.RotationManual, r.Trigger)
+				assert.Empty(t, r.Secret, "expected manual secrets not to be stored")
+				if r.Error == "" {
+					assert.Equal(t, ui.RotationStarted, r.Status, "expected rotation to be saved before the secret is changed")
+				}
+			}
+			for _, r := range updated {
+				assert.NotEqual(t, ui.RotationStarted, r.Status)
+				assert.Empty(t, r.Secret, "expected manual secrets not to be stored")
 			}
 			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
 			repoCall.Unset()
+			repoCall1.Unset()
 		})
 	}
 }
 
-func TestListDashboards(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestCreateRotationSchedule(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	login := sdk.Login{
+		Identity: validSession.User.Identity,
+		Secret:   password,
+		DomainID: validSession.Domain.ID,
+	}
+	token := sdk.Token{AccessToken: accessToken, RefreshToken: strings.Repeat("r", 32)}
+
 	cases := []struct {
-		desc           string
-		errUserProfile errors.SDKError
-		errRetrieve    error
-		err            error
+		desc     string
+		interval time.Duration
+		tokenErr errors.SDKError
+		saveErr  error
+		err      error
 	}{
 		{
-			desc: "success",
+			desc:     "create rotation schedule",
+			interval: 24 * time.Hour,
 		},
 		{
-			desc:           "sdk error",
-			errUserProfile: sdkerr,
-			err:            ui.ErrFailedRetrieveUserID,
+			desc:     "create rotation schedule with too short interval",
+			interval: time.Minute,
+			err:      ui.ErrFailedCreate,
 		},
 		{
-			desc:        "failed to retrieve dashboard",
-			errRetrieve: fmt.Errorf("failed to retrieve dashboard"),
-			err:         ui.ErrFailedRetreive,
+			desc:     "create rotation schedule with invalid password",
+			interval: 24 * time.Hour,
+			tokenErr: sdkerr,
+			err:      ui.ErrToken,
+		},
+		{
+			desc:     "create rotation schedule with repository error",
+			interval: 24 * time.Hour,
+			saveErr:  fmt.Errorf("failed to save"),
+			err:      ui.ErrFailedCreate,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			page := ui.DashboardPageMeta{
-				Offset:    0,
-				Limit:     10,
-				CreatedBy: validUser.ID,
-			}
-			sdkCall := sdkmock.On("UserProfile", validSession.Token).Return(validUser, tc.errUserProfile)
-			repoCall := repo.On("RetrieveAll", context.Background(), page).Return(ui.DashboardPage{}, tc.errRetrieve)
-			_, err := svc.ListDashboards(context.Background(), validSession.Token, 1, 10)
+			var saved ui.RotationSchedule
+			sdkCall := sdkmock.On("CreateToken", login).Return(token, tc.tokenErr)
+			repoCall := rotationRepo.On("SaveSchedule", context.Background(), mock.Anything).Return(tc.saveErr).Run(func(args mock.Arguments) {
+				saved = args.Get(1).(ui.RotationSchedule)
+			})
+			err := svc.CreateRotationSchedule(context.Background(), validSession, password, ui.RotationSchedule{Tag: "gateways", Interval: tc.interval})
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
 			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "UserProfile", validSession.Token)
-				repoCall.Parent.AssertCalled(t, "RetrieveAll", context.Background(), page)
+				assert.Equal(t, validSession.Domain.ID, saved.DomainID)
+				assert.Equal(t, validSession.User.ID, saved.UserID)
+				assert.NotEqual(t, token.RefreshToken, saved.RefreshToken, "expected refresh token to be encrypted")
+				assert.WithinDuration(t, time.Now().Add(tc.interval), saved.NextRunAt, time.Minute)
+				assert.WithinDuration(t, time.Now(), saved.TokenRefreshedAt, time.Minute)
 			}
 			sdkCall.Unset()
 			repoCall.Unset()
@@ -3786,117 +7271,362 @@ func TestListDashboards(t *testing.T) {
 	}
 }
 
-func TestDashboards(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestRunRotationSchedules(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// The refresh token of a schedule is encrypted on creation.
+	refreshToken := strings.Repeat("r", 32)
+	login := sdk.Login{Identity: validSession.User.Identity, Secret: password, DomainID: validSession.Domain.ID}
+	var schedule ui.RotationSchedule
+	sdkCall := sdkmock.On("CreateToken", login).Return(sdk.Token{AccessToken: accessToken, RefreshToken: refreshToken}, nil)
+	repoCall := rotationRepo.On("SaveSchedule", context.Background(), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		schedule = args.Get(1).(ui.RotationSchedule)
+	})
+	err = svc.CreateRotationSchedule(context.Background(), validSession, password, ui.RotationSchedule{Tag: "gateways", Interval: 24 * time.Hour})
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	sdkCall.Unset()
+	repoCall.Unset()
+
+	things := []sdk.Thing{
+		{ID: generateID(t), Name: namesgen.Generate()},
+		{ID: generateID(t), Name: namesgen.Generate()},
+	}
+	thingsPage := sdk.ThingsPage{Things: things}
+	thingsPage.Total = uint64(len(things))
+	now := time.Now().UTC()
 
 	cases := []struct {
-		desc string
-		prov oauth2.Provider
-		err  error
+		desc       string
+		notDue     bool
+		refreshErr errors.SDKError
+		updateErr  errors.SDKError
+		recordErr  error
+		rotated    int
+		failed     int
+		lastError  bool
+		expired    bool
+		err        error
 	}{
 		{
-			desc: "success",
-			prov: provider,
-			err:  nil,
+			desc:    "run rotation schedule",
+			rotated: 2,
+		},
+		{
+			desc:      "run rotation schedule with failing updates",
+			updateErr: sdkerr,
+			failed:    2,
+		},
+		{
+			desc:      "run rotation schedule with failing rotation update",
+			recordErr: fmt.Errorf("failed to update"),
+			lastError: true,
+			err:       ui.ErrFailedRotation,
+		},
+		{
+			desc:   "refresh token of a schedule that is not due",
+			notDue: true,
+		},
+		{
+			desc:       "run rotation schedule with failing token refresh",
+			refreshErr: sdkerr,
+			lastError:  true,
+			err:        ui.ErrFailedRotation,
+		},
+		{
+			desc:       "run rotation schedule with refused refresh token",
+			refreshErr: errors.NewSDKErrorWithStatus(fmt.Errorf("token expired"), http.StatusUnauthorized),
+			lastError:  true,
+			expired:    true,
+			err:        ui.ErrFailedRotation,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			page, err := svc.Dashboards(validSession)
+			var updated ui.RotationSchedule
+			var saved []ui.SecretRotation
+			var secrets []string
+			claimed := schedule
+			claimed.NextRunAt = now
+			nextRunAt := now.Add(schedule.Interval)
+			if tc.notDue {
+				claimed.NextRunAt, nextRunAt = now.Add(time.Hour), now.Add(time.Hour)
+			}
+			repoCall := rotationRepo.On("ClaimSchedules", context.Background(), now, now.Add(-6*time.Hour), now.Add(time.Hour)).Return([]ui.RotationSchedule{claimed}, nil)
+			sdkCall := sdkmock.On("RefreshToken", sdk.Login{DomainID: schedule.DomainID}, refreshToken).Return(sdk.Token{AccessToken: accessToken, RefreshToken: refreshToken}, tc.refreshErr)
+			repoCall1 := rotationRepo.On("UpdateSchedule", context.Background(), mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+				updated = args.Get(1).(ui.RotationSchedule)
+			})
+			sdkCall1 := sdkmock.On("Things", mock.Anything, accessToken).Return(thingsPage, nil)
+			sdkCall2 := sdkmock.On("UpdateThingSecret", mock.Anything, mock.Anything, accessToken).Return(sdk.Thing{}, tc.updateErr).Run(func(args mock.Arguments) {
+				secrets = append(secrets, args.String(1))
+			})
+			repoCall2 := rotationRepo.On("Save", context.Background(), mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+				for _, arg := range args[1:] {
+					r := arg.(ui.SecretRotation)
+					assert.Equal(t, ui.RotationStarted, r.Status, "expected rotation to be saved before the secret is changed")
+					assert.NotEmpty(t, r.Secret, "expected secret to be saved before it is changed")
+				}
+			})
+			repoCall4 := rotationRepo.On("UpdateRotations", context.Background(), mock.Anything, mock.Anything).Return(tc.recordErr).Run(func(args mock.Arguments) {
+				if tc.recordErr != nil {
+					return
+				}
+				for _, arg := range args[1:] {
+					saved = append(saved, arg.(ui.SecretRotation))
+				}
+			})
+			err := svc.RunRotationSchedules(context.Background(), now, 6*time.Hour)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				assert.NotEmpty(t, page, "expected page to be not empty")
+			assert.Equal(t, nextRunAt, updated.NextRunAt)
+			assert.Equal(t, tc.lastError, updated.LastError != "")
+			assert.Equal(t, tc.expired, updated.Expired)
+			if tc.refreshErr == nil {
+				assert.Equal(t, now, updated.TokenRefreshedAt, "expected token refresh to be recorded")
+			}
+			if tc.notDue {
+				assert.Empty(t, secrets, "expected secrets not to be rotated before the schedule is due")
+			}
+			var rotated, failed int
+			for _, r := range saved {
+				assert.Equal(t, ui.RotationScheduled, r.Trigger)
+				assert.Equal(t, schedule.ID, r.ScheduleID)
+				switch r.Status {
+				case ui.RotationRotated:
+					rotated++
+					assert.NotEmpty(t, r.Secret, "expected secret to be kept until downloaded")
+					assert.NotContains(t, secrets, r.Secret, "expected secret to be encrypted")
+				default:
+					failed++
+					assert.Empty(t, r.Secret)
+				}
+			}
+			assert.Equal(t, tc.rotated, rotated)
+			assert.Equal(t, tc.failed, failed)
+			if rotated > 0 {
+				repoCall3 := rotationRepo.On("TakeSecrets", context.Background(), validSession.Domain.ID, saved[0].RunID).Return(saved, nil)
+				res, err := svc.DownloadRotatedSecrets(context.Background(), validSession, saved[0].RunID)
+				require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+				records, err := csv.NewReader(bytes.NewReader(res)).ReadAll()
+				require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+				assert.Len(t, records, rotated+1)
+				for _, record := range records[1:] {
+					assert.Contains(t, secrets, record[2], "expected downloaded secret to be decrypted")
+				}
+				repoCall3.Unset()
 			}
+			repoCall.Unset()
+			repoCall1.Unset()
+			repoCall2.Unset()
+			repoCall4.Unset()
+			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
 		})
 	}
 }
 
-func TestUpdateDashboard(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestDownloadRotatedSecrets(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	runID := generateID(t)
+
 	cases := []struct {
-		desc           string
-		errUserProfile errors.SDKError
-		errUpdate      error
-		err            error
+		desc      string
+		rotations []ui.SecretRotation
+		takeErr   error
+		err       error
 	}{
 		{
-			desc: "success",
+			desc: "download secrets that were already downloaded",
+			err:  ui.ErrSecretsTaken,
 		},
 		{
-			desc:           "sdk error",
-			errUserProfile: sdkerr,
-			err:            ui.ErrFailedRetrieveUserID,
+			desc:      "download secrets that are not encrypted",
+			rotations: []ui.SecretRotation{{RunID: runID, ThingID: generateID(t), Secret: "secret"}},
+			err:       ui.ErrFailedRetreive,
 		},
 		{
-			desc:      "failed to update dashboard",
-			errUpdate: fmt.Errorf("failed to update dashboard"),
-			err:       ui.ErrFailedDashboardUpdate,
+			desc:    "download secrets with repository error",
+			takeErr: fmt.Errorf("failed to take"),
+			err:     ui.ErrFailedRetreive,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("UserProfile", validSession.Token).Return(validUser, tc.errUserProfile)
-			repoCall := repo.On("Update", context.Background(), "test", validUser.ID, validDashboardReq).Return(tc.errUpdate)
-			err := svc.UpdateDashboard(context.Background(), validSession.Token, "test", validDashboardReq)
+			repoCall := rotationRepo.On("TakeSecrets", context.Background(), validSession.Domain.ID, runID).Return(tc.rotations, tc.takeErr)
+			_, err := svc.DownloadRotatedSecrets(context.Background(), validSession, runID)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
-			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "UserProfile", validSession.Token)
-				repoCall.Parent.AssertCalled(t, "Update", context.Background(), "test", validUser.ID, validDashboardReq)
-			}
-			sdkCall.Unset()
 			repoCall.Unset()
 		})
 	}
 }
 
-func TestDeleteDashboard(t *testing.T) {
-	svc, err := ui.New(sdkmock, repo, idProvider, prefix, provider)
+func TestProvisionWizard(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	res, err := svc.ProvisionWizard(validSession)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Contains(t, string(res), "/things/provision")
+}
+
+func TestProvisionThing(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
+	thing := sdk.Thing{ID: generateID(t), Name: namesgen.Generate()}
+	existing := sdk.Channel{ID: generateID(t), Name: namesgen.Generate()}
+	created := sdk.Channel{ID: generateID(t), Name: namesgen.Generate()}
+	provision := ui.ThingProvision{
+		Name:         thing.Name,
+		ChannelIDs:   []string{existing.ID},
+		ChannelNames: []string{created.Name},
+		Bootstrap:    true,
+		ExternalID:   "external-id",
+		ExternalKey:  "external-key",
+		Dashboard:    true,
+		ValueName:    "temperature",
+	}
+
 	cases := []struct {
-		desc           string
-		errUserProfile errors.SDKError
-		errDelete      error
-		err            error
+		desc         string
+		provision    ui.ThingProvision
+		channelErr   errors.SDKError
+		createErr    errors.SDKError
+		connectErr   errors.SDKError
+		bootstrapErr errors.SDKError
+		dashboardErr error
+		deleteErr    errors.SDKError
+		removed      []string
+		contains     []string
+		err          error
 	}{
 		{
-			desc: "success",
+			desc:      "provision thing with bootstrap and dashboard",
+			provision: provision,
+			contains:  []string{thing.ID, existing.Name, created.Name, "external-key", "/dashboards/"},
 		},
 		{
-			desc:           "sdk error",
-			errUserProfile: sdkerr,
-			err:            ui.ErrFailedRetrieveUserID,
+			desc:      "provision thing only",
+			provision: ui.ThingProvision{Name: thing.Name},
+			contains:  []string{thing.ID},
 		},
 		{
-			desc:      "failed to delete dashboard",
-			errDelete: fmt.Errorf("failed to delete dashboard"),
-			err:       ui.ErrFailedDashboardDelete,
+			desc:       "provision thing with missing channel",
+			provision:  provision,
+			channelErr: sdkerr,
+			err:        ui.ErrFailedProvision,
+		},
+		{
+			desc:      "provision thing with invalid bootstrap content",
+			provision: ui.ThingProvision{Name: thing.Name, Bootstrap: true, Content: "{"},
+			err:       ui.ErrInvalidContent,
+		},
+		{
+			desc:      "provision thing with failing channel creation",
+			provision: provision,
+			createErr: sdkerr,
+			removed:   []string{"thing"},
+			err:       ui.ErrFailedProvision,
+		},
+		{
+			desc:       "provision thing with failing connection",
+			provision:  provision,
+			connectErr: sdkerr,
+			removed:    []string{"channel", "thing"},
+			err:        ui.ErrFailedProvision,
+		},
+		{
+			desc:         "provision thing with failing bootstrap",
+			provision:    provision,
+			bootstrapErr: sdkerr,
+			removed:      []string{"channel", "thing"},
+			err:          ui.ErrFailedProvision,
+		},
+		{
+			desc:         "provision thing with failing dashboard",
+			provision:    provision,
+			dashboardErr: fmt.Errorf("failed to save"),
+			removed:      []string{"bootstrap", "channel", "thing"},
+			err:          ui.ErrFailedProvision,
+		},
+		{
+			desc:         "provision thing with failing rollback",
+			provision:    provision,
+			dashboardErr: fmt.Errorf("failed to save"),
+			deleteErr:    sdkerr,
+			removed:      []string{"bootstrap", "channel", "thing"},
+			err:          ui.ErrProvisionRollback,
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			sdkCall := sdkmock.On("UserProfile", validSession.Token).Return(validUser, tc.errUserProfile)
-			repoCall := repo.On("Delete", context.Background(), "test", validUser.ID).Return(tc.errDelete)
-			err := svc.DeleteDashboard(context.Background(), validSession.Token, "test")
+			var removed []string
+			var dashboard ui.Dashboard
+			sdkCall := sdkmock.On("Channel", existing.ID, validSession.Token).Return(existing, tc.channelErr)
+			sdkCall1 := sdkmock.On("CreateThing", mock.Anything, validSession.Token).Return(
+				func(th sdk.Thing, _ string) sdk.Thing {
+					thing.Credentials = th.Credentials
+					return thing
+				}, nil)
+			sdkCall2 := sdkmock.On("CreateChannel", mock.Anything, validSession.Token).Return(created, tc.createErr)
+			sdkCall3 := sdkmock.On("ConnectThing", thing.ID, existing.ID, validSession.Token).Return(nil)
+			sdkCall4 := sdkmock.On("ConnectThing", thing.ID, created.ID, validSession.Token).Return(tc.connectErr)
+			sdkCall5 := sdkmock.On("AddBootstrap", mock.Anything, validSession.Token).Return(thing.ID, tc.bootstrapErr)
+			sdkCall6 := sdkmock.On("DeleteThing", thing.ID, validSession.Token).Return(tc.deleteErr).Run(func(args mock.Arguments) {
+				removed = append(removed, "thing")
+			})
+			sdkCall7 := sdkmock.On("DeleteChannel", created.ID, validSession.Token).Return(nil).Run(func(args mock.Arguments) {
+				removed = append(removed, "channel")
+			})
+			sdkCall8 := sdkmock.On("RemoveBootstrap", thing.ID, validSession.Token).Return(nil).Run(func(args mock.Arguments) {
+				removed = append(removed, "bootstrap")
+			})
+			repoCall := repo.On("Create", context.Background(), mock.Anything).Return(ui.Dashboard{}, tc.dashboardErr).Run(func(args mock.Arguments) {
+				dashboard = args.Get(1).(ui.Dashboard)
+			})
+			res, err := svc.ProvisionThing(context.Background(), validSession, tc.provision)
 			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			assert.Equal(t, tc.removed, removed)
+			for _, c := range tc.contains {
+				assert.Contains(t, string(res), c)
+			}
 			if err == nil {
-				sdkCall.Parent.AssertCalled(t, "UserProfile", validSession.Token)
-				repoCall.Parent.AssertCalled(t, "Delete", context.Background(), "test", validUser.ID)
+				assert.NotEmpty(t, thing.Credentials.Secret, "expected a generated secret")
+				assert.Contains(t, string(res), thing.Credentials.Secret)
+				if tc.provision.Dashboard {
+					var layout struct {
+						Items []struct {
+							WidgetID string `json:"widgetID"`
+						} `json:"items"`
+					}
+					err := json.Unmarshal([]byte(dashboard.Layout), &layout)
+					require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+					assert.Len(t, layout.Items, 2)
+					var metadata map[string]map[string]string
+					err = json.Unmarshal([]byte(dashboard.Metadata), &metadata)
+					require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+					for _, item := range layout.Items {
+						assert.Equal(t, thing.ID, metadata[item.WidgetID]["thing"])
+						assert.Equal(t, tc.provision.ValueName, metadata[item.WidgetID]["valueName"])
+					}
+				}
 			}
 			sdkCall.Unset()
+			sdkCall1.Unset()
+			sdkCall2.Unset()
+			sdkCall3.Unset()
+			sdkCall4.Unset()
+			sdkCall5.Unset()
+			sdkCall6.Unset()
+			sdkCall7.Unset()
+			sdkCall8.Unset()
 			repoCall.Unset()
 		})
 	}
 }
-
-func generateID(t *testing.T) string {
-	id, err := idProvider.ID()
-	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
-	return id
-}
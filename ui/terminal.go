@@ -0,0 +1,186 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/absmach/agent/pkg/bootstrap"
+	"github.com/absmach/magistrala/pkg/errors"
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+	mgsenml "github.com/absmach/senml"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+const (
+	terminalDisconnectQuiesce = 250
+	terminalUnsubscribeWait   = 5 * time.Second
+	terminalBrokerWait        = 10 * time.Second
+
+	channelTypeKey     = "type"
+	controlChannelType = "control"
+	dataChannelType    = "data"
+)
+
+// Terminal is a remote terminal opened on the agent of a bootstrapped thing.
+// It keeps a single MQTT client connected for as long as it is open and
+// routes the agent responses to the commands waiting for them.
+type Terminal struct {
+	ThingID   string
+	ChannelID string
+
+	client   mqtt.Client
+	pubTopic string
+	subTopic string
+
+	mu      sync.Mutex
+	pending map[string]chan string
+	closed  bool
+}
+
+func newTerminal(thingID, channelID, pubTopic, subTopic string) *Terminal {
+	return &Terminal{
+		ThingID:   thingID,
+		ChannelID: channelID,
+		pubTopic:  pubTopic,
+		subTopic:  subTopic,
+		pending:   make(map[string]chan string),
+	}
+}
+
+// Close unsubscribes from the agent responses and disconnects the MQTT
+// client. Commands still waiting for a response are left to be canceled by
+// their callers. Closing a terminal more than once has no effect.
+func (t *Terminal) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	t.mu.Unlock()
+
+	defer t.client.Disconnect(terminalDisconnectQuiesce)
+
+	token := t.client.Unsubscribe(t.subTopic)
+	if !token.WaitTimeout(terminalUnsubscribeWait) {
+		return ErrTerminalTimeout
+	}
+
+	return token.Error()
+}
+
+// register adds a command waiting for the response with the given id.
+func (t *Terminal) register(id string) (chan string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil, ErrTerminalClosed
+	}
+	ch := make(chan string, 1)
+	t.pending[id] = ch
+
+	return ch, nil
+}
+
+func (t *Terminal) unregister(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.pending, id)
+}
+
+// handle passes an agent response to the command it answers. Responses to
+// commands that are no longer waiting, such as canceled ones, are dropped.
+func (t *Terminal) handle(_ mqtt.Client, m mqtt.Message) {
+	var records []mgsenml.Record
+	if err := json.Unmarshal(m.Payload(), &records); err != nil || len(records) == 0 || records[0].StringValue == nil {
+		return
+	}
+	id := strings.TrimSuffix(records[0].BaseName, ":")
+
+	t.mu.Lock()
+	ch, ok := t.pending[id]
+	t.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	select {
+	case ch <- *records[0].StringValue:
+	default:
+	}
+}
+
+// controlChannel returns the channel the agent of a bootstrapped thing
+// listens to for commands. A requested channel is used as long as it is
+// connected to the config. Otherwise the channel is the control channel of
+// the agent config in the bootstrap content, then the first channel whose
+// metadata type is "control", and finally the channel the agent itself
+// picks when it bootstraps: the first one, or the second one when the first
+// is a data channel.
+func controlChannel(cfg sdk.BootstrapConfig, content bootstrap.ServicesConfig, channelID string) (string, error) {
+	channels := bootstrapChannels(cfg)
+	if len(channels) == 0 {
+		return "", ErrTerminalNoChannel
+	}
+
+	find := func(id string) bool {
+		for _, ch := range channels {
+			if ch.ID == id {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case channelID != "":
+		if !find(channelID) {
+			return "", errors.Wrap(ErrTerminalChannel, fmt.Errorf("channel %s", channelID))
+		}
+		return channelID, nil
+	case content.Agent.Channels.Control != "" && find(content.Agent.Channels.Control):
+		return content.Agent.Channels.Control, nil
+	}
+
+	for _, ch := range channels {
+		if channelType(ch) == controlChannelType {
+			return ch.ID, nil
+		}
+	}
+	if len(channels) > 1 && channelType(channels[0]) == dataChannelType {
+		return channels[1].ID, nil
+	}
+
+	return channels[0].ID, nil
+}
+
+// bootstrapChannels returns the channels of a bootstrap config, which the
+// SDK decodes either as channels or as channel ids.
+func bootstrapChannels(cfg sdk.BootstrapConfig) []sdk.Channel {
+	switch channels := cfg.Channels.(type) {
+	case []sdk.Channel:
+		return channels
+	case []string:
+		chs := make([]sdk.Channel, len(channels))
+		for i, id := range channels {
+			chs[i] = sdk.Channel{ID: id}
+		}
+		return chs
+	default:
+		return nil
+	}
+}
+
+func channelType(ch sdk.Channel) string {
+	t, _ := ch.Metadata[channelTypeKey].(string)
+
+	return t
+}
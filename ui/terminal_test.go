@@ -0,0 +1,222 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/absmach/magistrala-ui/ui"
+	"github.com/absmach/magistrala/pkg/errors"
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+	mgsenml "github.com/absmach/senml"
+	mqttserver "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// silentCommand is never answered by the test agent.
+const silentCommand = "sleep 60"
+
+// startAgent starts an in-process MQTT broker with an agent answering the
+// exec commands sent on any channel. The agent replies with the channel the
+// command was received on followed by the command itself. It returns the
+// broker url.
+func startAgent(t *testing.T) (*mqttserver.Server, string) {
+	server := mqttserver.New(&mqttserver.Options{
+		InlineClient: true,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	err := server.AddHook(new(auth.AllowHook), nil)
+	require.Nil(t, err, fmt.Sprintf("add broker hook unexpected error: %s", err))
+
+	tcp := listeners.NewTCP("tcp", "127.0.0.1:0", nil)
+	err = server.AddListener(tcp)
+	require.Nil(t, err, fmt.Sprintf("add broker listener unexpected error: %s", err))
+
+	go func() {
+		_ = server.Serve()
+	}()
+	t.Cleanup(func() {
+		server.Close()
+	})
+
+	err = server.Subscribe("channels/+/messages/req", 1, func(_ *mqttserver.Client, _ packets.Subscription, pk packets.Packet) {
+		var req []mgsenml.Record
+		if err := json.Unmarshal(pk.Payload, &req); err != nil || len(req) == 0 || req[0].StringValue == nil {
+			return
+		}
+		if *req[0].StringValue == silentCommand {
+			return
+		}
+		channelID := strings.Split(pk.TopicName, "/")[1]
+		result := fmt.Sprintf("%s %s", channelID, *req[0].StringValue)
+		res, err := json.Marshal([]mgsenml.Record{{BaseName: req[0].BaseName + ":", Name: "exec", StringValue: &result}})
+		if err != nil {
+			return
+		}
+		// Publishing from within the handler would block the broker.
+		go func() {
+			_ = server.Publish(fmt.Sprintf("channels/%s/messages/res/exec", channelID), res, false, 0)
+		}()
+	})
+	require.Nil(t, err, fmt.Sprintf("subscribe agent unexpected error: %s", err))
+
+	return server, "tcp://" + tcp.Address()
+}
+
+func TestTerminalControlChannel(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, brokerURL := startAgent(t)
+	thingID := generateID(t)
+	data := sdk.Channel{ID: generateID(t), Metadata: sdk.Metadata{"type": "data"}}
+	control := sdk.Channel{ID: generateID(t), Metadata: sdk.Metadata{"type": "control"}}
+	plain := sdk.Channel{ID: generateID(t)}
+	content := fmt.Sprintf(`{"agent":{"mqtt":{"url":%q}}}`, brokerURL)
+
+	cases := []struct {
+		desc      string
+		cfg       sdk.BootstrapConfig
+		channelID string
+		control   string
+	}{
+		{
+			desc:      "open terminal on requested channel",
+			cfg:       sdk.BootstrapConfig{ThingID: thingID, Content: content, Channels: []sdk.Channel{control, plain}},
+			channelID: plain.ID,
+			control:   plain.ID,
+		},
+		{
+			desc:    "open terminal on channel of the agent config",
+			cfg:     sdk.BootstrapConfig{ThingID: thingID, Content: fmt.Sprintf(`{"agent":{"channels":{"control":%q},"mqtt":{"url":%q}}}`, plain.ID, brokerURL), Channels: []sdk.Channel{control, plain}},
+			control: plain.ID,
+		},
+		{
+			desc:    "open terminal on control channel",
+			cfg:     sdk.BootstrapConfig{ThingID: thingID, Content: content, Channels: []sdk.Channel{plain, control}},
+			control: control.ID,
+		},
+		{
+			desc:    "open terminal after data channel",
+			cfg:     sdk.BootstrapConfig{ThingID: thingID, Content: content, Channels: []sdk.Channel{data, plain}},
+			control: plain.ID,
+		},
+		{
+			desc:    "open terminal on first channel",
+			cfg:     sdk.BootstrapConfig{ThingID: thingID, Content: content, Channels: []string{plain.ID, data.ID}},
+			control: plain.ID,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repoCall := policyRepo.On("Retrieve", mock.Anything, validSession.Domain.ID).Return(ui.TerminalPolicy{}, nil)
+			repoCall1 := auditRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+			repoCall2 := auditRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+			sdkCall := sdkmock.On("ViewBootstrap", thingID, validSession.Token).Return(tc.cfg, nil)
+
+			term, err := svc.OpenTerminal(context.Background(), validSession, thingID, tc.channelID)
+			require.Nil(t, err, fmt.Sprintf("open terminal unexpected error: %s", err))
+			assert.Equal(t, tc.control, term.ChannelID)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			res, err := svc.ProcessTerminalCommand(ctx, validSession, term, "uptime")
+			cancel()
+			assert.Nil(t, err, fmt.Sprintf("process terminal command unexpected error: %s", err))
+			assert.Equal(t, fmt.Sprintf("%s uptime", tc.control), res)
+			assert.Nil(t, term.Close())
+
+			repoCall.Unset()
+			repoCall1.Unset()
+			repoCall2.Unset()
+			sdkCall.Unset()
+		})
+	}
+}
+
+func TestTerminalCommand(t *testing.T) {
+	svc, err := ui.New(sdkmock, repo, tokenRepo, twoFactorRepo, auditRepo, policyRepo, templateRepo, rotationRepo, encKey, idProvider, prefix, provider)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	server, brokerURL := startAgent(t)
+	thingID := generateID(t)
+	channelID := generateID(t)
+	cfg := sdk.BootstrapConfig{
+		ThingID:  thingID,
+		Content:  fmt.Sprintf(`{"agent":{"mqtt":{"url":%q}}}`, brokerURL),
+		Channels: []sdk.Channel{{ID: channelID}},
+	}
+
+	repoCall := policyRepo.On("Retrieve", mock.Anything, validSession.Domain.ID).Return(ui.TerminalPolicy{}, nil)
+	repoCall1 := auditRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	repoCall2 := auditRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	sdkCall := sdkmock.On("ViewBootstrap", thingID, validSession.Token).Return(cfg, nil)
+	defer func() {
+		repoCall.Unset()
+		repoCall1.Unset()
+		repoCall2.Unset()
+		sdkCall.Unset()
+	}()
+
+	clients := server.Clients.Len()
+	term, err := svc.OpenTerminal(context.Background(), validSession, thingID, "")
+	require.Nil(t, err, fmt.Sprintf("open terminal unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		command string
+		timeout time.Duration
+		res     string
+		err     error
+	}{
+		{
+			desc:    "process terminal command",
+			command: "uptime",
+			timeout: 5 * time.Second,
+			res:     fmt.Sprintf("%s uptime", channelID),
+		},
+		{
+			desc:    "process terminal command with arguments",
+			command: "ls -la /tmp",
+			timeout: 5 * time.Second,
+			res:     fmt.Sprintf("%s ls -la /tmp", channelID),
+		},
+		{
+			desc:    "process terminal command without response",
+			command: silentCommand,
+			timeout: 100 * time.Millisecond,
+			err:     ui.ErrTerminalCanceled,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+			defer cancel()
+			res, err := svc.ProcessTerminalCommand(ctx, validSession, term, tc.command)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("expected error: %s, got: %s", tc.err, err))
+			assert.Equal(t, tc.res, res)
+		})
+	}
+
+	assert.Nil(t, term.Close())
+	assert.Eventually(t, func() bool {
+		return server.Clients.Len() == clients
+	}, 5*time.Second, 10*time.Millisecond, "expected the terminal client to be disconnected")
+
+	_, err = svc.ProcessTerminalCommand(context.Background(), validSession, term, "uptime")
+	assert.True(t, errors.Contains(err, ui.ErrTerminalClosed), fmt.Sprintf("expected error: %s, got: %s", ui.ErrTerminalClosed, err))
+}
@@ -0,0 +1,211 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// TokenScope limits the API endpoints a personal access token may call.
+type TokenScope string
+
+const (
+	EntitiesReadScope   TokenScope = "entities:read"
+	DataReadScope       TokenScope = "data:read"
+	DashboardsReadScope TokenScope = "dashboards:read"
+)
+
+// TokenScopes lists all scopes that can be granted to a personal access token.
+var TokenScopes = []TokenScope{EntitiesReadScope, DataReadScope, DashboardsReadScope}
+
+// PersonalTokenPrefix is prepended to every generated personal access token.
+const PersonalTokenPrefix = "mgpat_"
+
+// An upstream access token is renewed this long before it expires, so that
+// it does not expire while a request made with it is in flight.
+const accessTokenExpiryMargin = time.Minute
+
+type PersonalToken struct {
+	ID           string       `json:"id" db:"id"`
+	UserID       string       `json:"user_id" db:"user_id"`
+	DomainID     string       `json:"domain_id" db:"domain_id"`
+	Name         string       `json:"name" db:"name"`
+	Hash         string       `json:"-" db:"token_hash"`
+	Scopes       []TokenScope `json:"scopes" db:"scopes"`
+	RefreshToken string       `json:"-" db:"refresh_token"`
+	ExpiresAt    time.Time    `json:"expires_at" db:"expires_at"`
+	LastUsedAt   time.Time    `json:"last_used_at,omitempty" db:"last_used_at"`
+	RefreshedAt  time.Time    `json:"-" db:"refreshed_at"`
+	Revoked      bool         `json:"revoked" db:"revoked"`
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
+}
+
+// HasScope reports whether the token was granted the given scope.
+func (pt PersonalToken) HasScope(scope TokenScope) bool {
+	for _, s := range pt.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Expired reports whether the token is past its expiry time.
+func (pt PersonalToken) Expired() bool {
+	return !pt.ExpiresAt.IsZero() && time.Now().After(pt.ExpiresAt)
+}
+
+type PersonalTokenPage struct {
+	Total  uint64          `json:"total"`
+	Offset uint64          `json:"offset"`
+	Limit  uint64          `json:"limit"`
+	Tokens []PersonalToken `json:"tokens"`
+}
+
+type PersonalTokenPageMeta struct {
+	Total  uint64 `json:"total" db:"total"`
+	Offset uint64 `json:"offset" db:"offset"`
+	Limit  uint64 `json:"limit" db:"limit"`
+	UserID string `json:"user_id" db:"user_id"`
+}
+
+type PersonalTokenReq struct {
+	Name      string       `json:"name"`
+	Scopes    []TokenScope `json:"scopes"`
+	ExpiresAt time.Time    `json:"expires_at"`
+}
+
+// PersonalTokenRepository provides an interface for interacting with the personal access token storage.
+//
+//go:generate mockery --name PersonalTokenRepository --output=./mocks --filename tokens.go --quiet --note "Copyright (c) Abstract Machines"
+type PersonalTokenRepository interface {
+	// Persists a personal access token for a user. A non-nil error is
+	// returned to indicate a failure to persist.
+	Create(ctx context.Context, pt PersonalToken) (PersonalToken, error)
+
+	// Retrieves a personal access token by the hash of its value. A non-nil
+	// error is returned to indicate a failure to retrieve.
+	RetrieveByHash(ctx context.Context, hash string) (PersonalToken, error)
+
+	// Retrieves all personal access tokens for a user. A non-nil error is
+	// returned to indicate a failure to retrieve all.
+	RetrieveAll(ctx context.Context, page PersonalTokenPageMeta) (PersonalTokenPage, error)
+
+	// Retrieves the personal access tokens that are neither revoked nor
+	// expired and whose refresh token was last renewed before the given
+	// time. A non-nil error is returned to indicate a failure to retrieve.
+	RetrieveStale(ctx context.Context, refreshedBefore time.Time) ([]PersonalToken, error)
+
+	// Replaces the stored refresh token and records when it was renewed,
+	// provided the stored refresh token is still the current one. It reports
+	// whether the refresh token was replaced. A non-nil error is returned to
+	// indicate a failure to update.
+	UpdateRefreshToken(ctx context.Context, id, current, refreshToken string, refreshedAt time.Time) (bool, error)
+
+	// Updates the last time the personal access token was used. A non-nil
+	// error is returned to indicate a failure to update.
+	UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error
+
+	// Revokes a personal access token for a user. A non-nil error is returned
+	// to indicate a failure to revoke.
+	Revoke(ctx context.Context, id, userID string) error
+}
+
+// RunTokenRefreshWorker renews, every interval until the context is done, the
+// refresh tokens of the personal access tokens older than tokenMaxAge, so
+// that unused tokens keep working until their expiry date. Failures are
+// reported by the service middleware.
+func RunTokenRefreshWorker(ctx context.Context, svc Service, interval, tokenMaxAge time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			_ = svc.RefreshPersonalTokens(ctx, now, tokenMaxAge)
+		}
+	}
+}
+
+type cachedAccessToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// accessTokenCache keeps the upstream access tokens of personal access
+// tokens until they are about to expire, so that the refresh token is only
+// used when a new access token is needed.
+type accessTokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]cachedAccessToken
+}
+
+func newAccessTokenCache() *accessTokenCache {
+	return &accessTokenCache{tokens: make(map[string]cachedAccessToken)}
+}
+
+func (c *accessTokenCache) get(id string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.tokens[id]
+	if !ok || time.Now().Add(accessTokenExpiryMargin).After(cached.expiresAt) {
+		return "", false
+	}
+
+	return cached.token, true
+}
+
+// set caches the access token until its expiry time. Tokens without an
+// expiry time are not cached.
+func (c *accessTokenCache) set(id, token string) {
+	expiresAt, ok := accessTokenExpiry(token)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for key, cached := range c.tokens {
+		if now.After(cached.expiresAt) {
+			delete(c.tokens, key)
+		}
+	}
+	c.tokens[id] = cachedAccessToken{token: token, expiresAt: expiresAt}
+}
+
+func (c *accessTokenCache) remove(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.tokens, id)
+}
+
+// accessTokenExpiry reads the expiry time of an upstream access token. The
+// token is not verified, since it was just issued by the users service.
+func accessTokenExpiry(token string) (time.Time, bool) {
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return time.Time{}, false
+	}
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok {
+		return time.Time{}, false
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(exp), 0), true
+}
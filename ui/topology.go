@@ -0,0 +1,282 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	sdk "github.com/absmach/magistrala/pkg/sdk/go"
+	"golang.org/x/sync/errgroup"
+)
+
+// The kinds of edges of the topology graph. A connection links a thing to a
+// channel and a parent edge links a channel or group to its parent group.
+const (
+	EdgeConnection = "connection"
+	EdgeParent     = "parent"
+)
+
+const (
+	// MaxTopologyNodes limits the size of the topology graph.
+	MaxTopologyNodes = 2000
+
+	topologyConcurrency = 10
+)
+
+// TopologyFilter narrows the topology graph to a group, with its subgroups,
+// channels and the things connected to them, or to the things with a tag,
+// with their channels and groups.
+type TopologyFilter struct {
+	GroupID string
+	Tag     string
+}
+
+// TopologyNode is a thing, channel or group of the topology graph.
+type TopologyNode struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// TopologyEdge links two nodes of the topology graph.
+type TopologyEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Type   string `json:"type"`
+}
+
+// Topology is the graph of the things, channels and groups of a domain.
+type Topology struct {
+	Nodes []TopologyNode `json:"nodes"`
+	Edges []TopologyEdge `json:"edges"`
+}
+
+// topology assembles the topology graph of the domain. The things of every
+// channel are listed concurrently, topologyConcurrency channels at a time.
+func (us *uiService) topology(token string, filter TopologyFilter) (Topology, error) {
+	groups, err := us.allGroups(token)
+	if err != nil {
+		return Topology{}, err
+	}
+	channels, err := us.allChannels(token)
+	if err != nil {
+		return Topology{}, err
+	}
+
+	if filter.GroupID != "" {
+		subtree := groupSubtree(groups, filter.GroupID)
+		groups = slices.DeleteFunc(groups, func(g sdk.Group) bool { return !subtree[g.ID] })
+		channels = slices.DeleteFunc(channels, func(ch sdk.Channel) bool { return !subtree[ch.ParentID] })
+	}
+	if len(groups)+len(channels) > MaxTopologyNodes {
+		return Topology{}, ErrTooManyEntities
+	}
+
+	connected, err := us.channelThings(token, channels)
+	if err != nil {
+		return Topology{}, err
+	}
+
+	var things []sdk.Thing
+	if filter.GroupID == "" {
+		// Things connected to no channel only show up in the listing.
+		if things, err = us.allThings(token); err != nil {
+			return Topology{}, err
+		}
+	}
+	seen := make(map[string]bool)
+	for _, th := range things {
+		seen[th.ID] = true
+	}
+	for _, chThings := range connected {
+		for _, th := range chThings {
+			if !seen[th.ID] {
+				seen[th.ID] = true
+				things = append(things, th)
+			}
+		}
+	}
+	if filter.Tag != "" {
+		things = slices.DeleteFunc(things, func(th sdk.Thing) bool { return !slices.Contains(th.Tags, filter.Tag) })
+	}
+	if len(groups)+len(channels)+len(things) > MaxTopologyNodes {
+		return Topology{}, ErrTooManyEntities
+	}
+
+	kept := make(map[string]bool, len(things))
+	for _, th := range things {
+		kept[th.ID] = true
+	}
+
+	var topo Topology
+	for _, th := range things {
+		topo.Nodes = append(topo.Nodes, TopologyNode{ID: th.ID, Name: th.Name, Type: ThingsEntity, URL: fmt.Sprintf("%s/things/%s", us.prefix, th.ID)})
+	}
+
+	used := make(map[string]bool)
+	for i, ch := range channels {
+		linked := false
+		for _, th := range connected[i] {
+			if kept[th.ID] {
+				linked = true
+				topo.Edges = append(topo.Edges, TopologyEdge{Source: th.ID, Target: ch.ID, Type: EdgeConnection})
+			}
+		}
+		if filter.Tag != "" && !linked {
+			continue
+		}
+		used[ch.ID] = true
+		topo.Nodes = append(topo.Nodes, TopologyNode{ID: ch.ID, Name: ch.Name, Type: ChannelsEntity, URL: fmt.Sprintf("%s/channels/%s", us.prefix, ch.ID)})
+	}
+
+	// With a tag, only the groups above the remaining channels are kept.
+	parents := make(map[string]string, len(groups))
+	for _, g := range groups {
+		parents[g.ID] = g.ParentID
+	}
+	for _, ch := range channels {
+		if !used[ch.ID] {
+			continue
+		}
+		for id := ch.ParentID; id != "" && !used[id]; id = parents[id] {
+			if _, ok := parents[id]; !ok {
+				break
+			}
+			used[id] = true
+		}
+	}
+	for _, g := range groups {
+		if filter.Tag != "" && !used[g.ID] {
+			continue
+		}
+		used[g.ID] = true
+		topo.Nodes = append(topo.Nodes, TopologyNode{ID: g.ID, Name: g.Name, Type: GroupsEntity, URL: fmt.Sprintf("%s/groups/%s", us.prefix, g.ID)})
+	}
+
+	for _, ch := range channels {
+		if used[ch.ID] && used[ch.ParentID] {
+			topo.Edges = append(topo.Edges, TopologyEdge{Source: ch.ID, Target: ch.ParentID, Type: EdgeParent})
+		}
+	}
+	for _, g := range groups {
+		if used[g.ID] && used[g.ParentID] {
+			topo.Edges = append(topo.Edges, TopologyEdge{Source: g.ID, Target: g.ParentID, Type: EdgeParent})
+		}
+	}
+
+	return topo, nil
+}
+
+// groupSubtree returns the ids of the group and of the groups below it.
+func groupSubtree(groups []sdk.Group, id string) map[string]bool {
+	children := make(map[string][]string)
+	for _, g := range groups {
+		children[g.ParentID] = append(children[g.ParentID], g.ID)
+	}
+
+	subtree := map[string]bool{id: true}
+	for queue := []string{id}; len(queue) > 0; queue = queue[1:] {
+		for _, child := range children[queue[0]] {
+			if !subtree[child] {
+				subtree[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	return subtree
+}
+
+// channelThings lists the things connected to every channel, in the order of
+// the channels.
+func (us *uiService) channelThings(token string, channels []sdk.Channel) ([][]sdk.Thing, error) {
+	things := make([][]sdk.Thing, len(channels))
+
+	var g errgroup.Group
+	g.SetLimit(topologyConcurrency)
+	for i, ch := range channels {
+		i, id := i, ch.ID
+		g.Go(func() error {
+			for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+				page, err := us.sdk.ThingsByChannel(id, sdk.PageMetadata{Offset: offset, Limit: batchPageSize}, token)
+				if err != nil {
+					return err
+				}
+				if len(page.Things) == 0 {
+					break
+				}
+				things[i] = append(things[i], page.Things...)
+				total = page.Total
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return things, nil
+}
+
+func (us *uiService) allThings(token string) ([]sdk.Thing, error) {
+	var things []sdk.Thing
+	for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+		page, err := us.sdk.Things(sdk.PageMetadata{Offset: offset, Limit: batchPageSize}, token)
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Things) == 0 {
+			break
+		}
+		things = append(things, page.Things...)
+		total = page.Total
+		if total > MaxTopologyNodes {
+			return nil, ErrTooManyEntities
+		}
+	}
+
+	return things, nil
+}
+
+func (us *uiService) allChannels(token string) ([]sdk.Channel, error) {
+	var channels []sdk.Channel
+	for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+		page, err := us.sdk.Channels(sdk.PageMetadata{Offset: offset, Limit: batchPageSize}, token)
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Channels) == 0 {
+			break
+		}
+		channels = append(channels, page.Channels...)
+		total = page.Total
+		if total > MaxTopologyNodes {
+			return nil, ErrTooManyEntities
+		}
+	}
+
+	return channels, nil
+}
+
+func (us *uiService) allGroups(token string) ([]sdk.Group, error) {
+	var groups []sdk.Group
+	for offset, total := uint64(0), uint64(1); offset < total; offset += batchPageSize {
+		page, err := us.sdk.Groups(sdk.PageMetadata{Offset: offset, Limit: batchPageSize}, token)
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Groups) == 0 {
+			break
+		}
+		groups = append(groups, page.Groups...)
+		total = page.Total
+		if total > MaxTopologyNodes {
+			return nil, ErrTooManyEntities
+		}
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+package ui
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"html/template"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	// TwoFactorIssuer is the issuer shown by authenticator apps.
+	TwoFactorIssuer = "Magistrala"
+
+	// RecoveryCodesCount is the number of recovery codes generated on enrolment.
+	RecoveryCodesCount = 10
+
+	qrCodeSize = 200
+
+	totpPeriod = 30
+)
+
+// TwoFactor holds the TOTP enrolment of a user. The secret is stored
+// encrypted and only hashes of the recovery codes are stored. LastStep is the
+// time step of the last accepted code, which cannot be accepted again.
+type TwoFactor struct {
+	UserID        string    `json:"user_id" db:"user_id"`
+	Secret        string    `json:"-" db:"secret"`
+	Enabled       bool      `json:"enabled" db:"enabled"`
+	RecoveryCodes []string  `json:"-" db:"recovery_codes"`
+	LastStep      int64     `json:"-" db:"last_step"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TwoFactorRepository provides an interface for interacting with the two-factor authentication storage.
+//
+//go:generate mockery --name TwoFactorRepository --output=./mocks --filename twofactor.go --quiet --note "Copyright (c) Abstract Machines"
+type TwoFactorRepository interface {
+	// Persists the two-factor enrolment of a user, replacing any existing
+	// one. A non-nil error is returned to indicate a failure to persist.
+	Save(ctx context.Context, tf TwoFactor) error
+
+	// Retrieves the two-factor enrolment of a user. An empty enrolment is
+	// returned if the user has not enrolled. A non-nil error is returned to
+	// indicate a failure to retrieve.
+	Retrieve(ctx context.Context, userID string) (TwoFactor, error)
+
+	// Accepts the time step of a code of an enabled enrolment, unless a
+	// code of the same or a later step was accepted before. It returns
+	// whether the step was accepted. A non-nil error is returned to
+	// indicate a failure to update.
+	AcceptStep(ctx context.Context, userID string, step int64) (bool, error)
+
+	// Removes the hash of a recovery code from the remaining codes of an
+	// enabled enrolment. It returns whether the code was still left. A
+	// non-nil error is returned to indicate a failure to update.
+	UseRecoveryCode(ctx context.Context, userID, hash string) (bool, error)
+
+	// Removes the two-factor enrolment of a user. A non-nil error is
+	// returned to indicate a failure to remove.
+	Remove(ctx context.Context, userID string) error
+
+	// Sets whether members of a domain must use two-factor authentication.
+	// A non-nil error is returned to indicate a failure to persist.
+	SaveDomainRequirement(ctx context.Context, domainID string, required bool) error
+
+	// Retrieves whether members of a domain must use two-factor
+	// authentication. A non-nil error is returned to indicate a failure to
+	// retrieve.
+	RetrieveDomainRequirement(ctx context.Context, domainID string) (bool, error)
+}
+
+// totpSecret generates a new base32 encoded TOTP secret for the account.
+func totpSecret(account string) (string, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      TwoFactorIssuer,
+		AccountName: account,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return key.Secret(), nil
+}
+
+// totpQRCode renders the provisioning URI of the secret as a PNG QR code and
+// returns it as a data URL that can be embedded in a page.
+func totpQRCode(secret, account string) (template.URL, error) {
+	raw, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return "", err
+	}
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      TwoFactorIssuer,
+		AccountName: account,
+		Secret:      raw,
+	})
+	if err != nil {
+		return "", err
+	}
+	img, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())), nil
+}
+
+// totpStep returns the time step for which code is valid for the secret,
+// allowing for one period of clock skew, and whether there is one.
+func totpStep(code, secret string, now time.Time) (int64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	opts := totp.ValidateOpts{
+		Period:    totpPeriod,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	}
+
+	step := now.Unix() / totpPeriod
+	for _, s := range []int64{step - 1, step, step + 1} {
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(s*totpPeriod, 0).UTC(), opts)
+		if err == nil && subtle.ConstantTimeCompare([]byte(code), []byte(expected)) == 1 {
+			return s, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Abstract Machines
+// SPDX-License-Identifier: Apache-2.0
+
+// The Content-Security-Policy refuses inline event handlers, so elements
+// declare them in data-on<event> attributes instead, such as
+// data-onclick="viewThing('id')". A handler calls a global function, or a
+// method of this or event, with string literals, this or event as arguments.
+// A handler prefixed with return cancels the event when it returns false,
+// and a handler that stops the propagation of the event stops the handlers
+// of the enclosing elements, like inline handlers do.
+(function () {
+  const handlerPattern = /^\s*(return\s+)?([\w$]+(?:\.[\w$]+)*)\s*\(([^]*)\)\s*;?\s*$/;
+  const argPattern = /\s*(?:'((?:[^'\\]|\\.)*)'|"((?:[^"\\]|\\.)*)"|((?:this|event)(?:\.[\w$]+)*))\s*(,|$)/y;
+  const escapes = { n: "\n", r: "\r", t: "\t", b: "\b", f: "\f", v: "\v", 0: "\0" };
+
+  function unescape(literal) {
+    return literal.replace(/\\(u[0-9a-fA-F]{4}|x[0-9a-fA-F]{2}|[^])/g, function (_, esc) {
+      if (esc.length > 1) {
+        return String.fromCharCode(parseInt(esc.slice(1), 16));
+      }
+      return esc in escapes ? escapes[esc] : esc;
+    });
+  }
+
+  // resolve follows a dotted path from this, event or the window.
+  function resolve(path, element, event) {
+    const names = path.split(".");
+    let target = window;
+    if (names[0] === "this") {
+      target = element;
+      names.shift();
+    } else if (names[0] === "event") {
+      target = event;
+      names.shift();
+    }
+    let owner = window;
+    for (const name of names) {
+      owner = target;
+      target = target == null ? undefined : target[name];
+    }
+    return { owner: owner, value: target };
+  }
+
+  function parseArgs(source, element, event) {
+    const args = [];
+    source = source.trim();
+    argPattern.lastIndex = 0;
+    while (argPattern.lastIndex < source.length) {
+      const match = argPattern.exec(source);
+      if (!match) {
+        return null;
+      }
+      if (match[1] !== undefined) {
+        args.push(unescape(match[1]));
+      } else if (match[2] !== undefined) {
+        args.push(unescape(match[2]));
+      } else if (match[3] !== undefined) {
+        args.push(resolve(match[3], element, event).value);
+      }
+      if (match[4] === "") {
+        break;
+      }
+    }
+    return args;
+  }
+
+  function run(handler, element, event) {
+    const match = handlerPattern.exec(handler);
+    if (!match) {
+      console.error("invalid event handler:", handler);
+      return;
+    }
+    const callee = resolve(match[2], element, event);
+    const args = parseArgs(match[3], element, event);
+    if (typeof callee.value !== "function" || args === null) {
+      console.error("invalid event handler:", handler);
+      return;
+    }
+    const result = callee.value.apply(callee.owner, args);
+    if (match[1] && result === false) {
+      event.preventDefault();
+    }
+  }
+
+  ["click", "submit", "change", "keydown", "input"].forEach(function (type) {
+    const attribute = "data-on" + type;
+    document.addEventListener(type, function (event) {
+      let element = event.target instanceof Element ? event.target : null;
+      while (element && !event.cancelBubble) {
+        const handler = element.getAttribute(attribute);
+        if (handler !== null) {
+          run(handler, element, event);
+        }
+        element = element.parentElement;
+      }
+    });
+  });
+})();
@@ -1842,7 +1842,7 @@ class Widget {
     newItem.className = "item item-editable";
     newItem.innerHTML = `
         <div class="item-border">
-          <button type="button" class="btn btn-sm" id="removeItem" onclick="removeGridItem(this.parentNode.parentNode);">
+          <button type="button" class="btn btn-sm" id="removeItem" data-onclick="removeGridItem(this.parentNode.parentNode);">
             <i class="fas fa-trash-can"></i>
           </button>
           ${this.config.Content}
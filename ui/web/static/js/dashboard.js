@@ -294,7 +294,7 @@ function showNoWidgetPlaceholder() {
         data-bs-toggle="offcanvas"
         data-bs-target="#widgetsCanvas"
         aria-controls="widgetsCanvas"
-        onclick="editableCanvas()"
+        data-onclick="editableCanvas()"
       >
         <i class="fas fa-plus"></i>
         <span>Add Widgets</span>
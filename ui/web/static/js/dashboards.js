@@ -122,13 +122,13 @@ function createCard(data) {
             </div>
           </a>
           <div class="card-footer buttons">
-            <button type="button" class="btn me-2" onclick="deleteDashboard('${dashboard.id}')">
+            <button type="button" class="btn me-2" data-onclick="deleteDashboard('${dashboard.id}')">
               <i class="fas fa-trash-alt"></i>
             </button>
             <button
               type="button"
               class="btn me-2"
-              onclick="editDashboard('${dashboard.id}', '${dashboard.name}', '${dashboard.description}')"
+              data-onclick="editDashboard('${dashboard.id}', '${dashboard.name}', '${dashboard.description}')"
             >
               <i class="fas fa-edit"></i>
             </button>